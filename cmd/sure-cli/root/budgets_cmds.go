@@ -3,6 +3,8 @@ package root
 import (
 	"fmt"
 	"net/url"
+	"strconv"
+	"time"
 
 	"github.com/spf13/cobra"
 )
@@ -42,6 +44,133 @@ func newBudgetsCmd() *cobra.Command {
 		},
 	})
 
+	cmd.AddCommand(newBudgetsCreateCmd())
+	cmd.AddCommand(newBudgetsUpdateCmd())
+	cmd.AddCommand(newBudgetsSuggestCmd())
+	return cmd
+}
+
+type budgetCreateOpts struct {
+	Month            string
+	BudgetedSpending string
+	ExpectedIncome   string
+	Currency         string
+	Apply            bool
+}
+
+type budgetUpdateOpts struct {
+	BudgetedSpending string
+	ExpectedIncome   string
+	Apply            bool
+}
+
+type budgetCategoryUpdateOpts struct {
+	BudgetedSpending string
+	Apply            bool
+}
+
+// validBudgetAmount accepts plain non-negative decimals ("1400", "170.50").
+func validBudgetAmount(flag, v string) error {
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil || f < 0 {
+		return fmt.Errorf("%s must be a non-negative number, got %q", flag, v)
+	}
+	return nil
+}
+
+func buildBudgetCreatePayload(o budgetCreateOpts) (map[string]any, error) {
+	m, err := time.Parse("2006-01", o.Month)
+	if err != nil {
+		return nil, fmt.Errorf("month is required (YYYY-MM)")
+	}
+	budget := map[string]any{"start_date": m.Format("2006-01-02")}
+	if o.BudgetedSpending != "" {
+		if err := validBudgetAmount("budgeted-spending", o.BudgetedSpending); err != nil {
+			return nil, err
+		}
+		budget["budgeted_spending"] = o.BudgetedSpending
+	}
+	if o.ExpectedIncome != "" {
+		if err := validBudgetAmount("expected-income", o.ExpectedIncome); err != nil {
+			return nil, err
+		}
+		budget["expected_income"] = o.ExpectedIncome
+	}
+	if o.Currency != "" {
+		budget["currency"] = o.Currency
+	}
+	return map[string]any{"budget": budget}, nil
+}
+
+func buildBudgetUpdatePayload(o budgetUpdateOpts) (map[string]any, error) {
+	budget := map[string]any{}
+	if o.BudgetedSpending != "" {
+		if err := validBudgetAmount("budgeted-spending", o.BudgetedSpending); err != nil {
+			return nil, err
+		}
+		budget["budgeted_spending"] = o.BudgetedSpending
+	}
+	if o.ExpectedIncome != "" {
+		if err := validBudgetAmount("expected-income", o.ExpectedIncome); err != nil {
+			return nil, err
+		}
+		budget["expected_income"] = o.ExpectedIncome
+	}
+	if len(budget) == 0 {
+		return nil, fmt.Errorf("at least one of budgeted-spending or expected-income is required")
+	}
+	return map[string]any{"budget": budget}, nil
+}
+
+func buildBudgetCategoryUpdatePayload(o budgetCategoryUpdateOpts) (map[string]any, error) {
+	if o.BudgetedSpending == "" {
+		return nil, fmt.Errorf("budgeted-spending is required")
+	}
+	if err := validBudgetAmount("budgeted-spending", o.BudgetedSpending); err != nil {
+		return nil, err
+	}
+	return map[string]any{"budget_category": map[string]any{"budgeted_spending": o.BudgetedSpending}}, nil
+}
+
+func newBudgetsCreateCmd() *cobra.Command {
+	var o budgetCreateOpts
+	cmd := &cobra.Command{
+		Use:   "create",
+		Short: "Create a monthly budget (default dry-run; use --apply to execute)",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			payload, err := buildBudgetCreatePayload(o)
+			if err != nil {
+				failValidation(err)
+			}
+			dispatchWrite(o.Apply, "POST", "/api/v1/budgets", payload)
+		},
+	}
+	cmd.Flags().StringVar(&o.Month, "month", "", "budget month YYYY-MM (required)")
+	cmd.Flags().StringVar(&o.BudgetedSpending, "budgeted-spending", "", "total budgeted spending")
+	cmd.Flags().StringVar(&o.ExpectedIncome, "expected-income", "", "expected income")
+	cmd.Flags().StringVar(&o.Currency, "currency", "", "currency (default family currency)")
+	cmd.Flags().BoolVar(&o.Apply, "apply", false, "execute the create (otherwise dry-run)")
+	return cmd
+}
+
+func newBudgetsUpdateCmd() *cobra.Command {
+	var o budgetUpdateOpts
+	cmd := &cobra.Command{
+		Use:   "update <id>",
+		Short: "Update a budget's totals (default dry-run; use --apply to execute)",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			payload, err := buildBudgetUpdatePayload(o)
+			if err != nil {
+				failValidation(err)
+			}
+			dispatchWrite(o.Apply, "PATCH", fmt.Sprintf("/api/v1/budgets/%s", url.PathEscape(args[0])), payload)
+		},
+	}
+	cmd.Flags().StringVar(&o.BudgetedSpending, "budgeted-spending", "", "total budgeted spending")
+	cmd.Flags().StringVar(&o.ExpectedIncome, "expected-income", "", "expected income")
+	cmd.Flags().BoolVar(&o.Apply, "apply", false, "execute the update (otherwise dry-run)")
 	return cmd
 }
 
@@ -88,5 +217,25 @@ func newBudgetCategoriesCmd() *cobra.Command {
 		},
 	})
 
+	cmd.AddCommand(newBudgetCategoriesUpdateCmd())
+	return cmd
+}
+
+func newBudgetCategoriesUpdateCmd() *cobra.Command {
+	var o budgetCategoryUpdateOpts
+	cmd := &cobra.Command{
+		Use:   "update <id>",
+		Short: "Set the budgeted amount of a budget category (default dry-run; use --apply to execute)",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			payload, err := buildBudgetCategoryUpdatePayload(o)
+			if err != nil {
+				failValidation(err)
+			}
+			dispatchJournaledWrite(cmd, o.Apply, "PATCH", fmt.Sprintf("/api/v1/budget_categories/%s", url.PathEscape(args[0])), payload)
+		},
+	}
+	cmd.Flags().StringVar(&o.BudgetedSpending, "budgeted-spending", "", "budgeted amount for the category (required)")
+	cmd.Flags().BoolVar(&o.Apply, "apply", false, "execute the update (otherwise dry-run)")
 	return cmd
 }
@@ -52,8 +52,12 @@ func TestBudgetsCommandsRegistered(t *testing.T) {
 	}{
 		{[]string{"budgets", "list"}, "list"},
 		{[]string{"budgets", "show"}, "show"},
+		{[]string{"budgets", "create"}, "create"},
+		{[]string{"budgets", "update"}, "update"},
+		{[]string{"budgets", "suggest"}, "suggest"},
 		{[]string{"budget-categories", "list"}, "list"},
 		{[]string{"budget-categories", "show"}, "show"},
+		{[]string{"budget-categories", "update"}, "update"},
 	}
 	for _, c := range cases {
 		got, _, err := cmd.Find(c.args)
@@ -65,3 +69,50 @@ func TestBudgetsCommandsRegistered(t *testing.T) {
 		}
 	}
 }
+
+func TestBuildBudgetCreatePayload(t *testing.T) {
+	p, err := buildBudgetCreatePayload(budgetCreateOpts{Month: "2026-11", BudgetedSpending: "1400", ExpectedIncome: "3200.50", Currency: "EUR"})
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	b := p["budget"].(map[string]any)
+	if b["start_date"] != "2026-11-01" || b["budgeted_spending"] != "1400" || b["expected_income"] != "3200.50" || b["currency"] != "EUR" {
+		t.Fatalf("unexpected payload: %#v", b)
+	}
+
+	if _, err := buildBudgetCreatePayload(budgetCreateOpts{}); err == nil {
+		t.Fatalf("expected error without month")
+	}
+	if _, err := buildBudgetCreatePayload(budgetCreateOpts{Month: "2026-11", BudgetedSpending: "-5"}); err == nil {
+		t.Fatalf("expected error for negative amount")
+	}
+}
+
+func TestBuildBudgetUpdatePayload_RequiresAtLeastOneField(t *testing.T) {
+	if _, err := buildBudgetUpdatePayload(budgetUpdateOpts{}); err == nil {
+		t.Fatalf("expected error")
+	}
+	p, err := buildBudgetUpdatePayload(budgetUpdateOpts{ExpectedIncome: "3000"})
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if b := p["budget"].(map[string]any); len(b) != 1 || b["expected_income"] != "3000" {
+		t.Fatalf("unexpected payload: %#v", b)
+	}
+}
+
+func TestBuildBudgetCategoryUpdatePayload(t *testing.T) {
+	if _, err := buildBudgetCategoryUpdatePayload(budgetCategoryUpdateOpts{}); err == nil {
+		t.Fatalf("expected error without amount")
+	}
+	if _, err := buildBudgetCategoryUpdatePayload(budgetCategoryUpdateOpts{BudgetedSpending: "abc"}); err == nil {
+		t.Fatalf("expected error for non-numeric amount")
+	}
+	p, err := buildBudgetCategoryUpdatePayload(budgetCategoryUpdateOpts{BudgetedSpending: "170.50"})
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if b := p["budget_category"].(map[string]any); b["budgeted_spending"] != "170.50" {
+		t.Fatalf("unexpected payload: %#v", b)
+	}
+}
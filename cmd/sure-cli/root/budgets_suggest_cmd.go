@@ -0,0 +1,155 @@
+package root
+
+import (
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/we-promise/sure-cli/internal/api"
+	"github.com/we-promise/sure-cli/internal/output"
+	"github.com/we-promise/sure-cli/internal/plan"
+)
+
+func newBudgetsSuggestCmd() *cobra.Command {
+	var monthStr string
+	var months int
+	var roundTo float64
+	var budgetID string
+	var allowZero bool
+	var apply bool
+
+	cmd := &cobra.Command{
+		Use:   "suggest",
+		Short: "Propose category budgets from trailing median spend (default dry-run; use --apply to update)",
+		Long: `Propose a budget per category from the median monthly spend of the
+previous --months complete months, rounded up to --round.
+
+When Sure already has a budget for the month (or --budget-id), each proposal
+is compared with the current budget category. --apply updates those budget
+categories; categories without one in Sure, unchanged ones and suggestions of
+zero (no spend in most history months) are reported as skipped. Pass
+--allow-zero to set those budgets to zero as well.`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			now := time.Now().UTC()
+			month := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+			if monthStr != "" {
+				mm, err := time.Parse("2006-01", monthStr)
+				if err != nil {
+					output.Fail("invalid_month", "month must be YYYY-MM", nil)
+					return
+				}
+				month = mm
+			}
+			if months <= 0 {
+				failValidation(fmt.Errorf("months must be positive"))
+			}
+
+			client := api.New()
+			txs, err := api.FetchTransactionsWindow(client, month.AddDate(0, -months, 0), month.AddDate(0, 0, -1), 100)
+			if err != nil {
+				output.Fail("request_failed", err.Error(), nil)
+				return
+			}
+			txs, excluded := withoutTransfers(client, txs)
+			q := url.Values{}
+			if budgetID != "" {
+				q.Set("budget_id", budgetID)
+			} else {
+				q.Set("start_date", month.Format("2006-01-02"))
+				q.Set("end_date", month.AddDate(0, 1, -1).Format("2006-01-02"))
+			}
+			existing, err := api.FetchBudgetCategories(client, q)
+			if err != nil {
+				output.Fail("request_failed", err.Error(), nil)
+				return
+			}
+
+			report := plan.SuggestBudgets(month, months, roundTo, txs, existing)
+			planned := []map[string]any{}
+			skipped := []map[string]any{}
+			for _, s := range report.Suggestions {
+				if s.BudgetCategoryID == "" {
+					skipped = append(skipped, map[string]any{"category": s.Category, "reason": "no_budget_category"})
+					continue
+				}
+				if s.Delta != nil && *s.Delta == 0 {
+					skipped = append(skipped, map[string]any{"category": s.Category, "reason": "unchanged"})
+					continue
+				}
+				if s.Suggested == 0 && !allowZero {
+					skipped = append(skipped, map[string]any{"category": s.Category, "reason": "zero_median"})
+					continue
+				}
+				payload, _ := buildBudgetCategoryUpdatePayload(budgetCategoryUpdateOpts{BudgetedSpending: fmt.Sprintf("%.2f", s.Suggested)})
+				planned = append(planned, map[string]any{
+					"category":           s.Category,
+					"category_id":        s.CategoryID,
+					"budget_category_id": s.BudgetCategoryID,
+					"from":               s.Current,
+					"to":                 s.Suggested,
+					"request": map[string]any{
+						"method": "PATCH",
+						"path":   fmt.Sprintf("/api/v1/budget_categories/%s", url.PathEscape(s.BudgetCategoryID)),
+						"body":   payload,
+					},
+				})
+			}
+
+			data := map[string]any{
+				"dry_run":            !apply,
+				"month":              report.Month,
+				"history_months":     report.HistoryMonths,
+				"total_suggested":    report.TotalSuggested,
+				"suggestions":        report.Suggestions,
+				"assumptions":        report.Assumptions,
+				"excluded_transfers": excluded,
+				"planned_count":      len(planned),
+				"skipped_count":      len(skipped),
+				"planned":            planned,
+				"skipped":            skipped,
+			}
+			if !apply {
+				_ = output.Print(format, output.Envelope{Data: data, Meta: &output.Meta{Schema: "docs/schemas/v1/budgets_suggest.schema.json", Status: 200}})
+				return
+			}
+
+			rec := newWriteRecorder(cmd)
+			applied := []map[string]any{}
+			failures := []map[string]any{}
+			for _, p := range planned {
+				req := p["request"].(map[string]any)
+				var res any
+				r, err := rec.send(client, "PATCH", req["path"].(string), req["body"], &res)
+				if err != nil {
+					failures = append(failures, map[string]any{"category": p["category"], "error": err.Error()})
+					continue
+				}
+				if r.StatusCode() >= 400 {
+					failures = append(failures, map[string]any{"category": p["category"], "error": fmt.Sprintf("HTTP %d", r.StatusCode())})
+					continue
+				}
+				applied = append(applied, map[string]any{
+					"category":           p["category"],
+					"budget_category_id": p["budget_category_id"],
+					"from":               p["from"],
+					"to":                 p["to"],
+				})
+			}
+			data["applied_count"] = len(applied)
+			data["error_count"] = len(failures)
+			data["applied"] = applied
+			data["errors"] = failures
+			_ = output.Print(format, output.Envelope{Data: data, Meta: &output.Meta{Schema: "docs/schemas/v1/budgets_suggest.schema.json", Status: 200, OperationID: rec.save()}})
+		},
+	}
+	cmd.Flags().StringVar(&monthStr, "month", "", "budget month YYYY-MM (default current month)")
+	cmd.Flags().IntVar(&months, "months", 6, "trailing complete months used for the median")
+	cmd.Flags().Float64Var(&roundTo, "round", 5, "round suggestions up to a multiple of this amount (0 = cents)")
+	cmd.Flags().StringVar(&budgetID, "budget-id", "", "compare against and update this budget (default: the budget covering --month)")
+	cmd.Flags().BoolVar(&allowZero, "allow-zero", false, "also apply suggestions of zero (otherwise skipped as zero_median)")
+	cmd.Flags().BoolVar(&apply, "apply", false, "update existing budget categories (otherwise dry-run)")
+	return cmd
+}
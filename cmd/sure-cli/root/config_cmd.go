@@ -36,6 +36,8 @@ func newConfigCmd() *cobra.Command {
 		Short: "Show current heuristics configuration",
 		Run: func(cmd *cobra.Command, args []string) {
 			h := config.GetHeuristics()
+			aliases, err := config.GetMerchantAliases()
+			failValidation(err)
 			// If no custom keywords, show that defaults will be used
 			keywordsInfo := map[string]any{
 				"custom":        h.Fees.Keywords,
@@ -51,6 +53,10 @@ func newConfigCmd() *cobra.Command {
 				"subscriptions": h.Subscriptions,
 				"leaks":         h.Leaks,
 				"rules":         h.Rules,
+				"anomalies":     h.Anomalies,
+				"duplicates":    h.Duplicates,
+				"transfers":     h.Transfers,
+				"merchants":     config.MerchantsConfig{Aliases: aliases},
 			}})
 		},
 	})
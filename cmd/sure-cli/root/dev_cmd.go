@@ -0,0 +1,218 @@
+package root
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/we-promise/sure-cli/internal/fakesure"
+	"github.com/we-promise/sure-cli/internal/output"
+	"github.com/we-promise/sure-cli/internal/synth"
+)
+
+func newDevCmd() *cobra.Command {
+	cmd := &cobra.Command{Use: "dev", Short: "Developer tooling (local fake Sure API, synthetic data)"}
+	cmd.AddCommand(newDevFakeServerCmd())
+	cmd.AddCommand(newDevSeedCmd())
+	return cmd
+}
+
+func newDevFakeServerCmd() *cobra.Command {
+	var addr, apiKey, fixture, anchor string
+	var rateLimit int
+	var rateWindow time.Duration
+	var noRebase bool
+
+	cmd := &cobra.Command{
+		Use:   "fake-server",
+		Short: "Serve a seeded fake Sure API for local testing (blocks until interrupted)",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			ds := fakesure.DefaultDataset()
+			if fixture != "" {
+				b, err := os.ReadFile(fixture)
+				if err != nil {
+					output.Fail("fixture_read_failed", err.Error(), map[string]any{"path": fixture})
+					return
+				}
+				ds, err = fakesure.LoadDataset(b)
+				if err != nil {
+					output.Fail("fixture_invalid", err.Error(), map[string]any{"path": fixture})
+					return
+				}
+			}
+			// Rebase by default so "last N months" windows used by insights and
+			// plan commands land on fixture data regardless of today's date.
+			if !noRebase {
+				target := time.Now().UTC()
+				if anchor != "" {
+					t, err := time.Parse("2006-01-02", anchor)
+					if err != nil {
+						failValidation(err)
+						return
+					}
+					target = t
+				}
+				if err := ds.Rebase(target); err != nil {
+					output.Fail("fixture_invalid", err.Error(), nil)
+					return
+				}
+			}
+
+			srv := fakesure.New(fakesure.Options{
+				Dataset:    ds,
+				APIKey:     apiKey,
+				RateLimit:  rateLimit,
+				RateWindow: rateWindow,
+			})
+			ln, err := net.Listen("tcp", addr)
+			if err != nil {
+				output.Fail("listen_failed", err.Error(), map[string]any{"addr": addr})
+				return
+			}
+
+			_ = output.Print(format, output.Envelope{Data: map[string]any{
+				"url":         "http://" + ln.Addr().String(),
+				"api_key":     apiKey,
+				"email":       fakesure.DefaultEmail,
+				"password":    fakesure.DefaultPassword,
+				"anchor_date": ds.AnchorDate,
+				"rate_limit":  rateLimit,
+				"hint":        "sure-cli config set api_url <url>; sure-cli config set auth.mode api_key; sure-cli config set auth.api_key <api_key>",
+			}, Meta: &output.Meta{Status: 200}})
+
+			if err := http.Serve(ln, srv); err != nil {
+				output.Fail("serve_failed", err.Error(), nil)
+			}
+		},
+	}
+	cmd.Flags().StringVar(&addr, "addr", "127.0.0.1:3999", "listen address")
+	cmd.Flags().StringVar(&apiKey, "api-key", fakesure.DefaultAPIKey, "API key accepted via X-Api-Key")
+	cmd.Flags().StringVar(&fixture, "fixture", "", "fixture JSON file (default: embedded household)")
+	cmd.Flags().StringVar(&anchor, "anchor", "", "rebase fixture dates so the anchor lands on YYYY-MM-DD (default: today)")
+	cmd.Flags().BoolVar(&noRebase, "no-rebase", false, "serve fixture dates as-is")
+	cmd.Flags().IntVar(&rateLimit, "rate-limit", 0, "max requests per window (0 = unlimited)")
+	cmd.Flags().DurationVar(&rateWindow, "rate-window", time.Hour, "rate-limit window")
+	return cmd
+}
+
+type devSeedOpts struct {
+	Seed          int64
+	Months        int
+	End           string
+	Currency      string
+	Members       int
+	FeeLanguages  string
+	FileFormat    string
+	OutDir        string
+	NoInvestments bool
+	NoTransfers   bool
+}
+
+func newDevSeedCmd() *cobra.Command {
+	var o devSeedOpts
+
+	cmd := &cobra.Command{
+		Use:   "seed",
+		Short: "Generate a synthetic household (SureImport NDJSON or CSV) plus ground-truth labels",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			opts, err := buildSynthOptions(o)
+			failValidation(err)
+			if o.FileFormat != "ndjson" && o.FileFormat != "csv" {
+				failValidation(fmt.Errorf("file-format must be ndjson or csv"))
+			}
+
+			h, err := synth.Generate(opts)
+			failValidation(err)
+
+			var data, labels bytes.Buffer
+			if o.FileFormat == "csv" {
+				err = h.WriteCSV(&data)
+			} else {
+				err = h.WriteNDJSON(&data)
+			}
+			if err == nil {
+				err = synth.WriteLabels(&labels, h.Labels)
+			}
+			if err != nil {
+				output.Fail("generate_failed", err.Error(), nil)
+				return
+			}
+
+			if err := os.MkdirAll(o.OutDir, 0o755); err != nil {
+				output.Fail("write_failed", err.Error(), map[string]any{"path": o.OutDir})
+				return
+			}
+			dataPath := filepath.Join(o.OutDir, "household."+o.FileFormat)
+			labelsPath := filepath.Join(o.OutDir, "labels.json")
+			for path, b := range map[string][]byte{dataPath: data.Bytes(), labelsPath: labels.Bytes()} {
+				if err := os.WriteFile(path, b, 0o644); err != nil {
+					output.Fail("write_failed", err.Error(), map[string]any{"path": path})
+					return
+				}
+			}
+
+			importCmd := "sure-cli imports create --file " + dataPath + " --type SureImport --publish --apply"
+			if o.FileFormat == "csv" {
+				importCmd = "sure-cli imports create --file " + dataPath + " --type TransactionImport" +
+					" --date-col-label date --amount-col-label amount --name-col-label name" +
+					" --category-col-label category --account-col-label account --notes-col-label notes" +
+					" --date-format %Y-%m-%d --publish --apply"
+			}
+			_ = output.Print(format, output.Envelope{Data: map[string]any{
+				"seed":        opts.Seed,
+				"window":      map[string]any{"start": h.Labels.Start, "end": h.Labels.End},
+				"files":       map[string]any{"data": dataPath, "labels": labelsPath},
+				"file_format": o.FileFormat,
+				"counts": map[string]any{
+					"accounts":      len(h.Accounts),
+					"transactions":  len(h.Transactions),
+					"trades":        len(h.Trades),
+					"subscriptions": len(h.Labels.Subscriptions),
+					"fees":          len(h.Labels.Fees),
+					"leaks":         len(h.Labels.Leaks),
+					"rules":         len(h.Labels.Rules),
+					"transfers":     len(h.Labels.Transfers),
+				},
+				"import_command": importCmd,
+			}, Meta: &output.Meta{Status: 200}})
+		},
+	}
+	cmd.Flags().Int64Var(&o.Seed, "seed", 42, "RNG seed (same seed + options = identical output)")
+	cmd.Flags().IntVar(&o.Months, "months", 12, "months of history to generate")
+	cmd.Flags().StringVar(&o.End, "end", "", "last generated date YYYY-MM-DD (default: today)")
+	cmd.Flags().StringVar(&o.Currency, "currency", "EUR", "household currency")
+	cmd.Flags().IntVar(&o.Members, "members", 2, "earners in the household (1-2)")
+	cmd.Flags().StringVar(&o.FeeLanguages, "fee-languages", "en,es", "bank fee descriptor languages (en,es,de,fr)")
+	cmd.Flags().StringVar(&o.FileFormat, "file-format", "ndjson", "output format: ndjson (SureImport) | csv (TransactionImport)")
+	cmd.Flags().StringVar(&o.OutDir, "out-dir", ".", "directory for household.<format> and labels.json")
+	cmd.Flags().BoolVar(&o.NoInvestments, "no-investments", false, "skip the brokerage account and trades")
+	cmd.Flags().BoolVar(&o.NoTransfers, "no-transfers", false, "skip savings transfers and card payments")
+	return cmd
+}
+
+func buildSynthOptions(o devSeedOpts) (synth.Options, error) {
+	opts := synth.Options{
+		Seed:         o.Seed,
+		Months:       o.Months,
+		Currency:     o.Currency,
+		Members:      o.Members,
+		FeeLanguages: synth.ParseLanguages(o.FeeLanguages),
+		Investments:  !o.NoInvestments,
+		Transfers:    !o.NoTransfers,
+	}
+	if o.End != "" {
+		end, err := time.Parse("2006-01-02", o.End)
+		if err != nil {
+			return synth.Options{}, fmt.Errorf("invalid --end: %w", err)
+		}
+		opts.End = end
+	}
+	return opts, nil
+}
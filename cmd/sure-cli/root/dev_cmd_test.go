@@ -0,0 +1,67 @@
+package root
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/spf13/viper"
+
+	"github.com/we-promise/sure-cli/internal/synth"
+)
+
+func TestDevCommandShape(t *testing.T) {
+	cmd := newDevCmd()
+	fs := findSub(t, cmd, "fake-server")
+	for _, name := range []string{"addr", "api-key", "fixture", "anchor", "no-rebase", "rate-limit", "rate-window"} {
+		if fs.Flags().Lookup(name) == nil {
+			t.Fatalf("dev fake-server missing --%s", name)
+		}
+	}
+}
+
+func TestDevCommandRegistered(t *testing.T) {
+	root := New()
+	got, _, err := root.Find([]string{"dev", "fake-server"})
+	if err != nil {
+		t.Fatalf("dev fake-server not registered: %v", err)
+	}
+	if got.Name() != "fake-server" {
+		t.Fatalf("resolved to %q, want fake-server", got.Name())
+	}
+}
+
+func TestDevSeedCommandShape(t *testing.T) {
+	seed := findSub(t, newDevCmd(), "seed")
+	for _, name := range []string{"seed", "months", "end", "currency", "members", "fee-languages", "file-format", "out-dir", "no-investments", "no-transfers"} {
+		if seed.Flags().Lookup(name) == nil {
+			t.Fatalf("dev seed missing --%s", name)
+		}
+	}
+}
+
+func TestDevSeed_WritesDataAndLabels(t *testing.T) {
+	dir := t.TempDir()
+	viper.Reset()
+	cmd := New()
+	cmd.SetArgs([]string{"--config", dir + "/config.yaml", "dev", "seed", "--out-dir", dir, "--end", "2026-06-30", "--file-format", "csv"})
+	out := captureStdout(t, func() {
+		if err := cmd.Execute(); err != nil {
+			t.Fatal(err)
+		}
+	})
+	var env struct {
+		Data struct {
+			Files  map[string]string `json:"files"`
+			Counts map[string]int    `json:"counts"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal([]byte(out), &env); err != nil {
+		t.Fatalf("invalid envelope: %v\n%s", err, out)
+	}
+	if env.Data.Files["data"] != dir+"/household.csv" || env.Data.Counts["transactions"] == 0 {
+		t.Fatalf("unexpected output: %s", out)
+	}
+	if _, err := synth.LoadLabels(env.Data.Files["labels"]); err != nil {
+		t.Fatalf("labels not readable: %v", err)
+	}
+}
@@ -0,0 +1,320 @@
+package root
+
+import (
+	"encoding/json"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+
+	"github.com/we-promise/sure-cli/internal/fakesure"
+	"github.com/we-promise/sure-cli/internal/fakesure/fakesuretest"
+	"github.com/we-promise/sure-cli/internal/journal"
+)
+
+// runAgainstFake executes the root command with args against a fresh fake
+// Sure server and returns the decoded JSON envelope.
+func runAgainstFake(t *testing.T, srv *fakesuretest.Server, args ...string) map[string]any {
+	t.Helper()
+	return runAgainstFakeWithConfig(t, srv, t.TempDir()+"/config.yaml", args...)
+}
+
+// runAgainstFakeWithConfig is runAgainstFake with a config path shared
+// across runs, so journaled writes can be listed and undone.
+func runAgainstFakeWithConfig(t *testing.T, srv *fakesuretest.Server, cfg string, args ...string) map[string]any {
+	t.Helper()
+	viper.Reset()
+	viper.Set("api_url", srv.URL)
+	viper.Set("auth.mode", "api_key")
+	viper.Set("auth.api_key", srv.APIKey)
+
+	cmd := New()
+	cmd.SetArgs(append([]string{"--config", cfg}, args...))
+	out := captureStdout(t, func() {
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("%v: %v", args, err)
+		}
+	})
+	var env map[string]any
+	if err := json.Unmarshal([]byte(out), &env); err != nil {
+		t.Fatalf("%v: invalid JSON envelope: %v\n%s", args, err, out)
+	}
+	return env
+}
+
+func TestFakeServer_ReadCommands(t *testing.T) {
+	ds := fakesure.DefaultDataset()
+	if err := ds.Rebase(time.Now().UTC()); err != nil {
+		t.Fatal(err)
+	}
+	srv := fakesuretest.NewServer(t, fakesure.Options{Dataset: ds})
+	scenario := "../../../docs/examples/scenarios/lean-year.yaml"
+
+	cases := []struct {
+		args []string
+		key  string // top-level key expected in data
+	}{
+		{[]string{"accounts", "list"}, "accounts"},
+		{[]string{"accounts", "show", "acc_checking"}, "id"},
+		{[]string{"transactions", "list", "--per-page", "10"}, "transactions"},
+		{[]string{"transactions", "show", "txn_0001"}, "id"},
+		{[]string{"categories", "list"}, "categories"},
+		{[]string{"merchants", "list"}, "merchants"},
+		{[]string{"tags", "list"}, "tags"},
+		{[]string{"budgets", "list"}, "budgets"},
+		{[]string{"budget-categories", "list"}, "budget_categories"},
+		{[]string{"holdings", "list"}, "holdings"},
+		{[]string{"trades", "list"}, "trades"},
+		{[]string{"syncs", "list"}, "syncs"},
+		{[]string{"syncs", "latest"}, "id"},
+		{[]string{"imports", "list"}, "data"},
+		{[]string{"usage", "show"}, "rate_limit"},
+		{[]string{"insights", "merchants", "--by", "id"}, "merchants"},
+		{[]string{"insights", "anomalies", "--recent-days", "0"}, "candidates"},
+		{[]string{"insights", "duplicates"}, "candidates"},
+		{[]string{"insights", "categories"}, "movers"},
+		{[]string{"insights", "cashflow"}, "income_sources"},
+		{[]string{"transactions", "dedupe", "--months", "12"}, "planned"},
+		{[]string{"transactions", "bulk-update", "--search", "starbucks", "--set-notes", "coffee"}, "planned"},
+		{[]string{"budgets", "suggest", "--months", "3"}, "suggestions"},
+		{[]string{"plan", "forecast", "--balances"}, "accounts"},
+		{[]string{"recurring-transactions", "sync-detected"}, "planned"},
+		{[]string{"transfers", "suggest", "--days", "365"}, "planned"},
+		{[]string{"plan", "fire"}, "fi_number"},
+		{[]string{"plan", "longevity", "--age", "60", "--paths", "200"}, "success_rate"},
+		{[]string{"plan", "debt", "--extra", "100"}, "strategies"},
+		{[]string{"goals", "status"}, "goals"},
+		{[]string{"plan", "forecast", "--scenario", scenario}, "deltas"},
+		{[]string{"plan", "forecast", "--balances", "--scenario", scenario}, "deltas"},
+		{[]string{"plan", "runway"}, "tiers"},
+		{[]string{"plan", "runway", "--account-id", "acc_checking", "--scenario", scenario}, "deltas"},
+		{[]string{"plan", "fire", "--scenario", scenario}, "deltas"},
+		{[]string{"history", "list"}, "operations"},
+	}
+	for _, c := range cases {
+		env := runAgainstFake(t, srv, c.args...)
+		data, ok := env["data"].(map[string]any)
+		if !ok {
+			t.Fatalf("%v: data is not an object: %v", c.args, env)
+		}
+		if _, ok := data[c.key]; !ok {
+			t.Fatalf("%v: data missing %q: %v", c.args, c.key, data)
+		}
+	}
+}
+
+func TestFakeServer_InsightsFindFixtureSubscriptions(t *testing.T) {
+	ds := fakesure.DefaultDataset()
+	if err := ds.Rebase(time.Now().UTC()); err != nil {
+		t.Fatal(err)
+	}
+	srv := fakesuretest.NewServer(t, fakesure.Options{Dataset: ds})
+
+	env := runAgainstFake(t, srv, "insights", "subscriptions", "--months", "6")
+	data, _ := env["data"].(map[string]any)
+	subs, _ := data["candidates"].([]any)
+	names := map[string]bool{}
+	for _, s := range subs {
+		names[s.(map[string]any)["name"].(string)] = true
+	}
+	for _, want := range []string{"NETFLIX.COM", "Spotify"} {
+		if !names[want] {
+			t.Fatalf("subscription %q not detected; got %v", want, names)
+		}
+	}
+}
+
+func TestFakeServer_UndoRestoresJournaledWrites(t *testing.T) {
+	srv := fakesuretest.NewServer(t, fakesure.Options{})
+	cfg := t.TempDir() + "/config.yaml"
+	run := func(args ...string) map[string]any {
+		return runAgainstFakeWithConfig(t, srv, cfg, args...)
+	}
+
+	before := run("transactions", "show", "txn_0001")["data"].(map[string]any)
+	env := run("transactions", "update", "txn_0001", "--notes", "checked", "--category-id", "cat_groceries", "--apply")
+	updateOp, _ := env["meta"].(map[string]any)["operation_id"].(string)
+	env = run("tags", "create", "--name", "Trip", "--apply")
+	tagOp, _ := env["meta"].(map[string]any)["operation_id"].(string)
+	tagID := env["data"].(map[string]any)["id"].(string)
+	if updateOp == "" || tagOp == "" {
+		t.Fatalf("writes were not journaled: %q %q", updateOp, tagOp)
+	}
+
+	list := run("history", "list")["data"].(map[string]any)
+	ops := list["operations"].([]any)
+	if len(ops) != 2 || ops[0].(map[string]any)["id"] != tagOp {
+		t.Fatalf("history = %v", ops)
+	}
+
+	plan := run("undo", updateOp)["data"].(map[string]any)
+	if plan["dry_run"] != true || plan["planned_count"] != float64(1) {
+		t.Fatalf("undo dry-run = %v", plan)
+	}
+	if got := run("transactions", "show", "txn_0001")["data"].(map[string]any)["notes"]; got != "checked" {
+		t.Fatalf("dry-run changed notes to %v", got)
+	}
+
+	for _, op := range []string{updateOp, tagOp} {
+		res := run("undo", op, "--apply")["data"].(map[string]any)
+		if res["applied_count"] != float64(1) || res["error_count"] != float64(0) {
+			t.Fatalf("undo %s = %v", op, res)
+		}
+	}
+	after := run("transactions", "show", "txn_0001")["data"].(map[string]any)
+	if after["notes"] != before["notes"] || after["category"].(map[string]any)["id"] != before["category"].(map[string]any)["id"] {
+		t.Fatalf("txn_0001 not restored: notes=%v category=%v", after["notes"], after["category"])
+	}
+	for _, tag := range run("tags", "list")["data"].(map[string]any)["tags"].([]any) {
+		if tag.(map[string]any)["id"] == tagID {
+			t.Fatalf("tag %s still exists after undo", tagID)
+		}
+	}
+	if ops := run("history", "list")["data"].(map[string]any)["operations"].([]any); ops[2].(map[string]any)["undone_by"] == nil {
+		t.Fatalf("undone operation not marked: %v", ops[2])
+	}
+}
+
+func TestFakeServer_UndoClearsMerchantThatWasAbsent(t *testing.T) {
+	srv := fakesuretest.NewServer(t, fakesure.Options{})
+	dir := t.TempDir()
+	run := func(args ...string) map[string]any {
+		return runAgainstFakeWithConfig(t, srv, filepath.Join(dir, "config.yaml"), args...)
+	}
+
+	before := run("transactions", "show", "txn_0007")["data"].(map[string]any)
+	if before["merchant"] != nil {
+		t.Fatalf("fixture txn_0007 has a merchant: %v", before["merchant"])
+	}
+	// No command sets merchants yet, so send the write and journal it by hand.
+	path := "/api/v1/transactions/txn_0007"
+	body := map[string]any{"transaction": map[string]any{"merchant_id": "mer_acme"}}
+	raw, _ := json.Marshal(body)
+	req, _ := http.NewRequest(http.MethodPatch, srv.URL+path, strings.NewReader(string(raw)))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Api-Key", srv.APIKey)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil || resp.StatusCode != http.StatusOK {
+		t.Fatalf("PATCH = %v, %v", resp, err)
+	}
+	resp.Body.Close()
+	op := journal.NewOperation("transactions update", srv.URL, time.Now())
+	op.Writes = append(op.Writes, journal.Write{Method: "PATCH", Path: path, Body: body, Status: http.StatusOK, Before: before})
+	if err := journal.Append(filepath.Join(dir, "journal.jsonl"), *op); err != nil {
+		t.Fatal(err)
+	}
+
+	res := run("undo", op.ID, "--apply")["data"].(map[string]any)
+	if res["applied_count"] != float64(1) {
+		t.Fatalf("undo = %v", res)
+	}
+	if got := run("transactions", "show", "txn_0007")["data"].(map[string]any)["merchant"]; got != nil {
+		t.Fatalf("merchant after undo = %v, want none", got)
+	}
+}
+
+func TestFakeServer_DedupeFlagAppendsToNotesOnce(t *testing.T) {
+	srv := fakesuretest.NewServer(t, fakesure.Options{})
+	for _, notes := range []string{"", "receipt in drawer"} {
+		env := runAgainstFake(t, srv, "transactions", "create", "--account-id", "acc_checking", "--amount", "42.10",
+			"--date", time.Now().AddDate(0, 0, -7).Format("2006-01-02"), "--name", "Hardware Store", "--notes", notes, "--apply")
+		if env["data"].(map[string]any)["id"] == nil {
+			t.Fatalf("create = %v", env)
+		}
+	}
+
+	res := runAgainstFake(t, srv, "transactions", "dedupe", "--action", "flag", "--apply")["data"].(map[string]any)
+	if res["applied_count"] != float64(1) {
+		t.Fatalf("dedupe = %v", res)
+	}
+	id := res["applied"].([]any)[0].(map[string]any)["tx_id"].(string)
+	keep := res["applied"].([]any)[0].(map[string]any)["keep_tx_id"].(string)
+	want := "receipt in drawer\nPossible duplicate of " + keep + " (flagged by sure-cli transactions dedupe)"
+	if got := runAgainstFake(t, srv, "transactions", "show", id)["data"].(map[string]any)["notes"]; got != want {
+		t.Fatalf("notes = %q, want %q", got, want)
+	}
+
+	again := runAgainstFake(t, srv, "transactions", "dedupe", "--action", "flag")["data"].(map[string]any)
+	if again["planned_count"] != float64(0) || again["skipped"].([]any)[0].(map[string]any)["reason"] != "already_flagged" {
+		t.Fatalf("second dedupe = %v", again)
+	}
+}
+
+func TestFakeServer_BudgetsSuggestApplyIsJournaled(t *testing.T) {
+	ds := fakesure.DefaultDataset()
+	if err := ds.Rebase(time.Now().UTC()); err != nil {
+		t.Fatal(err)
+	}
+	srv := fakesuretest.NewServer(t, fakesure.Options{Dataset: ds})
+	cfg := t.TempDir() + "/config.yaml"
+	run := func(args ...string) map[string]any {
+		return runAgainstFakeWithConfig(t, srv, cfg, args...)
+	}
+	budgeted := func(id string) any {
+		return run("budget-categories", "show", id)["data"].(map[string]any)["budgeted_spending"]
+	}
+
+	before := budgeted("bc_06_dining")
+	env := run("budgets", "suggest", "--months", "3", "--apply")
+	res := env["data"].(map[string]any)
+	op, _ := env["meta"].(map[string]any)["operation_id"].(string)
+	if res["applied_count"] != res["planned_count"] || res["applied_count"] == float64(0) || op == "" {
+		t.Fatalf("apply = %v (operation %q)", res, op)
+	}
+	if budgeted("bc_06_dining") == before {
+		t.Fatalf("bc_06_dining still %v after apply", before)
+	}
+	if undo := run("undo", op, "--apply")["data"].(map[string]any); undo["error_count"] != float64(0) {
+		t.Fatalf("undo = %v", undo)
+	}
+	if got := budgeted("bc_06_dining"); got != before {
+		t.Fatalf("bc_06_dining after undo = %v, want %v", got, before)
+	}
+
+	// No spend before 2000, so every suggestion for that month is zero.
+	zero := run("budgets", "suggest", "--month", "2000-01", "--budget-id", "bud_2026_06")["data"].(map[string]any)
+	if zero["planned_count"] != float64(0) || zero["skipped"].([]any)[0].(map[string]any)["reason"] != "zero_median" {
+		t.Fatalf("zero suggestions = %v", zero)
+	}
+	allowed := run("budgets", "suggest", "--month", "2000-01", "--budget-id", "bud_2026_06", "--allow-zero")["data"].(map[string]any)
+	if allowed["planned_count"] != zero["skipped_count"] {
+		t.Fatalf("--allow-zero planned %v, want %v", allowed["planned_count"], zero["skipped_count"])
+	}
+}
+
+func TestFakeServer_SuggestedCreatesAreJournaled(t *testing.T) {
+	ds := fakesure.DefaultDataset()
+	if err := ds.Rebase(time.Now().UTC()); err != nil {
+		t.Fatal(err)
+	}
+	srv := fakesuretest.NewServer(t, fakesure.Options{Dataset: ds})
+	cfg := t.TempDir() + "/config.yaml"
+	run := func(args ...string) map[string]any {
+		return runAgainstFakeWithConfig(t, srv, cfg, args...)
+	}
+
+	env := run("recurring-transactions", "sync-detected", "--apply")
+	syncOp, _ := env["meta"].(map[string]any)["operation_id"].(string)
+	if syncOp == "" || env["data"].(map[string]any)["applied_count"] == float64(0) {
+		t.Fatalf("sync-detected = %v", env)
+	}
+	if res := run("undo", syncOp, "--apply")["data"].(map[string]any); res["applied_count"] != env["data"].(map[string]any)["applied_count"] {
+		t.Fatalf("undo = %v", res)
+	}
+	if again := run("recurring-transactions", "sync-detected")["data"].(map[string]any); again["planned_count"] != env["data"].(map[string]any)["planned_count"] {
+		t.Fatalf("after undo planned %v, want %v", again["planned_count"], env["data"].(map[string]any)["planned_count"])
+	}
+
+	env = run("transfers", "suggest", "--apply")
+	transferOp, _ := env["meta"].(map[string]any)["operation_id"].(string)
+	if transferOp == "" {
+		t.Fatalf("transfers suggest was not journaled: %v", env)
+	}
+	latest := run("history", "list")["data"].(map[string]any)["operations"].([]any)[0].(map[string]any)
+	if latest["id"] != transferOp || latest["undoable"] != false {
+		t.Fatalf("transfer links should be listed but not undoable: %v", latest)
+	}
+}
@@ -0,0 +1,234 @@
+package root
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/we-promise/sure-cli/internal/api"
+	"github.com/we-promise/sure-cli/internal/config"
+	"github.com/we-promise/sure-cli/internal/insights"
+	"github.com/we-promise/sure-cli/internal/models"
+	"github.com/we-promise/sure-cli/internal/output"
+	"github.com/we-promise/sure-cli/internal/plan"
+)
+
+func newGoalsCmd() *cobra.Command {
+	cmd := &cobra.Command{Use: "goals", Short: "Savings goals tracked against account balances (stored in local config)"}
+	cmd.AddCommand(newGoalsAddCmd())
+	cmd.AddCommand(&cobra.Command{
+		Use:   "list",
+		Short: "List configured goals",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			_ = output.Print(format, output.Envelope{Data: map[string]any{"goals": config.GetGoals()}})
+		},
+	})
+	cmd.AddCommand(&cobra.Command{
+		Use:   "remove <id>",
+		Short: "Remove a goal",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			goals := config.GetGoals()
+			kept := goals[:0]
+			for _, g := range goals {
+				if g.ID != args[0] {
+					kept = append(kept, g)
+				}
+			}
+			if len(kept) == len(goals) {
+				output.Fail("not_found", "goal not found", map[string]any{"id": args[0]})
+				return
+			}
+			if err := config.SaveGoals(kept); err != nil {
+				output.Fail("config_save_failed", err.Error(), nil)
+			}
+			_ = output.Print(format, output.Envelope{Data: map[string]any{"removed": args[0]}})
+		},
+	})
+	cmd.AddCommand(newGoalsStatusCmd())
+	return cmd
+}
+
+func newGoalsAddCmd() *cobra.Command {
+	var name, by string
+	var target, targetMonths float64
+	var accountIDs []string
+
+	cmd := &cobra.Command{
+		Use:   "add",
+		Short: "Add a savings goal",
+		Long: `Add a named savings goal linked to one or more accounts.
+
+The target is an amount (--target 15000) or months of average spend
+(--target-months 6, e.g. an emergency fund); --by sets an optional target
+date. Goals are stored under "goals" in the local config file.`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			client := api.New()
+			accounts, err := api.FetchAccounts(client)
+			if err != nil {
+				output.Fail("request_failed", err.Error(), nil)
+				return
+			}
+			goal, err := newGoal(config.GetGoals(), accounts, name, target, targetMonths, by, accountIDs, time.Now().UTC())
+			if errors.Is(err, errAccountNotFound) {
+				output.Fail("account_not_found", err.Error(), map[string]any{"account_ids": accountIDs})
+				return
+			}
+			failValidation(err)
+			if err := config.SaveGoals(append(config.GetGoals(), goal)); err != nil {
+				output.Fail("config_save_failed", err.Error(), nil)
+			}
+			_ = output.Print(format, output.Envelope{Data: map[string]any{"goal": goal}})
+		},
+	}
+	cmd.Flags().StringVar(&name, "name", "", "goal name (required)")
+	cmd.Flags().Float64Var(&target, "target", 0, "target amount")
+	cmd.Flags().Float64Var(&targetMonths, "target-months", 0, "target as months of average spend (instead of --target)")
+	cmd.Flags().StringVar(&by, "by", "", "target date (YYYY-MM-DD, or YYYY-MM for the month end)")
+	cmd.Flags().StringSliceVar(&accountIDs, "account-id", nil, "linked account(s) (required)")
+	return cmd
+}
+
+var slugPattern = regexp.MustCompile(`[^a-z0-9]+`)
+
+// newGoal validates a goal to add: a unique name, exactly one kind of
+// target, a future date and existing asset accounts.
+func newGoal(existing []config.Goal, accounts []models.Account, name string, target, targetMonths float64, by string, accountIDs []string, now time.Time) (config.Goal, error) {
+	name = strings.TrimSpace(name)
+	id := strings.Trim(slugPattern.ReplaceAllString(strings.ToLower(name), "-"), "-")
+	if id == "" {
+		return config.Goal{}, fmt.Errorf("--name is required")
+	}
+	for _, g := range existing {
+		if g.ID == id {
+			return config.Goal{}, fmt.Errorf("a goal with id %q already exists", id)
+		}
+	}
+	if (target > 0) == (targetMonths > 0) {
+		return config.Goal{}, fmt.Errorf("set exactly one of --target or --target-months (positive)")
+	}
+	if len(accountIDs) == 0 {
+		return config.Goal{}, fmt.Errorf("--account-id is required")
+	}
+	g := config.Goal{ID: id, Name: name, Target: target, TargetMonths: targetMonths, AccountIDs: accountIDs, CreatedAt: now.Format("2006-01-02")}
+	if by != "" {
+		d, err := time.Parse("2006-01-02", by)
+		if err != nil {
+			m, merr := time.Parse("2006-01", by)
+			if merr != nil {
+				return config.Goal{}, fmt.Errorf("--by must be YYYY-MM-DD or YYYY-MM")
+			}
+			d = m.AddDate(0, 1, -1)
+		}
+		if !d.After(now) {
+			return config.Goal{}, fmt.Errorf("--by must be in the future")
+		}
+		g.TargetDate = d.Format("2006-01-02")
+	}
+	byID := map[string]models.Account{}
+	for _, a := range accounts {
+		byID[a.ID] = a
+	}
+	for _, id := range accountIDs {
+		a, ok := byID[id]
+		if !ok {
+			return config.Goal{}, fmt.Errorf("%w: %s", errAccountNotFound, id)
+		}
+		if a.Classification == "liability" {
+			return config.Goal{}, fmt.Errorf("account %s is a liability; goals track asset balances", id)
+		}
+	}
+	return g, nil
+}
+
+func newGoalsStatusCmd() *cobra.Command {
+	var months, spendMonths int
+
+	cmd := &cobra.Command{
+		Use:   "status [id]",
+		Short: "Progress, required monthly saving, projected date and at-risk flags per goal",
+		Long: `Evaluate goals against the linked accounts' current balances.
+
+Velocity is the change of the linked balances (from Sure's balance history)
+over the last --months, per month; the completion date is projected from it.
+A goal is at risk when it is projected past its target date, has not grown,
+or its date has passed. Months-of-spend targets use the average monthly
+spend of the last --spend-months.`,
+		Args: cobra.MaximumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			var goals []plan.Goal
+			for _, g := range config.GetGoals() {
+				if len(args) == 1 && g.ID != args[0] {
+					continue
+				}
+				goals = append(goals, plan.Goal{ID: g.ID, Name: g.Name, Target: g.Target, TargetMonths: g.TargetMonths, TargetDate: g.TargetDate, AccountIDs: g.AccountIDs})
+			}
+			if len(args) == 1 && len(goals) == 0 {
+				output.Fail("not_found", "goal not found", map[string]any{"id": args[0]})
+				return
+			}
+			if months <= 0 || spendMonths <= 0 {
+				failValidation(fmt.Errorf("months must be positive"))
+			}
+
+			client := api.New()
+			now := time.Now().UTC()
+			accounts, err := api.FetchAccounts(client)
+			if err != nil {
+				output.Fail("request_failed", err.Error(), nil)
+				return
+			}
+			var history []models.Balance
+			linked := map[string]bool{}
+			needSpend := false
+			for _, g := range goals {
+				needSpend = needSpend || g.TargetMonths > 0
+				for _, id := range g.AccountIDs {
+					if linked[id] {
+						continue
+					}
+					linked[id] = true
+					q := url.Values{}
+					q.Set("account_id", id)
+					// A month of slack so a record just before the window counts.
+					q.Set("start_date", now.AddDate(0, -months-1, 0).Format("2006-01-02"))
+					bs, err := api.FetchBalances(client, q)
+					if err != nil {
+						output.Fail("request_failed", err.Error(), nil)
+						return
+					}
+					history = append(history, bs...)
+				}
+			}
+			var monthlySpend float64
+			var excluded *insights.TransferExclusion
+			if needSpend {
+				start := now.AddDate(0, -spendMonths, 0)
+				txs, err := api.FetchTransactionsWindow(client, start, now, 500)
+				if err != nil {
+					output.Fail("request_failed", err.Error(), nil)
+					return
+				}
+				txs, ex := withoutTransfers(client, txs)
+				spend, _ := plan.AnnualizedFlows(txs, start, now)
+				monthlySpend = math.Round(spend/12*100) / 100
+				excluded = &ex
+			}
+
+			res := plan.EvaluateGoals(goals, accounts, history, plan.GoalsOptions{AsOf: now, VelocityMonths: months, MonthlySpend: monthlySpend})
+			res.ExcludedTransfers = excluded
+			_ = output.Print(format, output.Envelope{Data: res, Meta: &output.Meta{Schema: "docs/schemas/v1/goals_status.schema.json", Status: 200}})
+		},
+	}
+	cmd.Flags().IntVar(&months, "months", 3, "window for the contribution velocity")
+	cmd.Flags().IntVar(&spendMonths, "spend-months", 6, "history months for the average spend (months-of-spend goals)")
+	return cmd
+}
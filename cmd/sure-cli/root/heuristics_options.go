@@ -0,0 +1,122 @@
+package root
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/we-promise/sure-cli/internal/config"
+	"github.com/we-promise/sure-cli/internal/insights"
+	"github.com/we-promise/sure-cli/internal/output"
+	"github.com/we-promise/sure-cli/internal/rules"
+)
+
+// The *FromConfig helpers map heuristics.* config keys onto the detector
+// options structs. Commands layer their own flags on top, but only when the
+// flag was set explicitly, so config stays the default.
+
+// merchantNormalizer builds the normalizer from heuristics.merchants.aliases,
+// failing the command on a malformed list or an invalid pattern rather than
+// silently ignoring it.
+func merchantNormalizer() *insights.Normalizer {
+	configured, err := config.GetMerchantAliases()
+	failValidation(err)
+	var aliases []insights.MerchantAlias
+	for _, a := range configured {
+		aliases = append(aliases, insights.MerchantAlias{Pattern: a.Pattern, Name: a.Name})
+	}
+	n, err := insights.NewNormalizer(aliases)
+	if err != nil {
+		output.Fail("config_invalid", "heuristics.merchants.aliases: "+err.Error(), nil)
+	}
+	return n
+}
+
+func subscriptionOptionsFromConfig() insights.SubscriptionOptions {
+	h := config.GetHeuristics().Subscriptions
+	n := h.MinOccurrences
+	return insights.SubscriptionOptions{
+		Bands: []insights.PeriodBand{
+			{Name: "weekly", MinDays: h.WeeklyMinDays, MaxDays: h.WeeklyMaxDays, MinCount: n},
+			{Name: "biweekly", MinDays: h.BiweeklyMinDays, MaxDays: h.BiweeklyMaxDays, MinCount: n},
+			{Name: "monthly", MinDays: h.PeriodMinDays, MaxDays: h.PeriodMaxDays, MinCount: n},
+			{Name: "quarterly", MinDays: h.QuarterlyMinDays, MaxDays: h.QuarterlyMaxDays, MinCount: n},
+			{Name: "yearly", MinDays: h.YearlyMinDays, MaxDays: h.YearlyMaxDays, MinCount: h.YearlyMinOccurrences},
+		},
+		StddevMaxDays:     h.StddevMaxDays,
+		AmountStddevRatio: h.AmountStddevRatio,
+		AmountStddevAbs:   insights.DefaultSubscriptionOptions().AmountStddevAbs,
+		PriceChangeMinPct: h.PriceChangeMinPct,
+		Normalizer:        merchantNormalizer(),
+	}
+}
+
+func feeOptionsFromConfig() insights.FeeOptions {
+	return insights.FeeOptions{Keywords: config.GetFeeKeywords(), Normalizer: merchantNormalizer()}
+}
+
+func leakOptionsFromConfig() insights.LeakOptions {
+	h := config.GetHeuristics().Leaks
+	return insights.LeakOptions{MinCount: h.MinCount, MinTotal: h.MinTotal, MaxAvg: h.MaxAvg, Normalizer: merchantNormalizer()}
+}
+
+func ruleOptionsFromConfig() rules.Options {
+	h := config.GetHeuristics().Rules
+	return rules.Options{MinConsistency: h.MinConsistency, MinOccurrences: h.MinOccurrences, MaxProposals: h.MaxProposals, Normalizer: merchantNormalizer()}
+}
+
+func anomalyOptionsFromConfig() insights.AnomalyOptions {
+	h := config.GetHeuristics().Anomalies
+	return insights.AnomalyOptions{
+		ZThreshold:           h.ZThreshold,
+		MinRatio:             h.MinRatio,
+		MinHistory:           h.MinHistory,
+		NewMerchantMinAmount: h.NewMerchantMinAmount,
+		Normalizer:           merchantNormalizer(),
+	}
+}
+
+func duplicateOptionsFromConfig() insights.DuplicateOptions {
+	h := config.GetHeuristics().Duplicates
+	return insights.DuplicateOptions{
+		WindowDays:    h.WindowDays,
+		MinSimilarity: h.MinSimilarity,
+		HabitualCount: h.HabitualCount,
+		Normalizer:    merchantNormalizer(),
+	}
+}
+
+func transferOptionsFromConfig() insights.TransferOptions {
+	return insights.TransferOptions{WindowDays: config.GetHeuristics().Transfers.WindowDays}
+}
+
+type subscriptionFlags struct {
+	stddevMaxDays     float64
+	amountStddevRatio float64
+	minOccurrences    int
+}
+
+func addSubscriptionFlags(cmd *cobra.Command, f *subscriptionFlags) {
+	def := insights.DefaultSubscriptionOptions()
+	cmd.Flags().Float64Var(&f.stddevMaxDays, "stddev-max-days", def.StddevMaxDays, "max stddev of days between charges (overrides heuristics.subscriptions.stddev_max_days)")
+	cmd.Flags().Float64Var(&f.amountStddevRatio, "amount-stddev-ratio", def.AmountStddevRatio, "max amount stddev / mean (overrides heuristics.subscriptions.amount_stddev_ratio)")
+	cmd.Flags().IntVar(&f.minOccurrences, "min-occurrences", 3, "min charges for non-yearly bands (overrides heuristics.subscriptions.min_occurrences)")
+}
+
+// subscriptionOptions returns config-derived options with any explicitly set
+// flags applied.
+func subscriptionOptions(cmd *cobra.Command, f subscriptionFlags) insights.SubscriptionOptions {
+	opts := subscriptionOptionsFromConfig()
+	if cmd.Flags().Changed("stddev-max-days") {
+		opts.StddevMaxDays = f.stddevMaxDays
+	}
+	if cmd.Flags().Changed("amount-stddev-ratio") {
+		opts.AmountStddevRatio = f.amountStddevRatio
+	}
+	if cmd.Flags().Changed("min-occurrences") {
+		for i := range opts.Bands {
+			if opts.Bands[i].Name != "yearly" {
+				opts.Bands[i].MinCount = f.minOccurrences
+			}
+		}
+	}
+	return opts
+}
@@ -0,0 +1,58 @@
+package root
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+
+	"github.com/we-promise/sure-cli/internal/config"
+)
+
+func TestHeuristicsFlagsRegistered(t *testing.T) {
+	for path, flags := range map[string][]string{
+		"insights subscriptions": {"stddev-max-days", "amount-stddev-ratio", "min-occurrences"},
+		"propose rules":          {"min-consistency", "min-occurrences"},
+	} {
+		sub, _, err := New().Find(strings.Fields(path))
+		if err != nil {
+			t.Fatalf("find %q: %v", path, err)
+		}
+		for _, name := range flags {
+			if sub.Flags().Lookup(name) == nil {
+				t.Fatalf("%s: expected flag %q", path, name)
+			}
+		}
+	}
+}
+
+func TestSubscriptionOptions_ConfigThenFlags(t *testing.T) {
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+	if err := config.Init(filepath.Join(t.TempDir(), "config.yaml")); err != nil {
+		t.Fatal(err)
+	}
+	viper.Set("heuristics.subscriptions.stddev_max_days", 5.0)
+	viper.Set("heuristics.subscriptions.yearly_min_occurrences", 3)
+
+	cmd := newInsightsSubscriptionsCmd()
+	if err := cmd.ParseFlags([]string{"--min-occurrences", "4"}); err != nil {
+		t.Fatal(err)
+	}
+	minOcc, _ := cmd.Flags().GetInt("min-occurrences")
+
+	opts := subscriptionOptions(cmd, subscriptionFlags{minOccurrences: minOcc})
+	if opts.StddevMaxDays != 5 {
+		t.Errorf("expected stddev from config (5), got %v", opts.StddevMaxDays)
+	}
+	for _, b := range opts.Bands {
+		want := 4
+		if b.Name == "yearly" {
+			want = 3
+		}
+		if b.MinCount != want {
+			t.Errorf("band %s: expected min_count %d, got %d", b.Name, want, b.MinCount)
+		}
+	}
+}
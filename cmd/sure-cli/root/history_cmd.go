@@ -0,0 +1,201 @@
+package root
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/we-promise/sure-cli/internal/api"
+	"github.com/we-promise/sure-cli/internal/config"
+	"github.com/we-promise/sure-cli/internal/journal"
+	"github.com/we-promise/sure-cli/internal/output"
+)
+
+func newHistoryCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "history",
+		Short: "Applied writes recorded in the local journal",
+		Long: `Every --apply of transactions create/update/delete/bulk-update/dedupe,
+propose rules, tags create/update/delete, categories create,
+budget-categories update, budgets suggest, recurring-transactions
+create/update/delete/sync-detected and transfers suggest records its writes,
+with the state they replaced, in a local journal (journal.path, default
+journal.jsonl next to the config file). Use 'undo' to revert one.
+
+Transfers linked by transfers suggest are listed but cannot be reverted:
+Sure has no endpoint to unlink them.`,
+	}
+	cmd.AddCommand(newHistoryListCmd())
+	cmd.AddCommand(newHistoryShowCmd())
+	return cmd
+}
+
+func loadJournal() (string, []journal.Operation) {
+	path, err := config.JournalPath()
+	if err != nil {
+		output.Fail("config_error", err.Error(), nil)
+	}
+	ops, err := journal.Load(path)
+	if err != nil {
+		output.Fail("journal_read_failed", err.Error(), nil)
+	}
+	return path, ops
+}
+
+// operationSummary is the history list view of one operation.
+func operationSummary(ops []journal.Operation, op journal.Operation) map[string]any {
+	_, err := journal.Inverse(op)
+	m := map[string]any{
+		"id":          op.ID,
+		"command":     op.Command,
+		"created_at":  op.CreatedAt,
+		"api_url":     op.APIURL,
+		"write_count": len(op.Writes),
+		"undoable":    err == nil,
+	}
+	if by := journal.UndoneBy(ops, op.ID); by != "" {
+		m["undone_by"] = by
+	}
+	if op.Undoes != "" {
+		m["undoes"] = op.Undoes
+	}
+	return m
+}
+
+func newHistoryListCmd() *cobra.Command {
+	var limit int
+	var command string
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List journaled operations, newest first",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			if limit < 0 {
+				failValidation(fmt.Errorf("limit must not be negative"))
+			}
+			path, ops := loadJournal()
+			items := []map[string]any{}
+			for i := len(ops) - 1; i >= 0; i-- {
+				if command != "" && !strings.Contains(ops[i].Command, command) {
+					continue
+				}
+				if limit > 0 && len(items) == limit {
+					break
+				}
+				items = append(items, operationSummary(ops, ops[i]))
+			}
+			_ = output.Print(format, output.Envelope{Data: map[string]any{
+				"journal":    path,
+				"total":      len(ops),
+				"count":      len(items),
+				"operations": items,
+			}, Meta: &output.Meta{Schema: "docs/schemas/v1/history_list.schema.json", Status: 200}})
+		},
+	}
+	cmd.Flags().IntVar(&limit, "limit", 20, "max operations to list (0 = all)")
+	cmd.Flags().StringVar(&command, "command", "", "only operations whose command contains this text (e.g. \"tags\")")
+	return cmd
+}
+
+func newHistoryShowCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "show <operation-id>",
+		Short: "Show a journaled operation with its prior state and inverse requests",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			_, ops := loadJournal()
+			op, ok := journal.Find(ops, args[0])
+			if !ok {
+				output.Fail("not_found", fmt.Sprintf("no operation %q in the journal", args[0]), nil)
+			}
+			data := map[string]any{"operation": op}
+			if by := journal.UndoneBy(ops, op.ID); by != "" {
+				data["undone_by"] = by
+			}
+			if inverse, err := journal.Inverse(op); err != nil {
+				data["inverse_error"] = err.Error()
+			} else {
+				data["inverse"] = inverse
+			}
+			_ = output.Print(format, output.Envelope{Data: data, Meta: &output.Meta{Schema: "docs/schemas/v1/history_show.schema.json", Status: 200}})
+		},
+	}
+}
+
+func newUndoCmd() *cobra.Command {
+	var apply, force bool
+	cmd := &cobra.Command{
+		Use:   "undo <operation-id>",
+		Short: "Revert a journaled operation (default dry-run; use --apply to execute)",
+		Long: `Plan the inverse of a journaled operation, newest write first: creates are
+deleted, updates restore the fields they changed from the recorded prior
+state, and deletes are recreated (with new ids).
+
+Without --apply this only reports the requests that would be sent. With
+--apply they are sent and recorded as a new operation, so an undo can be
+undone in turn.`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			_, ops := loadJournal()
+			op, ok := journal.Find(ops, args[0])
+			if !ok {
+				output.Fail("not_found", fmt.Sprintf("no operation %q in the journal", args[0]), nil)
+			}
+			if by := journal.UndoneBy(ops, op.ID); by != "" && !force {
+				failValidation(fmt.Errorf("operation %s was already undone by %s (use --force to send the inverse again)", op.ID, by))
+			}
+			if op.APIURL != "" && op.APIURL != config.APIURL() {
+				failValidation(fmt.Errorf("operation %s was applied to %s, but api_url is %s", op.ID, op.APIURL, config.APIURL()))
+			}
+			inverse, err := journal.Inverse(op)
+			failValidation(err)
+
+			data := map[string]any{
+				"dry_run":       !apply,
+				"operation":     operationSummary(ops, op),
+				"planned_count": len(inverse),
+				"planned":       inverse,
+			}
+			if !apply {
+				_ = output.Print(format, output.Envelope{Data: data, Meta: &output.Meta{Schema: "docs/schemas/v1/undo.schema.json", Status: 200}})
+				return
+			}
+
+			client := api.New()
+			rec := newWriteRecorder(cmd)
+			rec.op.Undoes = op.ID
+			results := []map[string]any{}
+			applied, failed := 0, 0
+			for _, req := range inverse {
+				res := map[string]any{"method": req.Method, "path": req.Path}
+				var out any
+				r, err := rec.send(client, req.Method, req.Path, req.Body, &out)
+				switch {
+				case err != nil:
+					res["status"], res["error"] = "error", err.Error()
+				case r.StatusCode() >= 400:
+					res["status"], res["http_status"], res["error"] = "error", r.StatusCode(), fmt.Sprintf("HTTP %d", r.StatusCode())
+				default:
+					res["status"], res["http_status"] = "applied", r.StatusCode()
+					if m, ok := out.(map[string]any); ok && req.Method == "POST" {
+						res["id"] = fmt.Sprint(m["id"])
+					}
+				}
+				if res["status"] == "applied" {
+					applied++
+				} else {
+					failed++
+				}
+				results = append(results, res)
+			}
+			data["applied_count"] = applied
+			data["error_count"] = failed
+			data["results"] = results
+			_ = output.Print(format, output.Envelope{Data: data, Meta: &output.Meta{Schema: "docs/schemas/v1/undo.schema.json", Status: 200, OperationID: rec.save()}})
+		},
+	}
+	cmd.Flags().BoolVar(&apply, "apply", false, "send the inverse requests (otherwise dry-run)")
+	cmd.Flags().BoolVar(&force, "force", false, "undo even if the operation was already undone")
+	return cmd
+}
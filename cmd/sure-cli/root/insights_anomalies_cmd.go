@@ -0,0 +1,78 @@
+package root
+
+import (
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/we-promise/sure-cli/internal/api"
+	"github.com/we-promise/sure-cli/internal/insights"
+	"github.com/we-promise/sure-cli/internal/output"
+)
+
+func newInsightsAnomaliesCmd() *cobra.Command {
+	var months int
+	var recentDays int
+	var zThreshold float64
+	var minRatio float64
+	var minHistory int
+	var newMerchantMin float64
+
+	cmd := &cobra.Command{
+		Use:   "anomalies",
+		Short: "Flag unusual transactions (amount outliers, new large merchants, same-day duplicates, odd days)",
+		Long: `Flag recent expenses that stand out from history.
+
+The lookback (--months) builds each merchant's and category's distribution;
+only transactions from the last --recent-days are reported.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			end := time.Now()
+			start := end.AddDate(0, -months, 0)
+			client := api.New()
+			txs, err := api.FetchTransactionsWindow(client, start, end, 100)
+			if err != nil {
+				output.Fail("request_failed", err.Error(), nil)
+				return
+			}
+			txs, excluded := withoutTransfers(client, txs)
+			opts := anomalyOptionsFromConfig()
+			if cmd.Flags().Changed("z-threshold") {
+				opts.ZThreshold = zThreshold
+			}
+			if cmd.Flags().Changed("min-ratio") {
+				opts.MinRatio = minRatio
+			}
+			if cmd.Flags().Changed("min-history") {
+				opts.MinHistory = minHistory
+			}
+			if cmd.Flags().Changed("new-merchant-min") {
+				opts.NewMerchantMinAmount = newMerchantMin
+			}
+			since := end.AddDate(0, 0, -recentDays)
+			if recentDays > 0 {
+				opts.Since = since
+			} else {
+				since = start
+			}
+			cands := insights.DetectAnomaliesWithOptions(txs, opts)
+			if cands == nil {
+				cands = []insights.AnomalyCandidate{}
+			}
+			_ = output.Print(format, output.Envelope{Data: map[string]any{
+				"window":             map[string]any{"start": start.Format("2006-01-02"), "end": end.Format("2006-01-02")},
+				"evaluated":          map[string]any{"start": since.Format("2006-01-02"), "end": end.Format("2006-01-02")},
+				"params":             opts,
+				"candidates":         cands,
+				"excluded_transfers": excluded,
+			}, Meta: &output.Meta{Schema: "docs/schemas/v1/insights_anomalies.schema.json", Status: 200}})
+		},
+	}
+	def := insights.DefaultAnomalyOptions()
+	cmd.Flags().IntVar(&months, "months", 6, "lookback months used as history")
+	cmd.Flags().IntVar(&recentDays, "recent-days", 30, "only report transactions from the last N days (0 = whole window)")
+	cmd.Flags().Float64Var(&zThreshold, "z-threshold", def.ZThreshold, "robust z-score cutoff (overrides heuristics.anomalies.z_threshold)")
+	cmd.Flags().Float64Var(&minRatio, "min-ratio", def.MinRatio, "minimum multiple of the median (overrides heuristics.anomalies.min_ratio)")
+	cmd.Flags().IntVar(&minHistory, "min-history", def.MinHistory, "transactions needed before a distribution is trusted (overrides heuristics.anomalies.min_history)")
+	cmd.Flags().Float64Var(&newMerchantMin, "new-merchant-min", def.NewMerchantMinAmount, "flag first-time merchants from this amount (overrides heuristics.anomalies.new_merchant_min_amount)")
+	return cmd
+}
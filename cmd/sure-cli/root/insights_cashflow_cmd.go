@@ -0,0 +1,62 @@
+package root
+
+import (
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/we-promise/sure-cli/internal/api"
+	"github.com/we-promise/sure-cli/internal/insights"
+	"github.com/we-promise/sure-cli/internal/output"
+)
+
+func newInsightsCashflowCmd() *cobra.Command {
+	var months int
+	var includeCurrent bool
+	opts := insights.DefaultCashflowOptions()
+
+	cmd := &cobra.Command{
+		Use:   "cashflow",
+		Short: "Monthly income, expense, net and savings rate with income stability and sources",
+		Long: `Build monthly income, expense, net and savings-rate series over the last
+--months complete months, measure their volatility, flag months with negative
+cash flow and classify income sources as salary, recurring or irregular.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			if months <= 0 {
+				months = 12
+			}
+			now := time.Now().UTC()
+			cur := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+			start := cur.AddDate(0, -months, 0)
+			end := cur.AddDate(0, 0, -1)
+			if includeCurrent {
+				start = cur.AddDate(0, -(months - 1), 0)
+				end = now
+			}
+			client := api.New()
+			txs, err := api.FetchTransactionsWindow(client, start, end, 100)
+			if err != nil {
+				output.Fail("request_failed", err.Error(), nil)
+				return
+			}
+			txs, excluded := withoutTransfers(client, txs)
+			opts.Start, opts.End = start, end
+			opts.Normalizer = merchantNormalizer()
+			report := insights.Cashflow(txs, opts)
+			_ = output.Print(format, output.Envelope{Data: map[string]any{
+				"window":             map[string]any{"start": start.Format("2006-01-02"), "end": end.Format("2006-01-02")},
+				"params":             opts,
+				"months":             report.Months,
+				"totals":             report.Totals,
+				"volatility":         report.Volatility,
+				"income_sources":     report.IncomeSources,
+				"negative_months":    report.NegativeMonths,
+				"excluded_transfers": excluded,
+			}, Meta: &output.Meta{Schema: "docs/schemas/v1/insights_cashflow.schema.json", Status: 200}})
+		},
+	}
+	cmd.Flags().IntVar(&months, "months", 12, "number of months")
+	cmd.Flags().BoolVar(&includeCurrent, "include-current", false, "include the current, incomplete month")
+	cmd.Flags().Float64Var(&opts.SalaryMaxAmountCV, "salary-max-cv", opts.SalaryMaxAmountCV, "max amount variation (stddev/mean) for an income to count as salary")
+	return cmd
+}
@@ -0,0 +1,76 @@
+package root
+
+import (
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/we-promise/sure-cli/internal/api"
+	"github.com/we-promise/sure-cli/internal/insights"
+	"github.com/we-promise/sure-cli/internal/output"
+)
+
+func newInsightsCategoriesCmd() *cobra.Command {
+	var monthStr string
+	var months int
+	opts := insights.DefaultCategoryOptions()
+
+	cmd := &cobra.Command{
+		Use:   "categories",
+		Short: "Category spending per month with month-over-month, rolling and year-over-year changes",
+		Long: `Aggregate expenses per category over monthly buckets and compare the
+report month (--month, default last complete month) with the previous month,
+the rolling average of the months before it and the same month last year.
+
+Subcategories are also rolled up into their parent using 'categories list'.
+The default 13-month lookback keeps last year's month in the window.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			now := time.Now()
+			month := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC).AddDate(0, -1, 0)
+			if monthStr != "" {
+				mm, err := time.Parse("2006-01", monthStr)
+				if err != nil {
+					output.Fail("invalid_month", "month must be YYYY-MM", nil)
+					return
+				}
+				month = mm
+			}
+			if months <= 0 {
+				months = 13
+			}
+			start := month.AddDate(0, -(months - 1), 0)
+			end := month.AddDate(0, 1, -1)
+
+			client := api.New()
+			txs, err := api.FetchTransactionsWindow(client, start, end, 100)
+			if err != nil {
+				output.Fail("request_failed", err.Error(), nil)
+				return
+			}
+			txs, excluded := withoutTransfers(client, txs)
+			cats, err := api.FetchCategories(client)
+			if err != nil {
+				output.Fail("request_failed", err.Error(), nil)
+				return
+			}
+			opts.Month, opts.Start, opts.Categories = month, start, cats
+			report := insights.CategoryTrends(txs, opts)
+			_ = output.Print(format, output.Envelope{Data: map[string]any{
+				"window":             map[string]any{"start": start.Format("2006-01-02"), "end": end.Format("2006-01-02")},
+				"params":             opts,
+				"month":              report.Month,
+				"months":             report.Months,
+				"total":              report.Total,
+				"categories":         report.Categories,
+				"parents":            report.Parents,
+				"movers":             report.Movers,
+				"excluded_transfers": excluded,
+			}, Meta: &output.Meta{Schema: "docs/schemas/v1/insights_categories.schema.json", Status: 200}})
+		},
+	}
+	cmd.Flags().StringVar(&monthStr, "month", "", "report month YYYY-MM (default last complete month)")
+	cmd.Flags().IntVar(&months, "months", 13, "months of buckets ending with the report month")
+	cmd.Flags().IntVar(&opts.RollingMonths, "rolling", opts.RollingMonths, "months in the rolling average before the report month")
+	cmd.Flags().IntVar(&opts.TopMovers, "movers", opts.TopMovers, "number of biggest movers to return")
+	return cmd
+}
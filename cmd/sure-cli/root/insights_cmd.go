@@ -7,5 +7,11 @@ func newInsightsCmd() *cobra.Command {
 	cmd.AddCommand(newInsightsSubscriptionsCmd())
 	cmd.AddCommand(newInsightsFeesCmd())
 	cmd.AddCommand(newInsightsLeaksCmd())
+	cmd.AddCommand(newInsightsMerchantsCmd())
+	cmd.AddCommand(newInsightsAnomaliesCmd())
+	cmd.AddCommand(newInsightsDuplicatesCmd())
+	cmd.AddCommand(newInsightsCategoriesCmd())
+	cmd.AddCommand(newInsightsCashflowCmd())
+	cmd.AddCommand(newInsightsEvalCmd())
 	return cmd
 }
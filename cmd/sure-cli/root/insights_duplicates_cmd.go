@@ -0,0 +1,76 @@
+package root
+
+import (
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/we-promise/sure-cli/internal/api"
+	"github.com/we-promise/sure-cli/internal/insights"
+	"github.com/we-promise/sure-cli/internal/output"
+)
+
+type duplicateFlags struct {
+	months        int
+	windowDays    int
+	minSimilarity float64
+}
+
+func addDuplicateFlags(cmd *cobra.Command, f *duplicateFlags) {
+	def := insights.DefaultDuplicateOptions()
+	cmd.Flags().IntVar(&f.months, "months", 3, "lookback months")
+	cmd.Flags().IntVar(&f.windowDays, "window-days", def.WindowDays, "max days between copies (overrides heuristics.duplicates.window_days)")
+	cmd.Flags().Float64Var(&f.minSimilarity, "min-similarity", def.MinSimilarity, "min name similarity 0..1 (overrides heuristics.duplicates.min_similarity)")
+}
+
+// detectDuplicates fetches the lookback window and runs the detector with
+// config defaults overridden by any flags the user set.
+func detectDuplicates(cmd *cobra.Command, f duplicateFlags) (start, end time.Time, opts insights.DuplicateOptions, cands []insights.DuplicateCandidate, txs []insights.Transaction, err error) {
+	end = time.Now()
+	start = end.AddDate(0, -f.months, 0)
+	txs, err = api.FetchTransactionsWindow(api.New(), start, end, 100)
+	if err != nil {
+		return
+	}
+	opts = duplicateOptionsFromConfig()
+	if cmd.Flags().Changed("window-days") {
+		opts.WindowDays = f.windowDays
+	}
+	if cmd.Flags().Changed("min-similarity") {
+		opts.MinSimilarity = f.minSimilarity
+	}
+	cands = insights.DetectDuplicatesWithOptions(txs, opts)
+	if cands == nil {
+		cands = []insights.DuplicateCandidate{}
+	}
+	return
+}
+
+func newInsightsDuplicatesCmd() *cobra.Command {
+	var f duplicateFlags
+
+	cmd := &cobra.Command{
+		Use:   "duplicates",
+		Short: "Find likely duplicate transactions (same account and amount, near date, similar name)",
+		Long: `Find transactions recorded more than once, typically by a bank sync and a
+CSV import of the same period.
+
+Each candidate names the copy to keep (the one with a category or merchant,
+otherwise the earliest) and the redundant ones. Use 'transactions dedupe' to
+delete or flag them.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			start, end, opts, cands, _, err := detectDuplicates(cmd, f)
+			if err != nil {
+				output.Fail("request_failed", err.Error(), nil)
+				return
+			}
+			_ = output.Print(format, output.Envelope{Data: map[string]any{
+				"window":     map[string]any{"start": start.Format("2006-01-02"), "end": end.Format("2006-01-02")},
+				"params":     opts,
+				"candidates": cands,
+			}, Meta: &output.Meta{Schema: "docs/schemas/v1/insights_duplicates.schema.json", Status: 200}})
+		},
+	}
+	addDuplicateFlags(cmd, &f)
+	return cmd
+}
@@ -0,0 +1,78 @@
+package root
+
+import (
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/we-promise/sure-cli/internal/api"
+	"github.com/we-promise/sure-cli/internal/eval"
+	"github.com/we-promise/sure-cli/internal/models"
+	"github.com/we-promise/sure-cli/internal/output"
+	"github.com/we-promise/sure-cli/internal/synth"
+)
+
+func newInsightsEvalCmd() *cobra.Command {
+	var labelsPath, input string
+
+	cmd := &cobra.Command{
+		Use:   "eval",
+		Short: "Score subscription/fee/leak/rule detection against ground-truth labels (precision/recall)",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			if labelsPath == "" {
+				output.Fail("validation_failed", "--labels is required", nil)
+				return
+			}
+			labels, err := synth.LoadLabels(labelsPath)
+			if err != nil {
+				output.Fail("labels_invalid", err.Error(), map[string]any{"path": labelsPath})
+				return
+			}
+
+			// Without --input, evaluate live data over the labelled window
+			// (e.g. after importing a `dev seed` household).
+			var txs []models.Transaction
+			source := "file"
+			if input != "" {
+				txs, err = eval.LoadTransactions(input)
+				if err != nil {
+					output.Fail("input_invalid", err.Error(), map[string]any{"path": input})
+					return
+				}
+			} else {
+				source = "api"
+				start, err1 := time.Parse("2006-01-02", labels.Start)
+				end, err2 := time.Parse("2006-01-02", labels.End)
+				if err1 != nil || err2 != nil {
+					output.Fail("labels_invalid", "labels start/end must be YYYY-MM-DD when --input is not set", nil)
+					return
+				}
+				txs, err = api.FetchTransactionsWindow(api.New(), start, end, 100)
+				if err != nil {
+					output.Fail("request_failed", err.Error(), nil)
+					return
+				}
+			}
+
+			opts := eval.Options{
+				Subscriptions: subscriptionOptionsFromConfig(),
+				Fees:          feeOptionsFromConfig(),
+				Leaks:         leakOptionsFromConfig(),
+				Rules:         ruleOptionsFromConfig(),
+			}
+			rep := eval.Run(txs, labels, opts)
+			_ = output.Print(format, output.Envelope{Data: map[string]any{
+				"source":            source,
+				"labels":            labelsPath,
+				"window":            map[string]any{"start": labels.Start, "end": labels.End},
+				"transaction_count": rep.TransactionCount,
+				"params":            opts,
+				"detectors":         rep.Detectors,
+			}, Meta: &output.Meta{Schema: "docs/schemas/v1/insights_eval.schema.json", Status: 200}})
+		},
+	}
+	cmd.Flags().StringVar(&labelsPath, "labels", "", "ground-truth labels JSON (as written by `dev seed`); required")
+	cmd.Flags().StringVar(&input, "input", "", "evaluate a local .ndjson/.csv file instead of fetching from the API")
+	return cmd
+}
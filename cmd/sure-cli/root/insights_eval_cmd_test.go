@@ -0,0 +1,97 @@
+package root
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+
+	"github.com/we-promise/sure-cli/internal/synth"
+)
+
+func TestInsightsEvalCommandShape(t *testing.T) {
+	cmd := newInsightsEvalCmd()
+	if cmd.Use != "eval" {
+		t.Fatalf("Use = %q, want eval", cmd.Use)
+	}
+	for _, name := range []string{"labels", "input"} {
+		if cmd.Flags().Lookup(name) == nil {
+			t.Fatalf("insights eval missing --%s", name)
+		}
+	}
+}
+
+func TestInsightsEvalCommandRegistered(t *testing.T) {
+	got, _, err := New().Find([]string{"insights", "eval"})
+	if err != nil {
+		t.Fatalf("insights eval not registered: %v", err)
+	}
+	if got.Name() != "eval" {
+		t.Fatalf("resolved to %q, want eval", got.Name())
+	}
+}
+
+func TestInsightsEval_ScoresLocalFile(t *testing.T) {
+	dir := t.TempDir()
+	o := synth.DefaultOptions()
+	o.End = time.Date(2026, 6, 30, 0, 0, 0, 0, time.UTC)
+	h, err := synth.Generate(o)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.Create(filepath.Join(dir, "household.ndjson"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := h.WriteNDJSON(data); err != nil {
+		t.Fatal(err)
+	}
+	_ = data.Close()
+	labels, err := os.Create(filepath.Join(dir, "labels.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := synth.WriteLabels(labels, h.Labels); err != nil {
+		t.Fatal(err)
+	}
+	_ = labels.Close()
+
+	viper.Reset()
+	cmd := New()
+	cmd.SetArgs([]string{"--config", filepath.Join(dir, "config.yaml"), "insights", "eval",
+		"--labels", labels.Name(), "--input", data.Name()})
+	out := captureStdout(t, func() {
+		if err := cmd.Execute(); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	var env struct {
+		Data struct {
+			Source           string `json:"source"`
+			TransactionCount int    `json:"transaction_count"`
+			Detectors        []struct {
+				Detector string  `json:"detector"`
+				Recall   float64 `json:"recall"`
+			} `json:"detectors"`
+		} `json:"data"`
+		Meta struct {
+			Schema string `json:"schema"`
+		} `json:"meta"`
+	}
+	if err := json.Unmarshal([]byte(out), &env); err != nil {
+		t.Fatalf("invalid envelope: %v\n%s", err, out)
+	}
+	if env.Data.Source != "file" || env.Data.TransactionCount != len(h.Transactions) {
+		t.Fatalf("unexpected data: %s", out)
+	}
+	if len(env.Data.Detectors) != 5 {
+		t.Fatalf("detectors = %d, want 5", len(env.Data.Detectors))
+	}
+	if env.Meta.Schema != "docs/schemas/v1/insights_eval.schema.json" {
+		t.Fatalf("schema = %q", env.Meta.Schema)
+	}
+}
@@ -4,7 +4,6 @@ import (
 	"time"
 
 	"github.com/we-promise/sure-cli/internal/api"
-	"github.com/we-promise/sure-cli/internal/config"
 	"github.com/we-promise/sure-cli/internal/insights"
 	"github.com/we-promise/sure-cli/internal/output"
 	"github.com/spf13/cobra"
@@ -25,13 +24,17 @@ func newInsightsFeesCmd() *cobra.Command {
 				return
 			}
 			// Use keywords from config (or defaults if empty)
-			keywords := config.GetFeeKeywords()
-			cands := insights.DetectFees(txs, keywords)
+			opts := feeOptionsFromConfig()
+			cands := insights.DetectFeesWithOptions(txs, opts)
 			if cands == nil {
 				cands = []insights.FeeCandidate{}
 			}
 			_ = output.Print(format, output.Envelope{Data: map[string]any{
-				"window":     map[string]any{"start": start.Format("2006-01-02"), "end": end.Format("2006-01-02")},
+				"window": map[string]any{"start": start.Format("2006-01-02"), "end": end.Format("2006-01-02")},
+				"params": map[string]any{
+					"custom_keywords": len(opts.Keywords) > 0,
+					"keyword_count":   len(insights.GetFeeKeywords(opts.Keywords)),
+				},
 				"candidates": cands,
 			}, Meta: &output.Meta{Schema: "docs/schemas/v1/insights_fees.schema.json"}})
 		},
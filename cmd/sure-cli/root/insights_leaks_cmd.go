@@ -21,25 +21,38 @@ func newInsightsLeaksCmd() *cobra.Command {
 		Run: func(cmd *cobra.Command, args []string) {
 			end := time.Now()
 			start := end.AddDate(0, -months, 0)
-			txs, err := api.FetchTransactionsWindow(api.New(), start, end, 100)
+			client := api.New()
+			txs, err := api.FetchTransactionsWindow(client, start, end, 100)
 			if err != nil {
 				output.Fail("request_failed", err.Error(), nil)
 				return
 			}
-			cands := insights.DetectLeaks(txs, minCount, minTotal, maxAvg)
+			txs, excluded := withoutTransfers(client, txs)
+			opts := leakOptionsFromConfig()
+			if cmd.Flags().Changed("min-count") {
+				opts.MinCount = minCount
+			}
+			if cmd.Flags().Changed("min-total") {
+				opts.MinTotal = minTotal
+			}
+			if cmd.Flags().Changed("max-avg") {
+				opts.MaxAvg = maxAvg
+			}
+			cands := insights.DetectLeaksWithOptions(txs, opts)
 			if cands == nil {
 				cands = []insights.LeakCandidate{}
 			}
 			_ = output.Print(format, output.Envelope{Data: map[string]any{
-				"window":     map[string]any{"start": start.Format("2006-01-02"), "end": end.Format("2006-01-02")},
-				"params":     map[string]any{"min_count": minCount, "min_total": minTotal, "max_avg": maxAvg},
-				"candidates": cands,
+				"window":             map[string]any{"start": start.Format("2006-01-02"), "end": end.Format("2006-01-02")},
+				"params":             opts,
+				"candidates":         cands,
+				"excluded_transfers": excluded,
 			}, Meta: &output.Meta{Schema: "docs/schemas/v1/insights_leaks.schema.json"}})
 		},
 	}
 	cmd.Flags().IntVar(&months, "months", 3, "lookback months")
-	cmd.Flags().IntVar(&minCount, "min-count", 3, "minimum occurrences")
-	cmd.Flags().Float64Var(&minTotal, "min-total", 15, "minimum total spend")
-	cmd.Flags().Float64Var(&maxAvg, "max-avg", 10, "maximum average per transaction")
+	cmd.Flags().IntVar(&minCount, "min-count", 3, "minimum occurrences (overrides heuristics.leaks.min_count)")
+	cmd.Flags().Float64Var(&minTotal, "min-total", 15, "minimum total spend (overrides heuristics.leaks.min_total)")
+	cmd.Flags().Float64Var(&maxAvg, "max-avg", 10, "maximum average per transaction (overrides heuristics.leaks.max_avg)")
 	return cmd
 }
@@ -0,0 +1,61 @@
+package root
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/we-promise/sure-cli/internal/api"
+	"github.com/we-promise/sure-cli/internal/insights"
+	"github.com/we-promise/sure-cli/internal/output"
+)
+
+func newInsightsMerchantsCmd() *cobra.Command {
+	var months int
+	opts := insights.DefaultMerchantOptions()
+
+	cmd := &cobra.Command{
+		Use:   "merchants",
+		Short: "Rank merchants by spend, count and average ticket with month-over-month trend",
+		Run: func(cmd *cobra.Command, args []string) {
+			switch opts.SortBy {
+			case "spend", "count", "avg":
+			default:
+				failValidation(fmt.Errorf("sort must be spend, count or avg"))
+			}
+			switch opts.GroupBy {
+			case "name", "id":
+			default:
+				failValidation(fmt.Errorf("by must be name or id"))
+			}
+
+			end := time.Now()
+			start := end.AddDate(0, -months, 0)
+			client := api.New()
+			txs, err := api.FetchTransactionsWindow(client, start, end, 100)
+			if err != nil {
+				output.Fail("request_failed", err.Error(), nil)
+				return
+			}
+			txs, excluded := withoutTransfers(client, txs)
+			opts.End = end
+			opts.Normalizer = merchantNormalizer()
+			report := insights.RankMerchants(txs, opts)
+			_ = output.Print(format, output.Envelope{Data: map[string]any{
+				"window":             map[string]any{"start": start.Format("2006-01-02"), "end": end.Format("2006-01-02")},
+				"params":             opts,
+				"total_spend":        report.TotalSpend,
+				"merchant_count":     report.MerchantCount,
+				"merchants":          report.Merchants,
+				"excluded_transfers": excluded,
+			}, Meta: &output.Meta{Schema: "docs/schemas/v1/insights_merchants.schema.json", Status: 200}})
+		},
+	}
+	cmd.Flags().IntVar(&months, "months", 6, "lookback months")
+	cmd.Flags().IntVar(&opts.Top, "top", opts.Top, "number of merchants to return (0 = all)")
+	cmd.Flags().StringVar(&opts.SortBy, "sort", opts.SortBy, "rank by spend|count|avg")
+	cmd.Flags().StringVar(&opts.GroupBy, "by", opts.GroupBy, "group by normalized name or Sure merchant id (name|id)")
+	cmd.Flags().Float64Var(&opts.TrendThresholdPct, "trend-threshold", opts.TrendThresholdPct, "month-over-month change (%) below which a trend is flat")
+	return cmd
+}
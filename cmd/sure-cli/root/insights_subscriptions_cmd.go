@@ -11,6 +11,7 @@ import (
 
 func newInsightsSubscriptionsCmd() *cobra.Command {
 	var months int
+	var flags subscriptionFlags
 
 	cmd := &cobra.Command{
 		Use:   "subscriptions",
@@ -19,21 +20,28 @@ func newInsightsSubscriptionsCmd() *cobra.Command {
 			end := time.Now()
 			start := end.AddDate(0, -months, 0)
 
-			txs, err := api.FetchTransactionsWindow(api.New(), start, end, 100)
+			client := api.New()
+			txs, err := api.FetchTransactionsWindow(client, start, end, 100)
 			if err != nil {
 				output.Fail("request_failed", err.Error(), nil)
 				return
 			}
-			cands := insights.DetectSubscriptions(txs)
+			txs, excluded := withoutTransfers(client, txs)
+			opts := subscriptionOptions(cmd, flags)
+			opts.AsOf = end
+			cands := insights.DetectSubscriptionsWithOptions(txs, opts)
 			if cands == nil {
 				cands = []insights.SubscriptionCandidate{}
 			}
 			_ = output.Print(format, output.Envelope{Data: map[string]any{
-				"window":     map[string]any{"start": start.Format("2006-01-02"), "end": end.Format("2006-01-02")},
-				"candidates": cands,
+				"window":             map[string]any{"start": start.Format("2006-01-02"), "end": end.Format("2006-01-02")},
+				"params":             opts,
+				"candidates":         cands,
+				"excluded_transfers": excluded,
 			}, Meta: &output.Meta{Schema: "docs/schemas/v1/insights_subscriptions.schema.json"}})
 		},
 	}
 	cmd.Flags().IntVar(&months, "months", 6, "lookback months")
+	addSubscriptionFlags(cmd, &flags)
 	return cmd
 }
@@ -161,6 +161,7 @@ func newRecurringTransactionsCmd() *cobra.Command {
 	cmd.AddCommand(newRecurringTransactionsCreateCmd())
 	cmd.AddCommand(newRecurringTransactionsUpdateCmd())
 	cmd.AddCommand(newRecurringTransactionsDeleteCmd())
+	cmd.AddCommand(newRecurringTransactionsSyncDetectedCmd())
 	return cmd
 }
 
@@ -175,7 +176,7 @@ func newRecurringTransactionsCreateCmd() *cobra.Command {
 				output.Fail("validation_failed", err.Error(), nil)
 				return
 			}
-			dispatchWrite(o.Apply, "POST", "/api/v1/recurring_transactions", payload)
+			dispatchJournaledWrite(cmd, o.Apply, "POST", "/api/v1/recurring_transactions", payload)
 		},
 	}
 	cmd.Flags().StringVar(&o.Name, "name", "", "name")
@@ -208,7 +209,7 @@ func newRecurringTransactionsUpdateCmd() *cobra.Command {
 				output.Fail("validation_failed", err.Error(), nil)
 				return
 			}
-			dispatchWrite(o.Apply, "PATCH", fmt.Sprintf("/api/v1/recurring_transactions/%s", url.PathEscape(args[0])), payload)
+			dispatchJournaledWrite(cmd, o.Apply, "PATCH", fmt.Sprintf("/api/v1/recurring_transactions/%s", url.PathEscape(args[0])), payload)
 		},
 	}
 	cmd.Flags().StringVar(&o.Status, "status", "", "status")
@@ -225,7 +226,7 @@ func newRecurringTransactionsDeleteCmd() *cobra.Command {
 		Short: "Delete recurring transaction (default dry-run; use --apply to execute)",
 		Args:  cobra.ExactArgs(1),
 		Run: func(cmd *cobra.Command, args []string) {
-			dispatchWrite(apply, "DELETE", fmt.Sprintf("/api/v1/recurring_transactions/%s", url.PathEscape(args[0])), nil)
+			dispatchJournaledWrite(cmd, apply, "DELETE", fmt.Sprintf("/api/v1/recurring_transactions/%s", url.PathEscape(args[0])), nil)
 		},
 	}
 	cmd.Flags().BoolVar(&apply, "apply", false, "execute the delete (otherwise dry-run)")
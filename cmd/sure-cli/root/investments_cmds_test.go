@@ -33,6 +33,7 @@ func TestInvestmentCommandsRegistered(t *testing.T) {
 		{"security-prices", "show"},
 		{"trades", "create"},
 		{"recurring-transactions", "delete"},
+		{"recurring-transactions", "sync-detected"},
 	} {
 		if _, _, err := cmd.Find(args); err != nil {
 			t.Fatalf("expected command %v: %v", args, err)
@@ -0,0 +1,102 @@
+package root
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/spf13/cobra"
+
+	"github.com/we-promise/sure-cli/internal/api"
+	"github.com/we-promise/sure-cli/internal/config"
+	"github.com/we-promise/sure-cli/internal/journal"
+)
+
+// writeRecorder sends the writes of one applied command and collects them
+// into a journal operation. It is safe for concurrent sends.
+type writeRecorder struct {
+	mu sync.Mutex
+	op *journal.Operation
+}
+
+func newWriteRecorder(cmd *cobra.Command) *writeRecorder {
+	name := strings.TrimPrefix(cmd.CommandPath(), cmd.Root().Name()+" ")
+	return &writeRecorder{op: journal.NewOperation(name, config.APIURL(), time.Now())}
+}
+
+// send performs one write. Updates and deletes read the resource first so
+// the journal holds the state they replace; if that read fails the write is
+// not sent and the read's response or error is returned instead.
+func (w *writeRecorder) send(client *api.Client, method, path string, body any, out *any) (*resty.Response, error) {
+	var before map[string]any
+	if method != "POST" && journal.Undoable(method, path) {
+		r, err := client.Get(path, &before)
+		if err != nil {
+			return nil, fmt.Errorf("read prior state: %w", err)
+		}
+		if r.StatusCode() >= 400 {
+			return r, nil
+		}
+	}
+
+	var r *resty.Response
+	var err error
+	switch method {
+	case "POST":
+		r, err = client.Post(path, body, out)
+	case "PATCH":
+		r, err = client.Patch(path, body, out)
+	case "PUT":
+		r, err = client.Put(path, body, out)
+	case "DELETE":
+		r, err = client.Delete(path, out)
+	default:
+		return nil, fmt.Errorf("writeRecorder: unsupported HTTP method %s", method)
+	}
+	if err != nil || r.StatusCode() >= 400 || !journal.Journaled(method, path) {
+		return r, err
+	}
+
+	after, _ := (*out).(map[string]any)
+	w.mu.Lock()
+	w.op.Writes = append(w.op.Writes, journal.Write{Method: method, Path: path, Body: body, Status: r.StatusCode(), Before: before, After: after})
+	w.mu.Unlock()
+	return r, err
+}
+
+// save appends the operation to the journal and returns its id, or "" when
+// nothing was written. A journal that cannot be written is reported on
+// stderr: the writes already happened and the command still succeeds.
+func (w *writeRecorder) save() string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if len(w.op.Writes) == 0 {
+		return ""
+	}
+	path, err := config.JournalPath()
+	if err == nil {
+		err = journal.Append(path, *w.op)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "journal: %v (operation %s not recorded)\n", err, w.op.ID)
+		return ""
+	}
+	return w.op.ID
+}
+
+// dispatchJournaledWrite is dispatchWrite for resources `undo` can revert:
+// the applied write is recorded in the journal and the envelope meta
+// carries its operation id.
+func dispatchJournaledWrite(cmd *cobra.Command, apply bool, method, path string, body any) {
+	if !apply {
+		printDryRun(method, path, body)
+		return
+	}
+	rec := newWriteRecorder(cmd)
+	var res any
+	r, err := rec.send(api.New(), method, path, body, &res)
+	respondOperation(r, err, res, rec.save())
+}
@@ -1,20 +1,28 @@
 package root
 
 import (
+	"errors"
 	"fmt"
+	"net/url"
+	"strings"
 	"time"
 
 	"github.com/we-promise/sure-cli/internal/api"
+	"github.com/we-promise/sure-cli/internal/config"
+	"github.com/we-promise/sure-cli/internal/models"
 	"github.com/we-promise/sure-cli/internal/output"
 	"github.com/we-promise/sure-cli/internal/plan"
 	"github.com/spf13/cobra"
 )
 
 func newPlanCmd() *cobra.Command {
-	cmd := &cobra.Command{Use: "plan", Short: "Planning commands (budget/runway/forecast)"}
+	cmd := &cobra.Command{Use: "plan", Short: "Planning commands (budget/runway/forecast/fire/longevity/debt)"}
 	cmd.AddCommand(newPlanBudgetCmd())
 	cmd.AddCommand(newPlanRunwayCmd())
 	cmd.AddCommand(newPlanForecastCmd())
+	cmd.AddCommand(newPlanFireCmd())
+	cmd.AddCommand(newPlanLongevityCmd())
+	cmd.AddCommand(newPlanDebtCmd())
 	return cmd
 }
 
@@ -22,11 +30,31 @@ func newPlanForecastCmd() *cobra.Command {
 	var days int
 	var includeDaily bool
 	var months int
+	var balances bool
+	var accountIDs []string
+	var minBalance float64
+	var scenarioPath string
 
 	cmd := &cobra.Command{
 		Use:   "forecast",
-		Short: "Forecast spending for the next N days",
+		Short: "Forecast spending (or account balances) for the next N days",
+		Long: `Forecast spending for the next N days.
+
+Recurring charges come from Sure's recurring transactions (expected day of
+month and average amount) merged with subscriptions detected from history;
+detections Sure already tracks, or marks inactive, are not double counted.
+
+With --balances, each cash account is projected day by day from its current
+balance: Sure recurring transactions, detected recurring income and
+subscriptions, plus the account's average discretionary spend. The output
+gives the lowest point per account and warns when a balance is projected to
+go negative (or under --min-balance). Depository accounts are used unless
+--account-id is given.
+
+With --scenario, the forecast is run twice, as is and with the scenario's
+adjustments (see README), and both are printed with the deltas.`,
 		Run: func(cmd *cobra.Command, args []string) {
+			scenario := readScenario(scenarioPath)
 			client := api.New()
 
 			// Fetch historical data (use months for lookback)
@@ -40,22 +68,117 @@ func newPlanForecastCmd() *cobra.Command {
 				output.Fail("request_failed", err.Error(), nil)
 				return
 			}
+			// Balances move with transfers, so only the aggregate forecast
+			// leaves them out.
+			allTxs := txs
+			txs, excluded := withoutTransfers(client, txs)
+
+			// Sure's recurring transactions are the ground truth; inactive
+			// ones suppress matching detections.
+			recurring, err := api.FetchRecurringTransactions(client, nil)
+			if err != nil {
+				output.Fail("request_failed", err.Error(), nil)
+				return
+			}
+
+			if balances {
+				all, err := api.FetchAccounts(client)
+				if err != nil {
+					output.Fail("request_failed", err.Error(), nil)
+					return
+				}
+				accounts, err := forecastAccounts(all, accountIDs)
+				if errors.Is(err, errAccountNotFound) {
+					output.Fail("account_not_found", err.Error(), map[string]any{"account_ids": accountIDs})
+					return
+				}
+				if err != nil {
+					failValidation(err)
+				}
+				opts := plan.BalanceForecastOptions{
+					Days:          days,
+					AsOf:          end,
+					HistoryStart:  start,
+					MinBalance:    minBalance,
+					Subscriptions: subscriptionOptionsFromConfig(),
+				}
+				result := plan.ComputeBalanceForecast(accounts, allTxs, recurring, opts)
+				if scenario != nil {
+					opts.Scenario = resolveScenario(client, scenario, allTxs, recurring, start, end)
+					with := plan.ComputeBalanceForecast(accounts, allTxs, recurring, opts)
+					printScenarioComparison(plan.CompareBalanceForecast(opts.Scenario, result, with))
+					return
+				}
+				_ = output.Print(format, output.Envelope{Data: result, Meta: &output.Meta{Schema: "docs/schemas/v1/plan_forecast_balances.schema.json", Status: 200}})
+				return
+			}
 
-			result := plan.ComputeForecast(txs, days, includeDaily)
+			opts := plan.ForecastOptions{Subscriptions: subscriptionOptionsFromConfig(), Recurring: recurring}
+			result := plan.ComputeForecastWithOptions(txs, days, includeDaily, opts)
+			result.ExcludedTransfers = &excluded
+			if scenario != nil {
+				opts.Scenario = resolveScenario(client, scenario, txs, recurring, start, end)
+				with := plan.ComputeForecastWithOptions(txs, days, includeDaily, opts)
+				with.ExcludedTransfers = &excluded
+				printScenarioComparison(plan.CompareForecast(opts.Scenario, result, with))
+				return
+			}
 			_ = output.Print(format, output.Envelope{Data: result, Meta: &output.Meta{Schema: "docs/schemas/v1/plan_forecast.schema.json", Status: 200}})
 		},
 	}
 	cmd.Flags().IntVar(&days, "days", 30, "forecast period in days")
 	cmd.Flags().IntVar(&months, "months", 6, "historical lookback months")
 	cmd.Flags().BoolVar(&includeDaily, "daily", false, "include daily breakdown")
+	cmd.Flags().BoolVar(&balances, "balances", false, "project per-account daily balances (income, recurring and spend)")
+	cmd.Flags().StringSliceVar(&accountIDs, "account-id", nil, "account(s) to project with --balances (default: all depository accounts)")
+	cmd.Flags().Float64Var(&minBalance, "min-balance", 0, "warn when a projected balance drops below this amount")
+	addScenarioFlag(cmd, &scenarioPath)
 	return cmd
 }
 
+var errAccountNotFound = errors.New("account not found in accounts list")
+
+// forecastAccounts picks the accounts for a balance forecast: the requested
+// ids (which must be asset accounts), else every depository account.
+func forecastAccounts(all []models.Account, ids []string) ([]models.Account, error) {
+	var out []models.Account
+	if len(ids) == 0 {
+		for _, a := range all {
+			if a.Classification == "asset" && a.AccountType == "depository" {
+				out = append(out, a)
+			}
+		}
+		return out, nil
+	}
+	byID := map[string]models.Account{}
+	for _, a := range all {
+		byID[a.ID] = a
+	}
+	for _, id := range ids {
+		a, ok := byID[id]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", errAccountNotFound, id)
+		}
+		if a.Classification == "liability" {
+			return nil, fmt.Errorf("account %s is a liability; balance forecasts need asset accounts", id)
+		}
+		out = append(out, a)
+	}
+	return out, nil
+}
+
 func newPlanBudgetCmd() *cobra.Command {
 	var monthStr string
+	var byCategory bool
+	var budgetID string
 	cmd := &cobra.Command{
 		Use:   "budget",
 		Short: "Budget pacing for a month (client-side heuristic)",
+		Long: `Budget pacing for a month (client-side heuristic).
+
+With --by-category, the month's Sure budget categories are joined with actual
+spend, each category is projected to month end from its daily pace, and
+categories on track to overspend are flagged.`,
 		Run: func(cmd *cobra.Command, args []string) {
 			m := time.Now().UTC()
 			if monthStr != "" {
@@ -75,52 +198,108 @@ func newPlanBudgetCmd() *cobra.Command {
 				output.Fail("request_failed", err.Error(), nil)
 				return
 			}
+			txs, excluded := withoutTransfers(client, txs)
+			if byCategory {
+				q := url.Values{}
+				if budgetID != "" {
+					q.Set("budget_id", budgetID)
+				} else {
+					q.Set("start_date", start.Format("2006-01-02"))
+					q.Set("end_date", end.AddDate(0, 0, -1).Format("2006-01-02"))
+				}
+				budgets, err := api.FetchBudgetCategories(client, q)
+				if err != nil {
+					output.Fail("request_failed", err.Error(), nil)
+					return
+				}
+				if len(budgets) == 0 {
+					output.Fail("not_found", "no Sure budget categories for "+start.Format("2006-01")+"; create a budget in Sure first", nil)
+					return
+				}
+				cats, err := api.FetchCategories(client)
+				if err != nil {
+					output.Fail("request_failed", err.Error(), nil)
+					return
+				}
+				res := plan.ComputeCategoryBudgets(start, time.Now().UTC(), budgets, cats, txs)
+				res.ExcludedTransfers = &excluded
+				_ = output.Print(format, output.Envelope{Data: res, Meta: &output.Meta{Schema: "docs/schemas/v1/plan_budget_categories.schema.json", Status: 200}})
+				return
+			}
 			res, err := plan.ComputeMonthlyBudget(m, txs)
 			if err != nil {
 				output.Fail("compute_failed", err.Error(), nil)
 				return
 			}
+			res.ExcludedTransfers = &excluded
 			_ = output.Print(format, output.Envelope{Data: res, Meta: &output.Meta{Schema: "docs/schemas/v1/plan_budget.schema.json", Status: 200}})
 		},
 	}
 	cmd.Flags().StringVar(&monthStr, "month", "", "month YYYY-MM")
+	cmd.Flags().BoolVar(&byCategory, "by-category", false, "track each Sure budget category against actual spend")
+	cmd.Flags().StringVar(&budgetID, "budget-id", "", "budget to track with --by-category (default: the budget covering --month)")
 	return cmd
 }
 
 func newPlanRunwayCmd() *cobra.Command {
-	var accountID string
+	var accountIDs []string
 	var windowDays int
+	var tierFlags map[string]string
+	var scenarioPath, currency string
 	cmd := &cobra.Command{
 		Use:   "runway",
-		Short: "Estimate runway months based on recent spending",
+		Short: "Estimate runway months per liquidity tier based on recent spending",
+		Long: `Estimate runway months across accounts, per liquidity tier.
+
+Balances are split into tiers: cash (depository accounts, using the cash
+balance where Sure reports one), near_cash (the cash balance of investment
+and crypto accounts) and illiquid (the rest). Liabilities are left out.
+Each tier's runway spends it together with the more liquid tiers; the
+top-level balance and runway_months are the cash tier's. Balances and
+expenses are not converted: those in another currency than --currency
+(default: the first counted account's) are left out and listed under
+warnings.
+
+Override tiers by account id, subtype or account type in the config
+(plan.liquidity_tiers) or with --tier, e.g. --tier acc_123=near_cash
+--tier property=exclude.
+
+The burn is the expenses of the last --days per 30 days. Transfers between
+own accounts (linked by Sure or marked as transfers, e.g. card payments)
+are not spending and are reported under excluded_transfers. Unlinked pairs
+up to heuristics.transfers.window_days apart still count; link them with
+'transfers suggest --apply'.
+
+With --scenario, the balances are also run down day by day with the
+scenario's adjustments (see README), and both estimates are printed with
+the deltas.`,
+		Args: cobra.NoArgs,
 		Run: func(cmd *cobra.Command, args []string) {
-			if accountID == "" {
-				output.Fail("missing_account", "--account-id is required", nil)
-				return
+			if windowDays <= 0 {
+				failValidation(fmt.Errorf("--days must be positive"))
+			}
+			tiers := config.GetLiquidityTiers()
+			for k, v := range tierFlags {
+				tiers[strings.ToLower(k)] = v
 			}
+			failValidation(plan.ValidateTiers(tiers))
+			scenario := readScenario(scenarioPath)
 			client := api.New()
 
-			// Find account balance by listing accounts (Sure API quirks: show may 404)
-			var res map[string]any
-			r, err := client.Get("/api/v1/accounts", &res)
-			checkResponse(r, err)
-			bal := ""
-			if arr, ok := res["accounts"].([]any); ok {
-				for _, it := range arr {
-					m, _ := it.(map[string]any)
-					if fmt.Sprint(m["id"]) == accountID {
-						bal = fmt.Sprint(m["balance"])
-						break
-					}
-				}
-			}
-			if bal == "" {
-				output.Fail("account_not_found", "account not found in accounts list", map[string]any{"account_id": accountID})
+			accounts, err := api.FetchAccounts(client)
+			if err != nil {
+				output.Fail("request_failed", err.Error(), nil)
 				return
 			}
-
-			if windowDays <= 0 {
-				windowDays = 90
+			known := map[string]bool{}
+			for _, a := range accounts {
+				known[a.ID] = true
+			}
+			for _, id := range accountIDs {
+				if !known[id] {
+					output.Fail("account_not_found", "account not found in accounts list", map[string]any{"account_id": id})
+					return
+				}
 			}
 
 			end := time.Now().UTC()
@@ -130,16 +309,26 @@ func newPlanRunwayCmd() *cobra.Command {
 				output.Fail("request_failed", err.Error(), nil)
 				return
 			}
+			txs, excluded := withoutTransfers(client, txs)
 
-			out, err := plan.ComputeRunway(bal, txs, windowDays)
+			out, err := plan.ComputeLiquidRunway(accounts, txs, plan.RunwayOptions{AsOf: end, WindowDays: windowDays, Tiers: tiers, AccountIDs: accountIDs, Currency: currency})
 			if err != nil {
 				output.Fail("compute_failed", err.Error(), nil)
 				return
 			}
+			out.ExcludedTransfers = &excluded
+			if scenario != nil {
+				effects := resolveScenario(client, scenario, txs, nil, start, end)
+				printScenarioComparison(plan.CompareRunway(effects, out, plan.ScenarioRunway(out, effects, end)))
+				return
+			}
 			_ = output.Print(format, output.Envelope{Data: out, Meta: &output.Meta{Schema: "docs/schemas/v1/plan_runway.schema.json", Status: 200}})
 		},
 	}
-	cmd.Flags().StringVar(&accountID, "account-id", "", "cash account id")
+	cmd.Flags().StringSliceVar(&accountIDs, "account-id", nil, "count only these accounts' balances (default: all accounts)")
 	cmd.Flags().IntVar(&windowDays, "days", 90, "lookback days")
+	cmd.Flags().StringToStringVar(&tierFlags, "tier", nil, "tier override, <account id|subtype|type>=cash|near_cash|illiquid|exclude (repeatable)")
+	cmd.Flags().StringVar(&currency, "currency", "", "runway currency; other currencies are left out (default: the first counted account's)")
+	addScenarioFlag(cmd, &scenarioPath)
 	return cmd
 }
@@ -0,0 +1,125 @@
+package root
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/we-promise/sure-cli/internal/config"
+	"github.com/we-promise/sure-cli/internal/models"
+)
+
+func TestForecastAccounts(t *testing.T) {
+	all := []models.Account{
+		{ID: "chk", Classification: "asset", AccountType: "depository"},
+		{ID: "brk", Classification: "asset", AccountType: "investment"},
+		{ID: "cc", Classification: "liability", AccountType: "credit_card"},
+	}
+	got, err := forecastAccounts(all, nil)
+	if err != nil || len(got) != 1 || got[0].ID != "chk" {
+		t.Fatalf("default selection = %+v, %v", got, err)
+	}
+	got, err = forecastAccounts(all, []string{"brk"})
+	if err != nil || len(got) != 1 || got[0].ID != "brk" {
+		t.Fatalf("explicit selection = %+v, %v", got, err)
+	}
+	if _, err := forecastAccounts(all, []string{"cc"}); err == nil || errors.Is(err, errAccountNotFound) {
+		t.Fatalf("liability: want validation error, got %v", err)
+	}
+	if _, err := forecastAccounts(all, []string{"nope"}); !errors.Is(err, errAccountNotFound) {
+		t.Fatalf("unknown id: want errAccountNotFound, got %v", err)
+	}
+}
+
+func TestParsePercent(t *testing.T) {
+	for in, want := range map[string]float64{"4%": 0.04, "3.5": 0.035, " -1% ": -0.01} {
+		got, err := parsePercent(in)
+		if err != nil || got != want {
+			t.Fatalf("parsePercent(%q) = %v, %v; want %v", in, got, err, want)
+		}
+	}
+	if _, err := parsePercent("four"); err == nil {
+		t.Fatal("want error for non-numeric rate")
+	}
+}
+
+func TestLongevityAges(t *testing.T) {
+	for _, c := range []struct {
+		age, retire, target int
+		ok                  bool
+	}{
+		{60, 0, 95, true},
+		{40, 65, 95, true},
+		{40, 30, 95, true}, // retiring in the past means now
+		{0, 0, 95, false},
+		{60, 0, 60, false},
+		{40, 95, 95, false},
+		{40, 100, 95, false},
+	} {
+		if err := longevityAges(c.age, c.retire, c.target); (err == nil) != c.ok {
+			t.Errorf("longevityAges(%d, %d, %d) = %v, want ok=%v", c.age, c.retire, c.target, err, c.ok)
+		}
+	}
+}
+
+func TestDebtInputs(t *testing.T) {
+	accounts := []models.Account{
+		{ID: "chk", Name: "Checking", Classification: "asset", BalanceText: "€100.00"},
+		{ID: "cc", Name: "Card", Classification: "liability", BalanceText: "€1,200.00"},
+		{ID: "loan", Name: "Loan", Classification: "liability", BalanceText: "€5,000.00"},
+		{ID: "paid", Name: "Paid off", Classification: "liability", BalanceText: "€0.00"},
+	}
+	cfg := []config.DebtConfig{{AccountID: "cc", APR: 19.9, MinPayment: 40}, {AccountID: "loan", APR: 5}}
+	debts, warnings, err := debtInputs(accounts, cfg, map[string]string{"loan": "6.5"}, nil, nil)
+	if err != nil || len(debts) != 2 {
+		t.Fatalf("debts = %+v, %v", debts, err)
+	}
+	if d := debts[0]; d.AccountID != "cc" || d.APR != 0.199 || d.MinPayment != 40 || d.Source != "config" {
+		t.Fatalf("configured debt = %+v", d)
+	}
+	// Flag APR wins; the missing minimum is assumed at 2% of the balance.
+	if d := debts[1]; d.APR != 0.065 || d.MinPayment != 100 || d.Source != "assumed" || len(warnings) != 1 {
+		t.Fatalf("loan = %+v, warnings %v", d, warnings)
+	}
+
+	if _, _, err := debtInputs(accounts, nil, nil, nil, []string{"chk"}); err == nil || errors.Is(err, errAccountNotFound) {
+		t.Fatalf("asset account: want validation error, got %v", err)
+	}
+	if _, _, err := debtInputs(accounts, nil, map[string]string{"nope": "5"}, nil, nil); !errors.Is(err, errAccountNotFound) {
+		t.Fatalf("unknown --apr account: want errAccountNotFound, got %v", err)
+	}
+	if _, _, err := debtInputs(accounts, nil, map[string]string{"cc": "high"}, nil, nil); err == nil {
+		t.Fatal("want error for a non-numeric APR")
+	}
+}
+
+func TestNewGoal(t *testing.T) {
+	now := time.Date(2026, 6, 30, 0, 0, 0, 0, time.UTC)
+	accounts := []models.Account{{ID: "sav", Classification: "asset"}, {ID: "cc", Classification: "liability"}}
+	g, err := newGoal(nil, accounts, " Car (new) ", 15000, 0, "2027-06", []string{"sav"}, now)
+	if err != nil || g.ID != "car-new" || g.TargetDate != "2027-06-30" || g.CreatedAt != "2026-06-30" {
+		t.Fatalf("goal = %+v, %v", g, err)
+	}
+	existing := []config.Goal{g}
+	cases := map[string]func() error{
+		"duplicate": func() error {
+			_, err := newGoal(existing, accounts, "car new", 1, 0, "", []string{"sav"}, now)
+			return err
+		},
+		"both targets": func() error { _, err := newGoal(nil, accounts, "x", 1, 6, "", []string{"sav"}, now); return err },
+		"no target":    func() error { _, err := newGoal(nil, accounts, "x", 0, 0, "", []string{"sav"}, now); return err },
+		"past date": func() error {
+			_, err := newGoal(nil, accounts, "x", 1, 0, "2026-01-01", []string{"sav"}, now)
+			return err
+		},
+		"liability": func() error { _, err := newGoal(nil, accounts, "x", 1, 0, "", []string{"cc"}, now); return err },
+	}
+	for name, f := range cases {
+		if err := f(); err == nil || errors.Is(err, errAccountNotFound) {
+			t.Fatalf("%s: want validation error, got %v", name, err)
+		}
+	}
+	if _, err := newGoal(nil, accounts, "x", 1, 0, "", []string{"nope"}, now); !errors.Is(err, errAccountNotFound) {
+		t.Fatalf("unknown account: want errAccountNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,181 @@
+package root
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/we-promise/sure-cli/internal/api"
+	"github.com/we-promise/sure-cli/internal/config"
+	"github.com/we-promise/sure-cli/internal/insights"
+	"github.com/we-promise/sure-cli/internal/models"
+	"github.com/we-promise/sure-cli/internal/output"
+	"github.com/we-promise/sure-cli/internal/plan"
+)
+
+func newPlanDebtCmd() *cobra.Command {
+	var extra float64
+	var strategy string
+	var order, accountIDs []string
+	var aprs, minPayments map[string]string
+	var maxMonths int
+
+	cmd := &cobra.Command{
+		Use:   "debt",
+		Short: "Debt payoff plan: avalanche vs snowball (vs custom order)",
+		Long: `Simulate paying off liability accounts month by month and compare
+strategies: avalanche (highest APR first), snowball (smallest balance first)
+and custom (--order). Each month every debt gets its minimum; --extra and
+the minimums of debts already paid off go to the next debt in line.
+
+Balances come from Sure. APR and minimum payment are not tracked by Sure;
+set them per account in config or with flags (flags win):
+
+  plan:
+    debts:
+      - account_id: acc_card
+        apr: 19.9          # percent
+        min_payment: 50
+
+Debts without an APR are assumed interest-free, and without a minimum
+payment are assumed to pay 2% of the balance (see warnings).`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			var strategies []string
+			switch strategy {
+			case "", "all":
+			case "avalanche", "snowball", "custom":
+				strategies = []string{strategy}
+			default:
+				failValidation(fmt.Errorf("strategy must be avalanche, snowball, custom or all"))
+			}
+			if strategy == "custom" && len(order) == 0 {
+				failValidation(fmt.Errorf("--strategy custom needs --order"))
+			}
+			if extra < 0 {
+				failValidation(fmt.Errorf("extra must not be negative"))
+			}
+
+			client := api.New()
+			accounts, err := api.FetchAccounts(client)
+			if err != nil {
+				output.Fail("request_failed", err.Error(), nil)
+				return
+			}
+			debts, warnings, err := debtInputs(accounts, config.GetDebts(), aprs, minPayments, accountIDs)
+			if errors.Is(err, errAccountNotFound) {
+				output.Fail("account_not_found", err.Error(), map[string]any{"account_ids": accountIDs})
+				return
+			}
+			failValidation(err)
+
+			result := plan.PlanDebtPayoff(debts, plan.DebtOptions{
+				Extra:      extra,
+				Strategies: strategies,
+				Order:      order,
+				MaxMonths:  maxMonths,
+				AsOf:       time.Now().UTC(),
+			})
+			result.Warnings = append(warnings, result.Warnings...)
+			for _, a := range accounts {
+				if a.Classification == "liability" && result.Currency == "" {
+					result.Currency = a.Currency
+				}
+			}
+			_ = output.Print(format, output.Envelope{Data: result, Meta: &output.Meta{Schema: "docs/schemas/v1/plan_debt.schema.json", Status: 200}})
+		},
+	}
+	cmd.Flags().Float64Var(&extra, "extra", 0, "monthly amount paid on top of the minimums")
+	cmd.Flags().StringVar(&strategy, "strategy", "all", "avalanche|snowball|custom|all")
+	cmd.Flags().StringSliceVar(&order, "order", nil, "custom payoff order by account id (enables the custom strategy)")
+	cmd.Flags().StringSliceVar(&accountIDs, "account-id", nil, "liability accounts to include (default: all with a balance)")
+	cmd.Flags().StringToStringVar(&aprs, "apr", nil, "APR percent per account (acc_id=19.9); overrides config")
+	cmd.Flags().StringToStringVar(&minPayments, "min-payment", nil, "minimum monthly payment per account (acc_id=50); overrides config")
+	cmd.Flags().IntVar(&maxMonths, "max-months", 600, "simulation horizon in months")
+	return cmd
+}
+
+// debtInputs builds the debts to plan from Sure's liability accounts and
+// the configured/flagged terms. Flags override config; missing terms are
+// assumed (0% APR, 2% of balance minimum) and reported as warnings.
+func debtInputs(accounts []models.Account, cfg []config.DebtConfig, aprs, minPayments map[string]string, ids []string) ([]plan.Debt, []string, error) {
+	byID := map[string]models.Account{}
+	for _, a := range accounts {
+		byID[a.ID] = a
+	}
+	for id := range aprs {
+		if _, ok := byID[id]; !ok {
+			return nil, nil, fmt.Errorf("%w: %s", errAccountNotFound, id)
+		}
+	}
+	for id := range minPayments {
+		if _, ok := byID[id]; !ok {
+			return nil, nil, fmt.Errorf("%w: %s", errAccountNotFound, id)
+		}
+	}
+
+	var selected []models.Account
+	if len(ids) == 0 {
+		for _, a := range accounts {
+			if a.Classification == "liability" {
+				selected = append(selected, a)
+			}
+		}
+	} else {
+		for _, id := range ids {
+			a, ok := byID[id]
+			if !ok {
+				return nil, nil, fmt.Errorf("%w: %s", errAccountNotFound, id)
+			}
+			if a.Classification != "liability" {
+				return nil, nil, fmt.Errorf("account %s is not a liability", id)
+			}
+			selected = append(selected, a)
+		}
+	}
+
+	terms := map[string]config.DebtConfig{}
+	for _, c := range cfg {
+		terms[c.AccountID] = c
+	}
+	debts := []plan.Debt{}
+	warnings := []string{}
+	for _, a := range selected {
+		bal, err := insights.ParseAmount(a.BalanceText)
+		if err != nil || math.Abs(bal) < 0.005 {
+			continue
+		}
+		d := plan.Debt{AccountID: a.ID, Account: a.Name, Balance: math.Round(math.Abs(bal)*100) / 100, Source: "config"}
+		t, hasCfg := terms[a.ID]
+		apr, minPay := t.APR, t.MinPayment
+		if v, ok := aprs[a.ID]; ok {
+			if apr, err = strconv.ParseFloat(v, 64); err != nil || apr < 0 {
+				return nil, nil, fmt.Errorf("invalid --apr for %s: %q", a.ID, v)
+			}
+			d.Source = "flag"
+		}
+		if v, ok := minPayments[a.ID]; ok {
+			if minPay, err = strconv.ParseFloat(v, 64); err != nil || minPay < 0 {
+				return nil, nil, fmt.Errorf("invalid --min-payment for %s: %q", a.ID, v)
+			}
+			d.Source = "flag"
+		}
+		_, aprSet := aprs[a.ID]
+		if !hasCfg && !aprSet {
+			d.Source = "assumed"
+			warnings = append(warnings, fmt.Sprintf("%s: no APR configured; assumed 0%%", a.Name))
+		}
+		if minPay <= 0 {
+			minPay = math.Round(d.Balance*2) / 100
+			d.Source = "assumed"
+			warnings = append(warnings, fmt.Sprintf("%s: no minimum payment configured; assumed 2%% of the balance (%.2f)", a.Name, minPay))
+		}
+		d.APR, d.MinPayment = math.Round(apr*100)/10000, minPay
+		debts = append(debts, d)
+	}
+	return debts, warnings, nil
+}
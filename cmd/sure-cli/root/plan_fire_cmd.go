@@ -0,0 +1,125 @@
+package root
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/we-promise/sure-cli/internal/api"
+	"github.com/we-promise/sure-cli/internal/models"
+	"github.com/we-promise/sure-cli/internal/output"
+	"github.com/we-promise/sure-cli/internal/plan"
+)
+
+func newPlanFireCmd() *cobra.Command {
+	var spend float64
+	var swr, realReturn string
+	var contribution float64
+	var months, years, age int
+	var includeCash bool
+	var scenarioPath string
+
+	cmd := &cobra.Command{
+		Use:   "fire",
+		Short: "Financial-independence number and years to FI from Sure data",
+		Long: `Estimate the financial-independence (FI) number and when it is reached.
+
+Annual spend is annualized from the last --months of transactions unless
+--spend is given; the FI number is that spend divided by the safe withdrawal
+rate. The investable portfolio is the balance of investment and crypto
+accounts (with their latest holdings), plus depository cash with
+--include-cash. Each year it grows by the real return and the contribution
+(income from history minus the annual spend, --spend included, unless
+--contribution is given). Balances are not converted: accounts in another
+currency than the family's are left out and listed under warnings.
+
+Rates accept "4%" or "4". With --scenario, the estimate is also run with the
+scenario's adjustments (see README) and both are printed with the deltas.`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			rate, err := parsePercent(swr)
+			failValidation(err)
+			ret, err := parsePercent(realReturn)
+			failValidation(err)
+			if rate <= 0 {
+				failValidation(fmt.Errorf("swr must be positive"))
+			}
+			if months <= 0 {
+				failValidation(fmt.Errorf("months must be positive"))
+			}
+			scenario := readScenario(scenarioPath)
+
+			client := api.New()
+			end := time.Now().UTC()
+			start := end.AddDate(0, -months, 0)
+			txs, err := api.FetchTransactionsWindow(client, start, end, 500)
+			if err != nil {
+				output.Fail("request_failed", err.Error(), nil)
+				return
+			}
+			txs, excluded := withoutTransfers(client, txs)
+			accounts, err := api.FetchAccounts(client)
+			if err != nil {
+				output.Fail("request_failed", err.Error(), nil)
+				return
+			}
+			holdings, err := api.FetchHoldings(client, nil)
+			if err != nil {
+				output.Fail("request_failed", err.Error(), nil)
+				return
+			}
+			// The balance sheet only adds net worth for context; older Sure
+			// versions without it still get a result.
+			var sheet *models.BalanceSheet
+			if bs, err := api.FetchBalanceSheet(client); err == nil {
+				sheet = &bs
+			}
+
+			opts := plan.FireOptions{
+				AnnualSpend:    spend,
+				WithdrawalRate: rate,
+				RealReturn:     ret,
+				Years:          years,
+				Age:            age,
+				IncludeCash:    includeCash,
+				AsOf:           end,
+				HistoryStart:   start,
+			}
+			if cmd.Flags().Changed("contribution") {
+				opts.Contribution = &contribution
+			}
+			result := plan.ComputeFire(accounts, holdings, sheet, txs, opts)
+			result.ExcludedTransfers = &excluded
+			if scenario != nil {
+				opts.Scenario = resolveScenario(client, scenario, txs, nil, start, end)
+				with := plan.ComputeFire(accounts, holdings, sheet, txs, opts)
+				with.ExcludedTransfers = &excluded
+				printScenarioComparison(plan.CompareFire(opts.Scenario, result, with))
+				return
+			}
+			_ = output.Print(format, output.Envelope{Data: result, Meta: &output.Meta{Schema: "docs/schemas/v1/plan_fire.schema.json", Status: 200}})
+		},
+	}
+	cmd.Flags().Float64Var(&spend, "spend", 0, "annual spend override (default: annualized from history)")
+	cmd.Flags().StringVar(&swr, "swr", "4%", "safe withdrawal rate")
+	cmd.Flags().StringVar(&realReturn, "return", "5%", "expected real (after-inflation) yearly return")
+	cmd.Flags().Float64Var(&contribution, "contribution", 0, "yearly amount invested (default: income from history minus the annual spend)")
+	cmd.Flags().IntVar(&months, "months", 12, "history months used to annualize spend and income")
+	cmd.Flags().IntVar(&years, "years", 30, "projection years (extended to the FI year)")
+	cmd.Flags().IntVar(&age, "age", 0, "current age (adds ages to the projection)")
+	cmd.Flags().BoolVar(&includeCash, "include-cash", false, "count depository balances as investable")
+	addScenarioFlag(cmd, &scenarioPath)
+	return cmd
+}
+
+// parsePercent reads "4%" or "4" as 0.04.
+func parsePercent(s string) (float64, error) {
+	v, err := strconv.ParseFloat(strings.TrimSuffix(strings.TrimSpace(s), "%"), 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid percentage %q", s)
+	}
+	return v / 100, nil
+}
@@ -0,0 +1,153 @@
+package root
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/we-promise/sure-cli/internal/api"
+	"github.com/we-promise/sure-cli/internal/output"
+	"github.com/we-promise/sure-cli/internal/plan"
+)
+
+func newPlanLongevityCmd() *cobra.Command {
+	var age, targetAge, retireAge, months, paths int
+	var portfolio, spend, contribution float64
+	var expReturn, volatility, inflation, inflationVol, targetSuccess string
+	var includeCash bool
+	var seed int64
+
+	cmd := &cobra.Command{
+		Use:   "longevity",
+		Short: "Monte Carlo retirement simulation: will the money last to --target-age?",
+		Long: `Simulate thousands of market paths from --age to --target-age and report
+the probability of not running out of money, percentile wealth bands per
+year (in today's money) and the annual spend that reaches --target-success.
+
+The starting portfolio is the investable balance from Sure (investment and
+crypto accounts, plus depository cash with --include-cash) unless
+--portfolio is given; balances are not converted, so accounts in another
+currency than the first counted one are left out and listed under
+warnings. Spend is annualized from the last --months of
+transactions unless --spend is given. Before --retire-age the portfolio
+receives --contribution each year (default: income from history minus
+the annual spend); from then on spend is withdrawn, growing with
+inflation.
+
+Returns and inflation are nominal; rates accept "7%" or "7". The same
+--seed and options give identical output.`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			failValidation(longevityAges(age, retireAge, targetAge))
+			if paths <= 0 || paths > 100000 {
+				failValidation(fmt.Errorf("paths must be between 1 and 100000"))
+			}
+			if months <= 0 {
+				failValidation(fmt.Errorf("months must be positive"))
+			}
+			rates := map[string]string{"return": expReturn, "volatility": volatility, "inflation": inflation, "inflation-volatility": inflationVol, "target-success": targetSuccess}
+			parsed := map[string]float64{}
+			for name, v := range rates {
+				r, err := parsePercent(v)
+				failValidation(err)
+				parsed[name] = r
+			}
+			if parsed["volatility"] < 0 || parsed["inflation-volatility"] < 0 {
+				failValidation(fmt.Errorf("volatility must not be negative"))
+			}
+			if parsed["target-success"] <= 0 || parsed["target-success"] > 1 {
+				failValidation(fmt.Errorf("target-success must be between 0%% and 100%%"))
+			}
+
+			client := api.New()
+			end := time.Now().UTC()
+			start := end.AddDate(0, -months, 0)
+			txs, err := api.FetchTransactionsWindow(client, start, end, 500)
+			if err != nil {
+				output.Fail("request_failed", err.Error(), nil)
+				return
+			}
+			txs, excluded := withoutTransfers(client, txs)
+			histSpend, histIncome := plan.AnnualizedFlows(txs, start, end)
+
+			var currency string
+			var warnings []string
+			if !cmd.Flags().Changed("portfolio") {
+				accounts, err := api.FetchAccounts(client)
+				if err != nil {
+					output.Fail("request_failed", err.Error(), nil)
+					return
+				}
+				holdings, err := api.FetchHoldings(client, nil)
+				if err != nil {
+					output.Fail("request_failed", err.Error(), nil)
+					return
+				}
+				_, portfolio, currency, warnings = plan.InvestablePortfolio(accounts, holdings, includeCash, "")
+			}
+
+			spendSource := "override"
+			if spend <= 0 {
+				spend, spendSource = histSpend, "history"
+			}
+			if !cmd.Flags().Changed("contribution") {
+				contribution = math.Max(0, histIncome-spend)
+			}
+
+			result := plan.SimulateLongevity(plan.LongevityOptions{
+				Age:                 age,
+				TargetAge:           targetAge,
+				RetireAge:           retireAge,
+				Portfolio:           portfolio,
+				AnnualSpend:         spend,
+				AnnualContribution:  contribution,
+				ExpectedReturn:      parsed["return"],
+				Volatility:          parsed["volatility"],
+				Inflation:           parsed["inflation"],
+				InflationVolatility: parsed["inflation-volatility"],
+				TargetSuccess:       parsed["target-success"],
+				Paths:               paths,
+				Seed:                seed,
+				AsOf:                end,
+			})
+			result.Currency, result.SpendSource, result.Warnings = currency, spendSource, warnings
+			if spendSource == "history" || !cmd.Flags().Changed("contribution") {
+				result.ExcludedTransfers = &excluded
+			}
+			_ = output.Print(format, output.Envelope{Data: result, Meta: &output.Meta{Schema: "docs/schemas/v1/plan_longevity.schema.json", Status: 200}})
+		},
+	}
+	cmd.Flags().IntVar(&age, "age", 0, "current age (required)")
+	cmd.Flags().IntVar(&targetAge, "target-age", 95, "age the money must last to")
+	cmd.Flags().IntVar(&retireAge, "retire-age", 0, "age withdrawals start (default: now)")
+	cmd.Flags().Float64Var(&portfolio, "portfolio", 0, "starting portfolio override (default: investable balance from Sure)")
+	cmd.Flags().BoolVar(&includeCash, "include-cash", false, "count depository balances as investable")
+	cmd.Flags().Float64Var(&spend, "spend", 0, "annual spend in today's money (default: annualized from history)")
+	cmd.Flags().Float64Var(&contribution, "contribution", 0, "yearly contribution before --retire-age (default: income from history minus the annual spend)")
+	cmd.Flags().IntVar(&months, "months", 12, "history months used to annualize spend and income")
+	cmd.Flags().StringVar(&expReturn, "return", "7%", "expected nominal yearly return")
+	cmd.Flags().StringVar(&volatility, "volatility", "15%", "yearly return standard deviation")
+	cmd.Flags().StringVar(&inflation, "inflation", "2.5%", "expected yearly inflation")
+	cmd.Flags().StringVar(&inflationVol, "inflation-volatility", "1%", "yearly inflation standard deviation")
+	cmd.Flags().StringVar(&targetSuccess, "target-success", "90%", "success rate the sustainable spend is solved for")
+	cmd.Flags().IntVar(&paths, "paths", 5000, "number of simulated paths")
+	cmd.Flags().Int64Var(&seed, "seed", 42, "RNG seed (same seed + options = identical output)")
+	return cmd
+}
+
+// longevityAges checks that --age, --retire-age (when set) and --target-age
+// run in order.
+func longevityAges(age, retireAge, targetAge int) error {
+	if age <= 0 {
+		return fmt.Errorf("--age is required")
+	}
+	if targetAge <= age {
+		return fmt.Errorf("target-age must be greater than age")
+	}
+	if retireAge != 0 && retireAge >= targetAge {
+		return fmt.Errorf("retire-age must be less than target-age")
+	}
+	return nil
+}
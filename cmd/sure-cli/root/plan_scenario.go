@@ -0,0 +1,59 @@
+package root
+
+import (
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/we-promise/sure-cli/internal/api"
+	"github.com/we-promise/sure-cli/internal/insights"
+	"github.com/we-promise/sure-cli/internal/models"
+	"github.com/we-promise/sure-cli/internal/output"
+	"github.com/we-promise/sure-cli/internal/plan"
+)
+
+const scenarioFlagUsage = "what-if scenario file (YAML); prints baseline and scenario side by side with deltas"
+
+func addScenarioFlag(cmd *cobra.Command, path *string) {
+	cmd.Flags().StringVar(path, "scenario", "", scenarioFlagUsage)
+}
+
+// readScenario loads --scenario before any request is made; nil when the
+// flag is unset. An unreadable or invalid file fails the command.
+func readScenario(path string) *plan.Scenario {
+	if path == "" {
+		return nil
+	}
+	s, err := plan.LoadScenario(path)
+	if err != nil {
+		output.Fail("scenario_invalid", err.Error(), map[string]any{"path": path})
+		return nil
+	}
+	return &s
+}
+
+// resolveScenario matches the scenario against the baseline: recurring
+// items from Sure (fetched unless given) and detection over txs, and
+// category spend over [start, asOf].
+func resolveScenario(client *api.Client, s *plan.Scenario, txs []models.Transaction, recurring []models.RecurringTransaction, start, asOf time.Time) *plan.ScenarioEffects {
+	if recurring == nil {
+		var err error
+		recurring, err = api.FetchRecurringTransactions(client, nil)
+		if err != nil {
+			output.Fail("request_failed", err.Error(), nil)
+			return nil
+		}
+	}
+	sub := subscriptionOptionsFromConfig()
+	return s.Resolve(plan.ScenarioBase{
+		AsOf:         asOf,
+		Items:        plan.BaselineRecurring(txs, recurring, sub, insights.CashflowOptions{}),
+		Transactions: txs,
+		HistoryStart: start,
+		Normalizer:   sub.Normalizer,
+	})
+}
+
+func printScenarioComparison(cmp plan.ScenarioComparison) {
+	_ = output.Print(format, output.Envelope{Data: cmp, Meta: &output.Meta{Schema: "docs/schemas/v1/plan_scenario.schema.json", Status: 200}})
+}
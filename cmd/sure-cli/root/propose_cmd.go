@@ -22,6 +22,8 @@ func newProposeRulesCmd() *cobra.Command {
 	var months int
 	var apply bool
 	var minConfidence float64
+	var minConsistency float64
+	var minOccurrences int
 
 	cmd := &cobra.Command{
 		Use:   "rules",
@@ -40,7 +42,14 @@ func newProposeRulesCmd() *cobra.Command {
 				return
 			}
 
-			result := rules.ProposeRules(txs)
+			opts := ruleOptionsFromConfig()
+			if cmd.Flags().Changed("min-consistency") {
+				opts.MinConsistency = minConsistency
+			}
+			if cmd.Flags().Changed("min-occurrences") {
+				opts.MinOccurrences = minOccurrences
+			}
+			result := rules.ProposeRulesWithOptions(txs, opts)
 
 			if !apply {
 				// Just show proposals
@@ -53,6 +62,7 @@ func newProposeRulesCmd() *cobra.Command {
 				minConfidence = 0.8 // default safety threshold
 			}
 
+			rec := newWriteRecorder(cmd)
 			var applied []map[string]any
 			var skipped []map[string]any
 			var errors []map[string]any
@@ -84,7 +94,7 @@ func newProposeRulesCmd() *cobra.Command {
 						},
 					}
 					var res any
-					r, err := client.Put(path, payload, &res)
+					r, err := rec.send(client, "PUT", path, payload, &res)
 					if err != nil {
 						errors = append(errors, map[string]any{
 							"tx_id":   txID,
@@ -117,11 +127,13 @@ func newProposeRulesCmd() *cobra.Command {
 				"applied":       applied,
 				"skipped":       skipped,
 				"errors":        errors,
-			}, Meta: &output.Meta{Status: 200}})
+			}, Meta: &output.Meta{Status: 200, OperationID: rec.save()}})
 		},
 	}
 	cmd.Flags().IntVar(&months, "months", 3, "lookback months")
 	cmd.Flags().BoolVar(&apply, "apply", false, "execute the proposed rules (otherwise dry-run)")
 	cmd.Flags().Float64Var(&minConfidence, "min-confidence", 0.8, "minimum confidence to apply (with --apply)")
+	cmd.Flags().Float64Var(&minConsistency, "min-consistency", 0.7, "share of a name's transactions in one category before proposing (overrides heuristics.rules.min_consistency)")
+	cmd.Flags().IntVar(&minOccurrences, "min-occurrences", 2, "minimum transactions per name (overrides heuristics.rules.min_occurrences)")
 	return cmd
 }
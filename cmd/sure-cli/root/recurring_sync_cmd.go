@@ -0,0 +1,174 @@
+package root
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/we-promise/sure-cli/internal/api"
+	"github.com/we-promise/sure-cli/internal/insights"
+	"github.com/we-promise/sure-cli/internal/models"
+	"github.com/we-promise/sure-cli/internal/output"
+	"github.com/we-promise/sure-cli/internal/plan"
+)
+
+func newRecurringTransactionsSyncDetectedCmd() *cobra.Command {
+	var months int
+	var minConfidence float64
+	var apply bool
+
+	cmd := &cobra.Command{
+		Use:   "sync-detected",
+		Short: "Create Sure recurring transactions from detected subscriptions (default dry-run; use --apply to execute)",
+		Long: `Run the 'insights subscriptions' detector and propose a Sure recurring
+transaction for every active monthly subscription Sure does not track yet
+(matched by merchant, whatever the Sure entry's status).
+
+Without --apply this only reports the requests that would be sent.`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			if minConfidence < 0 || minConfidence > 1 {
+				failValidation(fmt.Errorf("min-confidence must be in [0, 1]"))
+			}
+			if months <= 0 {
+				failValidation(fmt.Errorf("months must be positive"))
+			}
+
+			client := api.New()
+			end := time.Now().UTC()
+			start := end.AddDate(0, -months, 0)
+			txs, err := api.FetchTransactionsWindow(client, start, end, 100)
+			if err != nil {
+				output.Fail("request_failed", err.Error(), nil)
+				return
+			}
+			txs, excluded := withoutTransfers(client, txs)
+			sure, err := api.FetchRecurringTransactions(client, nil)
+			if err != nil {
+				output.Fail("request_failed", err.Error(), nil)
+				return
+			}
+
+			opts := subscriptionOptionsFromConfig()
+			opts.AsOf = end
+			planned, skipped := planRecurringSync(insights.DetectSubscriptionsWithOptions(txs, opts), sure, txs, opts.Normalizer, minConfidence)
+			data := map[string]any{
+				"dry_run":            !apply,
+				"min_confidence":     minConfidence,
+				"window":             map[string]any{"start": start.Format("2006-01-02"), "end": end.Format("2006-01-02")},
+				"planned_count":      len(planned),
+				"skipped_count":      len(skipped),
+				"planned":            planned,
+				"skipped":            skipped,
+				"excluded_transfers": excluded,
+			}
+			if !apply {
+				_ = output.Print(format, output.Envelope{Data: data, Meta: &output.Meta{Schema: "docs/schemas/v1/recurring_sync_detected.schema.json", Status: 200}})
+				return
+			}
+
+			rec := newWriteRecorder(cmd)
+			applied := []map[string]any{}
+			failures := []map[string]any{}
+			for _, p := range planned {
+				req := p["request"].(map[string]any)
+				var res any
+				r, err := rec.send(client, "POST", req["path"].(string), req["body"], &res)
+				if err != nil {
+					failures = append(failures, map[string]any{"merchant": p["merchant"], "error": err.Error()})
+					continue
+				}
+				if r.StatusCode() >= 400 {
+					failures = append(failures, map[string]any{"merchant": p["merchant"], "error": fmt.Sprintf("HTTP %d", r.StatusCode())})
+					continue
+				}
+				created, _ := res.(map[string]any)
+				applied = append(applied, map[string]any{"merchant": p["merchant"], "name": p["name"], "recurring_id": fmt.Sprint(created["id"])})
+			}
+			data["applied_count"] = len(applied)
+			data["error_count"] = len(failures)
+			data["applied"] = applied
+			data["errors"] = failures
+			_ = output.Print(format, output.Envelope{Data: data, Meta: &output.Meta{Schema: "docs/schemas/v1/recurring_sync_detected.schema.json", Status: 200, OperationID: rec.save()}})
+		},
+	}
+	cmd.Flags().IntVar(&months, "months", 12, "lookback months for detection")
+	cmd.Flags().Float64Var(&minConfidence, "min-confidence", 0.8, "only propose subscriptions with at least this confidence")
+	cmd.Flags().BoolVar(&apply, "apply", false, "execute the creates (otherwise dry-run)")
+	return cmd
+}
+
+// planRecurringSync splits detected subscriptions into create requests for
+// Sure and skips (missed, low confidence, not monthly, already tracked).
+// Sure recurring transactions repeat monthly on expected_day_of_month, so
+// other cadences cannot be represented.
+func planRecurringSync(cands []insights.SubscriptionCandidate, sure []models.RecurringTransaction, txs []models.Transaction, n *insights.Normalizer, minConfidence float64) (planned, skipped []map[string]any) {
+	planned, skipped = []map[string]any{}, []map[string]any{}
+	tracked := map[string]string{}
+	for _, rt := range sure {
+		tracked[n.Key(models.Transaction{Name: rt.Name, MerchantName: rt.MerchantName})] = rt.ID
+	}
+	byID := map[string]models.Transaction{}
+	for _, tx := range txs {
+		byID[tx.ID] = tx
+	}
+
+	for _, c := range cands {
+		conf := math.Round(c.Confidence*100) / 100
+		skip := map[string]any{"name": c.Name, "merchant": c.Merchant, "confidence": conf}
+		switch {
+		case c.Status == "missed":
+			skip["reason"] = "missed"
+		case conf < minConfidence:
+			skip["reason"] = "confidence_below_threshold"
+		case c.Period != "monthly":
+			skip["reason"] = "not_monthly"
+		case tracked[c.Merchant] != "":
+			skip["reason"] = "already_tracked"
+			skip["recurring_id"] = tracked[c.Merchant]
+		}
+		if skip["reason"] != nil {
+			skipped = append(skipped, skip)
+			continue
+		}
+
+		it := plan.MergeRecurring(nil, []insights.SubscriptionCandidate{c}, n)[0]
+		o := recurringCreateOpts{
+			Name:               c.Name,
+			Amount:             fmt.Sprintf("%.2f", it.Amount),
+			ExpectedDayOfMonth: strconv.Itoa(it.ExpectedDayOfMonth),
+			LastOccurrenceDate: c.LastDate.Format("2006-01-02"),
+			NextExpectedDate:   it.NextExpectedDate,
+			Status:             "active",
+			OccurrenceCount:    strconv.Itoa(c.Count),
+			Manual:             "false",
+		}
+		if it.AmountMin != nil {
+			o.ExpectedAmountMin = fmt.Sprintf("%.2f", *it.AmountMin)
+			o.ExpectedAmountMax = fmt.Sprintf("%.2f", *it.AmountMax)
+			o.ExpectedAmountAvg = fmt.Sprintf("%.2f", *it.AmountAvg)
+		}
+		if len(c.SampleTxIDs) > 0 {
+			last := byID[c.SampleTxIDs[len(c.SampleTxIDs)-1]]
+			o.AccountID, o.MerchantID = last.AccountID, last.MerchantID
+			if last.Currency != "" && last.Currency != "<nil>" {
+				o.Currency = last.Currency
+			}
+		}
+		payload, err := buildRecurringCreatePayload(o)
+		if err != nil {
+			skipped = append(skipped, map[string]any{"name": c.Name, "merchant": c.Merchant, "confidence": conf, "reason": err.Error()})
+			continue
+		}
+		planned = append(planned, map[string]any{
+			"name":       c.Name,
+			"merchant":   c.Merchant,
+			"confidence": conf,
+			"request":    map[string]any{"method": "POST", "path": "/api/v1/recurring_transactions", "body": payload},
+		})
+	}
+	return planned, skipped
+}
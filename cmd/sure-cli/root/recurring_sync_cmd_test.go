@@ -0,0 +1,52 @@
+package root
+
+import (
+	"testing"
+	"time"
+
+	"github.com/we-promise/sure-cli/internal/insights"
+	"github.com/we-promise/sure-cli/internal/models"
+)
+
+func TestPlanRecurringSync(t *testing.T) {
+	last := time.Date(2026, 6, 24, 0, 0, 0, 0, time.UTC)
+	monthly := func(name, key string) insights.SubscriptionCandidate {
+		return insights.SubscriptionCandidate{
+			Name: name, Merchant: key, Count: 6, AvgAmount: 12.99, AvgPeriodDays: 30.2, Period: "monthly",
+			LastDate: last, LastAmount: 12.99, NextExpectedDate: "2026-07-24", Status: "active", Confidence: 0.9,
+			AmountHistory: []insights.AmountPoint{{Amount: 11.99}, {Amount: 12.99}},
+			SampleTxIDs:   []string{"t1", "t2"},
+		}
+	}
+	weekly := monthly("Cleaner", "cleaner")
+	weekly.Period = "weekly"
+	missed := monthly("Gym", "gym")
+	missed.Status = "missed"
+	low := monthly("Maybe", "maybe")
+	low.Confidence = 0.7
+
+	cands := []insights.SubscriptionCandidate{monthly("NETFLIX.COM", "netflix"), monthly("Monthly Rent", "monthly rent"), weekly, missed, low}
+	sure := []models.RecurringTransaction{{ID: "rec_rent", Name: "Monthly Rent", Status: "inactive"}}
+	txs := []models.Transaction{{ID: "t2", AccountID: "acc_credit", MerchantID: "mer_netflix", Currency: "EUR"}}
+
+	planned, skipped := planRecurringSync(cands, sure, txs, nil, 0.8)
+	if len(planned) != 1 {
+		t.Fatalf("planned = %+v", planned)
+	}
+	body := planned[0]["request"].(map[string]any)["body"].(map[string]any)["recurring_transaction"].(map[string]any)
+	if body["account_id"] != "acc_credit" || body["merchant_id"] != "mer_netflix" || body["amount"] != "12.99" ||
+		body["expected_day_of_month"] != "24" || body["next_expected_date"] != "2026-07-24" || body["expected_amount_min"] != "11.99" {
+		t.Fatalf("unexpected payload: %#v", body)
+	}
+
+	reasons := map[string]string{}
+	for _, s := range skipped {
+		reasons[s["merchant"].(string)] = s["reason"].(string)
+	}
+	want := map[string]string{"monthly rent": "already_tracked", "cleaner": "not_monthly", "gym": "missed", "maybe": "confidence_below_threshold"}
+	for k, v := range want {
+		if reasons[k] != v {
+			t.Fatalf("skip reasons = %v, want %v", reasons, want)
+		}
+	}
+}
@@ -103,7 +103,7 @@ func newCategoriesCreateCmd() *cobra.Command {
 			if err != nil {
 				failValidation(err)
 			}
-			dispatchWrite(o.Apply, "POST", "/api/v1/categories", payload)
+			dispatchJournaledWrite(cmd, o.Apply, "POST", "/api/v1/categories", payload)
 		},
 	}
 	cmd.Flags().StringVar(&o.Name, "name", "", "category name (required, unique within family)")
@@ -187,7 +187,7 @@ func newTagsCreateCmd() *cobra.Command {
 			if err != nil {
 				failValidation(err)
 			}
-			dispatchWrite(o.Apply, "POST", "/api/v1/tags", payload)
+			dispatchJournaledWrite(cmd, o.Apply, "POST", "/api/v1/tags", payload)
 		},
 	}
 	cmd.Flags().StringVar(&o.Name, "name", "", "tag name (required)")
@@ -207,7 +207,7 @@ func newTagsUpdateCmd() *cobra.Command {
 			if err != nil {
 				failValidation(err)
 			}
-			dispatchWrite(o.Apply, "PATCH", fmt.Sprintf("/api/v1/tags/%s", url.PathEscape(args[0])), payload)
+			dispatchJournaledWrite(cmd, o.Apply, "PATCH", fmt.Sprintf("/api/v1/tags/%s", url.PathEscape(args[0])), payload)
 		},
 	}
 	cmd.Flags().StringVar(&o.Name, "name", "", "tag name")
@@ -223,7 +223,7 @@ func newTagsDeleteCmd() *cobra.Command {
 		Short: "Delete tag (default dry-run; use --apply to execute)",
 		Args:  cobra.ExactArgs(1),
 		Run: func(cmd *cobra.Command, args []string) {
-			dispatchWrite(apply, "DELETE", fmt.Sprintf("/api/v1/tags/%s", url.PathEscape(args[0])), nil)
+			dispatchJournaledWrite(cmd, apply, "DELETE", fmt.Sprintf("/api/v1/tags/%s", url.PathEscape(args[0])), nil)
 		},
 	}
 	cmd.Flags().BoolVar(&apply, "apply", false, "execute the delete (otherwise dry-run)")
@@ -21,12 +21,18 @@ const maxRespondBodyBytes = 1024
 // print* helper passed 4xx/5xx response bodies through as Envelope.Data
 // instead of Envelope.Error.
 func respond(r *resty.Response, err error, data any) {
+	respondOperation(r, err, data, "")
+}
+
+// respondOperation is respond for journaled writes: operationID, when set,
+// goes into the envelope meta so callers can pass it to `undo`.
+func respondOperation(r *resty.Response, err error, data any, operationID string) {
 	checkResponse(r, err)
 	status := 0
 	if r != nil {
 		status = r.StatusCode()
 	}
-	if err := output.Print(format, output.Envelope{Data: data, Meta: &output.Meta{Status: status}}); err != nil {
+	if err := output.Print(format, output.Envelope{Data: data, Meta: &output.Meta{Status: status, OperationID: operationID}}); err != nil {
 		output.Fail("output_failed", err.Error(), nil)
 	}
 }
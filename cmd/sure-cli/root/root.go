@@ -61,7 +61,10 @@ func New() *cobra.Command {
 	cmd.AddCommand(newUsageCmd())
 	cmd.AddCommand(newInsightsCmd())
 	cmd.AddCommand(newPlanCmd())
+	cmd.AddCommand(newGoalsCmd())
 	cmd.AddCommand(newProposeCmd())
+	cmd.AddCommand(newHistoryCmd())
+	cmd.AddCommand(newUndoCmd())
 	cmd.AddCommand(newExportCmd())
 	cmd.AddCommand(newStatusCmd())
 	cmd.AddCommand(newHoldingsCmd())
@@ -75,6 +78,7 @@ func New() *cobra.Command {
 	cmd.AddCommand(newProviderConnectionsCmd())
 	cmd.AddCommand(newChatsCmd())
 	cmd.AddCommand(newAuthCmd())
+	cmd.AddCommand(newDevCmd())
 	cmd.AddCommand(&cobra.Command{
 		Use:   "version",
 		Short: "Print version information",
@@ -6,7 +6,9 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/we-promise/sure-cli/internal/api"
+	"github.com/we-promise/sure-cli/internal/config"
 	"github.com/we-promise/sure-cli/internal/insights"
+	"github.com/we-promise/sure-cli/internal/models"
 	"github.com/we-promise/sure-cli/internal/output"
 	"github.com/we-promise/sure-cli/internal/plan"
 )
@@ -24,6 +26,7 @@ func newStatusCmd() *cobra.Command {
 			checkResponse(r, err)
 
 			accounts, _ := accountsRes["accounts"].([]any)
+			tiers := config.GetLiquidityTiers()
 			var totalBalance float64
 			var cashBalance float64
 			var accountSummaries []map[string]any
@@ -51,10 +54,12 @@ func newStatusCmd() *cobra.Command {
 					"currency":     currency,
 				})
 
-				// Track cash accounts for runway
-				if accType == "depository" || accType == "checking" || accType == "savings" {
+				// Track cash accounts for runway: the cash tier of plan runway.
+				str := func(k string) string { s, _ := a[k].(string); return s }
+				tier, configured := plan.AccountTier(models.Account{ID: fmt.Sprint(a["id"]), AccountType: str("account_type"), Subtype: str("subtype"), Classification: str("classification")}, tiers)
+				if tier == plan.TierCash {
 					cash, ok := amountFromAPIOK(a, "cash_balance", "cash_balance_cents")
-					if !ok {
+					if !ok || configured {
 						cash = bal
 					}
 					cashBalance += cash
@@ -69,6 +74,10 @@ func newStatusCmd() *cobra.Command {
 				output.Fail("request_failed", err.Error(), nil)
 				return
 			}
+			// One transfers fetch covers both the month and the 6-month
+			// subscription window below.
+			linked := linkedTransferIDs(client, end.AddDate(0, -6, 0), end)
+			txs, excluded := insights.ExcludeTransfers(txs, linked)
 
 			// Calculate monthly spend
 			var monthlySpend float64
@@ -112,7 +121,8 @@ func newStatusCmd() *cobra.Command {
 
 			// 5. Get subscription count
 			subTxs, _ := api.FetchTransactionsWindow(client, end.AddDate(0, -6, 0), end, 500)
-			subs := insights.DetectSubscriptions(subTxs)
+			subTxs, subExcluded := insights.ExcludeTransfers(subTxs, linked)
+			subs := insights.DetectSubscriptionsWithOptions(subTxs, subscriptionOptionsFromConfig())
 			var monthlySubscriptions float64
 			for _, s := range subs {
 				if s.AvgPeriodDays > 0 {
@@ -136,6 +146,10 @@ func newStatusCmd() *cobra.Command {
 					"expenses":      formatMoneyValue(monthlySpend, primaryCurrency),
 					"net":           formatMoneyValue(monthlyIncome-monthlySpend, primaryCurrency),
 					"subscriptions": formatMoneyValue(monthlySubscriptions, primaryCurrency),
+					// Transfers between own accounts are not income or spend.
+					"excluded_transfers": excluded,
+					// ...and over the 6 months subscriptions are detected from.
+					"subscriptions_excluded_transfers": subExcluded,
 				},
 				"runway": map[string]any{
 					"months":       runwayMonths,
@@ -0,0 +1,554 @@
+package root
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/spf13/cobra"
+
+	"github.com/we-promise/sure-cli/internal/api"
+	"github.com/we-promise/sure-cli/internal/models"
+	"github.com/we-promise/sure-cli/internal/output"
+)
+
+// bulkChange is the set of fields to change on one transaction. A nil field
+// is left as is; an empty notes, category or tags value clears it.
+type bulkChange struct {
+	ID       string
+	Name     *string
+	Notes    *string
+	Category *string   // id or name
+	Tags     *[]string // ids or names; replaces the tag set
+	AddTags  []string  // ids or names added to the tag set
+}
+
+func (c *bulkChange) set(field string, values []string) error {
+	value := strings.Join(values, ",")
+	switch field {
+	case "name":
+		if strings.TrimSpace(value) == "" {
+			return fmt.Errorf("name must not be empty")
+		}
+		c.Name = &value
+	case "notes":
+		c.Notes = &value
+	case "category", "category_id":
+		value = strings.TrimSpace(value)
+		c.Category = &value
+	case "tags", "tag_ids":
+		tags := splitFlagValues(values)
+		c.Tags = &tags
+	case "add_tags":
+		c.AddTags = append(c.AddTags, splitFlagValues(values)...)
+	default:
+		return fmt.Errorf("unknown field %q (name, notes, category, tags, add_tags)", field)
+	}
+	return nil
+}
+
+func (c bulkChange) empty() bool {
+	return c.Name == nil && c.Notes == nil && c.Category == nil && c.Tags == nil && len(c.AddTags) == 0
+}
+
+// parseBulkChanges reads one change per transaction. CSV rows are
+// `id,field=value,...` (quote a cell to keep commas in the value; an
+// optional header row starting with "id" is skipped); NDJSON lines are
+// objects with an "id" and the fields, tags as a list or comma-separated.
+func parseBulkChanges(r io.Reader, kind string) ([]bulkChange, error) {
+	var out []bulkChange
+	seen := map[string]int{}
+	add := func(line int, c bulkChange) error {
+		if c.ID == "" {
+			return fmt.Errorf("line %d: missing id", line)
+		}
+		if c.empty() {
+			return fmt.Errorf("line %d: no fields to change for %s", line, c.ID)
+		}
+		if prev, ok := seen[c.ID]; ok {
+			return fmt.Errorf("line %d: %s already changed on line %d", line, c.ID, prev)
+		}
+		seen[c.ID] = line
+		out = append(out, c)
+		return nil
+	}
+
+	switch kind {
+	case "csv":
+		cr := csv.NewReader(r)
+		cr.FieldsPerRecord = -1
+		cr.TrimLeadingSpace = true
+		for first := true; ; first = false {
+			rec, err := cr.Read()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return nil, err
+			}
+			line, _ := cr.FieldPos(0)
+			if len(rec) == 1 && strings.TrimSpace(rec[0]) == "" {
+				continue
+			}
+			if first && strings.EqualFold(strings.TrimSpace(rec[0]), "id") && !strings.Contains(strings.Join(rec[1:], ""), "=") {
+				continue
+			}
+			c := bulkChange{ID: strings.TrimSpace(rec[0])}
+			for _, cell := range rec[1:] {
+				field, value, ok := strings.Cut(cell, "=")
+				if !ok {
+					return nil, fmt.Errorf("line %d: %q is not field=value", line, cell)
+				}
+				if err := c.set(strings.ToLower(strings.TrimSpace(field)), []string{value}); err != nil {
+					return nil, fmt.Errorf("line %d: %w", line, err)
+				}
+			}
+			if err := add(line, c); err != nil {
+				return nil, err
+			}
+		}
+	case "ndjson":
+		sc := bufio.NewScanner(r)
+		sc.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+		for line := 1; sc.Scan(); line++ {
+			raw := bytes.TrimSpace(sc.Bytes())
+			if len(raw) == 0 {
+				continue
+			}
+			var obj map[string]any
+			if err := json.Unmarshal(raw, &obj); err != nil {
+				return nil, fmt.Errorf("line %d: %w", line, err)
+			}
+			c := bulkChange{}
+			if id, ok := obj["id"].(string); ok {
+				c.ID = strings.TrimSpace(id)
+			}
+			for field, v := range obj {
+				var values []string
+				switch t := v.(type) {
+				case nil:
+					continue
+				case string:
+					values = []string{t}
+				case []any:
+					for _, e := range t {
+						values = append(values, fmt.Sprint(e))
+					}
+				default:
+					return nil, fmt.Errorf("line %d: %s must be a string or a list", line, field)
+				}
+				if field == "id" {
+					continue
+				}
+				if err := c.set(strings.ToLower(field), values); err != nil {
+					return nil, fmt.Errorf("line %d: %w", line, err)
+				}
+			}
+			if err := add(line, c); err != nil {
+				return nil, err
+			}
+		}
+		if err := sc.Err(); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("file format must be csv or ndjson")
+	}
+	return out, nil
+}
+
+// bulkRefs resolves category and tag references (id or name, case-insensitive).
+type bulkRefs struct {
+	categories []models.Category
+	tags       []models.Tag
+}
+
+func (r bulkRefs) category(ref string) (models.Category, bool) {
+	for _, c := range r.categories {
+		if c.ID == ref {
+			return c, true
+		}
+	}
+	for _, c := range r.categories {
+		if strings.EqualFold(c.Name, ref) {
+			return c, true
+		}
+	}
+	return models.Category{}, false
+}
+
+func (r bulkRefs) tag(ref string) (models.Tag, bool) {
+	for _, t := range r.tags {
+		if t.ID == ref {
+			return t, true
+		}
+	}
+	for _, t := range r.tags {
+		if strings.EqualFold(t.Name, ref) {
+			return t, true
+		}
+	}
+	return models.Tag{}, false
+}
+
+// planBulkUpdate diffs each change against the transaction's current state
+// and builds the PATCH request for the fields that actually change.
+// Transactions not found or already matching are skipped; unknown category
+// or tag references fail the whole plan.
+func planBulkUpdate(changes []bulkChange, current map[string]models.Transaction, refs bulkRefs) (planned, skipped []map[string]any, err error) {
+	planned, skipped = []map[string]any{}, []map[string]any{}
+	var unknown []string
+	for _, c := range changes {
+		tx, ok := current[c.ID]
+		if !ok {
+			skipped = append(skipped, map[string]any{"id": c.ID, "reason": "not_found"})
+			continue
+		}
+		body := map[string]any{}
+		diff := map[string]any{}
+		if c.Name != nil && *c.Name != tx.Name {
+			body["name"] = *c.Name
+			diff["name"] = map[string]any{"before": tx.Name, "after": *c.Name}
+		}
+		if c.Notes != nil && *c.Notes != tx.Notes {
+			body["notes"] = *c.Notes
+			diff["notes"] = map[string]any{"before": tx.Notes, "after": *c.Notes}
+		}
+		if c.Category != nil {
+			var cat models.Category
+			if *c.Category != "" {
+				if cat, ok = refs.category(*c.Category); !ok {
+					unknown = appendMissing(unknown, "category "+*c.Category)
+					continue
+				}
+			}
+			if cat.ID != tx.CategoryID {
+				body["category_id"] = cat.ID
+				diff["category"] = map[string]any{"before": tx.CategoryName, "after": cat.Name}
+			}
+		}
+		if c.Tags != nil || len(c.AddTags) > 0 {
+			var ids, names []string
+			if c.Tags == nil {
+				ids, names = append(ids, tx.TagIDs...), append(names, tx.TagNames...)
+			}
+			refsOK := true
+			var want []string
+			if c.Tags != nil {
+				want = append(want, *c.Tags...)
+			}
+			for _, ref := range append(want, c.AddTags...) {
+				t, ok := refs.tag(ref)
+				if !ok {
+					unknown = appendMissing(unknown, "tag "+ref)
+					refsOK = false
+					continue
+				}
+				if !containsString(ids, t.ID) {
+					ids, names = append(ids, t.ID), append(names, t.Name)
+				}
+			}
+			if !refsOK {
+				continue
+			}
+			if !sameStringSet(ids, tx.TagIDs) {
+				if ids == nil {
+					ids, names = []string{}, []string{}
+				}
+				before := tx.TagNames
+				if before == nil {
+					before = []string{}
+				}
+				body["tag_ids"] = ids
+				diff["tags"] = map[string]any{"before": before, "after": names}
+			}
+		}
+		if len(body) == 0 {
+			skipped = append(skipped, map[string]any{"id": c.ID, "reason": "unchanged"})
+			continue
+		}
+		planned = append(planned, map[string]any{
+			"id":      tx.ID,
+			"name":    tx.Name,
+			"date":    tx.Date.Format("2006-01-02"),
+			"changes": diff,
+			"request": map[string]any{
+				"method": "PATCH",
+				"path":   fmt.Sprintf("/api/v1/transactions/%s", url.PathEscape(tx.ID)),
+				"body":   map[string]any{"transaction": body},
+			},
+		})
+	}
+	if len(unknown) > 0 {
+		return nil, nil, fmt.Errorf("unknown %s", strings.Join(unknown, ", "))
+	}
+	return planned, skipped, nil
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+func appendMissing(list []string, s string) []string {
+	if containsString(list, s) {
+		return list
+	}
+	return append(list, s)
+}
+
+func sameStringSet(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for _, v := range a {
+		if !containsString(b, v) {
+			return false
+		}
+	}
+	return true
+}
+
+// runBounded calls fn(0..n-1) with at most concurrency calls in flight.
+func runBounded(n, concurrency int, fn func(i int)) {
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < concurrency && w < n; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				fn(i)
+			}
+		}()
+	}
+	for i := 0; i < n; i++ {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+}
+
+func newTransactionsBulkUpdateCmd() *cobra.Command {
+	var filter txFilterFlags
+	var file, fileFormat string
+	var setName, setNotes, setCategory string
+	var setTags, addTags []string
+	var concurrency int
+	var apply bool
+
+	cmd := &cobra.Command{
+		Use:   "bulk-update",
+		Short: "Update many transactions from a file or a filter (default dry-run; use --apply to execute)",
+		Long: `Change name, notes, category and tags on many transactions at once.
+
+Select them either with --file, a CSV of id,field=value rows or NDJSON
+objects ({"id": "...", "category": "Groceries", "tags": ["weekly"]}), or with
+the transactions list filters plus --set-*/--add-tags. Categories and tags
+are given by id or name; tags replaces the tag set, add_tags adds to it; an
+empty notes, category or tags value clears it.
+
+Without --apply this prints the before/after diff of every transaction and
+the requests that would be sent. With --apply the requests run with at most
+--concurrency in flight and each one is reported.`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			if concurrency < 1 || concurrency > 16 {
+				failValidation(fmt.Errorf("concurrency must be between 1 and 16"))
+			}
+			setFlags := cmd.Flags().Changed("set-name") || cmd.Flags().Changed("set-notes") || cmd.Flags().Changed("set-category") || cmd.Flags().Changed("set-tags") || len(addTags) > 0
+			q := filter.query()
+
+			client := api.New()
+			var changes []bulkChange
+			current := map[string]models.Transaction{}
+			data := map[string]any{"dry_run": !apply}
+
+			switch {
+			case file != "" && (setFlags || len(q) > 0):
+				failValidation(fmt.Errorf("use either --file or filters with --set-*/--add-tags, not both"))
+			case file != "":
+				kind := fileFormat
+				if kind == "" {
+					kind = "csv"
+					if ext := strings.ToLower(filepath.Ext(file)); ext == ".ndjson" || ext == ".jsonl" {
+						kind = "ndjson"
+					}
+				}
+				var r io.Reader = os.Stdin
+				if file != "-" {
+					f, err := os.Open(file)
+					if err != nil {
+						failValidation(err)
+						return
+					}
+					defer f.Close()
+					r = f
+				}
+				var err error
+				if changes, err = parseBulkChanges(r, kind); err != nil {
+					failValidation(err)
+					return
+				}
+				// Prior state, fetched one by one.
+				found := make([]*models.Transaction, len(changes))
+				errs := make([]error, len(changes))
+				runBounded(len(changes), concurrency, func(i int) {
+					var res map[string]any
+					r, err := client.Get(fmt.Sprintf("/api/v1/transactions/%s", url.PathEscape(changes[i].ID)), &res)
+					switch {
+					case err != nil:
+						errs[i] = err
+					case r.StatusCode() == 404:
+					case r.StatusCode() >= 400:
+						errs[i] = fmt.Errorf("%s: HTTP %d", changes[i].ID, r.StatusCode())
+					default:
+						tx := api.ParseTransaction(res)
+						found[i] = &tx
+					}
+				})
+				for i, err := range errs {
+					if err != nil {
+						output.Fail("request_failed", err.Error(), nil)
+						return
+					}
+					if found[i] != nil {
+						current[found[i].ID] = *found[i]
+					}
+				}
+				data["source"] = "file"
+				data["file"] = file
+			default:
+				if !setFlags {
+					failValidation(fmt.Errorf("provide --file, or filters with --set-name/--set-notes/--set-category/--set-tags/--add-tags"))
+				}
+				if len(q) == 0 {
+					failValidation(fmt.Errorf("at least one filter is required (e.g. --search, --category-id, --start-date)"))
+				}
+				c := bulkChange{}
+				if cmd.Flags().Changed("set-name") {
+					failValidation(c.set("name", []string{setName}))
+				}
+				if cmd.Flags().Changed("set-notes") {
+					_ = c.set("notes", []string{setNotes})
+				}
+				if cmd.Flags().Changed("set-category") {
+					_ = c.set("category", []string{setCategory})
+				}
+				if cmd.Flags().Changed("set-tags") {
+					_ = c.set("tags", setTags)
+				}
+				_ = c.set("add_tags", addTags)
+				txs, err := api.FetchTransactions(client, q, 100)
+				if err != nil {
+					output.Fail("request_failed", err.Error(), nil)
+					return
+				}
+				for _, tx := range txs {
+					c.ID = tx.ID
+					changes = append(changes, c)
+					current[tx.ID] = tx
+				}
+				filterOut := map[string]any{}
+				for k, v := range q {
+					if len(v) == 1 && !strings.HasSuffix(k, "[]") {
+						filterOut[k] = v[0]
+					} else {
+						filterOut[strings.TrimSuffix(k, "[]")] = v
+					}
+				}
+				data["source"] = "filter"
+				data["filter"] = filterOut
+			}
+
+			var needCategories, needTags bool
+			for _, c := range changes {
+				needCategories = needCategories || (c.Category != nil && *c.Category != "")
+				needTags = needTags || (c.Tags != nil && len(*c.Tags) > 0) || len(c.AddTags) > 0
+			}
+			var refs bulkRefs
+			var err error
+			if needCategories {
+				if refs.categories, err = api.FetchCategories(client); err != nil {
+					output.Fail("request_failed", err.Error(), nil)
+					return
+				}
+			}
+			if needTags {
+				if refs.tags, err = api.FetchTags(client); err != nil {
+					output.Fail("request_failed", err.Error(), nil)
+					return
+				}
+			}
+			planned, skipped, err := planBulkUpdate(changes, current, refs)
+			if err != nil {
+				failValidation(err)
+				return
+			}
+			data["selected_count"] = len(changes)
+			data["planned_count"] = len(planned)
+			data["skipped_count"] = len(skipped)
+			data["planned"] = planned
+			data["skipped"] = skipped
+			if !apply {
+				_ = output.Print(format, output.Envelope{Data: data, Meta: &output.Meta{Schema: "docs/schemas/v1/transactions_bulk_update.schema.json", Status: 200}})
+				return
+			}
+
+			rec := newWriteRecorder(cmd)
+			results := make([]map[string]any, len(planned))
+			runBounded(len(planned), concurrency, func(i int) {
+				p := planned[i]
+				req := p["request"].(map[string]any)
+				res := map[string]any{"id": p["id"]}
+				var out any
+				r, err := rec.send(client, "PATCH", req["path"].(string), req["body"], &out)
+				switch {
+				case err != nil:
+					res["status"], res["error"] = "error", err.Error()
+				case r.StatusCode() >= 400:
+					res["status"], res["http_status"], res["error"] = "error", r.StatusCode(), fmt.Sprintf("HTTP %d", r.StatusCode())
+				default:
+					res["status"], res["http_status"] = "applied", r.StatusCode()
+				}
+				results[i] = res
+			})
+			applied, failed := 0, 0
+			for _, res := range results {
+				if res["status"] == "applied" {
+					applied++
+				} else {
+					failed++
+				}
+			}
+			data["concurrency"] = concurrency
+			data["applied_count"] = applied
+			data["error_count"] = failed
+			data["results"] = results
+			_ = output.Print(format, output.Envelope{Data: data, Meta: &output.Meta{Schema: "docs/schemas/v1/transactions_bulk_update.schema.json", Status: 200, OperationID: rec.save()}})
+		},
+	}
+	addTxFilterFlags(cmd, &filter)
+	cmd.Flags().StringVar(&file, "file", "", "CSV (id,field=value) or NDJSON changes; - reads stdin")
+	cmd.Flags().StringVar(&fileFormat, "file-format", "", "csv|ndjson (default: from the file extension, else csv)")
+	cmd.Flags().StringVar(&setName, "set-name", "", "new name for the matched transactions")
+	cmd.Flags().StringVar(&setNotes, "set-notes", "", "new notes (empty clears)")
+	cmd.Flags().StringVar(&setCategory, "set-category", "", "category id or name (empty clears)")
+	cmd.Flags().StringSliceVar(&setTags, "set-tags", nil, "replace the tags (ids or names; empty clears)")
+	cmd.Flags().StringSliceVar(&addTags, "add-tags", nil, "add tags (ids or names)")
+	cmd.Flags().IntVar(&concurrency, "concurrency", 4, "requests in flight with --apply (1-16)")
+	cmd.Flags().BoolVar(&apply, "apply", false, "execute the updates (otherwise dry-run)")
+	return cmd
+}
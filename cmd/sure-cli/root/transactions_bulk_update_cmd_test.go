@@ -0,0 +1,83 @@
+package root
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/we-promise/sure-cli/internal/models"
+)
+
+func TestParseBulkChanges(t *testing.T) {
+	csvIn := "id,changes\ntx1,category=Groceries,\"tags=weekly, food\"\n\ntx2,notes=\n"
+	got, err := parseBulkChanges(strings.NewReader(csvIn), "csv")
+	if err != nil || len(got) != 2 {
+		t.Fatalf("csv: %+v, %v", got, err)
+	}
+	if *got[0].Category != "Groceries" || len(*got[0].Tags) != 2 || (*got[0].Tags)[1] != "food" || *got[1].Notes != "" {
+		t.Fatalf("csv changes = %+v / %+v", got[0], got[1])
+	}
+
+	ndjson := `{"id":"tx1","tags":["a","b"],"add_tags":"c","notes":null}` + "\n" + `{"id":"tx2","name":"Rent"}`
+	got, err = parseBulkChanges(strings.NewReader(ndjson), "ndjson")
+	if err != nil || len(got) != 2 || len(*got[0].Tags) != 2 || got[0].AddTags[0] != "c" || got[0].Notes != nil || *got[1].Name != "Rent" {
+		t.Fatalf("ndjson: %+v, %v", got, err)
+	}
+
+	bad := map[string]string{
+		"csv:no equals":     "tx1,Groceries\n",
+		"csv:unknown field": "tx1,amount=5\n",
+		"csv:duplicate id":  "tx1,notes=a\ntx1,notes=b\n",
+		"csv:no fields":     "tx1\n",
+		"csv:empty name":    "tx1,name=\n",
+		"ndjson:no id":      `{"notes":"x"}`,
+		"ndjson:number":     `{"id":"tx1","notes":5}`,
+	}
+	for name, in := range bad {
+		kind, _, _ := strings.Cut(name, ":")
+		if _, err := parseBulkChanges(strings.NewReader(in), kind); err == nil {
+			t.Errorf("%s: want an error", name)
+		}
+	}
+}
+
+func TestPlanBulkUpdate(t *testing.T) {
+	refs := bulkRefs{
+		categories: []models.Category{{ID: "cat_g", Name: "Groceries"}, {ID: "cat_d", Name: "Dining"}},
+		tags:       []models.Tag{{ID: "tag_w", Name: "Weekly"}, {ID: "tag_f", Name: "Food"}},
+	}
+	current := map[string]models.Transaction{
+		"tx1": {ID: "tx1", Name: "Mercadona", CategoryID: "cat_d", CategoryName: "Dining", TagIDs: []string{"tag_f"}, TagNames: []string{"Food"}},
+		"tx2": {ID: "tx2", Name: "Rent", Notes: "June"},
+	}
+	str := func(s string) *string { return &s }
+	changes := []bulkChange{
+		{ID: "tx1", Category: str("groceries"), AddTags: []string{"weekly", "Food"}},
+		{ID: "tx2", Notes: str("June")},
+		{ID: "tx3", Name: str("x")},
+	}
+	planned, skipped, err := planBulkUpdate(changes, current, refs)
+	if err != nil || len(planned) != 1 || len(skipped) != 2 {
+		t.Fatalf("planned=%v skipped=%v err=%v", planned, skipped, err)
+	}
+	body := planned[0]["request"].(map[string]any)["body"].(map[string]any)["transaction"].(map[string]any)
+	if body["category_id"] != "cat_g" || strings.Join(body["tag_ids"].([]string), ",") != "tag_f,tag_w" {
+		t.Fatalf("body = %v", body)
+	}
+	diff := planned[0]["changes"].(map[string]any)["category"].(map[string]any)
+	if diff["before"] != "Dining" || diff["after"] != "Groceries" {
+		t.Fatalf("diff = %v", diff)
+	}
+	if skipped[0]["reason"] != "unchanged" || skipped[1]["reason"] != "not_found" {
+		t.Fatalf("skipped = %v", skipped)
+	}
+
+	// Clearing the tags of an untagged transaction is a no-op; unknown
+	// references fail the plan.
+	none := []string{}
+	if p, _, _ := planBulkUpdate([]bulkChange{{ID: "tx2", Tags: &none}}, current, refs); len(p) != 0 {
+		t.Fatalf("clear tags on untagged = %v", p)
+	}
+	if _, _, err := planBulkUpdate([]bulkChange{{ID: "tx1", Category: str("Travel")}}, current, refs); err == nil || !strings.Contains(err.Error(), "Travel") {
+		t.Fatalf("unknown category: %v", err)
+	}
+}
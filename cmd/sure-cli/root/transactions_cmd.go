@@ -13,13 +13,7 @@ import (
 func newTransactionsCmd() *cobra.Command {
 	cmd := &cobra.Command{Use: "transactions", Short: "Transactions"}
 
-	var from, to string
-	var startDate, endDate string
-	var account, category, merchant string
-	var accountID, categoryID, merchantID string
-	var typ, search string
-	var accountIDs, categoryIDs, merchantIDs, tagIDs []string
-	var minAmount, maxAmount string
+	var filter txFilterFlags
 	var page, perPage int
 
 	list := &cobra.Command{
@@ -28,61 +22,7 @@ func newTransactionsCmd() *cobra.Command {
 		Run: func(cmd *cobra.Command, args []string) {
 			client := api.New()
 
-			q := url.Values{}
-			if startDate == "" {
-				startDate = from
-			}
-			if endDate == "" {
-				endDate = to
-			}
-			if accountID == "" {
-				accountID = account
-			}
-			if categoryID == "" {
-				categoryID = category
-			}
-			if merchantID == "" {
-				merchantID = merchant
-			}
-			if startDate != "" {
-				q.Set("start_date", startDate)
-			}
-			if endDate != "" {
-				q.Set("end_date", endDate)
-			}
-			if accountID != "" {
-				q.Set("account_id", accountID)
-			}
-			if categoryID != "" {
-				q.Set("category_id", categoryID)
-			}
-			if merchantID != "" {
-				q.Set("merchant_id", merchantID)
-			}
-			if minAmount != "" {
-				q.Set("min_amount", minAmount)
-			}
-			if maxAmount != "" {
-				q.Set("max_amount", maxAmount)
-			}
-			if typ != "" {
-				q.Set("type", typ)
-			}
-			if search != "" {
-				q.Set("search", search)
-			}
-			for _, id := range splitFlagValues(accountIDs) {
-				q.Add("account_ids[]", id)
-			}
-			for _, id := range splitFlagValues(categoryIDs) {
-				q.Add("category_ids[]", id)
-			}
-			for _, id := range splitFlagValues(merchantIDs) {
-				q.Add("merchant_ids[]", id)
-			}
-			for _, id := range splitFlagValues(tagIDs) {
-				q.Add("tag_ids[]", id)
-			}
+			q := filter.query()
 			if page > 0 {
 				q.Set("page", fmt.Sprintf("%d", page))
 			}
@@ -101,24 +41,7 @@ func newTransactionsCmd() *cobra.Command {
 		},
 	}
 
-	list.Flags().StringVar(&from, "from", "", "start date (YYYY-MM-DD)")
-	list.Flags().StringVar(&to, "to", "", "end date (YYYY-MM-DD)")
-	list.Flags().StringVar(&startDate, "start-date", "", "start date (YYYY-MM-DD, maps to start_date)")
-	list.Flags().StringVar(&endDate, "end-date", "", "end date (YYYY-MM-DD, maps to end_date)")
-	list.Flags().StringVar(&account, "account", "", "account id (alias for --account-id)")
-	list.Flags().StringVar(&category, "category", "", "category id (alias for --category-id)")
-	list.Flags().StringVar(&merchant, "merchant", "", "merchant id (alias for --merchant-id)")
-	list.Flags().StringVar(&accountID, "account-id", "", "account id")
-	list.Flags().StringVar(&categoryID, "category-id", "", "category id")
-	list.Flags().StringVar(&merchantID, "merchant-id", "", "merchant id")
-	list.Flags().StringVar(&minAmount, "min-amount", "", "minimum amount")
-	list.Flags().StringVar(&maxAmount, "max-amount", "", "maximum amount")
-	list.Flags().StringVar(&typ, "type", "", "transaction type: income|expense")
-	list.Flags().StringVar(&search, "search", "", "search name, notes, or merchant")
-	list.Flags().StringSliceVar(&accountIDs, "account-ids", nil, "account ids (repeat or comma-separated)")
-	list.Flags().StringSliceVar(&categoryIDs, "category-ids", nil, "category ids (repeat or comma-separated)")
-	list.Flags().StringSliceVar(&merchantIDs, "merchant-ids", nil, "merchant ids (repeat or comma-separated)")
-	list.Flags().StringSliceVar(&tagIDs, "tag-ids", nil, "tag ids (repeat or comma-separated)")
+	addTxFilterFlags(list, &filter)
 	list.Flags().IntVar(&page, "page", 1, "page number")
 	list.Flags().IntVar(&perPage, "per-page", 25, "items per page (maps to per_page)")
 	cmd.AddCommand(list)
@@ -139,10 +62,107 @@ func newTransactionsCmd() *cobra.Command {
 	cmd.AddCommand(newTransactionsCreateCmd())
 	cmd.AddCommand(newTransactionsUpdateCmd())
 	cmd.AddCommand(newTransactionsDeleteCmd())
+	cmd.AddCommand(newTransactionsDedupeCmd())
+	cmd.AddCommand(newTransactionsBulkUpdateCmd())
 
 	return cmd
 }
 
+// txFilterFlags are the transactions list filters, shared with commands
+// that select transactions the same way (bulk-update).
+type txFilterFlags struct {
+	from, to                                     string
+	startDate, endDate                           string
+	account, category, merchant                  string
+	accountID, categoryID, merchantID            string
+	typ, search                                  string
+	accountIDs, categoryIDs, merchantIDs, tagIDs []string
+	minAmount, maxAmount                         string
+}
+
+func addTxFilterFlags(cmd *cobra.Command, f *txFilterFlags) {
+	cmd.Flags().StringVar(&f.from, "from", "", "start date (YYYY-MM-DD)")
+	cmd.Flags().StringVar(&f.to, "to", "", "end date (YYYY-MM-DD)")
+	cmd.Flags().StringVar(&f.startDate, "start-date", "", "start date (YYYY-MM-DD, maps to start_date)")
+	cmd.Flags().StringVar(&f.endDate, "end-date", "", "end date (YYYY-MM-DD, maps to end_date)")
+	cmd.Flags().StringVar(&f.account, "account", "", "account id (alias for --account-id)")
+	cmd.Flags().StringVar(&f.category, "category", "", "category id (alias for --category-id)")
+	cmd.Flags().StringVar(&f.merchant, "merchant", "", "merchant id (alias for --merchant-id)")
+	cmd.Flags().StringVar(&f.accountID, "account-id", "", "account id")
+	cmd.Flags().StringVar(&f.categoryID, "category-id", "", "category id")
+	cmd.Flags().StringVar(&f.merchantID, "merchant-id", "", "merchant id")
+	cmd.Flags().StringVar(&f.minAmount, "min-amount", "", "minimum amount")
+	cmd.Flags().StringVar(&f.maxAmount, "max-amount", "", "maximum amount")
+	cmd.Flags().StringVar(&f.typ, "type", "", "transaction type: income|expense")
+	cmd.Flags().StringVar(&f.search, "search", "", "search name, notes, or merchant")
+	cmd.Flags().StringSliceVar(&f.accountIDs, "account-ids", nil, "account ids (repeat or comma-separated)")
+	cmd.Flags().StringSliceVar(&f.categoryIDs, "category-ids", nil, "category ids (repeat or comma-separated)")
+	cmd.Flags().StringSliceVar(&f.merchantIDs, "merchant-ids", nil, "merchant ids (repeat or comma-separated)")
+	cmd.Flags().StringSliceVar(&f.tagIDs, "tag-ids", nil, "tag ids (repeat or comma-separated)")
+}
+
+// query maps the filters to Sure's list parameters (aliases resolved).
+func (f txFilterFlags) query() url.Values {
+	q := url.Values{}
+	startDate, endDate := f.startDate, f.endDate
+	if startDate == "" {
+		startDate = f.from
+	}
+	if endDate == "" {
+		endDate = f.to
+	}
+	accountID, categoryID, merchantID := f.accountID, f.categoryID, f.merchantID
+	if accountID == "" {
+		accountID = f.account
+	}
+	if categoryID == "" {
+		categoryID = f.category
+	}
+	if merchantID == "" {
+		merchantID = f.merchant
+	}
+	if startDate != "" {
+		q.Set("start_date", startDate)
+	}
+	if endDate != "" {
+		q.Set("end_date", endDate)
+	}
+	if accountID != "" {
+		q.Set("account_id", accountID)
+	}
+	if categoryID != "" {
+		q.Set("category_id", categoryID)
+	}
+	if merchantID != "" {
+		q.Set("merchant_id", merchantID)
+	}
+	if f.minAmount != "" {
+		q.Set("min_amount", f.minAmount)
+	}
+	if f.maxAmount != "" {
+		q.Set("max_amount", f.maxAmount)
+	}
+	if f.typ != "" {
+		q.Set("type", f.typ)
+	}
+	if f.search != "" {
+		q.Set("search", f.search)
+	}
+	for _, id := range splitFlagValues(f.accountIDs) {
+		q.Add("account_ids[]", id)
+	}
+	for _, id := range splitFlagValues(f.categoryIDs) {
+		q.Add("category_ids[]", id)
+	}
+	for _, id := range splitFlagValues(f.merchantIDs) {
+		q.Add("merchant_ids[]", id)
+	}
+	for _, id := range splitFlagValues(f.tagIDs) {
+		q.Add("tag_ids[]", id)
+	}
+	return q
+}
+
 func splitFlagValues(values []string) []string {
 	var out []string
 	for _, value := range values {
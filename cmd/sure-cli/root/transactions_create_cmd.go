@@ -45,10 +45,10 @@ func newTransactionsCreateCmd() *cobra.Command {
 				return
 			}
 
-			client := api.New()
+			rec := newWriteRecorder(cmd)
 			var res any
-			r, err := client.Post("/api/v1/transactions", payload, &res)
-			respond(r, err, res)
+			r, err := rec.send(api.New(), "POST", "/api/v1/transactions", payload, &res)
+			respondOperation(r, err, res, rec.save())
 		},
 	}
 
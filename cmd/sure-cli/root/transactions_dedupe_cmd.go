@@ -0,0 +1,142 @@
+package root
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/we-promise/sure-cli/internal/api"
+	"github.com/we-promise/sure-cli/internal/output"
+)
+
+func newTransactionsDedupeCmd() *cobra.Command {
+	var f duplicateFlags
+	var minConfidence float64
+	var action string
+	var apply bool
+
+	cmd := &cobra.Command{
+		Use:   "dedupe",
+		Short: "Delete or flag likely duplicate transactions (default dry-run; use --apply to execute)",
+		Long: `Run the 'insights duplicates' detector and remove the redundant copies.
+
+Without --apply this only reports the requests that would be sent. With
+--action flag the duplicates are kept and a pointer to the copy being kept
+is appended to their notes, so they can be reviewed in Sure. Copies that
+already carry that pointer are skipped.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			if action != "delete" && action != "flag" {
+				failValidation(fmt.Errorf("action must be delete or flag"))
+			}
+			if minConfidence <= 0 || minConfidence > 1 {
+				failValidation(fmt.Errorf("min-confidence must be in (0, 1]"))
+			}
+
+			start, end, opts, cands, txs, err := detectDuplicates(cmd, f)
+			if err != nil {
+				output.Fail("request_failed", err.Error(), nil)
+				return
+			}
+			notes := map[string]string{}
+			for _, t := range txs {
+				notes[t.ID] = t.Notes
+			}
+
+			planned := []map[string]any{}
+			skipped := []map[string]any{}
+			for _, c := range cands {
+				if c.Confidence < minConfidence {
+					skipped = append(skipped, map[string]any{
+						"keep_tx_id": c.KeepTxID,
+						"tx_ids":     c.DuplicateTxIDs,
+						"confidence": c.Confidence,
+						"reason":     "confidence_below_threshold",
+					})
+					continue
+				}
+				for _, id := range c.DuplicateTxIDs {
+					req := map[string]any{"method": "DELETE", "path": fmt.Sprintf("/api/v1/transactions/%s", url.PathEscape(id))}
+					if action == "flag" {
+						marker := fmt.Sprintf("Possible duplicate of %s (flagged by sure-cli transactions dedupe)", c.KeepTxID)
+						if strings.Contains(notes[id], marker) {
+							skipped = append(skipped, map[string]any{
+								"keep_tx_id": c.KeepTxID,
+								"tx_ids":     []string{id},
+								"confidence": c.Confidence,
+								"reason":     "already_flagged",
+							})
+							continue
+						}
+						req["method"] = "PATCH"
+						req["body"] = map[string]any{"transaction": map[string]any{"notes": appendNote(notes[id], marker)}}
+					}
+					planned = append(planned, map[string]any{
+						"tx_id":      id,
+						"keep_tx_id": c.KeepTxID,
+						"account":    c.Account,
+						"amount":     c.Amount,
+						"merchant":   c.Merchant,
+						"confidence": c.Confidence,
+						"reason":     c.Reason,
+						"request":    req,
+					})
+				}
+			}
+
+			data := map[string]any{
+				"dry_run":        !apply,
+				"action":         action,
+				"min_confidence": minConfidence,
+				"window":         map[string]any{"start": start.Format("2006-01-02"), "end": end.Format("2006-01-02")},
+				"params":         opts,
+				"planned_count":  len(planned),
+				"skipped_count":  len(skipped),
+				"planned":        planned,
+				"skipped":        skipped,
+			}
+			if !apply {
+				_ = output.Print(format, output.Envelope{Data: data, Meta: &output.Meta{Schema: "docs/schemas/v1/transactions_dedupe.schema.json", Status: 200}})
+				return
+			}
+
+			client := api.New()
+			rec := newWriteRecorder(cmd)
+			applied := []map[string]any{}
+			failures := []map[string]any{}
+			for _, p := range planned {
+				req := p["request"].(map[string]any)
+				var res any
+				r, err := rec.send(client, req["method"].(string), req["path"].(string), req["body"], &res)
+				if err != nil {
+					failures = append(failures, map[string]any{"tx_id": p["tx_id"], "error": err.Error()})
+					continue
+				}
+				if r.StatusCode() >= 400 {
+					failures = append(failures, map[string]any{"tx_id": p["tx_id"], "error": fmt.Sprintf("HTTP %d", r.StatusCode())})
+					continue
+				}
+				applied = append(applied, map[string]any{"tx_id": p["tx_id"], "keep_tx_id": p["keep_tx_id"], "method": req["method"]})
+			}
+			data["applied_count"] = len(applied)
+			data["error_count"] = len(failures)
+			data["applied"] = applied
+			data["errors"] = failures
+			_ = output.Print(format, output.Envelope{Data: data, Meta: &output.Meta{Schema: "docs/schemas/v1/transactions_dedupe.schema.json", Status: 200, OperationID: rec.save()}})
+		},
+	}
+	addDuplicateFlags(cmd, &f)
+	cmd.Flags().Float64Var(&minConfidence, "min-confidence", 0.8, "only act on groups with at least this confidence")
+	cmd.Flags().StringVar(&action, "action", "delete", "delete the redundant copies or flag them via notes (delete|flag)")
+	cmd.Flags().BoolVar(&apply, "apply", false, "execute the requests (otherwise dry-run)")
+	return cmd
+}
+
+// appendNote adds line to existing notes on a line of its own.
+func appendNote(notes, line string) string {
+	if notes = strings.TrimRight(notes, " \n"); notes == "" {
+		return line
+	}
+	return notes + "\n" + line
+}
@@ -36,10 +36,10 @@ func newTransactionsDeleteCmd() *cobra.Command {
 				return
 			}
 
-			client := api.New()
+			rec := newWriteRecorder(cmd)
 			var res any
-			r, err := client.Delete(path, &res)
-			respond(r, err, res)
+			r, err := rec.send(api.New(), "DELETE", path, nil, &res)
+			respondOperation(r, err, res, rec.save())
 		},
 	}
 
@@ -51,10 +51,10 @@ func newTransactionsUpdateCmd() *cobra.Command {
 				return
 			}
 
-			client := api.New()
+			rec := newWriteRecorder(cmd)
 			var res any
-			r, err := client.Patch(path, payload, &res)
-			respond(r, err, res)
+			r, err := rec.send(api.New(), "PATCH", path, payload, &res)
+			respondOperation(r, err, res, rec.save())
 		},
 	}
 
@@ -50,6 +50,7 @@ func newTransfersCmd() *cobra.Command {
 			printGet(fmt.Sprintf("/api/v1/transfers/%s", url.PathEscape(args[0])))
 		},
 	})
+	cmd.AddCommand(newTransfersSuggestCmd())
 
 	return cmd
 }
@@ -0,0 +1,64 @@
+package root
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/we-promise/sure-cli/internal/api"
+	"github.com/we-promise/sure-cli/internal/insights"
+	"github.com/we-promise/sure-cli/internal/models"
+	"github.com/we-promise/sure-cli/internal/output"
+)
+
+// withoutTransfers drops money moved between the user's own accounts
+// (savings transfers, card and loan payments) from txs before spend and
+// income are aggregated: legs of Sure's transfers over the same dates and
+// transactions Sure marks as transfers. Unlinked look-alike pairs stay in;
+// `transfers suggest` proposes linking them.
+func withoutTransfers(client *api.Client, txs []models.Transaction) ([]models.Transaction, insights.TransferExclusion) {
+	if len(txs) == 0 {
+		return txs, insights.TransferExclusion{}
+	}
+	first, last := txs[0].Date, txs[0].Date
+	for _, tx := range txs {
+		if tx.Date.Before(first) {
+			first = tx.Date
+		}
+		if tx.Date.After(last) {
+			last = tx.Date
+		}
+	}
+	return insights.ExcludeTransfers(txs, linkedTransferIDs(client, first, last))
+}
+
+// linkedTransferIDs returns the ids of both legs of the Sure transfers
+// around [first, last]. Transfers are dated by their outflow, so the range
+// is widened by the transfer window to catch inflows inside it. A server
+// without the transfers endpoint yields an empty set (Sure's markers still
+// apply); any other failure ends the command rather than silently
+// counting transfers as spend.
+func linkedTransferIDs(client *api.Client, first, last time.Time) map[string]bool {
+	opts := transferOptionsFromConfig()
+	q := url.Values{}
+	q.Set("start_date", first.AddDate(0, 0, -opts.WindowDays).Format("2006-01-02"))
+	q.Set("end_date", last.AddDate(0, 0, opts.WindowDays).Format("2006-01-02"))
+	linked := map[string]bool{}
+	transfers, err := api.FetchTransfers(client, q)
+	var se *api.StatusError
+	if errors.As(err, &se) && (se.Code == http.StatusNotFound || se.Code == http.StatusNotImplemented) {
+		return linked
+	}
+	if err != nil {
+		output.Fail("request_failed", "load linked transfers: "+err.Error(), nil)
+	}
+	for _, t := range transfers {
+		for _, id := range []string{t.OutflowTransactionID, t.InflowTransactionID} {
+			if id != "" {
+				linked[id] = true
+			}
+		}
+	}
+	return linked
+}
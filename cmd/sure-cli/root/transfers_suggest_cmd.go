@@ -0,0 +1,162 @@
+package root
+
+import (
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/we-promise/sure-cli/internal/api"
+	"github.com/we-promise/sure-cli/internal/insights"
+	"github.com/we-promise/sure-cli/internal/output"
+)
+
+func newTransfersSuggestCmd() *cobra.Command {
+	var days, windowDays int
+	var accountID string
+	var minConfidence float64
+	var apply bool
+
+	cmd := &cobra.Command{
+		Use:   "suggest",
+		Short: "Suggest transfers between own accounts (default dry-run; use --apply to link them)",
+		Long: `Pair unlinked outflows with inflows of the same amount in another account
+at most --window-days apart, skip pairs already rejected in Sure, and score
+each pair: closer dates and transfer/payment keywords raise the confidence,
+other same-amount legs in the window lower it.
+
+Without --apply this only reports the requests that would be sent. With
+--apply, pairs at or above --min-confidence are linked as transfers in Sure.`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			if days <= 0 {
+				failValidation(fmt.Errorf("days must be positive"))
+			}
+			if minConfidence <= 0 || minConfidence > 1 {
+				failValidation(fmt.Errorf("min-confidence must be in (0, 1]"))
+			}
+			opts := transferOptionsFromConfig()
+			if cmd.Flags().Changed("window-days") {
+				if windowDays <= 0 {
+					failValidation(fmt.Errorf("window-days must be positive"))
+				}
+				opts.WindowDays = windowDays
+			}
+
+			client := api.New()
+			end := time.Now().UTC()
+			start := end.AddDate(0, 0, -days)
+			txs, err := api.FetchTransactionsWindow(client, start, end, 100)
+			if err != nil {
+				output.Fail("request_failed", err.Error(), nil)
+				return
+			}
+			// Transfers are dated by their outflow, which can fall up to
+			// WindowDays before an inflow inside the window.
+			q := url.Values{}
+			q.Set("start_date", start.AddDate(0, 0, -opts.WindowDays).Format("2006-01-02"))
+			q.Set("end_date", end.Format("2006-01-02"))
+			transfers, err := api.FetchTransfers(client, q)
+			if err != nil {
+				output.Fail("request_failed", err.Error(), nil)
+				return
+			}
+			rejectedTransfers, err := api.FetchRejectedTransfers(client, q)
+			if err != nil {
+				output.Fail("request_failed", err.Error(), nil)
+				return
+			}
+			linked := map[string]bool{}
+			for _, t := range transfers {
+				linked[t.OutflowTransactionID], linked[t.InflowTransactionID] = true, true
+			}
+			rejected := map[string]bool{}
+			for _, t := range rejectedTransfers {
+				rejected[insights.TransferKey(t.OutflowTransactionID, t.InflowTransactionID)] = true
+			}
+
+			planned := []map[string]any{}
+			skipped := []map[string]any{}
+			for _, s := range insights.SuggestTransfers(txs, linked, rejected, opts) {
+				if accountID != "" && s.Outflow.AccountID != accountID && s.Inflow.AccountID != accountID {
+					continue
+				}
+				if s.Confidence < minConfidence {
+					skipped = append(skipped, map[string]any{
+						"outflow_tx_id": s.Outflow.ID,
+						"inflow_tx_id":  s.Inflow.ID,
+						"amount":        s.Amount,
+						"confidence":    s.Confidence,
+						"reason":        "confidence_below_threshold",
+					})
+					continue
+				}
+				planned = append(planned, map[string]any{
+					"outflow":      s.Outflow,
+					"inflow":       s.Inflow,
+					"amount":       s.Amount,
+					"days_apart":   s.DaysApart,
+					"alternatives": s.Alternatives,
+					"confidence":   s.Confidence,
+					"reason":       s.Reason,
+					"request": map[string]any{
+						"method": "POST",
+						"path":   "/api/v1/transfers",
+						"body": map[string]any{"transfer": map[string]any{
+							"outflow_transaction_id": s.Outflow.ID,
+							"inflow_transaction_id":  s.Inflow.ID,
+						}},
+					},
+				})
+			}
+
+			data := map[string]any{
+				"dry_run":        !apply,
+				"min_confidence": minConfidence,
+				"window":         map[string]any{"start": start.Format("2006-01-02"), "end": end.Format("2006-01-02")},
+				"params":         opts,
+				"rejected_count": len(rejectedTransfers),
+				"planned_count":  len(planned),
+				"skipped_count":  len(skipped),
+				"planned":        planned,
+				"skipped":        skipped,
+			}
+			if !apply {
+				_ = output.Print(format, output.Envelope{Data: data, Meta: &output.Meta{Schema: "docs/schemas/v1/transfers_suggest.schema.json", Status: 200}})
+				return
+			}
+
+			rec := newWriteRecorder(cmd)
+			applied := []map[string]any{}
+			failures := []map[string]any{}
+			for _, p := range planned {
+				req := p["request"].(map[string]any)
+				out, in := p["outflow"].(insights.TransferLegTx), p["inflow"].(insights.TransferLegTx)
+				var res any
+				r, err := rec.send(client, "POST", req["path"].(string), req["body"], &res)
+				if err != nil {
+					failures = append(failures, map[string]any{"outflow_tx_id": out.ID, "inflow_tx_id": in.ID, "error": err.Error()})
+					continue
+				}
+				if r.StatusCode() >= 400 {
+					failures = append(failures, map[string]any{"outflow_tx_id": out.ID, "inflow_tx_id": in.ID, "error": fmt.Sprintf("HTTP %d", r.StatusCode())})
+					continue
+				}
+				created, _ := res.(map[string]any)
+				applied = append(applied, map[string]any{"outflow_tx_id": out.ID, "inflow_tx_id": in.ID, "transfer_id": fmt.Sprint(created["id"])})
+			}
+			data["applied_count"] = len(applied)
+			data["error_count"] = len(failures)
+			data["applied"] = applied
+			data["errors"] = failures
+			_ = output.Print(format, output.Envelope{Data: data, Meta: &output.Meta{Schema: "docs/schemas/v1/transfers_suggest.schema.json", Status: 200, OperationID: rec.save()}})
+		},
+	}
+	cmd.Flags().IntVar(&days, "days", 90, "history window in days")
+	cmd.Flags().IntVar(&windowDays, "window-days", 0, "max days between the two legs (default: heuristics.transfers.window_days)")
+	cmd.Flags().StringVar(&accountID, "account-id", "", "only pairs with a leg in this account")
+	cmd.Flags().Float64Var(&minConfidence, "min-confidence", 0.8, "only link pairs with at least this confidence")
+	cmd.Flags().BoolVar(&apply, "apply", false, "create the transfers (otherwise dry-run)")
+	return cmd
+}
@@ -0,0 +1,65 @@
+package api
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/we-promise/sure-cli/internal/models"
+)
+
+// FetchAccounts pulls every account by paging the Sure API.
+func FetchAccounts(client *Client) ([]models.Account, error) {
+	page := 1
+	var all []models.Account
+
+	for {
+		q := url.Values{}
+		q.Set("page", fmt.Sprintf("%d", page))
+		q.Set("per_page", "100")
+		path := "/api/v1/accounts?" + q.Encode()
+
+		var res map[string]any
+		r, err := client.Get(path, &res)
+		if err != nil {
+			return nil, err
+		}
+		if r.StatusCode() >= 400 {
+			return nil, fmt.Errorf("request failed: status %d", r.StatusCode())
+		}
+
+		items, _ := res["accounts"].([]any)
+		for _, it := range items {
+			m, _ := it.(map[string]any)
+			all = append(all, models.Account{
+				ID:              fmt.Sprint(m["id"]),
+				Name:            fmt.Sprint(m["name"]),
+				AccountType:     optString(m["account_type"]),
+				Subtype:         optString(m["subtype"]),
+				Classification:  optString(m["classification"]),
+				Currency:        optString(m["currency"]),
+				BalanceText:     optString(m["balance"]),
+				CashBalanceText: optString(m["cash_balance"]),
+			})
+		}
+
+		pg, _ := res["pagination"].(map[string]any)
+		if pg == nil {
+			break
+		}
+		totalPages := asInt(pg["total_pages"])
+		if totalPages <= 0 || page >= totalPages {
+			break
+		}
+		page++
+	}
+
+	return all, nil
+}
+
+// optString is fmt.Sprint for optional JSON fields: null becomes "".
+func optString(v any) string {
+	if v == nil {
+		return ""
+	}
+	return fmt.Sprint(v)
+}
@@ -0,0 +1,89 @@
+package api
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/we-promise/sure-cli/internal/models"
+)
+
+// FetchBalanceSheet reads Sure's balance sheet. Money fields may come as
+// formatted strings or as {amount, formatted} objects; both are accepted.
+func FetchBalanceSheet(client *Client) (models.BalanceSheet, error) {
+	var res map[string]any
+	r, err := client.Get("/api/v1/balance_sheet", &res)
+	if err != nil {
+		return models.BalanceSheet{}, err
+	}
+	if r.StatusCode() >= 400 {
+		return models.BalanceSheet{}, fmt.Errorf("request failed: status %d", r.StatusCode())
+	}
+	money := func(v any) string {
+		if m, ok := v.(map[string]any); ok {
+			if f := optString(m["formatted"]); f != "" {
+				return f
+			}
+			return optString(m["amount"])
+		}
+		return optString(v)
+	}
+	return models.BalanceSheet{
+		Currency:        optString(res["currency"]),
+		AssetsText:      money(res["assets"]),
+		LiabilitiesText: money(res["liabilities"]),
+		NetWorthText:    money(res["net_worth"]),
+	}, nil
+}
+
+// FetchBalances pulls every balance history record matching q (account_id,
+// start_date, end_date) by paging the Sure API.
+func FetchBalances(client *Client, q url.Values) ([]models.Balance, error) {
+	page := 1
+	var all []models.Balance
+
+	for {
+		pq := url.Values{}
+		for k, v := range q {
+			pq[k] = v
+		}
+		pq.Set("page", fmt.Sprintf("%d", page))
+		pq.Set("per_page", "100")
+		path := "/api/v1/balances?" + pq.Encode()
+
+		var res map[string]any
+		r, err := client.Get(path, &res)
+		if err != nil {
+			return nil, err
+		}
+		if r.StatusCode() >= 400 {
+			return nil, fmt.Errorf("request failed: status %d", r.StatusCode())
+		}
+
+		items, _ := res["balances"].([]any)
+		for _, it := range items {
+			m, _ := it.(map[string]any)
+			b := models.Balance{
+				ID:          fmt.Sprint(m["id"]),
+				Date:        optString(m["date"]),
+				BalanceText: optString(m["balance"]),
+				Currency:    optString(m["currency"]),
+			}
+			if am, ok := m["account"].(map[string]any); ok {
+				b.AccountID = fmt.Sprint(am["id"])
+			}
+			all = append(all, b)
+		}
+
+		pg, _ := res["pagination"].(map[string]any)
+		if pg == nil {
+			break
+		}
+		totalPages := asInt(pg["total_pages"])
+		if totalPages <= 0 || page >= totalPages {
+			break
+		}
+		page++
+	}
+
+	return all, nil
+}
@@ -0,0 +1,65 @@
+package api
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/we-promise/sure-cli/internal/models"
+)
+
+// FetchBudgetCategories pulls every budget category matching q (budget_id,
+// start_date, end_date, ...) by paging the Sure API.
+func FetchBudgetCategories(client *Client, q url.Values) ([]models.BudgetCategory, error) {
+	page := 1
+	var all []models.BudgetCategory
+
+	for {
+		pq := url.Values{}
+		for k, v := range q {
+			pq[k] = v
+		}
+		pq.Set("page", fmt.Sprintf("%d", page))
+		pq.Set("per_page", "100")
+		path := "/api/v1/budget_categories?" + pq.Encode()
+
+		var res map[string]any
+		r, err := client.Get(path, &res)
+		if err != nil {
+			return nil, err
+		}
+		if r.StatusCode() >= 400 {
+			return nil, fmt.Errorf("request failed: status %d", r.StatusCode())
+		}
+
+		items, _ := res["budget_categories"].([]any)
+		for _, it := range items {
+			m, _ := it.(map[string]any)
+			bc := models.BudgetCategory{
+				ID:           fmt.Sprint(m["id"]),
+				BudgetID:     fmt.Sprint(m["budget_id"]),
+				BudgetedText: fmt.Sprint(m["budgeted_spending"]),
+				ActualText:   fmt.Sprint(m["actual_spending"]),
+				Currency:     fmt.Sprint(m["currency"]),
+				StartDate:    fmt.Sprint(m["start_date"]),
+				EndDate:      fmt.Sprint(m["end_date"]),
+			}
+			if cm, ok := m["category"].(map[string]any); ok {
+				bc.CategoryID = fmt.Sprint(cm["id"])
+				bc.CategoryName = fmt.Sprint(cm["name"])
+			}
+			all = append(all, bc)
+		}
+
+		pg, _ := res["pagination"].(map[string]any)
+		if pg == nil {
+			break
+		}
+		totalPages := asInt(pg["total_pages"])
+		if totalPages <= 0 || page >= totalPages {
+			break
+		}
+		page++
+	}
+
+	return all, nil
+}
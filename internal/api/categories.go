@@ -0,0 +1,57 @@
+package api
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/we-promise/sure-cli/internal/models"
+)
+
+// FetchCategories pulls every category (with its parent) by paging the Sure API.
+func FetchCategories(client *Client) ([]models.Category, error) {
+	page := 1
+	var all []models.Category
+
+	for {
+		q := url.Values{}
+		q.Set("page", fmt.Sprintf("%d", page))
+		q.Set("per_page", "100")
+		path := "/api/v1/categories?" + q.Encode()
+
+		var res map[string]any
+		r, err := client.Get(path, &res)
+		if err != nil {
+			return nil, err
+		}
+		if r.StatusCode() >= 400 {
+			return nil, fmt.Errorf("request failed: status %d", r.StatusCode())
+		}
+
+		items, _ := res["categories"].([]any)
+		for _, it := range items {
+			m, _ := it.(map[string]any)
+			c := models.Category{
+				ID:             fmt.Sprint(m["id"]),
+				Name:           fmt.Sprint(m["name"]),
+				Classification: fmt.Sprint(m["classification"]),
+			}
+			if pm, ok := m["parent"].(map[string]any); ok {
+				c.ParentID = fmt.Sprint(pm["id"])
+				c.ParentName = fmt.Sprint(pm["name"])
+			}
+			all = append(all, c)
+		}
+
+		pg, _ := res["pagination"].(map[string]any)
+		if pg == nil {
+			break
+		}
+		totalPages := asInt(pg["total_pages"])
+		if totalPages <= 0 || page >= totalPages {
+			break
+		}
+		page++
+	}
+
+	return all, nil
+}
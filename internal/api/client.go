@@ -5,6 +5,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/go-resty/resty/v2"
@@ -14,6 +15,8 @@ import (
 type Client struct {
 	http       *resty.Client
 	refreshing bool
+	// mu serializes token refreshes when requests run concurrently.
+	mu sync.Mutex
 }
 
 func New() *Client {
@@ -52,6 +55,8 @@ func (c *Client) ensureFreshToken() error {
 	if config.AuthMode() == "api_key" {
 		return nil
 	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
 	if c.refreshing {
 		return nil
 	}
@@ -108,6 +113,15 @@ func (c *Client) ensureFreshToken() error {
 	return nil
 }
 
+// StatusError is an HTTP error status returned by a fetch helper, so
+// callers can tell a server without the endpoint (404) from a failed
+// request.
+type StatusError struct {
+	Code int
+}
+
+func (e *StatusError) Error() string { return fmt.Sprintf("request failed: status %d", e.Code) }
+
 func (c *Client) Get(path string, out any) (*resty.Response, error) {
 	if err := c.ensureFreshToken(); err != nil {
 		return nil, err
@@ -0,0 +1,67 @@
+package api
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/we-promise/sure-cli/internal/models"
+)
+
+// FetchHoldings pulls every holding matching q (account_id, date, ...) by
+// paging the Sure API.
+func FetchHoldings(client *Client, q url.Values) ([]models.Holding, error) {
+	page := 1
+	var all []models.Holding
+
+	for {
+		pq := url.Values{}
+		for k, v := range q {
+			pq[k] = v
+		}
+		pq.Set("page", fmt.Sprintf("%d", page))
+		pq.Set("per_page", "100")
+		path := "/api/v1/holdings?" + pq.Encode()
+
+		var res map[string]any
+		r, err := client.Get(path, &res)
+		if err != nil {
+			return nil, err
+		}
+		if r.StatusCode() >= 400 {
+			return nil, fmt.Errorf("request failed: status %d", r.StatusCode())
+		}
+
+		items, _ := res["holdings"].([]any)
+		for _, it := range items {
+			m, _ := it.(map[string]any)
+			h := models.Holding{
+				ID:        fmt.Sprint(m["id"]),
+				Ticker:    optString(m["symbol"]),
+				Name:      optString(m["name"]),
+				Date:      optString(m["date"]),
+				Qty:       optString(m["quantity"]),
+				ValueText: optString(m["value"]),
+				Currency:  optString(m["currency"]),
+			}
+			if am, ok := m["account"].(map[string]any); ok {
+				h.AccountID = fmt.Sprint(am["id"])
+			}
+			if sm, ok := m["security"].(map[string]any); ok {
+				h.SecurityID = fmt.Sprint(sm["id"])
+			}
+			all = append(all, h)
+		}
+
+		pg, _ := res["pagination"].(map[string]any)
+		if pg == nil {
+			break
+		}
+		totalPages := asInt(pg["total_pages"])
+		if totalPages <= 0 || page >= totalPages {
+			break
+		}
+		page++
+	}
+
+	return all, nil
+}
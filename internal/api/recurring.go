@@ -0,0 +1,75 @@
+package api
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/we-promise/sure-cli/internal/models"
+)
+
+// FetchRecurringTransactions pulls every recurring transaction matching q
+// (status, account_id) by paging the Sure API.
+func FetchRecurringTransactions(client *Client, q url.Values) ([]models.RecurringTransaction, error) {
+	page := 1
+	var all []models.RecurringTransaction
+
+	for {
+		pq := url.Values{}
+		for k, v := range q {
+			pq[k] = v
+		}
+		pq.Set("page", fmt.Sprintf("%d", page))
+		pq.Set("per_page", "100")
+		path := "/api/v1/recurring_transactions?" + pq.Encode()
+
+		var res map[string]any
+		r, err := client.Get(path, &res)
+		if err != nil {
+			return nil, err
+		}
+		if r.StatusCode() >= 400 {
+			return nil, fmt.Errorf("request failed: status %d", r.StatusCode())
+		}
+
+		items, _ := res["recurring_transactions"].([]any)
+		for _, it := range items {
+			m, _ := it.(map[string]any)
+			rt := models.RecurringTransaction{
+				ID:                 fmt.Sprint(m["id"]),
+				Name:               optString(m["name"]),
+				AmountText:         optString(m["amount"]),
+				Currency:           optString(m["currency"]),
+				ExpectedDayOfMonth: asInt(m["expected_day_of_month"]),
+				LastOccurrenceDate: optString(m["last_occurrence_date"]),
+				NextExpectedDate:   optString(m["next_expected_date"]),
+				Status:             optString(m["status"]),
+				OccurrenceCount:    asInt(m["occurrence_count"]),
+				ExpectedMinText:    optString(m["expected_amount_min"]),
+				ExpectedMaxText:    optString(m["expected_amount_max"]),
+				ExpectedAvgText:    optString(m["expected_amount_avg"]),
+			}
+			rt.Manual, _ = m["manual"].(bool)
+			if am, ok := m["account"].(map[string]any); ok {
+				rt.AccountID = fmt.Sprint(am["id"])
+				rt.AccountName = fmt.Sprint(am["name"])
+			}
+			if mm, ok := m["merchant"].(map[string]any); ok {
+				rt.MerchantID = fmt.Sprint(mm["id"])
+				rt.MerchantName = fmt.Sprint(mm["name"])
+			}
+			all = append(all, rt)
+		}
+
+		pg, _ := res["pagination"].(map[string]any)
+		if pg == nil {
+			break
+		}
+		totalPages := asInt(pg["total_pages"])
+		if totalPages <= 0 || page >= totalPages {
+			break
+		}
+		page++
+	}
+
+	return all, nil
+}
@@ -0,0 +1,52 @@
+package api
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/we-promise/sure-cli/internal/models"
+)
+
+// FetchTags pulls every tag by paging the Sure API.
+func FetchTags(client *Client) ([]models.Tag, error) {
+	page := 1
+	var all []models.Tag
+
+	for {
+		q := url.Values{}
+		q.Set("page", fmt.Sprintf("%d", page))
+		q.Set("per_page", "100")
+		path := "/api/v1/tags?" + q.Encode()
+
+		var res map[string]any
+		r, err := client.Get(path, &res)
+		if err != nil {
+			return nil, err
+		}
+		if r.StatusCode() >= 400 {
+			return nil, fmt.Errorf("request failed: status %d", r.StatusCode())
+		}
+
+		items, _ := res["tags"].([]any)
+		for _, it := range items {
+			m, _ := it.(map[string]any)
+			all = append(all, models.Tag{
+				ID:    fmt.Sprint(m["id"]),
+				Name:  fmt.Sprint(m["name"]),
+				Color: optString(m["color"]),
+			})
+		}
+
+		pg, _ := res["pagination"].(map[string]any)
+		if pg == nil {
+			break
+		}
+		totalPages := asInt(pg["total_pages"])
+		if totalPages <= 0 || page >= totalPages {
+			break
+		}
+		page++
+	}
+
+	return all, nil
+}
@@ -11,6 +11,15 @@ import (
 // FetchTransactionsWindow pulls all transactions within [start,end] by paging the Sure API.
 // It returns an agent-friendly typed slice (no map[string]any).
 func FetchTransactionsWindow(client *Client, start, end time.Time, perPage int) ([]models.Transaction, error) {
+	q := url.Values{}
+	q.Set("start_date", start.Format("2006-01-02"))
+	q.Set("end_date", end.Format("2006-01-02"))
+	return FetchTransactions(client, q, perPage)
+}
+
+// FetchTransactions pulls every transaction matching q (the transactions
+// list filters) by paging the Sure API.
+func FetchTransactions(client *Client, q url.Values, perPage int) ([]models.Transaction, error) {
 	if perPage <= 0 {
 		perPage = 100
 	}
@@ -19,12 +28,13 @@ func FetchTransactionsWindow(client *Client, start, end time.Time, perPage int)
 	var all []models.Transaction
 
 	for {
-		q := url.Values{}
-		q.Set("page", fmt.Sprintf("%d", page))
-		q.Set("per_page", fmt.Sprintf("%d", perPage))
-		q.Set("start_date", start.Format("2006-01-02"))
-		q.Set("end_date", end.Format("2006-01-02"))
-		path := "/api/v1/transactions?" + q.Encode()
+		pq := url.Values{}
+		for k, v := range q {
+			pq[k] = v
+		}
+		pq.Set("page", fmt.Sprintf("%d", page))
+		pq.Set("per_page", fmt.Sprintf("%d", perPage))
+		path := "/api/v1/transactions?" + pq.Encode()
 
 		var res map[string]any
 		r, err := client.Get(path, &res)
@@ -38,29 +48,7 @@ func FetchTransactionsWindow(client *Client, start, end time.Time, perPage int)
 		items, _ := res["transactions"].([]any)
 		for _, it := range items {
 			m, _ := it.(map[string]any)
-			tx := models.Transaction{
-				ID:             fmt.Sprint(m["id"]),
-				Name:           fmt.Sprint(m["name"]),
-				Classification: fmt.Sprint(m["classification"]),
-				AmountText:     fmt.Sprint(m["amount"]),
-				Currency:       fmt.Sprint(m["currency"]),
-			}
-			if d, ok := m["date"].(string); ok {
-				if tt, err := time.Parse("2006-01-02", d); err == nil {
-					tx.Date = tt
-				}
-			}
-			if am, ok := m["account"].(map[string]any); ok {
-				tx.AccountName = fmt.Sprint(am["name"])
-			}
-			if cm, ok := m["category"].(map[string]any); ok {
-				tx.CategoryName = fmt.Sprint(cm["name"])
-				tx.CategoryID = fmt.Sprint(cm["id"])
-			}
-			if mm, ok := m["merchant"].(map[string]any); ok {
-				tx.MerchantName = fmt.Sprint(mm["name"])
-			}
-			all = append(all, tx)
+			all = append(all, ParseTransaction(m))
 		}
 
 		pg, _ := res["pagination"].(map[string]any)
@@ -77,6 +65,48 @@ func FetchTransactionsWindow(client *Client, start, end time.Time, perPage int)
 	return all, nil
 }
 
+// ParseTransaction maps one transaction of Sure's JSON to the typed view.
+func ParseTransaction(m map[string]any) models.Transaction {
+	tx := models.Transaction{
+		ID:             fmt.Sprint(m["id"]),
+		Name:           fmt.Sprint(m["name"]),
+		Classification: fmt.Sprint(m["classification"]),
+		AmountText:     fmt.Sprint(m["amount"]),
+		Currency:       fmt.Sprint(m["currency"]),
+		Notes:          optString(m["notes"]),
+		Kind:           optString(m["kind"]),
+	}
+	if d, ok := m["date"].(string); ok {
+		if tt, err := time.Parse("2006-01-02", d); err == nil {
+			tx.Date = tt
+		}
+	}
+	if am, ok := m["account"].(map[string]any); ok {
+		tx.AccountID = fmt.Sprint(am["id"])
+		tx.AccountName = fmt.Sprint(am["name"])
+	}
+	if cm, ok := m["category"].(map[string]any); ok {
+		tx.CategoryName = fmt.Sprint(cm["name"])
+		tx.CategoryID = fmt.Sprint(cm["id"])
+	}
+	if mm, ok := m["merchant"].(map[string]any); ok {
+		tx.MerchantID = fmt.Sprint(mm["id"])
+		tx.MerchantName = fmt.Sprint(mm["name"])
+	}
+	if tags, ok := m["tags"].([]any); ok {
+		for _, t := range tags {
+			if tm, ok := t.(map[string]any); ok {
+				tx.TagIDs = append(tx.TagIDs, fmt.Sprint(tm["id"]))
+				tx.TagNames = append(tx.TagNames, fmt.Sprint(tm["name"]))
+			}
+		}
+	}
+	if tm, ok := m["transfer"].(map[string]any); ok {
+		tx.TransferID = optString(tm["id"])
+	}
+	return tx
+}
+
 func asInt(v any) int {
 	switch t := v.(type) {
 	case float64:
@@ -0,0 +1,81 @@
+package api
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/we-promise/sure-cli/internal/models"
+)
+
+// FetchTransfers pulls every transfer matching q (status, account_id,
+// start_date, end_date) by paging the Sure API.
+func FetchTransfers(client *Client, q url.Values) ([]models.Transfer, error) {
+	return fetchTransferList(client, "/api/v1/transfers", "transfers", q)
+}
+
+// FetchRejectedTransfers pulls every rejected transfer suggestion matching
+// q (account_id, start_date, end_date).
+func FetchRejectedTransfers(client *Client, q url.Values) ([]models.Transfer, error) {
+	return fetchTransferList(client, "/api/v1/rejected_transfers", "rejected_transfers", q)
+}
+
+func fetchTransferList(client *Client, base, key string, q url.Values) ([]models.Transfer, error) {
+	page := 1
+	var all []models.Transfer
+
+	for {
+		pq := url.Values{}
+		for k, v := range q {
+			pq[k] = v
+		}
+		pq.Set("page", fmt.Sprintf("%d", page))
+		pq.Set("per_page", "100")
+		path := base + "?" + pq.Encode()
+
+		var res map[string]any
+		r, err := client.Get(path, &res)
+		if err != nil {
+			return nil, err
+		}
+		if r.StatusCode() >= 400 {
+			return nil, &StatusError{Code: r.StatusCode()}
+		}
+
+		items, _ := res[key].([]any)
+		for _, it := range items {
+			m, _ := it.(map[string]any)
+			all = append(all, parseTransfer(m))
+		}
+
+		pg, _ := res["pagination"].(map[string]any)
+		if pg == nil {
+			break
+		}
+		totalPages := asInt(pg["total_pages"])
+		if totalPages <= 0 || page >= totalPages {
+			break
+		}
+		page++
+	}
+
+	return all, nil
+}
+
+// parseTransfer accepts the legs as nested transactions or as *_id fields.
+func parseTransfer(m map[string]any) models.Transfer {
+	t := models.Transfer{
+		ID:                   fmt.Sprint(m["id"]),
+		Status:               optString(m["status"]),
+		Date:                 optString(m["date"]),
+		AmountText:           optString(m["amount"]),
+		OutflowTransactionID: optString(m["outflow_transaction_id"]),
+		InflowTransactionID:  optString(m["inflow_transaction_id"]),
+	}
+	if tm, ok := m["outflow_transaction"].(map[string]any); ok {
+		t.OutflowTransactionID = optString(tm["id"])
+	}
+	if tm, ok := m["inflow_transaction"].(map[string]any); ok {
+		t.InflowTransactionID = optString(tm["id"])
+	}
+	return t
+}
@@ -44,13 +44,32 @@ func Init(cfgFile string) error {
 	viper.SetDefault("heuristics.subscriptions.period_max_days", 40)
 	viper.SetDefault("heuristics.subscriptions.weekly_min_days", 6)
 	viper.SetDefault("heuristics.subscriptions.weekly_max_days", 9)
+	viper.SetDefault("heuristics.subscriptions.biweekly_min_days", 12)
+	viper.SetDefault("heuristics.subscriptions.biweekly_max_days", 16)
+	viper.SetDefault("heuristics.subscriptions.quarterly_min_days", 80)
+	viper.SetDefault("heuristics.subscriptions.quarterly_max_days", 100)
+	viper.SetDefault("heuristics.subscriptions.yearly_min_days", 350)
+	viper.SetDefault("heuristics.subscriptions.yearly_max_days", 380)
+	viper.SetDefault("heuristics.subscriptions.min_occurrences", 3)
+	viper.SetDefault("heuristics.subscriptions.yearly_min_occurrences", 2)
 	viper.SetDefault("heuristics.subscriptions.stddev_max_days", 3.0)
 	viper.SetDefault("heuristics.subscriptions.amount_stddev_ratio", 0.1)
+	viper.SetDefault("heuristics.subscriptions.price_change_min_pct", 5.0)
 	viper.SetDefault("heuristics.leaks.min_count", 3)
 	viper.SetDefault("heuristics.leaks.min_total", 15.0)
 	viper.SetDefault("heuristics.leaks.max_avg", 10.0)
 	viper.SetDefault("heuristics.rules.min_consistency", 0.7)
 	viper.SetDefault("heuristics.rules.min_occurrences", 2)
+	viper.SetDefault("heuristics.rules.max_proposals", 20)
+	viper.SetDefault("heuristics.anomalies.z_threshold", 3.5)
+	viper.SetDefault("heuristics.anomalies.min_ratio", 1.5)
+	viper.SetDefault("heuristics.anomalies.min_history", 5)
+	viper.SetDefault("heuristics.anomalies.new_merchant_min_amount", 100.0)
+	viper.SetDefault("heuristics.duplicates.window_days", 3)
+	viper.SetDefault("heuristics.duplicates.min_similarity", 0.6)
+	viper.SetDefault("heuristics.duplicates.habitual_count", 4)
+	viper.SetDefault("heuristics.transfers.window_days", 3)
+	viper.SetDefault("heuristics.merchants.aliases", []map[string]string{}) // [{pattern, name}]
 
 	if cfgFile != "" {
 		viper.SetConfigFile(cfgFile)
@@ -102,3 +121,20 @@ func APIURL() string { return viper.GetString("api_url") }
 func AuthMode() string { return viper.GetString("auth.mode") }
 func Token() string    { return viper.GetString("auth.token") }
 func APIKey() string   { return viper.GetString("auth.api_key") }
+
+// JournalPath is the file `history` and `undo` read applied writes from:
+// journal.path when set, else journal.jsonl next to the config file.
+func JournalPath() (string, error) {
+	if p := viper.GetString("journal.path"); p != "" {
+		return p, nil
+	}
+	cfgFile := viper.ConfigFileUsed()
+	if cfgFile == "" {
+		path, err := defaultConfigPath()
+		if err != nil {
+			return "", err
+		}
+		cfgFile = path
+	}
+	return filepath.Join(filepath.Dir(cfgFile), "journal.jsonl"), nil
+}
@@ -48,3 +48,62 @@ func TestSave_CreatesParentDirAndWritesFile(t *testing.T) {
 		t.Fatalf("api_url mismatch: got %q", got)
 	}
 }
+
+func TestGetMerchantAliases_FromYAML(t *testing.T) {
+	viper.Reset()
+
+	cfg := filepath.Join(t.TempDir(), "config.yaml")
+	yaml := "heuristics:\n  merchants:\n    aliases:\n      - pattern: \"^amzn\"\n        name: Amazon\n"
+	if err := os.WriteFile(cfg, []byte(yaml), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := Init(cfg); err != nil {
+		t.Fatalf("init failed: %v", err)
+	}
+
+	got, err := GetMerchantAliases()
+	if err != nil || len(got) != 1 || got[0].Pattern != "^amzn" || got[0].Name != "Amazon" {
+		t.Fatalf("unexpected aliases: %+v, %v", got, err)
+	}
+
+	// A map instead of a list is reported, not read as "no aliases".
+	viper.Set("heuristics.merchants.aliases", map[string]any{"amzn": "Amazon"})
+	if got, err := GetMerchantAliases(); err == nil {
+		t.Fatalf("malformed aliases = %+v, want an error", got)
+	}
+	viper.Set("heuristics.merchants.aliases", []any{"^amzn"})
+	if got, err := GetMerchantAliases(); err == nil {
+		t.Fatalf("string entry = %+v, want an error", got)
+	}
+}
+
+func TestGetDebts(t *testing.T) {
+	viper.Reset()
+	viper.Set("plan.debts", []map[string]any{{"account_id": "acc_card", "apr": 19.9, "min_payment": 50}})
+	got := GetDebts()
+	if len(got) != 1 || got[0].AccountID != "acc_card" || got[0].APR != 19.9 || got[0].MinPayment != 50 {
+		t.Fatalf("GetDebts = %+v", got)
+	}
+}
+
+func TestSaveGoals_RoundTrip(t *testing.T) {
+	viper.Reset()
+	cfg := filepath.Join(t.TempDir(), "config.yaml")
+	viper.SetConfigFile(cfg)
+
+	want := []Goal{
+		{ID: "car", Name: "Car", Target: 15000, TargetDate: "2027-06-30", AccountIDs: []string{"acc_savings"}, CreatedAt: "2026-06-30"},
+		{ID: "emergency-fund", Name: "Emergency fund", TargetMonths: 6, AccountIDs: []string{"acc_savings", "acc_checking"}, CreatedAt: "2026-06-30"},
+	}
+	if err := SaveGoals(want); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	viper.Reset()
+	if err := Init(cfg); err != nil {
+		t.Fatalf("init: %v", err)
+	}
+	got := GetGoals()
+	if len(got) != 2 || got[0].Target != 15000 || got[0].TargetDate != "2027-06-30" || got[1].TargetMonths != 6 || len(got[1].AccountIDs) != 2 {
+		t.Fatalf("GetGoals after reload = %+v", got)
+	}
+}
@@ -1,6 +1,11 @@
 package config
 
-import "github.com/spf13/viper"
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/spf13/viper"
+)
 
 // HeuristicsConfig holds all configurable heuristic parameters.
 type HeuristicsConfig struct {
@@ -8,19 +13,33 @@ type HeuristicsConfig struct {
 	Subscriptions SubscriptionsConfig `json:"subscriptions"`
 	Leaks         LeaksConfig         `json:"leaks"`
 	Rules         RulesConfig         `json:"rules"`
+	Anomalies     AnomaliesConfig     `json:"anomalies"`
+	Duplicates    DuplicatesConfig    `json:"duplicates"`
+	Transfers     TransfersConfig     `json:"transfers"`
 }
 
 type FeesConfig struct {
 	Keywords []string `json:"keywords"`
 }
 
+// SubscriptionsConfig holds the cadence bands and stability thresholds.
+// PeriodMinDays/PeriodMaxDays are the monthly band (the original key names).
 type SubscriptionsConfig struct {
-	PeriodMinDays     float64 `json:"period_min_days"`
-	PeriodMaxDays     float64 `json:"period_max_days"`
-	WeeklyMinDays     float64 `json:"weekly_min_days"`
-	WeeklyMaxDays     float64 `json:"weekly_max_days"`
-	StddevMaxDays     float64 `json:"stddev_max_days"`
-	AmountStddevRatio float64 `json:"amount_stddev_ratio"`
+	PeriodMinDays        float64 `json:"period_min_days"`
+	PeriodMaxDays        float64 `json:"period_max_days"`
+	WeeklyMinDays        float64 `json:"weekly_min_days"`
+	WeeklyMaxDays        float64 `json:"weekly_max_days"`
+	BiweeklyMinDays      float64 `json:"biweekly_min_days"`
+	BiweeklyMaxDays      float64 `json:"biweekly_max_days"`
+	QuarterlyMinDays     float64 `json:"quarterly_min_days"`
+	QuarterlyMaxDays     float64 `json:"quarterly_max_days"`
+	YearlyMinDays        float64 `json:"yearly_min_days"`
+	YearlyMaxDays        float64 `json:"yearly_max_days"`
+	MinOccurrences       int     `json:"min_occurrences"`
+	YearlyMinOccurrences int     `json:"yearly_min_occurrences"`
+	StddevMaxDays        float64 `json:"stddev_max_days"`
+	AmountStddevRatio    float64 `json:"amount_stddev_ratio"`
+	PriceChangeMinPct    float64 `json:"price_change_min_pct"`
 }
 
 type LeaksConfig struct {
@@ -32,6 +51,40 @@ type LeaksConfig struct {
 type RulesConfig struct {
 	MinConsistency float64 `json:"min_consistency"`
 	MinOccurrences int     `json:"min_occurrences"`
+	MaxProposals   int     `json:"max_proposals"`
+}
+
+type AnomaliesConfig struct {
+	ZThreshold           float64 `json:"z_threshold"`
+	MinRatio             float64 `json:"min_ratio"`
+	MinHistory           int     `json:"min_history"`
+	NewMerchantMinAmount float64 `json:"new_merchant_min_amount"`
+}
+
+type DuplicatesConfig struct {
+	WindowDays    int     `json:"window_days"`
+	MinSimilarity float64 `json:"min_similarity"`
+	HabitualCount int     `json:"habitual_count"`
+}
+
+// TransfersConfig tunes how transfers between own accounts are matched
+// when Sure has not linked them.
+type TransfersConfig struct {
+	WindowDays int `json:"window_days"`
+}
+
+// MerchantsConfig holds user-defined merchant aliases applied before the
+// built-in name normalization. It is read on its own by GetMerchantAliases,
+// which can fail.
+type MerchantsConfig struct {
+	Aliases []MerchantAlias `json:"aliases"`
+}
+
+// MerchantAlias maps descriptors matching Pattern (case-insensitive regexp)
+// to the merchant Name.
+type MerchantAlias struct {
+	Pattern string `json:"pattern" mapstructure:"pattern"`
+	Name    string `json:"name" mapstructure:"name"`
 }
 
 // GetHeuristics returns the current heuristics configuration.
@@ -41,12 +94,21 @@ func GetHeuristics() HeuristicsConfig {
 			Keywords: viper.GetStringSlice("heuristics.fees.keywords"),
 		},
 		Subscriptions: SubscriptionsConfig{
-			PeriodMinDays:     viper.GetFloat64("heuristics.subscriptions.period_min_days"),
-			PeriodMaxDays:     viper.GetFloat64("heuristics.subscriptions.period_max_days"),
-			WeeklyMinDays:     viper.GetFloat64("heuristics.subscriptions.weekly_min_days"),
-			WeeklyMaxDays:     viper.GetFloat64("heuristics.subscriptions.weekly_max_days"),
-			StddevMaxDays:     viper.GetFloat64("heuristics.subscriptions.stddev_max_days"),
-			AmountStddevRatio: viper.GetFloat64("heuristics.subscriptions.amount_stddev_ratio"),
+			PeriodMinDays:        viper.GetFloat64("heuristics.subscriptions.period_min_days"),
+			PeriodMaxDays:        viper.GetFloat64("heuristics.subscriptions.period_max_days"),
+			WeeklyMinDays:        viper.GetFloat64("heuristics.subscriptions.weekly_min_days"),
+			WeeklyMaxDays:        viper.GetFloat64("heuristics.subscriptions.weekly_max_days"),
+			BiweeklyMinDays:      viper.GetFloat64("heuristics.subscriptions.biweekly_min_days"),
+			BiweeklyMaxDays:      viper.GetFloat64("heuristics.subscriptions.biweekly_max_days"),
+			QuarterlyMinDays:     viper.GetFloat64("heuristics.subscriptions.quarterly_min_days"),
+			QuarterlyMaxDays:     viper.GetFloat64("heuristics.subscriptions.quarterly_max_days"),
+			YearlyMinDays:        viper.GetFloat64("heuristics.subscriptions.yearly_min_days"),
+			YearlyMaxDays:        viper.GetFloat64("heuristics.subscriptions.yearly_max_days"),
+			MinOccurrences:       viper.GetInt("heuristics.subscriptions.min_occurrences"),
+			YearlyMinOccurrences: viper.GetInt("heuristics.subscriptions.yearly_min_occurrences"),
+			StddevMaxDays:        viper.GetFloat64("heuristics.subscriptions.stddev_max_days"),
+			AmountStddevRatio:    viper.GetFloat64("heuristics.subscriptions.amount_stddev_ratio"),
+			PriceChangeMinPct:    viper.GetFloat64("heuristics.subscriptions.price_change_min_pct"),
 		},
 		Leaks: LeaksConfig{
 			MinCount: viper.GetInt("heuristics.leaks.min_count"),
@@ -56,10 +118,54 @@ func GetHeuristics() HeuristicsConfig {
 		Rules: RulesConfig{
 			MinConsistency: viper.GetFloat64("heuristics.rules.min_consistency"),
 			MinOccurrences: viper.GetInt("heuristics.rules.min_occurrences"),
+			MaxProposals:   viper.GetInt("heuristics.rules.max_proposals"),
+		},
+		Anomalies: AnomaliesConfig{
+			ZThreshold:           viper.GetFloat64("heuristics.anomalies.z_threshold"),
+			MinRatio:             viper.GetFloat64("heuristics.anomalies.min_ratio"),
+			MinHistory:           viper.GetInt("heuristics.anomalies.min_history"),
+			NewMerchantMinAmount: viper.GetFloat64("heuristics.anomalies.new_merchant_min_amount"),
+		},
+		Duplicates: DuplicatesConfig{
+			WindowDays:    viper.GetInt("heuristics.duplicates.window_days"),
+			MinSimilarity: viper.GetFloat64("heuristics.duplicates.min_similarity"),
+			HabitualCount: viper.GetInt("heuristics.duplicates.habitual_count"),
+		},
+		Transfers: TransfersConfig{
+			WindowDays: viper.GetInt("heuristics.transfers.window_days"),
 		},
 	}
 }
 
+// GetMerchantAliases returns configured merchant aliases. A value that is
+// not a list of pattern/name entries is an error; invalid patterns are
+// rejected when the normalizer is built.
+func GetMerchantAliases() ([]MerchantAlias, error) {
+	const key = "heuristics.merchants.aliases"
+	aliases := []MerchantAlias{}
+	// Viper decodes weakly (a single map becomes a one-entry list), so
+	// check the shape first.
+	if raw := viper.Get(key); raw != nil {
+		list := reflect.ValueOf(raw)
+		if list.Kind() != reflect.Slice {
+			return nil, fmt.Errorf("%s: want a list of {pattern, name} entries, got %T", key, raw)
+		}
+		for i := 0; i < list.Len(); i++ {
+			item := list.Index(i)
+			if item.Kind() == reflect.Interface {
+				item = item.Elem()
+			}
+			if item.Kind() != reflect.Map {
+				return nil, fmt.Errorf("%s: entry %d: want {pattern, name}, got %v", key, i, item.Kind())
+			}
+		}
+	}
+	if err := viper.UnmarshalKey(key, &aliases); err != nil {
+		return nil, fmt.Errorf("%s: %w", key, err)
+	}
+	return aliases, nil
+}
+
 // GetFeeKeywords returns configured fee keywords or empty slice (caller should use defaults).
 func GetFeeKeywords() []string {
 	return viper.GetStringSlice("heuristics.fees.keywords")
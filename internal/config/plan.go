@@ -0,0 +1,66 @@
+package config
+
+import "github.com/spf13/viper"
+
+// DebtConfig holds the loan terms Sure does not track for a liability
+// account: APR (percent, e.g. 19.9) and the monthly minimum payment.
+type DebtConfig struct {
+	AccountID  string  `json:"account_id" mapstructure:"account_id"`
+	APR        float64 `json:"apr" mapstructure:"apr"`
+	MinPayment float64 `json:"min_payment" mapstructure:"min_payment"`
+}
+
+// GetDebts returns the configured debts (plan.debts); malformed entries
+// decode as empty.
+func GetDebts() []DebtConfig {
+	debts := []DebtConfig{}
+	_ = viper.UnmarshalKey("plan.debts", &debts)
+	return debts
+}
+
+// GetLiquidityTiers returns the runway tier overrides
+// (plan.liquidity_tiers): account id, subtype or account type to
+// cash|near_cash|illiquid|exclude. Keys are lower-cased by viper.
+func GetLiquidityTiers() map[string]string {
+	return viper.GetStringMapString("plan.liquidity_tiers")
+}
+
+// Goal is a named savings goal tracked against linked account balances.
+// Either Target (an amount) or TargetMonths (months of average spend) is
+// set; TargetDate (YYYY-MM-DD) is optional.
+type Goal struct {
+	ID           string   `json:"id" mapstructure:"id"`
+	Name         string   `json:"name" mapstructure:"name"`
+	Target       float64  `json:"target,omitempty" mapstructure:"target"`
+	TargetMonths float64  `json:"target_months,omitempty" mapstructure:"target_months"`
+	TargetDate   string   `json:"target_date,omitempty" mapstructure:"target_date"`
+	AccountIDs   []string `json:"account_ids" mapstructure:"account_ids"`
+	CreatedAt    string   `json:"created_at" mapstructure:"created_at"`
+}
+
+// GetGoals returns the configured savings goals (goals).
+func GetGoals() []Goal {
+	goals := []Goal{}
+	_ = viper.UnmarshalKey("goals", &goals)
+	return goals
+}
+
+// SaveGoals replaces the configured goals and writes the config file.
+func SaveGoals(goals []Goal) error {
+	list := make([]map[string]any, 0, len(goals))
+	for _, g := range goals {
+		m := map[string]any{"id": g.ID, "name": g.Name, "account_ids": g.AccountIDs, "created_at": g.CreatedAt}
+		if g.Target > 0 {
+			m["target"] = g.Target
+		}
+		if g.TargetMonths > 0 {
+			m["target_months"] = g.TargetMonths
+		}
+		if g.TargetDate != "" {
+			m["target_date"] = g.TargetDate
+		}
+		list = append(list, m)
+	}
+	viper.Set("goals", list)
+	return Save()
+}
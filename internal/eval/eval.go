@@ -0,0 +1,165 @@
+// Package eval scores the insights detectors and the rule proposer against
+// ground-truth labels (as produced by `dev seed`), so threshold changes can be
+// judged by precision and recall instead of by eye.
+package eval
+
+import (
+	"sort"
+
+	"github.com/we-promise/sure-cli/internal/insights"
+	"github.com/we-promise/sure-cli/internal/models"
+	"github.com/we-promise/sure-cli/internal/rules"
+	"github.com/we-promise/sure-cli/internal/synth"
+)
+
+// Options carries the detector parameters used for the run. Zero values use
+// each detector's defaults.
+type Options struct {
+	Subscriptions insights.SubscriptionOptions `json:"subscriptions"`
+	Fees          insights.FeeOptions          `json:"fees"`
+	Leaks         insights.LeakOptions         `json:"leaks"`
+	Rules         rules.Options                `json:"rules"`
+}
+
+// DetectorReport compares one detector's predictions with the labels. Items
+// are identified by transaction name (detectors group by name); rule items are
+// "pattern => category".
+type DetectorReport struct {
+	Detector       string   `json:"detector"`
+	Expected       int      `json:"expected"`
+	Predicted      int      `json:"predicted"`
+	Precision      float64  `json:"precision"`
+	Recall         float64  `json:"recall"`
+	F1             float64  `json:"f1"`
+	TruePositives  []string `json:"true_positives"`
+	FalsePositives []string `json:"false_positives"`
+	FalseNegatives []string `json:"false_negatives"`
+}
+
+// Report is the result of Run.
+type Report struct {
+	TransactionCount int              `json:"transaction_count"`
+	Detectors        []DetectorReport `json:"detectors"`
+}
+
+// Run executes every detector over txs and scores it against labels.
+func Run(txs []models.Transaction, labels *synth.Labels, opts Options) Report {
+	var subsPred, pricePred, feesPred, leaksPred, rulesPred []string
+	for _, c := range insights.DetectSubscriptionsWithOptions(txs, opts.Subscriptions) {
+		subsPred = append(subsPred, c.Name)
+		for _, pc := range c.PriceChanges {
+			pricePred = append(pricePred, priceKey(c.Name, pc.Date))
+		}
+	}
+	for _, c := range insights.DetectFeesWithOptions(txs, opts.Fees) {
+		feesPred = append(feesPred, c.Name)
+	}
+	for _, c := range insights.DetectLeaksWithOptions(txs, opts.Leaks) {
+		leaksPred = append(leaksPred, c.Name)
+	}
+	for _, p := range rules.ProposeRulesWithOptions(txs, opts.Rules).Proposals {
+		rulesPred = append(rulesPred, ruleKey(p.Pattern, p.Value))
+	}
+
+	var subsExp, priceExp, feesExp, leaksExp, rulesExp []string
+	for _, s := range labels.Subscriptions {
+		subsExp = append(subsExp, s.Name)
+		for _, pc := range s.PriceChanges {
+			priceExp = append(priceExp, priceKey(s.Name, pc.Date))
+		}
+	}
+	for _, f := range labels.Fees {
+		feesExp = append(feesExp, f.Name)
+	}
+	for _, l := range labels.Leaks {
+		leaksExp = append(leaksExp, l.Name)
+	}
+	for _, r := range labels.Rules {
+		rulesExp = append(rulesExp, ruleKey(r.Pattern, r.Category))
+	}
+
+	return Report{
+		TransactionCount: len(txs),
+		Detectors: []DetectorReport{
+			Score("subscriptions", subsPred, subsExp),
+			Score("price_changes", pricePred, priceExp),
+			Score("fees", feesPred, feesExp),
+			Score("leaks", leaksPred, leaksExp),
+			Score("rules", rulesPred, rulesExp),
+		},
+	}
+}
+
+// priceKey matches price changes by name and month, since the first charge
+// at a new price can shift by a few days.
+func priceKey(name, date string) string {
+	if len(date) >= 7 {
+		date = date[:7]
+	}
+	return name + " @ " + date
+}
+
+func ruleKey(pattern, category string) string {
+	return pattern + " => " + category
+}
+
+// Score computes the confusion lists and metrics for one detector. With
+// nothing expected, recall is 1; with nothing predicted, precision is 1 only
+// if nothing was expected either.
+func Score(detector string, predicted, expected []string) DetectorReport {
+	pred := set(predicted)
+	exp := set(expected)
+
+	r := DetectorReport{
+		Detector:       detector,
+		Expected:       len(exp),
+		Predicted:      len(pred),
+		TruePositives:  []string{},
+		FalsePositives: []string{},
+		FalseNegatives: []string{},
+	}
+	for k := range pred {
+		if exp[k] {
+			r.TruePositives = append(r.TruePositives, k)
+		} else {
+			r.FalsePositives = append(r.FalsePositives, k)
+		}
+	}
+	for k := range exp {
+		if !pred[k] {
+			r.FalseNegatives = append(r.FalseNegatives, k)
+		}
+	}
+	sort.Strings(r.TruePositives)
+	sort.Strings(r.FalsePositives)
+	sort.Strings(r.FalseNegatives)
+
+	tp := float64(len(r.TruePositives))
+	switch {
+	case r.Predicted > 0:
+		r.Precision = round3(tp / float64(r.Predicted))
+	case r.Expected == 0:
+		r.Precision = 1
+	}
+	if r.Expected > 0 {
+		r.Recall = round3(tp / float64(r.Expected))
+	} else {
+		r.Recall = 1
+	}
+	if r.Precision+r.Recall > 0 {
+		r.F1 = round3(2 * r.Precision * r.Recall / (r.Precision + r.Recall))
+	}
+	return r
+}
+
+func set(xs []string) map[string]bool {
+	m := make(map[string]bool, len(xs))
+	for _, x := range xs {
+		m[x] = true
+	}
+	return m
+}
+
+func round3(v float64) float64 {
+	return float64(int64(v*1000+0.5)) / 1000
+}
@@ -0,0 +1,96 @@
+package eval
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/we-promise/sure-cli/internal/synth"
+)
+
+func TestScore_Confusion(t *testing.T) {
+	r := Score("x", []string{"a", "b", "c"}, []string{"b", "c", "d", "e"})
+	if !reflect.DeepEqual(r.TruePositives, []string{"b", "c"}) ||
+		!reflect.DeepEqual(r.FalsePositives, []string{"a"}) ||
+		!reflect.DeepEqual(r.FalseNegatives, []string{"d", "e"}) {
+		t.Fatalf("confusion = %+v", r)
+	}
+	if r.Precision != 0.667 || r.Recall != 0.5 || r.F1 != 0.572 {
+		t.Fatalf("metrics = p%v r%v f%v", r.Precision, r.Recall, r.F1)
+	}
+}
+
+func TestScore_EmptySets(t *testing.T) {
+	r := Score("x", nil, nil)
+	if r.Precision != 1 || r.Recall != 1 {
+		t.Fatalf("empty/empty = p%v r%v, want 1/1", r.Precision, r.Recall)
+	}
+	r = Score("x", nil, []string{"a"})
+	if r.Precision != 0 || r.Recall != 0 {
+		t.Fatalf("nothing predicted = p%v r%v, want 0/0", r.Precision, r.Recall)
+	}
+}
+
+func household(t *testing.T) *synth.Household {
+	t.Helper()
+	o := synth.DefaultOptions()
+	o.End = time.Date(2026, 6, 30, 0, 0, 0, 0, time.UTC)
+	h, err := synth.Generate(o)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return h
+}
+
+func TestRun_SyntheticHousehold(t *testing.T) {
+	h := household(t)
+	rep := Run(h.ModelTransactions(), &h.Labels, Options{})
+	if rep.TransactionCount != len(h.Transactions) {
+		t.Fatalf("transaction_count = %d", rep.TransactionCount)
+	}
+	byName := map[string]DetectorReport{}
+	for _, d := range rep.Detectors {
+		byName[d.Detector] = d
+	}
+	for _, name := range []string{"subscriptions", "price_changes", "fees", "leaks", "rules"} {
+		if _, ok := byName[name]; !ok {
+			t.Fatalf("missing detector %q", name)
+		}
+	}
+	if byName["fees"].Recall != 1 {
+		t.Fatalf("fees recall = %v, want 1 (missed %v)", byName["fees"].Recall, byName["fees"].FalseNegatives)
+	}
+	if byName["leaks"].Recall != 1 {
+		t.Fatalf("leaks recall = %v, want 1", byName["leaks"].Recall)
+	}
+}
+
+func TestReadNDJSONAndCSV_MatchGenerator(t *testing.T) {
+	h := household(t)
+	want := Run(h.ModelTransactions(), &h.Labels, Options{})
+
+	var nd bytes.Buffer
+	if err := h.WriteNDJSON(&nd); err != nil {
+		t.Fatal(err)
+	}
+	txs, err := ReadNDJSON(&nd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := Run(txs, &h.Labels, Options{}); !reflect.DeepEqual(got, want) {
+		t.Fatalf("ndjson report differs:\n got %+v\nwant %+v", got, want)
+	}
+
+	var csv bytes.Buffer
+	if err := h.WriteCSV(&csv); err != nil {
+		t.Fatal(err)
+	}
+	txs, err = ReadCSV(&csv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(txs) != len(h.Transactions) {
+		t.Fatalf("csv rows = %d, want %d", len(txs), len(h.Transactions))
+	}
+}
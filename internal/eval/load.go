@@ -0,0 +1,175 @@
+package eval
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/we-promise/sure-cli/internal/models"
+)
+
+// LoadTransactions reads a transaction set from a SureImport NDJSON file
+// (.ndjson/.json) or a CSV with date, amount, name, category and account
+// columns where inflows are positive (.csv) — the two formats `dev seed`
+// writes.
+func LoadTransactions(path string) ([]models.Transaction, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		return ReadCSV(f)
+	case ".ndjson", ".json", ".jsonl":
+		return ReadNDJSON(f)
+	default:
+		return nil, fmt.Errorf("unsupported input extension %q (use .ndjson or .csv)", filepath.Ext(path))
+	}
+}
+
+// ReadNDJSON decodes Transaction records from SureImport NDJSON, resolving
+// account, category and merchant names from records earlier in the stream.
+// Amounts follow Sure's entry convention (positive = outflow).
+func ReadNDJSON(r io.Reader) ([]models.Transaction, error) {
+	accounts := map[string]string{}
+	categories := map[string]string{}
+	merchants := map[string]string{}
+	var out []models.Transaction
+
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 64*1024), 4*1024*1024)
+	line := 0
+	for sc.Scan() {
+		line++
+		text := strings.TrimSpace(sc.Text())
+		if text == "" {
+			continue
+		}
+		var rec struct {
+			Type string         `json:"type"`
+			Data map[string]any `json:"data"`
+		}
+		if err := json.Unmarshal([]byte(text), &rec); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		id := str(rec.Data["id"])
+		switch rec.Type {
+		case "Account":
+			accounts[id] = str(rec.Data["name"])
+		case "Category":
+			categories[id] = str(rec.Data["name"])
+		case "Merchant":
+			merchants[id] = str(rec.Data["name"])
+		case "Transaction":
+			cents, err := parseDecimal(str(rec.Data["amount"]))
+			if err != nil {
+				return nil, fmt.Errorf("line %d: amount: %w", line, err)
+			}
+			date, err := time.Parse("2006-01-02", str(rec.Data["date"]))
+			if err != nil {
+				return nil, fmt.Errorf("line %d: date: %w", line, err)
+			}
+			catID := str(rec.Data["category_id"])
+			tx := newTransaction(id, str(rec.Data["name"]), cents, str(rec.Data["currency"]), date)
+			tx.AccountID = str(rec.Data["account_id"])
+			tx.AccountName = accounts[tx.AccountID]
+			tx.CategoryID = catID
+			tx.CategoryName = categories[catID]
+			tx.MerchantID = str(rec.Data["merchant_id"])
+			tx.MerchantName = merchants[tx.MerchantID]
+			out = append(out, tx)
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ReadCSV decodes a header-first CSV. Rows get synthetic ids ("csv_<row>")
+// since TransactionImport CSVs carry none.
+func ReadCSV(r io.Reader) ([]models.Transaction, error) {
+	rows, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
+	col := map[string]int{}
+	for i, h := range rows[0] {
+		col[strings.ToLower(strings.TrimSpace(h))] = i
+	}
+	for _, req := range []string{"date", "amount", "name"} {
+		if _, ok := col[req]; !ok {
+			return nil, fmt.Errorf("csv missing %q column", req)
+		}
+	}
+	get := func(row []string, name string) string {
+		if i, ok := col[name]; ok && i < len(row) {
+			return row[i]
+		}
+		return ""
+	}
+
+	var out []models.Transaction
+	for i, row := range rows[1:] {
+		date, err := time.Parse("2006-01-02", get(row, "date"))
+		if err != nil {
+			return nil, fmt.Errorf("row %d: date: %w", i+2, err)
+		}
+		cents, err := parseDecimal(get(row, "amount"))
+		if err != nil {
+			return nil, fmt.Errorf("row %d: amount: %w", i+2, err)
+		}
+		// Inflows are positive in the CSV; flip to Sure's entry convention.
+		tx := newTransaction("csv_"+strconv.Itoa(i+1), get(row, "name"), -cents, "", date)
+		tx.AccountName = get(row, "account")
+		tx.CategoryName = get(row, "category")
+		out = append(out, tx)
+	}
+	return out, nil
+}
+
+// newTransaction builds the API-shaped view: Classification from the entry
+// sign and AmountText formatted like Sure's JSON ("12.50" / "-2800.00").
+func newTransaction(id, name string, cents int64, currency string, date time.Time) models.Transaction {
+	class := "expense"
+	if cents < 0 {
+		class = "income"
+	}
+	return models.Transaction{
+		ID:             id,
+		Name:           name,
+		Classification: class,
+		AmountText:     strconv.FormatFloat(float64(cents)/100, 'f', 2, 64),
+		Currency:       currency,
+		Date:           date,
+	}
+}
+
+func parseDecimal(s string) (int64, error) {
+	f, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+	if err != nil {
+		return 0, err
+	}
+	if f < 0 {
+		return int64(f*100 - 0.5), nil
+	}
+	return int64(f*100 + 0.5), nil
+}
+
+func str(v any) string {
+	if v == nil {
+		return ""
+	}
+	return fmt.Sprint(v)
+}
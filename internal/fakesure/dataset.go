@@ -0,0 +1,275 @@
+package fakesure
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+//go:embed fixtures/default.json
+var defaultFixture []byte
+
+// Dataset is the in-memory state served by the fake. Amounts are stored in
+// cents so filters never round-trip through formatted strings; the API layer
+// renders them the way Sure does ("€12.00", "-€2,800.00").
+//
+// Transaction amounts follow Sure's entry convention: positive is an outflow
+// (expense), negative is an inflow (income).
+type Dataset struct {
+	AnchorDate        string           `json:"anchor_date"`
+	Currency          string           `json:"currency"`
+	Accounts          []Account        `json:"accounts"`
+	Categories        []Category       `json:"categories"`
+	Merchants         []Merchant       `json:"merchants"`
+	Tags              []Tag            `json:"tags"`
+	Transactions      []Transaction    `json:"transactions"`
+	Transfers         []Transfer       `json:"transfers"`
+	RejectedTransfers []Transfer       `json:"rejected_transfers"`
+	Budgets           []Budget         `json:"budgets"`
+	BudgetCategories  []BudgetCategory `json:"budget_categories"`
+	Recurring         []Recurring      `json:"recurring_transactions"`
+	Securities        []Security       `json:"securities"`
+	Holdings          []Holding        `json:"holdings"`
+	Trades            []Trade          `json:"trades"`
+	Syncs             []Sync           `json:"syncs"`
+	Imports           []Import         `json:"imports"`
+}
+
+type Account struct {
+	ID               string `json:"id"`
+	Name             string `json:"name"`
+	AccountType      string `json:"account_type"`
+	Subtype          string `json:"subtype,omitempty"`
+	Classification   string `json:"classification"`
+	Currency         string `json:"currency"`
+	BalanceCents     int64  `json:"balance_cents"`
+	CashBalanceCents *int64 `json:"cash_balance_cents,omitempty"`
+}
+
+type Category struct {
+	ID             string `json:"id"`
+	Name           string `json:"name"`
+	Classification string `json:"classification"`
+	Color          string `json:"color"`
+	Icon           string `json:"icon,omitempty"`
+	ParentID       string `json:"parent_id,omitempty"`
+}
+
+type Merchant struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type Tag struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Color string `json:"color,omitempty"`
+}
+
+type Transaction struct {
+	ID          string   `json:"id"`
+	AccountID   string   `json:"account_id"`
+	Date        string   `json:"date"`
+	AmountCents int64    `json:"amount_cents"`
+	Currency    string   `json:"currency,omitempty"`
+	Name        string   `json:"name"`
+	Notes       string   `json:"notes,omitempty"`
+	CategoryID  string   `json:"category_id,omitempty"`
+	MerchantID  string   `json:"merchant_id,omitempty"`
+	TagIDs      []string `json:"tag_ids,omitempty"`
+}
+
+// Transfer links the outflow and inflow legs of money moved between two
+// accounts of the household.
+type Transfer struct {
+	ID                   string `json:"id"`
+	OutflowTransactionID string `json:"outflow_transaction_id"`
+	InflowTransactionID  string `json:"inflow_transaction_id"`
+	Status               string `json:"status,omitempty"` // pending|confirmed; unset on rejected pairs
+	Notes                string `json:"notes,omitempty"`
+}
+
+type Budget struct {
+	ID                    string `json:"id"`
+	StartDate             string `json:"start_date"`
+	Currency              string `json:"currency"`
+	BudgetedSpendingCents int64  `json:"budgeted_spending_cents"`
+	ExpectedIncomeCents   int64  `json:"expected_income_cents"`
+}
+
+type BudgetCategory struct {
+	ID                    string `json:"id"`
+	BudgetID              string `json:"budget_id"`
+	CategoryID            string `json:"category_id"`
+	BudgetedSpendingCents int64  `json:"budgeted_spending_cents"`
+}
+
+// Recurring is a Sure recurring transaction. AmountCents follows the entry
+// convention (positive outflow); the expected_amount_* fields are optional.
+type Recurring struct {
+	ID                 string `json:"id"`
+	AccountID          string `json:"account_id,omitempty"`
+	MerchantID         string `json:"merchant_id,omitempty"`
+	Name               string `json:"name"`
+	AmountCents        int64  `json:"amount_cents"`
+	Currency           string `json:"currency,omitempty"`
+	ExpectedDayOfMonth int    `json:"expected_day_of_month"`
+	LastOccurrenceDate string `json:"last_occurrence_date"`
+	NextExpectedDate   string `json:"next_expected_date"`
+	Status             string `json:"status"`
+	OccurrenceCount    int    `json:"occurrence_count"`
+	Manual             bool   `json:"manual,omitempty"`
+	ExpectedMinCents   *int64 `json:"expected_amount_min_cents,omitempty"`
+	ExpectedMaxCents   *int64 `json:"expected_amount_max_cents,omitempty"`
+	ExpectedAvgCents   *int64 `json:"expected_amount_avg_cents,omitempty"`
+}
+
+type Security struct {
+	ID                   string `json:"id"`
+	Ticker               string `json:"ticker"`
+	Name                 string `json:"name"`
+	ExchangeOperatingMIC string `json:"exchange_operating_mic,omitempty"`
+}
+
+type Holding struct {
+	ID         string `json:"id"`
+	AccountID  string `json:"account_id"`
+	SecurityID string `json:"security_id"`
+	Date       string `json:"date"`
+	Qty        string `json:"qty"`
+	PriceCents int64  `json:"price_cents"`
+	Currency   string `json:"currency"`
+}
+
+type Trade struct {
+	ID         string `json:"id"`
+	AccountID  string `json:"account_id"`
+	SecurityID string `json:"security_id"`
+	Date       string `json:"date"`
+	Qty        string `json:"qty"`
+	PriceCents int64  `json:"price_cents"`
+	Currency   string `json:"currency"`
+}
+
+type Sync struct {
+	ID           string `json:"id"`
+	Status       string `json:"status"`
+	SyncableType string `json:"syncable_type"`
+	SyncableID   string `json:"syncable_id"`
+	CreatedAt    string `json:"created_at"`
+	CompletedAt  string `json:"completed_at,omitempty"`
+	Error        string `json:"error,omitempty"`
+}
+
+type Import struct {
+	ID        string      `json:"id"`
+	Type      string      `json:"type"`
+	Status    string      `json:"status"`
+	AccountID string      `json:"account_id,omitempty"`
+	RowsCount int         `json:"rows_count"`
+	CreatedAt string      `json:"created_at"`
+	Rows      []ImportRow `json:"rows,omitempty"`
+}
+
+type ImportRow struct {
+	ID     string   `json:"id"`
+	Date   string   `json:"date"`
+	Amount string   `json:"amount"`
+	Name   string   `json:"name"`
+	Valid  bool     `json:"valid"`
+	Errors []string `json:"errors,omitempty"`
+}
+
+// DefaultDataset returns a fresh copy of the embedded fixture household:
+// four accounts, six months of salary/rent/subscription/grocery activity,
+// savings transfers and card payments, monthly budgets, Sure recurring transactions, a small brokerage portfolio, syncs and one import.
+func DefaultDataset() *Dataset {
+	ds, err := LoadDataset(defaultFixture)
+	if err != nil {
+		// The fixture is embedded at build time; failing to parse it is a
+		// programming error, not a runtime condition.
+		panic(fmt.Sprintf("fakesure: embedded fixture: %v", err))
+	}
+	return ds
+}
+
+// LoadDataset parses a fixture document in the same shape as
+// fixtures/default.json.
+func LoadDataset(b []byte) (*Dataset, error) {
+	var ds Dataset
+	if err := json.Unmarshal(b, &ds); err != nil {
+		return nil, err
+	}
+	if ds.Currency == "" {
+		ds.Currency = "EUR"
+	}
+	return &ds, nil
+}
+
+// Rebase shifts every dated record so that AnchorDate lands on target.
+// Entry dates move by whole days (preserving the spacing the recurring
+// detectors rely on); budgets move by whole months so they stay aligned with
+// calendar months.
+func (ds *Dataset) Rebase(target time.Time) error {
+	anchor, err := time.Parse(dateLayout, ds.AnchorDate)
+	if err != nil {
+		return fmt.Errorf("anchor_date: %w", err)
+	}
+	target = time.Date(target.Year(), target.Month(), target.Day(), 0, 0, 0, 0, time.UTC)
+	days := int(target.Sub(anchor).Hours() / 24)
+	months := (target.Year()-anchor.Year())*12 + int(target.Month()) - int(anchor.Month())
+
+	shiftDate := func(s string) string {
+		t, err := time.Parse(dateLayout, s)
+		if err != nil {
+			return s
+		}
+		return t.AddDate(0, 0, days).Format(dateLayout)
+	}
+	shiftStamp := func(s string) string {
+		t, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			return s
+		}
+		return t.AddDate(0, 0, days).Format(time.RFC3339)
+	}
+
+	for i := range ds.Transactions {
+		ds.Transactions[i].Date = shiftDate(ds.Transactions[i].Date)
+	}
+	for i := range ds.Holdings {
+		ds.Holdings[i].Date = shiftDate(ds.Holdings[i].Date)
+	}
+	for i := range ds.Recurring {
+		ds.Recurring[i].LastOccurrenceDate = shiftDate(ds.Recurring[i].LastOccurrenceDate)
+		ds.Recurring[i].NextExpectedDate = shiftDate(ds.Recurring[i].NextExpectedDate)
+		if t, err := time.Parse(dateLayout, ds.Recurring[i].NextExpectedDate); err == nil {
+			ds.Recurring[i].ExpectedDayOfMonth = t.Day()
+		}
+	}
+	for i := range ds.Trades {
+		ds.Trades[i].Date = shiftDate(ds.Trades[i].Date)
+	}
+	for i := range ds.Syncs {
+		ds.Syncs[i].CreatedAt = shiftStamp(ds.Syncs[i].CreatedAt)
+		if ds.Syncs[i].CompletedAt != "" {
+			ds.Syncs[i].CompletedAt = shiftStamp(ds.Syncs[i].CompletedAt)
+		}
+	}
+	for i := range ds.Imports {
+		ds.Imports[i].CreatedAt = shiftStamp(ds.Imports[i].CreatedAt)
+		for j := range ds.Imports[i].Rows {
+			ds.Imports[i].Rows[j].Date = shiftDate(ds.Imports[i].Rows[j].Date)
+		}
+	}
+	for i := range ds.Budgets {
+		t, err := time.Parse(dateLayout, ds.Budgets[i].StartDate)
+		if err != nil {
+			continue
+		}
+		ds.Budgets[i].StartDate = t.AddDate(0, months, 0).Format(dateLayout)
+	}
+	ds.AnchorDate = target.Format(dateLayout)
+	return nil
+}
@@ -0,0 +1,31 @@
+// Package fakesuretest starts a fakesure.Server on a loopback listener for the
+// lifetime of a test.
+package fakesuretest
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/we-promise/sure-cli/internal/fakesure"
+)
+
+// Server is a running fake plus the URL and API key to reach it.
+type Server struct {
+	*fakesure.Server
+	URL    string
+	APIKey string
+}
+
+// NewServer starts a fake Sure API seeded from opts (zero value = default
+// fixture) and shuts it down via t.Cleanup.
+func NewServer(t testing.TB, opts fakesure.Options) *Server {
+	t.Helper()
+	fs := fakesure.New(opts)
+	srv := httptest.NewServer(fs)
+	t.Cleanup(srv.Close)
+	key := opts.APIKey
+	if key == "" {
+		key = fakesure.DefaultAPIKey
+	}
+	return &Server{Server: fs, URL: srv.URL, APIKey: key}
+}
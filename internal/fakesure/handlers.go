@@ -0,0 +1,1495 @@
+package fakesure
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+func (s *Server) routes() *http.ServeMux {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("POST /api/v1/auth/login", s.handleLogin)
+	mux.HandleFunc("POST /api/v1/auth/refresh", s.handleRefresh)
+
+	mux.HandleFunc("GET /api/v1/usage", s.handleUsage)
+
+	mux.HandleFunc("GET /api/v1/accounts", s.handleAccounts)
+	mux.HandleFunc("GET /api/v1/accounts/{id}", s.handleAccount)
+	mux.HandleFunc("GET /api/v1/balance_sheet", s.handleBalanceSheet)
+	mux.HandleFunc("GET /api/v1/balances", s.handleBalances)
+
+	mux.HandleFunc("GET /api/v1/transactions", s.handleTransactions)
+	mux.HandleFunc("GET /api/v1/transactions/{id}", s.handleTransaction)
+	mux.HandleFunc("POST /api/v1/transactions", s.handleTransactionCreate)
+	mux.HandleFunc("PATCH /api/v1/transactions/{id}", s.handleTransactionUpdate)
+	mux.HandleFunc("PUT /api/v1/transactions/{id}", s.handleTransactionUpdate)
+	mux.HandleFunc("DELETE /api/v1/transactions/{id}", s.handleTransactionDelete)
+
+	mux.HandleFunc("GET /api/v1/transfers", s.handleTransfers)
+	mux.HandleFunc("GET /api/v1/transfers/{id}", s.handleTransfer)
+	mux.HandleFunc("POST /api/v1/transfers", s.handleTransferCreate)
+	mux.HandleFunc("GET /api/v1/rejected_transfers", s.handleRejectedTransfers)
+	mux.HandleFunc("GET /api/v1/rejected_transfers/{id}", s.handleRejectedTransfer)
+
+	mux.HandleFunc("GET /api/v1/categories", s.handleCategories)
+	mux.HandleFunc("GET /api/v1/categories/{id}", s.handleCategory)
+	mux.HandleFunc("POST /api/v1/categories", s.handleCategoryCreate)
+	mux.HandleFunc("PATCH /api/v1/categories/{id}", s.handleCategoryUpdate)
+	mux.HandleFunc("DELETE /api/v1/categories/{id}", s.handleCategoryDelete)
+
+	mux.HandleFunc("GET /api/v1/merchants", s.handleMerchants)
+	mux.HandleFunc("GET /api/v1/merchants/{id}", s.handleMerchant)
+
+	mux.HandleFunc("GET /api/v1/tags", s.handleTags)
+	mux.HandleFunc("GET /api/v1/tags/{id}", s.handleTag)
+	mux.HandleFunc("POST /api/v1/tags", s.handleTagCreate)
+	mux.HandleFunc("PATCH /api/v1/tags/{id}", s.handleTagUpdate)
+	mux.HandleFunc("DELETE /api/v1/tags/{id}", s.handleTagDelete)
+
+	mux.HandleFunc("GET /api/v1/imports", s.handleImports)
+	mux.HandleFunc("GET /api/v1/imports/{id}", s.handleImport)
+	mux.HandleFunc("GET /api/v1/imports/{id}/rows", s.handleImportRows)
+	mux.HandleFunc("POST /api/v1/imports", s.handleImportCreate)
+	mux.HandleFunc("POST /api/v1/imports/preflight", s.handleImportPreflight)
+
+	mux.HandleFunc("GET /api/v1/budgets", s.handleBudgets)
+	mux.HandleFunc("GET /api/v1/budgets/{id}", s.handleBudget)
+	mux.HandleFunc("GET /api/v1/budget_categories", s.handleBudgetCategories)
+	mux.HandleFunc("GET /api/v1/budget_categories/{id}", s.handleBudgetCategory)
+	mux.HandleFunc("POST /api/v1/budgets", s.handleBudgetCreate)
+	mux.HandleFunc("PATCH /api/v1/budgets/{id}", s.handleBudgetUpdate)
+	mux.HandleFunc("PATCH /api/v1/budget_categories/{id}", s.handleBudgetCategoryUpdate)
+
+	mux.HandleFunc("GET /api/v1/recurring_transactions", s.handleRecurringList)
+	mux.HandleFunc("GET /api/v1/recurring_transactions/{id}", s.handleRecurring)
+	mux.HandleFunc("POST /api/v1/recurring_transactions", s.handleRecurringCreate)
+	mux.HandleFunc("PATCH /api/v1/recurring_transactions/{id}", s.handleRecurringUpdate)
+	mux.HandleFunc("DELETE /api/v1/recurring_transactions/{id}", s.handleRecurringDelete)
+
+	mux.HandleFunc("GET /api/v1/holdings", s.handleHoldings)
+	mux.HandleFunc("GET /api/v1/holdings/{id}", s.handleHolding)
+	mux.HandleFunc("GET /api/v1/trades", s.handleTrades)
+	mux.HandleFunc("GET /api/v1/trades/{id}", s.handleTrade)
+
+	mux.HandleFunc("POST /api/v1/sync", s.handleSyncTrigger)
+	mux.HandleFunc("GET /api/v1/syncs", s.handleSyncs)
+	mux.HandleFunc("GET /api/v1/syncs/latest", s.handleSyncLatest)
+	mux.HandleFunc("GET /api/v1/syncs/{id}", s.handleSync)
+
+	// Writes the CLI can send but the fake does not model (trades,
+	// valuations, chats, family exports, users, auth/enable_ai) fail
+	// loudly instead of looking like a missing record.
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && strings.HasPrefix(r.URL.Path, "/api/v1/") {
+			writeError(w, http.StatusNotImplemented, "not_implemented", "fakesure does not implement "+r.Method+" "+r.URL.Path)
+			return
+		}
+		notFound(w)
+	})
+	return mux
+}
+
+// ---------- auth ----------
+
+func (s *Server) handleLogin(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Email    string         `json:"email"`
+		Password string         `json:"password"`
+		OTPCode  string         `json:"otp_code"`
+		Device   map[string]any `json:"device"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "bad_request", "invalid JSON body")
+		return
+	}
+	if req.Device == nil {
+		writeError(w, http.StatusBadRequest, "bad_request", "device information is required")
+		return
+	}
+	if !strings.EqualFold(req.Email, s.opts.Email) || req.Password != s.opts.Password {
+		writeError(w, http.StatusUnauthorized, "invalid_credentials", "Invalid email or password")
+		return
+	}
+	if s.opts.OTP != "" && req.OTPCode != s.opts.OTP {
+		writeJSON(w, http.StatusUnauthorized, map[string]any{"error": "Two-factor authentication required", "mfa_required": true})
+		return
+	}
+	res := s.issueTokens()
+	res["user"] = map[string]any{"id": "usr_demo", "email": s.opts.Email, "first_name": "Demo", "last_name": "User"}
+	writeJSON(w, http.StatusOK, res)
+}
+
+func (s *Server) handleRefresh(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		RefreshToken string `json:"refresh_token"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "bad_request", "invalid JSON body")
+		return
+	}
+	if !s.refreshTokens[req.RefreshToken] {
+		writeError(w, http.StatusUnauthorized, "invalid_grant", "Refresh token is invalid or revoked")
+		return
+	}
+	// Rotate: the old refresh token is single-use, like Doorkeeper's default.
+	delete(s.refreshTokens, req.RefreshToken)
+	writeJSON(w, http.StatusOK, s.issueTokens())
+}
+
+func (s *Server) handleUsage(w http.ResponseWriter, r *http.Request) {
+	reset := s.windowStart.Add(s.opts.RateWindow)
+	limit := any(nil)
+	remaining := any(nil)
+	tier := "unlimited"
+	if s.opts.RateLimit > 0 {
+		tier = "standard"
+		limit = s.opts.RateLimit
+		rem := s.opts.RateLimit - s.windowCount
+		if rem < 0 {
+			rem = 0
+		}
+		remaining = rem
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"api_key": map[string]any{"name": "fake-sure", "scopes": []string{"read_write"}},
+		"rate_limit": map[string]any{
+			"tier":             tier,
+			"limit":            limit,
+			"current_count":    s.windowCount,
+			"remaining":        remaining,
+			"reset_in_seconds": int(reset.Sub(s.opts.Now()).Seconds()),
+			"reset_at":         reset.UTC().Format(time.RFC3339),
+		},
+	})
+}
+
+// ---------- accounts ----------
+
+func (s *Server) handleAccounts(w http.ResponseWriter, r *http.Request) {
+	items := make([]map[string]any, 0, len(s.ds.Accounts))
+	for _, a := range s.ds.Accounts {
+		items = append(items, s.renderAccount(a))
+	}
+	page, pg := paginate(items, r.URL.Query())
+	writeJSON(w, http.StatusOK, map[string]any{"accounts": page, "pagination": pg})
+}
+
+func (s *Server) handleAccount(w http.ResponseWriter, r *http.Request) {
+	a := s.account(r.PathValue("id"))
+	if a == nil {
+		notFound(w)
+		return
+	}
+	writeJSON(w, http.StatusOK, s.renderAccount(*a))
+}
+
+// handleBalanceSheet sums account balances by classification in the family
+// currency (the fixture holds a single currency, so no conversion).
+func (s *Server) handleBalanceSheet(w http.ResponseWriter, r *http.Request) {
+	var assets, liabilities int64
+	for _, a := range s.ds.Accounts {
+		if a.Classification == "liability" {
+			liabilities += a.BalanceCents
+		} else {
+			assets += a.BalanceCents
+		}
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"currency":    s.ds.Currency,
+		"assets":      formatMoney(assets, s.ds.Currency),
+		"liabilities": formatMoney(liabilities, s.ds.Currency),
+		"net_worth":   formatMoney(assets-liabilities, s.ds.Currency),
+	})
+}
+
+// handleBalances serves balance history derived from the transactions: one
+// record per account at each month end in [start_date, end_date] (default:
+// the year up to the anchor date) plus end_date itself, newest first. A
+// past balance is the current one with later transactions undone.
+func (s *Server) handleBalances(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	anchor, _ := time.Parse(dateLayout, s.ds.AnchorDate)
+	end, err := time.Parse(dateLayout, q.Get("end_date"))
+	if err != nil || end.After(anchor) {
+		end = anchor
+	}
+	start, err := time.Parse(dateLayout, q.Get("start_date"))
+	if err != nil {
+		start = end.AddDate(-1, 0, 0)
+	}
+	dates := []string{end.Format(dateLayout)}
+	for d := time.Date(end.Year(), end.Month(), 0, 0, 0, 0, 0, time.UTC); !d.Before(start); d = time.Date(d.Year(), d.Month(), 0, 0, 0, 0, 0, time.UTC) {
+		dates = append(dates, d.Format(dateLayout))
+	}
+
+	items := []map[string]any{}
+	for _, a := range s.ds.Accounts {
+		if v := q.Get("account_id"); v != "" && a.ID != v {
+			continue
+		}
+		if v := q.Get("currency"); v != "" && a.Currency != v {
+			continue
+		}
+		for _, date := range dates {
+			bal := a.BalanceCents
+			for _, t := range s.ds.Transactions {
+				if t.AccountID != a.ID || t.Date <= date {
+					continue
+				}
+				// Expenses (positive) lowered an asset and raised a liability.
+				if a.Classification == "liability" {
+					bal -= t.AmountCents
+				} else {
+					bal += t.AmountCents
+				}
+			}
+			items = append(items, map[string]any{
+				"id":       "bal_" + a.ID + "_" + date,
+				"date":     date,
+				"balance":  formatMoney(bal, a.Currency),
+				"currency": a.Currency,
+				"account":  s.accountRef(a.ID),
+			})
+		}
+	}
+	page, pg := paginate(items, q)
+	writeJSON(w, http.StatusOK, map[string]any{"balances": page, "pagination": pg})
+}
+
+// ---------- transactions ----------
+
+func (s *Server) handleTransactions(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	accountIDs := multiParam(q, "account_ids")
+	if v := q.Get("account_id"); v != "" {
+		accountIDs = append(accountIDs, v)
+	}
+	categoryIDs := multiParam(q, "category_ids")
+	if v := q.Get("category_id"); v != "" {
+		categoryIDs = append(categoryIDs, v)
+	}
+	merchantIDs := multiParam(q, "merchant_ids")
+	if v := q.Get("merchant_id"); v != "" {
+		merchantIDs = append(merchantIDs, v)
+	}
+	tagIDs := multiParam(q, "tag_ids")
+	start, end := q.Get("start_date"), q.Get("end_date")
+	typ := q.Get("type")
+	search := strings.ToLower(q.Get("search"))
+	minCents, hasMin := parseCents(q.Get("min_amount"))
+	maxCents, hasMax := parseCents(q.Get("max_amount"))
+
+	var matched []Transaction
+	for _, t := range s.ds.Transactions {
+		if start != "" && t.Date < start {
+			continue
+		}
+		if end != "" && t.Date > end {
+			continue
+		}
+		if len(accountIDs) > 0 && !contains(accountIDs, t.AccountID) {
+			continue
+		}
+		if len(categoryIDs) > 0 && !contains(categoryIDs, t.CategoryID) {
+			continue
+		}
+		if len(merchantIDs) > 0 && !contains(merchantIDs, t.MerchantID) {
+			continue
+		}
+		if len(tagIDs) > 0 && !anyIn(tagIDs, t.TagIDs) {
+			continue
+		}
+		if typ == "income" && t.AmountCents >= 0 {
+			continue
+		}
+		if typ == "expense" && t.AmountCents <= 0 {
+			continue
+		}
+		abs := t.AmountCents
+		if abs < 0 {
+			abs = -abs
+		}
+		if hasMin && abs < minCents {
+			continue
+		}
+		if hasMax && abs > maxCents {
+			continue
+		}
+		if search != "" && !s.transactionMatches(t, search) {
+			continue
+		}
+		matched = append(matched, t)
+	}
+	// Sure orders newest first; ties broken by id for stable paging.
+	sort.SliceStable(matched, func(i, j int) bool {
+		if matched[i].Date == matched[j].Date {
+			return matched[i].ID > matched[j].ID
+		}
+		return matched[i].Date > matched[j].Date
+	})
+
+	page, pg := paginate(matched, q)
+	items := make([]map[string]any, 0, len(page))
+	for _, t := range page {
+		items = append(items, s.renderTransaction(t))
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"transactions": items, "pagination": pg})
+}
+
+func (s *Server) transactionMatches(t Transaction, needle string) bool {
+	if strings.Contains(strings.ToLower(t.Name), needle) || strings.Contains(strings.ToLower(t.Notes), needle) {
+		return true
+	}
+	if m := s.merchant(t.MerchantID); m != nil && strings.Contains(strings.ToLower(m.Name), needle) {
+		return true
+	}
+	return false
+}
+
+func anyIn(want, have []string) bool {
+	for _, h := range have {
+		if contains(want, h) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Server) transactionIndex(id string) int {
+	for i := range s.ds.Transactions {
+		if s.ds.Transactions[i].ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+func (s *Server) handleTransaction(w http.ResponseWriter, r *http.Request) {
+	i := s.transactionIndex(r.PathValue("id"))
+	if i < 0 {
+		notFound(w)
+		return
+	}
+	writeJSON(w, http.StatusOK, s.renderTransaction(s.ds.Transactions[i]))
+}
+
+type transactionParams struct {
+	AccountID  *string   `json:"account_id"`
+	Date       *string   `json:"date"`
+	Amount     any       `json:"amount"`
+	Nature     string    `json:"nature"`
+	Name       *string   `json:"name"`
+	Notes      *string   `json:"notes"`
+	Currency   *string   `json:"currency"`
+	CategoryID *string   `json:"category_id"`
+	MerchantID *string   `json:"merchant_id"`
+	TagIDs     *[]string `json:"tag_ids"`
+}
+
+func decodeTransactionParams(r *http.Request) (transactionParams, bool) {
+	var body struct {
+		Transaction transactionParams `json:"transaction"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		return transactionParams{}, false
+	}
+	return body.Transaction, true
+}
+
+// apply copies the provided fields onto t and reports validation errors the
+// way Sure's 422 responses do.
+func (p transactionParams) apply(s *Server, t *Transaction) []string {
+	var errs []string
+	if p.AccountID != nil {
+		if s.account(*p.AccountID) == nil {
+			errs = append(errs, "Account must exist")
+		}
+		t.AccountID = *p.AccountID
+	}
+	if p.Date != nil {
+		if _, err := time.Parse(dateLayout, *p.Date); err != nil {
+			errs = append(errs, "Date is invalid")
+		}
+		t.Date = *p.Date
+	}
+	if p.Amount != nil {
+		cents, ok := parseCents(p.Amount)
+		if !ok {
+			errs = append(errs, "Amount is not a number")
+		}
+		if cents < 0 {
+			cents = -cents
+		}
+		switch p.Nature {
+		case "income", "inflow":
+			cents = -cents
+		case "", "expense", "outflow":
+		default:
+			errs = append(errs, "Nature is invalid")
+		}
+		t.AmountCents = cents
+	}
+	if p.Name != nil {
+		t.Name = *p.Name
+	}
+	if p.Notes != nil {
+		t.Notes = *p.Notes
+	}
+	if p.Currency != nil {
+		t.Currency = *p.Currency
+	}
+	if p.CategoryID != nil {
+		if *p.CategoryID != "" && s.category(*p.CategoryID) == nil {
+			errs = append(errs, "Category must exist")
+		}
+		t.CategoryID = *p.CategoryID
+	}
+	if p.MerchantID != nil {
+		t.MerchantID = *p.MerchantID
+	}
+	if p.TagIDs != nil {
+		t.TagIDs = append([]string(nil), (*p.TagIDs)...)
+	}
+	if strings.TrimSpace(t.Name) == "" {
+		errs = append(errs, "Name can't be blank")
+	}
+	return errs
+}
+
+func (s *Server) handleTransactionCreate(w http.ResponseWriter, r *http.Request) {
+	p, ok := decodeTransactionParams(r)
+	if !ok {
+		writeError(w, http.StatusBadRequest, "bad_request", "invalid JSON body")
+		return
+	}
+	if p.AccountID == nil || p.Date == nil || p.Amount == nil {
+		writeJSON(w, http.StatusUnprocessableEntity, map[string]any{"error": "validation_failed", "errors": []string{"account_id, date and amount are required"}})
+		return
+	}
+	t := Transaction{ID: s.nextID("txn")}
+	if errs := p.apply(s, &t); len(errs) > 0 {
+		writeJSON(w, http.StatusUnprocessableEntity, map[string]any{"error": "validation_failed", "errors": errs})
+		return
+	}
+	s.ds.Transactions = append(s.ds.Transactions, t)
+	writeJSON(w, http.StatusCreated, s.renderTransaction(t))
+}
+
+func (s *Server) handleTransactionUpdate(w http.ResponseWriter, r *http.Request) {
+	i := s.transactionIndex(r.PathValue("id"))
+	if i < 0 {
+		notFound(w)
+		return
+	}
+	p, ok := decodeTransactionParams(r)
+	if !ok {
+		writeError(w, http.StatusBadRequest, "bad_request", "invalid JSON body")
+		return
+	}
+	t := s.ds.Transactions[i]
+	if errs := p.apply(s, &t); len(errs) > 0 {
+		writeJSON(w, http.StatusUnprocessableEntity, map[string]any{"error": "validation_failed", "errors": errs})
+		return
+	}
+	s.ds.Transactions[i] = t
+	writeJSON(w, http.StatusOK, s.renderTransaction(t))
+}
+
+func (s *Server) handleTransactionDelete(w http.ResponseWriter, r *http.Request) {
+	i := s.transactionIndex(r.PathValue("id"))
+	if i < 0 {
+		notFound(w)
+		return
+	}
+	id := s.ds.Transactions[i].ID
+	s.ds.Transactions = append(s.ds.Transactions[:i], s.ds.Transactions[i+1:]...)
+	// Deleting either leg destroys the transfer (or rejection), as in Sure.
+	s.ds.Transfers = withoutLeg(s.ds.Transfers, id)
+	s.ds.RejectedTransfers = withoutLeg(s.ds.RejectedTransfers, id)
+	writeJSON(w, http.StatusOK, map[string]any{"message": "Transaction deleted successfully"})
+}
+
+// ---------- transfers ----------
+
+func withoutLeg(transfers []Transfer, txID string) []Transfer {
+	kept := transfers[:0]
+	for _, tr := range transfers {
+		if tr.OutflowTransactionID != txID && tr.InflowTransactionID != txID {
+			kept = append(kept, tr)
+		}
+	}
+	return kept
+}
+
+// filterTransfers applies the list filters shared by transfers and
+// rejected transfers (status, account_id on either leg, outflow date range).
+func (s *Server) filterTransfers(transfers []Transfer, q url.Values) []map[string]any {
+	items := []map[string]any{}
+	for _, tr := range transfers {
+		out, in := s.transaction(tr.OutflowTransactionID), s.transaction(tr.InflowTransactionID)
+		if out == nil || in == nil {
+			continue
+		}
+		if v := q.Get("status"); v != "" && tr.Status != v {
+			continue
+		}
+		if v := q.Get("account_id"); v != "" && out.AccountID != v && in.AccountID != v {
+			continue
+		}
+		if v := q.Get("start_date"); v != "" && out.Date < v {
+			continue
+		}
+		if v := q.Get("end_date"); v != "" && out.Date > v {
+			continue
+		}
+		items = append(items, s.renderTransfer(tr))
+	}
+	return items
+}
+
+func (s *Server) handleTransfers(w http.ResponseWriter, r *http.Request) {
+	page, pg := paginate(s.filterTransfers(s.ds.Transfers, r.URL.Query()), r.URL.Query())
+	writeJSON(w, http.StatusOK, map[string]any{"transfers": page, "pagination": pg})
+}
+
+func (s *Server) handleTransfer(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	for _, tr := range s.ds.Transfers {
+		if tr.ID == id {
+			writeJSON(w, http.StatusOK, s.renderTransfer(tr))
+			return
+		}
+	}
+	notFound(w)
+}
+
+// handleTransferCreate links two existing transactions, like matching a
+// transfer in Sure: opposite amounts on different accounts, neither leg
+// already in a transfer. A rejection of the same pair is lifted.
+func (s *Server) handleTransferCreate(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Transfer struct {
+			OutflowTransactionID string `json:"outflow_transaction_id"`
+			InflowTransactionID  string `json:"inflow_transaction_id"`
+			Notes                string `json:"notes"`
+		} `json:"transfer"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, "bad_request", "invalid JSON body")
+		return
+	}
+	p := body.Transfer
+	out, in := s.transaction(p.OutflowTransactionID), s.transaction(p.InflowTransactionID)
+	var errs []string
+	if out == nil {
+		errs = append(errs, "Outflow transaction must exist")
+	}
+	if in == nil {
+		errs = append(errs, "Inflow transaction must exist")
+	}
+	if out != nil && in != nil {
+		if out.AccountID == in.AccountID {
+			errs = append(errs, "Transfer must have different accounts")
+		}
+		if out.AmountCents <= 0 || out.AmountCents != -in.AmountCents {
+			errs = append(errs, "Transfer transactions must have opposite amounts")
+		}
+		if s.transferOf(out.ID) != nil || s.transferOf(in.ID) != nil {
+			errs = append(errs, "Transaction is already part of a transfer")
+		}
+	}
+	if len(errs) > 0 {
+		writeJSON(w, http.StatusUnprocessableEntity, map[string]any{"error": "validation_failed", "errors": errs})
+		return
+	}
+	tr := Transfer{ID: s.nextID("tr"), OutflowTransactionID: out.ID, InflowTransactionID: in.ID, Status: "confirmed", Notes: p.Notes}
+	s.ds.Transfers = append(s.ds.Transfers, tr)
+	kept := s.ds.RejectedTransfers[:0]
+	for _, rt := range s.ds.RejectedTransfers {
+		if rt.OutflowTransactionID != out.ID || rt.InflowTransactionID != in.ID {
+			kept = append(kept, rt)
+		}
+	}
+	s.ds.RejectedTransfers = kept
+	writeJSON(w, http.StatusCreated, s.renderTransfer(tr))
+}
+
+func (s *Server) handleRejectedTransfers(w http.ResponseWriter, r *http.Request) {
+	page, pg := paginate(s.filterTransfers(s.ds.RejectedTransfers, r.URL.Query()), r.URL.Query())
+	writeJSON(w, http.StatusOK, map[string]any{"rejected_transfers": page, "pagination": pg})
+}
+
+func (s *Server) handleRejectedTransfer(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	for _, tr := range s.ds.RejectedTransfers {
+		if tr.ID == id {
+			writeJSON(w, http.StatusOK, s.renderTransfer(tr))
+			return
+		}
+	}
+	notFound(w)
+}
+
+// ---------- categories, merchants, tags ----------
+
+func (s *Server) handleCategories(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	rootsOnly := q.Get("roots_only") == "true"
+	parentID := q.Get("parent_id")
+	items := []map[string]any{}
+	for _, c := range s.ds.Categories {
+		if rootsOnly && c.ParentID != "" {
+			continue
+		}
+		if parentID != "" && c.ParentID != parentID {
+			continue
+		}
+		items = append(items, s.renderCategory(c))
+	}
+	page, pg := paginate(items, q)
+	writeJSON(w, http.StatusOK, map[string]any{"categories": page, "pagination": pg})
+}
+
+func (s *Server) handleCategory(w http.ResponseWriter, r *http.Request) {
+	c := s.category(r.PathValue("id"))
+	if c == nil {
+		notFound(w)
+		return
+	}
+	writeJSON(w, http.StatusOK, s.renderCategory(*c))
+}
+
+func (s *Server) handleCategoryCreate(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Category struct {
+			Name     string `json:"name"`
+			Color    string `json:"color"`
+			Icon     string `json:"icon"`
+			ParentID string `json:"parent_id"`
+		} `json:"category"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, "bad_request", "invalid JSON body")
+		return
+	}
+	p := body.Category
+	var errs []string
+	if strings.TrimSpace(p.Name) == "" {
+		errs = append(errs, "Name can't be blank")
+	}
+	for _, c := range s.ds.Categories {
+		if strings.EqualFold(c.Name, p.Name) {
+			errs = append(errs, "Name has already been taken")
+		}
+	}
+	if p.ParentID != "" && s.category(p.ParentID) == nil {
+		errs = append(errs, "Parent must exist")
+	}
+	if len(errs) > 0 {
+		writeJSON(w, http.StatusUnprocessableEntity, map[string]any{"error": "validation_failed", "errors": errs})
+		return
+	}
+	c := Category{ID: s.nextID("cat"), Name: p.Name, Color: p.Color, Icon: p.Icon, ParentID: p.ParentID, Classification: "expense"}
+	s.ds.Categories = append(s.ds.Categories, c)
+	writeJSON(w, http.StatusCreated, s.renderCategory(c))
+}
+
+func (s *Server) handleCategoryUpdate(w http.ResponseWriter, r *http.Request) {
+	c := s.category(r.PathValue("id"))
+	if c == nil {
+		notFound(w)
+		return
+	}
+	var body struct {
+		Category struct {
+			Name     *string `json:"name"`
+			Color    *string `json:"color"`
+			Icon     *string `json:"icon"`
+			ParentID *string `json:"parent_id"`
+		} `json:"category"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, "bad_request", "invalid JSON body")
+		return
+	}
+	p := body.Category
+	var errs []string
+	if p.Name != nil {
+		if strings.TrimSpace(*p.Name) == "" {
+			errs = append(errs, "Name can't be blank")
+		}
+		for _, other := range s.ds.Categories {
+			if other.ID != c.ID && strings.EqualFold(other.Name, *p.Name) {
+				errs = append(errs, "Name has already been taken")
+			}
+		}
+	}
+	if p.ParentID != nil && *p.ParentID != "" && (*p.ParentID == c.ID || s.category(*p.ParentID) == nil) {
+		errs = append(errs, "Parent must exist")
+	}
+	if len(errs) > 0 {
+		writeJSON(w, http.StatusUnprocessableEntity, map[string]any{"error": "validation_failed", "errors": errs})
+		return
+	}
+	if p.Name != nil {
+		c.Name = *p.Name
+	}
+	if p.Color != nil {
+		c.Color = *p.Color
+	}
+	if p.Icon != nil {
+		c.Icon = *p.Icon
+	}
+	if p.ParentID != nil {
+		c.ParentID = *p.ParentID
+	}
+	writeJSON(w, http.StatusOK, s.renderCategory(*c))
+}
+
+// handleCategoryDelete leaves the category's transactions uncategorized and
+// its subcategories at the top level, as Sure does without a replacement.
+func (s *Server) handleCategoryDelete(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	for i := range s.ds.Categories {
+		if s.ds.Categories[i].ID != id {
+			continue
+		}
+		s.ds.Categories = append(s.ds.Categories[:i], s.ds.Categories[i+1:]...)
+		for j := range s.ds.Categories {
+			if s.ds.Categories[j].ParentID == id {
+				s.ds.Categories[j].ParentID = ""
+			}
+		}
+		for j := range s.ds.Transactions {
+			if s.ds.Transactions[j].CategoryID == id {
+				s.ds.Transactions[j].CategoryID = ""
+			}
+		}
+		writeJSON(w, http.StatusOK, map[string]any{"message": "Category deleted successfully"})
+		return
+	}
+	notFound(w)
+}
+
+func (s *Server) handleMerchants(w http.ResponseWriter, r *http.Request) {
+	page, pg := paginate(s.ds.Merchants, r.URL.Query())
+	writeJSON(w, http.StatusOK, map[string]any{"merchants": page, "pagination": pg})
+}
+
+func (s *Server) handleMerchant(w http.ResponseWriter, r *http.Request) {
+	m := s.merchant(r.PathValue("id"))
+	if m == nil {
+		notFound(w)
+		return
+	}
+	writeJSON(w, http.StatusOK, m)
+}
+
+func (s *Server) handleTags(w http.ResponseWriter, r *http.Request) {
+	page, pg := paginate(s.ds.Tags, r.URL.Query())
+	writeJSON(w, http.StatusOK, map[string]any{"tags": page, "pagination": pg})
+}
+
+func (s *Server) handleTag(w http.ResponseWriter, r *http.Request) {
+	t := s.tag(r.PathValue("id"))
+	if t == nil {
+		notFound(w)
+		return
+	}
+	writeJSON(w, http.StatusOK, t)
+}
+
+type tagParams struct {
+	Tag struct {
+		Name  *string `json:"name"`
+		Color *string `json:"color"`
+	} `json:"tag"`
+}
+
+func (s *Server) handleTagCreate(w http.ResponseWriter, r *http.Request) {
+	var p tagParams
+	if err := json.NewDecoder(r.Body).Decode(&p); err != nil {
+		writeError(w, http.StatusBadRequest, "bad_request", "invalid JSON body")
+		return
+	}
+	if p.Tag.Name == nil || strings.TrimSpace(*p.Tag.Name) == "" {
+		writeJSON(w, http.StatusUnprocessableEntity, map[string]any{"error": "validation_failed", "errors": []string{"Name can't be blank"}})
+		return
+	}
+	t := Tag{ID: s.nextID("tag"), Name: *p.Tag.Name}
+	if p.Tag.Color != nil {
+		t.Color = *p.Tag.Color
+	}
+	s.ds.Tags = append(s.ds.Tags, t)
+	writeJSON(w, http.StatusCreated, t)
+}
+
+func (s *Server) handleTagUpdate(w http.ResponseWriter, r *http.Request) {
+	t := s.tag(r.PathValue("id"))
+	if t == nil {
+		notFound(w)
+		return
+	}
+	var p tagParams
+	if err := json.NewDecoder(r.Body).Decode(&p); err != nil {
+		writeError(w, http.StatusBadRequest, "bad_request", "invalid JSON body")
+		return
+	}
+	if p.Tag.Name != nil {
+		t.Name = *p.Tag.Name
+	}
+	if p.Tag.Color != nil {
+		t.Color = *p.Tag.Color
+	}
+	writeJSON(w, http.StatusOK, t)
+}
+
+func (s *Server) handleTagDelete(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	for i := range s.ds.Tags {
+		if s.ds.Tags[i].ID == id {
+			s.ds.Tags = append(s.ds.Tags[:i], s.ds.Tags[i+1:]...)
+			for j := range s.ds.Transactions {
+				s.ds.Transactions[j].TagIDs = without(s.ds.Transactions[j].TagIDs, id)
+			}
+			writeJSON(w, http.StatusOK, map[string]any{"message": "Tag deleted successfully"})
+			return
+		}
+	}
+	notFound(w)
+}
+
+func without(list []string, v string) []string {
+	var out []string
+	for _, x := range list {
+		if x != v {
+			out = append(out, x)
+		}
+	}
+	return out
+}
+
+// ---------- imports ----------
+
+func (s *Server) importByID(id string) *Import {
+	for i := range s.ds.Imports {
+		if s.ds.Imports[i].ID == id {
+			return &s.ds.Imports[i]
+		}
+	}
+	return nil
+}
+
+func (s *Server) handleImports(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	items := []map[string]any{}
+	for _, imp := range s.ds.Imports {
+		if v := q.Get("status"); v != "" && imp.Status != v {
+			continue
+		}
+		if v := q.Get("type"); v != "" && imp.Type != v {
+			continue
+		}
+		items = append(items, renderImport(imp))
+	}
+	page, pg := paginate(items, q)
+	writeJSON(w, http.StatusOK, map[string]any{"data": page, "meta": pg})
+}
+
+func (s *Server) handleImport(w http.ResponseWriter, r *http.Request) {
+	imp := s.importByID(r.PathValue("id"))
+	if imp == nil {
+		notFound(w)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"data": renderImport(*imp)})
+}
+
+func (s *Server) handleImportRows(w http.ResponseWriter, r *http.Request) {
+	imp := s.importByID(r.PathValue("id"))
+	if imp == nil {
+		notFound(w)
+		return
+	}
+	page, pg := paginate(imp.Rows, r.URL.Query())
+	writeJSON(w, http.StatusOK, map[string]any{"data": page, "meta": pg})
+}
+
+// importFields reads the import form from either a multipart upload or the
+// JSON raw_file_content body sure-cli sends, returning the fields and the file
+// content.
+func importFields(r *http.Request) (map[string]string, string, bool) {
+	fields := map[string]string{}
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		if err := r.ParseMultipartForm(10 << 20); err != nil {
+			return nil, "", false
+		}
+		for k, v := range r.MultipartForm.Value {
+			if len(v) > 0 {
+				fields[k] = v[0]
+			}
+		}
+		content := ""
+		if f, _, err := r.FormFile("file"); err == nil {
+			defer f.Close()
+			var b strings.Builder
+			buf := make([]byte, 32*1024)
+			for {
+				n, err := f.Read(buf)
+				b.Write(buf[:n])
+				if err != nil {
+					break
+				}
+			}
+			content = b.String()
+		}
+		return fields, content, true
+	}
+	if err := json.NewDecoder(r.Body).Decode(&fields); err != nil {
+		return nil, "", false
+	}
+	return fields, fields["raw_file_content"], true
+}
+
+func countImportRows(content string) int {
+	n := 0
+	for _, line := range strings.Split(content, "\n") {
+		if strings.TrimSpace(line) != "" {
+			n++
+		}
+	}
+	return n
+}
+
+func (s *Server) handleImportCreate(w http.ResponseWriter, r *http.Request) {
+	fields, content, ok := importFields(r)
+	if !ok {
+		writeError(w, http.StatusBadRequest, "bad_request", "invalid import payload")
+		return
+	}
+	if content == "" {
+		writeJSON(w, http.StatusUnprocessableEntity, map[string]any{"error": "validation_failed", "errors": []string{"file or raw_file_content is required"}})
+		return
+	}
+	typ := fields["type"]
+	if typ == "" {
+		typ = "TransactionImport"
+	}
+	rows := countImportRows(content)
+	if typ == "TransactionImport" && rows > 0 {
+		rows-- // CSV header
+	}
+	status := "pending"
+	if fields["publish"] == "true" {
+		status = "importing"
+	}
+	imp := Import{
+		ID:        s.nextID("imp"),
+		Type:      typ,
+		Status:    status,
+		AccountID: fields["account_id"],
+		RowsCount: rows,
+		CreatedAt: s.opts.Now().UTC().Format(time.RFC3339),
+	}
+	s.ds.Imports = append(s.ds.Imports, imp)
+	writeJSON(w, http.StatusCreated, map[string]any{"data": renderImport(imp)})
+}
+
+func (s *Server) handleImportPreflight(w http.ResponseWriter, r *http.Request) {
+	fields, content, ok := importFields(r)
+	if !ok {
+		writeError(w, http.StatusBadRequest, "bad_request", "invalid import payload")
+		return
+	}
+	var errs []string
+	if fields["type"] == "" {
+		errs = append(errs, "type is required")
+	}
+	if content == "" {
+		errs = append(errs, "file or raw_file_content is required")
+	}
+	rows := countImportRows(content)
+	if fields["type"] == "TransactionImport" && rows > 0 {
+		rows--
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"data": map[string]any{
+		"valid":      len(errs) == 0,
+		"errors":     errs,
+		"rows_count": rows,
+		"type":       fields["type"],
+	}})
+}
+
+// ---------- budgets ----------
+
+func budgetPeriod(b Budget) (string, string) {
+	start, err := time.Parse(dateLayout, b.StartDate)
+	if err != nil {
+		return b.StartDate, b.StartDate
+	}
+	return b.StartDate, start.AddDate(0, 1, -1).Format(dateLayout)
+}
+
+func (s *Server) handleBudgets(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	items := []map[string]any{}
+	for _, b := range s.ds.Budgets {
+		start, end := budgetPeriod(b)
+		if v := q.Get("start_date"); v != "" && start < v {
+			continue
+		}
+		if v := q.Get("end_date"); v != "" && end > v {
+			continue
+		}
+		items = append(items, s.renderBudget(b))
+	}
+	page, pg := paginate(items, q)
+	writeJSON(w, http.StatusOK, map[string]any{"budgets": page, "pagination": pg})
+}
+
+func (s *Server) handleBudget(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	for _, b := range s.ds.Budgets {
+		if b.ID == id {
+			writeJSON(w, http.StatusOK, s.renderBudget(b))
+			return
+		}
+	}
+	notFound(w)
+}
+
+func (s *Server) handleBudgetCategories(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	items := []map[string]any{}
+	for _, bc := range s.ds.BudgetCategories {
+		if v := q.Get("budget_id"); v != "" && bc.BudgetID != v {
+			continue
+		}
+		if v := q.Get("category_id"); v != "" && bc.CategoryID != v {
+			continue
+		}
+		m := s.renderBudgetCategory(bc)
+		if v := q.Get("start_date"); v != "" {
+			if start, _ := m["start_date"].(string); start < v {
+				continue
+			}
+		}
+		if v := q.Get("end_date"); v != "" {
+			if end, _ := m["end_date"].(string); end > v {
+				continue
+			}
+		}
+		items = append(items, m)
+	}
+	page, pg := paginate(items, q)
+	writeJSON(w, http.StatusOK, map[string]any{"budget_categories": page, "pagination": pg})
+}
+
+func (s *Server) handleBudgetCategory(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	for _, bc := range s.ds.BudgetCategories {
+		if bc.ID == id {
+			writeJSON(w, http.StatusOK, s.renderBudgetCategory(bc))
+			return
+		}
+	}
+	notFound(w)
+}
+
+func (s *Server) handleRecurringList(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	items := []map[string]any{}
+	for _, rt := range s.ds.Recurring {
+		if v := q.Get("status"); v != "" && rt.Status != v {
+			continue
+		}
+		if v := q.Get("account_id"); v != "" && rt.AccountID != v {
+			continue
+		}
+		items = append(items, s.renderRecurring(rt))
+	}
+	page, pg := paginate(items, q)
+	writeJSON(w, http.StatusOK, map[string]any{"recurring_transactions": page, "pagination": pg})
+}
+
+func (s *Server) handleRecurring(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	for _, rt := range s.ds.Recurring {
+		if rt.ID == id {
+			writeJSON(w, http.StatusOK, s.renderRecurring(rt))
+			return
+		}
+	}
+	notFound(w)
+}
+
+// recurringFields is a recurring_transaction body as the CLI sends it;
+// numbers may arrive as strings.
+type recurringFields map[string]any
+
+func decodeRecurringFields(r *http.Request) (recurringFields, bool) {
+	var p struct {
+		Recurring map[string]any `json:"recurring_transaction"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&p); err != nil || p.Recurring == nil {
+		return nil, false
+	}
+	return p.Recurring, true
+}
+
+func (f recurringFields) str(k string) string {
+	v, _ := f[k].(string)
+	return v
+}
+
+func (f recurringFields) num(k string) int {
+	switch v := f[k].(type) {
+	case float64:
+		return int(v)
+	case string:
+		n, _ := strconv.Atoi(v)
+		return n
+	}
+	return 0
+}
+
+func (f recurringFields) cents(k string) *int64 {
+	if c, ok := parseCents(f[k]); ok {
+		return &c
+	}
+	return nil
+}
+
+// handleRecurringCreate accepts the fields the CLI sends. Amounts follow
+// the entry convention (positive outflow).
+func (s *Server) handleRecurringCreate(w http.ResponseWriter, r *http.Request) {
+	f, ok := decodeRecurringFields(r)
+	if !ok {
+		writeError(w, http.StatusBadRequest, "bad_request", "invalid JSON body")
+		return
+	}
+	str, num, cents := f.str, f.num, f.cents
+	rt := Recurring{
+		ID:                 s.nextID("rec"),
+		AccountID:          str("account_id"),
+		MerchantID:         str("merchant_id"),
+		Name:               str("name"),
+		Currency:           str("currency"),
+		ExpectedDayOfMonth: num("expected_day_of_month"),
+		LastOccurrenceDate: str("last_occurrence_date"),
+		NextExpectedDate:   str("next_expected_date"),
+		Status:             str("status"),
+		OccurrenceCount:    num("occurrence_count"),
+		Manual:             str("manual") == "true" || f["manual"] == true,
+		ExpectedMinCents:   cents("expected_amount_min"),
+		ExpectedMaxCents:   cents("expected_amount_max"),
+		ExpectedAvgCents:   cents("expected_amount_avg"),
+	}
+	if c := cents("amount"); c != nil {
+		rt.AmountCents = *c
+	}
+	if rt.Status == "" {
+		rt.Status = "active"
+	}
+	var errs []string
+	if rt.Name == "" && rt.MerchantID == "" {
+		errs = append(errs, "name or merchant is required")
+	}
+	if rt.LastOccurrenceDate == "" || rt.NextExpectedDate == "" {
+		errs = append(errs, "last_occurrence_date and next_expected_date are required")
+	}
+	if rt.AccountID != "" && s.account(rt.AccountID) == nil {
+		errs = append(errs, "Account not found")
+	}
+	if len(errs) > 0 {
+		writeJSON(w, http.StatusUnprocessableEntity, map[string]any{"error": "validation_failed", "errors": errs})
+		return
+	}
+	s.ds.Recurring = append(s.ds.Recurring, rt)
+	writeJSON(w, http.StatusCreated, s.renderRecurring(rt))
+}
+
+// handleRecurringUpdate applies the fields 'recurring-transactions update'
+// sends: status, expected_day_of_month and next_expected_date.
+func (s *Server) handleRecurringUpdate(w http.ResponseWriter, r *http.Request) {
+	var rt *Recurring
+	for i := range s.ds.Recurring {
+		if s.ds.Recurring[i].ID == r.PathValue("id") {
+			rt = &s.ds.Recurring[i]
+		}
+	}
+	if rt == nil {
+		notFound(w)
+		return
+	}
+	f, ok := decodeRecurringFields(r)
+	if !ok {
+		writeError(w, http.StatusBadRequest, "bad_request", "invalid JSON body")
+		return
+	}
+	var errs []string
+	if _, ok := f["status"]; ok {
+		switch f.str("status") {
+		case "active", "inactive":
+		default:
+			errs = append(errs, "Status is not included in the list")
+		}
+	}
+	if _, ok := f["expected_day_of_month"]; ok {
+		if d := f.num("expected_day_of_month"); d < 1 || d > 31 {
+			errs = append(errs, "Expected day of month must be between 1 and 31")
+		}
+	}
+	if _, ok := f["next_expected_date"]; ok {
+		if _, err := time.Parse(dateLayout, f.str("next_expected_date")); err != nil {
+			errs = append(errs, "Next expected date is invalid")
+		}
+	}
+	if len(errs) > 0 {
+		writeJSON(w, http.StatusUnprocessableEntity, map[string]any{"error": "validation_failed", "errors": errs})
+		return
+	}
+	if _, ok := f["status"]; ok {
+		rt.Status = f.str("status")
+	}
+	if _, ok := f["expected_day_of_month"]; ok {
+		rt.ExpectedDayOfMonth = f.num("expected_day_of_month")
+	}
+	if _, ok := f["next_expected_date"]; ok {
+		rt.NextExpectedDate = f.str("next_expected_date")
+	}
+	writeJSON(w, http.StatusOK, s.renderRecurring(*rt))
+}
+
+func (s *Server) handleRecurringDelete(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	for i := range s.ds.Recurring {
+		if s.ds.Recurring[i].ID == id {
+			s.ds.Recurring = append(s.ds.Recurring[:i], s.ds.Recurring[i+1:]...)
+			writeJSON(w, http.StatusOK, map[string]any{"message": "Recurring transaction deleted successfully"})
+			return
+		}
+	}
+	notFound(w)
+}
+
+type budgetParams struct {
+	Budget struct {
+		StartDate        *string `json:"start_date"`
+		Currency         *string `json:"currency"`
+		BudgetedSpending any     `json:"budgeted_spending"`
+		ExpectedIncome   any     `json:"expected_income"`
+	} `json:"budget"`
+}
+
+func (s *Server) handleBudgetCreate(w http.ResponseWriter, r *http.Request) {
+	var p budgetParams
+	if err := json.NewDecoder(r.Body).Decode(&p); err != nil {
+		writeError(w, http.StatusBadRequest, "bad_request", "invalid JSON body")
+		return
+	}
+	if p.Budget.StartDate == nil {
+		writeJSON(w, http.StatusUnprocessableEntity, map[string]any{"error": "validation_failed", "errors": []string{"start_date is required"}})
+		return
+	}
+	for _, b := range s.ds.Budgets {
+		if b.StartDate == *p.Budget.StartDate {
+			writeJSON(w, http.StatusUnprocessableEntity, map[string]any{"error": "validation_failed", "errors": []string{"Start date has already been taken"}})
+			return
+		}
+	}
+	b := Budget{ID: s.nextID("bud"), StartDate: *p.Budget.StartDate, Currency: s.ds.Currency}
+	if p.Budget.Currency != nil {
+		b.Currency = *p.Budget.Currency
+	}
+	if c, ok := parseCents(p.Budget.BudgetedSpending); ok {
+		b.BudgetedSpendingCents = c
+	}
+	if c, ok := parseCents(p.Budget.ExpectedIncome); ok {
+		b.ExpectedIncomeCents = c
+	}
+	s.ds.Budgets = append(s.ds.Budgets, b)
+	writeJSON(w, http.StatusCreated, s.renderBudget(b))
+}
+
+func (s *Server) handleBudgetUpdate(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	var b *Budget
+	for i := range s.ds.Budgets {
+		if s.ds.Budgets[i].ID == id {
+			b = &s.ds.Budgets[i]
+		}
+	}
+	if b == nil {
+		notFound(w)
+		return
+	}
+	var p budgetParams
+	if err := json.NewDecoder(r.Body).Decode(&p); err != nil {
+		writeError(w, http.StatusBadRequest, "bad_request", "invalid JSON body")
+		return
+	}
+	if c, ok := parseCents(p.Budget.BudgetedSpending); ok {
+		b.BudgetedSpendingCents = c
+	}
+	if c, ok := parseCents(p.Budget.ExpectedIncome); ok {
+		b.ExpectedIncomeCents = c
+	}
+	writeJSON(w, http.StatusOK, s.renderBudget(*b))
+}
+
+func (s *Server) handleBudgetCategoryUpdate(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	var bc *BudgetCategory
+	for i := range s.ds.BudgetCategories {
+		if s.ds.BudgetCategories[i].ID == id {
+			bc = &s.ds.BudgetCategories[i]
+		}
+	}
+	if bc == nil {
+		notFound(w)
+		return
+	}
+	var p struct {
+		BudgetCategory struct {
+			BudgetedSpending any `json:"budgeted_spending"`
+		} `json:"budget_category"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&p); err != nil {
+		writeError(w, http.StatusBadRequest, "bad_request", "invalid JSON body")
+		return
+	}
+	c, ok := parseCents(p.BudgetCategory.BudgetedSpending)
+	if !ok {
+		writeJSON(w, http.StatusUnprocessableEntity, map[string]any{"error": "validation_failed", "errors": []string{"budgeted_spending is required"}})
+		return
+	}
+	bc.BudgetedSpendingCents = c
+	writeJSON(w, http.StatusOK, s.renderBudgetCategory(*bc))
+}
+
+// ---------- investments ----------
+
+func (s *Server) handleHoldings(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	accountIDs := multiParam(q, "account_ids")
+	if v := q.Get("account_id"); v != "" {
+		accountIDs = append(accountIDs, v)
+	}
+	items := []map[string]any{}
+	for _, h := range s.ds.Holdings {
+		if len(accountIDs) > 0 && !contains(accountIDs, h.AccountID) {
+			continue
+		}
+		if v := q.Get("security_id"); v != "" && h.SecurityID != v {
+			continue
+		}
+		if v := q.Get("date"); v != "" && h.Date != v {
+			continue
+		}
+		if v := q.Get("start_date"); v != "" && h.Date < v {
+			continue
+		}
+		if v := q.Get("end_date"); v != "" && h.Date > v {
+			continue
+		}
+		items = append(items, s.renderHolding(h))
+	}
+	page, pg := paginate(items, q)
+	writeJSON(w, http.StatusOK, map[string]any{"holdings": page, "pagination": pg})
+}
+
+func (s *Server) handleHolding(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	for _, h := range s.ds.Holdings {
+		if h.ID == id {
+			writeJSON(w, http.StatusOK, s.renderHolding(h))
+			return
+		}
+	}
+	notFound(w)
+}
+
+func (s *Server) handleTrades(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	accountIDs := multiParam(q, "account_ids")
+	if v := q.Get("account_id"); v != "" {
+		accountIDs = append(accountIDs, v)
+	}
+	items := []map[string]any{}
+	for _, t := range s.ds.Trades {
+		if len(accountIDs) > 0 && !contains(accountIDs, t.AccountID) {
+			continue
+		}
+		if v := q.Get("start_date"); v != "" && t.Date < v {
+			continue
+		}
+		if v := q.Get("end_date"); v != "" && t.Date > v {
+			continue
+		}
+		items = append(items, s.renderTrade(t))
+	}
+	page, pg := paginate(items, q)
+	writeJSON(w, http.StatusOK, map[string]any{"trades": page, "pagination": pg})
+}
+
+func (s *Server) handleTrade(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	for _, t := range s.ds.Trades {
+		if t.ID == id {
+			writeJSON(w, http.StatusOK, s.renderTrade(t))
+			return
+		}
+	}
+	notFound(w)
+}
+
+// ---------- syncs ----------
+
+func (s *Server) sortedSyncs() []Sync {
+	out := append([]Sync(nil), s.ds.Syncs...)
+	sort.SliceStable(out, func(i, j int) bool { return out[i].CreatedAt > out[j].CreatedAt })
+	return out
+}
+
+func (s *Server) handleSyncTrigger(w http.ResponseWriter, r *http.Request) {
+	sy := Sync{
+		ID:           s.nextID("sync"),
+		Status:       "pending",
+		SyncableType: "Family",
+		SyncableID:   "fam_demo",
+		CreatedAt:    s.opts.Now().UTC().Format(time.RFC3339),
+	}
+	s.ds.Syncs = append(s.ds.Syncs, sy)
+	writeJSON(w, http.StatusAccepted, map[string]any{"id": sy.ID, "status": sy.Status, "message": "Sync queued"})
+}
+
+func (s *Server) handleSyncs(w http.ResponseWriter, r *http.Request) {
+	page, pg := paginate(s.sortedSyncs(), r.URL.Query())
+	writeJSON(w, http.StatusOK, map[string]any{"syncs": page, "pagination": pg})
+}
+
+func (s *Server) handleSyncLatest(w http.ResponseWriter, r *http.Request) {
+	syncs := s.sortedSyncs()
+	if len(syncs) == 0 {
+		writeJSON(w, http.StatusOK, nil)
+		return
+	}
+	writeJSON(w, http.StatusOK, syncs[0])
+}
+
+func (s *Server) handleSync(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	for _, sy := range s.ds.Syncs {
+		if sy.ID == id {
+			writeJSON(w, http.StatusOK, sy)
+			return
+		}
+	}
+	notFound(w)
+}
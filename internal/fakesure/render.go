@@ -0,0 +1,454 @@
+package fakesure
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+var currencySymbols = map[string]string{
+	"EUR": "€",
+	"USD": "$",
+	"GBP": "£",
+	"JPY": "¥",
+	"INR": "₹",
+}
+
+// formatMoney renders cents the way Sure's Money#format does for the API:
+// symbol prefix, thousands separators, two decimals, leading minus.
+func formatMoney(cents int64, currency string) string {
+	neg := cents < 0
+	if neg {
+		cents = -cents
+	}
+	whole := strconv.FormatInt(cents/100, 10)
+	var b strings.Builder
+	for i, r := range whole {
+		if i > 0 && (len(whole)-i)%3 == 0 {
+			b.WriteByte(',')
+		}
+		b.WriteRune(r)
+	}
+	frac := cents % 100
+	s := b.String() + "." + strconv.FormatInt(frac/10, 10) + strconv.FormatInt(frac%10, 10)
+	if sym, ok := currencySymbols[currency]; ok {
+		s = sym + s
+	} else {
+		s = currency + " " + s
+	}
+	if neg {
+		s = "-" + s
+	}
+	return s
+}
+
+// parseCents accepts plain decimal strings ("12.5", "-3") or JSON numbers as
+// sent in write payloads.
+func parseCents(v any) (int64, bool) {
+	switch t := v.(type) {
+	case float64:
+		return int64(t*100 + sign(t)*0.5), true
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(t), 64)
+		if err != nil {
+			return 0, false
+		}
+		return int64(f*100 + sign(f)*0.5), true
+	}
+	return 0, false
+}
+
+func sign(f float64) float64 {
+	if f < 0 {
+		return -1
+	}
+	return 1
+}
+
+func itoa(n int) string { return strconv.Itoa(n) }
+
+// paginate slices items per Sure's page/per_page params (per_page capped at
+// 100) and returns the pagination block Sure includes in list responses.
+func paginate[T any](items []T, q url.Values) ([]T, map[string]any) {
+	page, _ := strconv.Atoi(q.Get("page"))
+	if page < 1 {
+		page = 1
+	}
+	perPage, _ := strconv.Atoi(q.Get("per_page"))
+	if perPage < 1 {
+		perPage = 25
+	}
+	if perPage > 100 {
+		perPage = 100
+	}
+	total := len(items)
+	totalPages := (total + perPage - 1) / perPage
+	start := (page - 1) * perPage
+	if start > total {
+		start = total
+	}
+	end := start + perPage
+	if end > total {
+		end = total
+	}
+	return items[start:end], map[string]any{
+		"page":        page,
+		"per_page":    perPage,
+		"total_count": total,
+		"total_pages": totalPages,
+	}
+}
+
+// multiParam returns values for both `key` and `key[]` so filters accept the
+// repeated-array form sure-cli sends (account_ids[]=a&account_ids[]=b).
+func multiParam(q url.Values, key string) []string {
+	var out []string
+	out = append(out, q[key]...)
+	out = append(out, q[key+"[]"]...)
+	return out
+}
+
+func contains(list []string, v string) bool {
+	for _, x := range list {
+		if x == v {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Server) account(id string) *Account {
+	for i := range s.ds.Accounts {
+		if s.ds.Accounts[i].ID == id {
+			return &s.ds.Accounts[i]
+		}
+	}
+	return nil
+}
+
+func (s *Server) category(id string) *Category {
+	for i := range s.ds.Categories {
+		if s.ds.Categories[i].ID == id {
+			return &s.ds.Categories[i]
+		}
+	}
+	return nil
+}
+
+func (s *Server) merchant(id string) *Merchant {
+	for i := range s.ds.Merchants {
+		if s.ds.Merchants[i].ID == id {
+			return &s.ds.Merchants[i]
+		}
+	}
+	return nil
+}
+
+func (s *Server) tag(id string) *Tag {
+	for i := range s.ds.Tags {
+		if s.ds.Tags[i].ID == id {
+			return &s.ds.Tags[i]
+		}
+	}
+	return nil
+}
+
+func (s *Server) transaction(id string) *Transaction {
+	if i := s.transactionIndex(id); i >= 0 {
+		return &s.ds.Transactions[i]
+	}
+	return nil
+}
+
+// transferOf returns the transfer a transaction is a leg of.
+func (s *Server) transferOf(txID string) *Transfer {
+	for i, tr := range s.ds.Transfers {
+		if tr.OutflowTransactionID == txID || tr.InflowTransactionID == txID {
+			return &s.ds.Transfers[i]
+		}
+	}
+	return nil
+}
+
+func (s *Server) security(id string) *Security {
+	for i := range s.ds.Securities {
+		if s.ds.Securities[i].ID == id {
+			return &s.ds.Securities[i]
+		}
+	}
+	return nil
+}
+
+func (s *Server) renderAccount(a Account) map[string]any {
+	m := map[string]any{
+		"id":             a.ID,
+		"name":           a.Name,
+		"balance":        formatMoney(a.BalanceCents, a.Currency),
+		"currency":       a.Currency,
+		"classification": a.Classification,
+		"account_type":   a.AccountType,
+	}
+	if a.Subtype != "" {
+		m["subtype"] = a.Subtype
+	}
+	if a.CashBalanceCents != nil {
+		m["cash_balance"] = formatMoney(*a.CashBalanceCents, a.Currency)
+	}
+	return m
+}
+
+func (s *Server) accountRef(id string) any {
+	a := s.account(id)
+	if a == nil {
+		return nil
+	}
+	return map[string]any{"id": a.ID, "name": a.Name, "account_type": a.AccountType}
+}
+
+func (s *Server) renderCategory(c Category) map[string]any {
+	m := map[string]any{
+		"id":             c.ID,
+		"name":           c.Name,
+		"classification": c.Classification,
+		"color":          c.Color,
+		"icon":           c.Icon,
+		"parent":         nil,
+	}
+	if p := s.category(c.ParentID); p != nil {
+		m["parent"] = map[string]any{"id": p.ID, "name": p.Name}
+	}
+	return m
+}
+
+func (s *Server) renderTransaction(t Transaction) map[string]any {
+	currency := t.Currency
+	if currency == "" {
+		currency = s.ds.Currency
+		if a := s.account(t.AccountID); a != nil {
+			currency = a.Currency
+		}
+	}
+	classification := "expense"
+	if t.AmountCents < 0 {
+		classification = "income"
+	}
+	m := map[string]any{
+		"id":             t.ID,
+		"date":           t.Date,
+		"amount":         formatMoney(t.AmountCents, currency),
+		"currency":       currency,
+		"name":           t.Name,
+		"notes":          t.Notes,
+		"classification": classification,
+		"account":        s.accountRef(t.AccountID),
+		"category":       nil,
+		"merchant":       nil,
+		"tags":           []any{},
+		"transfer":       nil,
+	}
+	if c := s.category(t.CategoryID); c != nil {
+		m["category"] = map[string]any{"id": c.ID, "name": c.Name, "classification": c.Classification, "color": c.Color, "icon": c.Icon}
+	}
+	if tr := s.transferOf(t.ID); tr != nil {
+		other := tr.InflowTransactionID
+		if other == t.ID {
+			other = tr.OutflowTransactionID
+		}
+		ref := map[string]any{"id": tr.ID, "amount": m["amount"], "currency": currency, "other_account": nil}
+		if o := s.transaction(other); o != nil {
+			ref["other_account"] = s.accountRef(o.AccountID)
+		}
+		m["transfer"] = ref
+	}
+	if mer := s.merchant(t.MerchantID); mer != nil {
+		m["merchant"] = map[string]any{"id": mer.ID, "name": mer.Name}
+	}
+	tags := []any{}
+	for _, id := range t.TagIDs {
+		if tg := s.tag(id); tg != nil {
+			tags = append(tags, map[string]any{"id": tg.ID, "name": tg.Name, "color": tg.Color})
+		}
+	}
+	m["tags"] = tags
+	return m
+}
+
+func (s *Server) renderRecurring(rt Recurring) map[string]any {
+	currency := rt.Currency
+	if currency == "" {
+		currency = s.ds.Currency
+		if a := s.account(rt.AccountID); a != nil {
+			currency = a.Currency
+		}
+	}
+	money := func(c *int64) any {
+		if c == nil {
+			return nil
+		}
+		return formatMoney(*c, currency)
+	}
+	m := map[string]any{
+		"id":                    rt.ID,
+		"name":                  rt.Name,
+		"amount":                formatMoney(rt.AmountCents, currency),
+		"currency":              currency,
+		"expected_day_of_month": rt.ExpectedDayOfMonth,
+		"last_occurrence_date":  rt.LastOccurrenceDate,
+		"next_expected_date":    rt.NextExpectedDate,
+		"status":                rt.Status,
+		"occurrence_count":      rt.OccurrenceCount,
+		"manual":                rt.Manual,
+		"expected_amount_min":   money(rt.ExpectedMinCents),
+		"expected_amount_max":   money(rt.ExpectedMaxCents),
+		"expected_amount_avg":   money(rt.ExpectedAvgCents),
+		"account":               s.accountRef(rt.AccountID),
+		"merchant":              nil,
+	}
+	if mer := s.merchant(rt.MerchantID); mer != nil {
+		m["merchant"] = map[string]any{"id": mer.ID, "name": mer.Name}
+	}
+	return m
+}
+
+func (s *Server) renderBudget(b Budget) map[string]any {
+	start, end := budgetPeriod(b)
+	var actual int64
+	for _, t := range s.ds.Transactions {
+		if t.AmountCents > 0 && t.Date >= start && t.Date <= end {
+			actual += t.AmountCents
+		}
+	}
+	return map[string]any{
+		"id":                b.ID,
+		"start_date":        start,
+		"end_date":          end,
+		"currency":          b.Currency,
+		"budgeted_spending": formatMoney(b.BudgetedSpendingCents, b.Currency),
+		"expected_income":   formatMoney(b.ExpectedIncomeCents, b.Currency),
+		"actual_spending":   formatMoney(actual, b.Currency),
+	}
+}
+
+func (s *Server) renderBudgetCategory(bc BudgetCategory) map[string]any {
+	currency := s.ds.Currency
+	var budget *Budget
+	for i := range s.ds.Budgets {
+		if s.ds.Budgets[i].ID == bc.BudgetID {
+			budget = &s.ds.Budgets[i]
+			currency = budget.Currency
+		}
+	}
+	m := map[string]any{
+		"id":                bc.ID,
+		"budget_id":         bc.BudgetID,
+		"budgeted_spending": formatMoney(bc.BudgetedSpendingCents, currency),
+		"currency":          currency,
+		"category":          nil,
+	}
+	if c := s.category(bc.CategoryID); c != nil {
+		m["category"] = map[string]any{"id": c.ID, "name": c.Name}
+	}
+	if budget != nil {
+		start, end := budgetPeriod(*budget)
+		m["start_date"] = start
+		m["end_date"] = end
+		var actual int64
+		for _, t := range s.ds.Transactions {
+			if t.AmountCents > 0 && t.CategoryID == bc.CategoryID && t.Date >= start && t.Date <= end {
+				actual += t.AmountCents
+			}
+		}
+		m["actual_spending"] = formatMoney(actual, currency)
+	}
+	return m
+}
+
+func (s *Server) renderHolding(h Holding) map[string]any {
+	qty, _ := strconv.ParseFloat(h.Qty, 64)
+	value := int64(qty*float64(h.PriceCents) + 0.5)
+	m := map[string]any{
+		"id":       h.ID,
+		"date":     h.Date,
+		"quantity": h.Qty,
+		"price":    formatMoney(h.PriceCents, h.Currency),
+		"value":    formatMoney(value, h.Currency),
+		"currency": h.Currency,
+		"account":  s.accountRef(h.AccountID),
+	}
+	if sec := s.security(h.SecurityID); sec != nil {
+		m["name"] = sec.Name
+		m["symbol"] = sec.Ticker
+		m["security"] = map[string]any{"id": sec.ID, "ticker": sec.Ticker, "name": sec.Name}
+	}
+	return m
+}
+
+func (s *Server) renderTrade(t Trade) map[string]any {
+	qty, _ := strconv.ParseFloat(t.Qty, 64)
+	side := "buy"
+	if qty < 0 {
+		side = "sell"
+	}
+	amount := int64(qty*float64(t.PriceCents) + sign(qty)*0.5)
+	m := map[string]any{
+		"id":       t.ID,
+		"date":     t.Date,
+		"side":     side,
+		"quantity": t.Qty,
+		"price":    formatMoney(t.PriceCents, t.Currency),
+		"amount":   formatMoney(amount, t.Currency),
+		"currency": t.Currency,
+		"account":  s.accountRef(t.AccountID),
+	}
+	if sec := s.security(t.SecurityID); sec != nil {
+		m["symbol"] = sec.Ticker
+		m["security"] = map[string]any{"id": sec.ID, "ticker": sec.Ticker, "name": sec.Name}
+	}
+	return m
+}
+
+// renderTransfer follows Sure's transfer JSON: both legs as nested
+// transactions, dated and valued by the outflow. Rejected transfers render
+// the same without a status.
+func (s *Server) renderTransfer(tr Transfer) map[string]any {
+	leg := func(id string) any {
+		t := s.transaction(id)
+		if t == nil {
+			return nil
+		}
+		r := s.renderTransaction(*t)
+		return map[string]any{"id": t.ID, "date": t.Date, "amount": r["amount"], "name": t.Name, "account": r["account"]}
+	}
+	m := map[string]any{
+		"id":                  tr.ID,
+		"status":              tr.Status,
+		"notes":               nil,
+		"date":                nil,
+		"amount":              nil,
+		"currency":            s.ds.Currency,
+		"outflow_transaction": leg(tr.OutflowTransactionID),
+		"inflow_transaction":  leg(tr.InflowTransactionID),
+	}
+	if tr.Notes != "" {
+		m["notes"] = tr.Notes
+	}
+	if tr.Status == "" { // rejected pairs
+		delete(m, "status")
+	}
+	if out := s.transaction(tr.OutflowTransactionID); out != nil {
+		r := s.renderTransaction(*out)
+		m["date"], m["amount"], m["currency"] = out.Date, r["amount"], r["currency"]
+	}
+	return m
+}
+
+func renderImport(i Import) map[string]any {
+	return map[string]any{
+		"id":         i.ID,
+		"type":       i.Type,
+		"status":     i.Status,
+		"account_id": i.AccountID,
+		"rows_count": i.RowsCount,
+		"created_at": i.CreatedAt,
+	}
+}
@@ -0,0 +1,209 @@
+// Package fakesure is an in-process stand-in for the Sure REST API. It serves
+// a seeded fixture household over the same /api/v1 routes sure-cli calls, so
+// integration tests and agent developers can exercise every command without a
+// real instance. It is deliberately small: enough behavior to be realistic
+// (auth, pagination, filters, rate limits, writes), not a reimplementation.
+//
+// Writes are modeled for transactions, transfers, categories, tags,
+// imports, budgets and recurring transactions. Other writes the CLI can
+// send (trades, valuations, chats, family exports, users, auth) get a 501
+// not_implemented response.
+package fakesure
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	dateLayout = "2006-01-02"
+
+	// DefaultAPIKey is accepted via X-Api-Key when Options.APIKey is empty.
+	DefaultAPIKey = "fake-sure-api-key"
+	// DefaultEmail and DefaultPassword are the credentials /auth/login accepts
+	// when Options leaves them empty.
+	DefaultEmail    = "demo@example.com"
+	DefaultPassword = "password"
+)
+
+// Options configures a Server. The zero value serves the default fixture
+// with no rate limit.
+type Options struct {
+	Dataset  *Dataset // nil = DefaultDataset()
+	APIKey   string
+	Email    string
+	Password string
+	OTP      string // when set, /auth/login requires a matching otp_code
+
+	// RateLimit caps authenticated requests per RateWindow (0 = unlimited).
+	RateLimit  int
+	RateWindow time.Duration
+
+	// TokenTTL is the access token lifetime reported as expires_in.
+	TokenTTL time.Duration
+
+	// Now is the clock used for token expiry and rate-limit windows.
+	Now func() time.Time
+}
+
+// Server implements http.Handler. All state lives in memory and is guarded by
+// a single mutex; writes (transactions, tags, categories, imports) mutate the
+// dataset so follow-up reads observe them.
+type Server struct {
+	mu   sync.Mutex
+	opts Options
+	ds   *Dataset
+	mux  *http.ServeMux
+
+	accessTokens  map[string]time.Time // token -> expiry
+	refreshTokens map[string]bool
+
+	windowStart time.Time
+	windowCount int
+	seq         int
+}
+
+// New builds a Server from opts, filling defaults for anything left empty.
+func New(opts Options) *Server {
+	if opts.Dataset == nil {
+		opts.Dataset = DefaultDataset()
+	}
+	if opts.APIKey == "" {
+		opts.APIKey = DefaultAPIKey
+	}
+	if opts.Email == "" {
+		opts.Email = DefaultEmail
+	}
+	if opts.Password == "" {
+		opts.Password = DefaultPassword
+	}
+	if opts.RateWindow <= 0 {
+		opts.RateWindow = time.Hour
+	}
+	if opts.TokenTTL <= 0 {
+		opts.TokenTTL = time.Hour
+	}
+	if opts.Now == nil {
+		opts.Now = time.Now
+	}
+	s := &Server{
+		opts:          opts,
+		ds:            opts.Dataset,
+		accessTokens:  map[string]time.Time{},
+		refreshTokens: map[string]bool{},
+	}
+	s.mux = s.routes()
+	return s
+}
+
+// Dataset exposes the live dataset for test assertions. Callers must not
+// mutate it while requests are in flight.
+func (s *Server) Dataset() *Dataset {
+	return s.ds
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !strings.HasPrefix(r.URL.Path, "/api/v1/auth/") {
+		if !s.authorized(r) {
+			writeError(w, http.StatusUnauthorized, "unauthorized", "Access token or API key is invalid, expired, or missing")
+			return
+		}
+		if !s.allowRequest(w) {
+			return
+		}
+	}
+	s.mux.ServeHTTP(w, r)
+}
+
+func (s *Server) authorized(r *http.Request) bool {
+	if k := r.Header.Get("X-Api-Key"); k != "" {
+		return k == s.opts.APIKey
+	}
+	auth := r.Header.Get("Authorization")
+	token, ok := strings.CutPrefix(auth, "Bearer ")
+	if !ok {
+		return false
+	}
+	exp, ok := s.accessTokens[token]
+	return ok && s.opts.Now().Before(exp)
+}
+
+// allowRequest applies the fixed-window rate limit and sets the same
+// X-RateLimit-* headers Sure emits. It writes the 429 itself when the window
+// is exhausted.
+func (s *Server) allowRequest(w http.ResponseWriter) bool {
+	now := s.opts.Now()
+	if s.windowStart.IsZero() || now.Sub(s.windowStart) >= s.opts.RateWindow {
+		s.windowStart = now
+		s.windowCount = 0
+	}
+	s.windowCount++
+	if s.opts.RateLimit <= 0 {
+		return true
+	}
+	remaining := s.opts.RateLimit - s.windowCount
+	if remaining < 0 {
+		remaining = 0
+	}
+	reset := s.windowStart.Add(s.opts.RateWindow)
+	h := w.Header()
+	h.Set("X-RateLimit-Limit", itoa(s.opts.RateLimit))
+	h.Set("X-RateLimit-Remaining", itoa(remaining))
+	h.Set("X-RateLimit-Reset", itoa(int(reset.Unix())))
+	if s.windowCount > s.opts.RateLimit {
+		h.Set("Retry-After", itoa(int(reset.Sub(now).Seconds())+1))
+		writeError(w, http.StatusTooManyRequests, "rate_limit_exceeded", "Rate limit exceeded. Try again later.")
+		return false
+	}
+	return true
+}
+
+func (s *Server) issueTokens() map[string]any {
+	access := randomToken()
+	refresh := randomToken()
+	now := s.opts.Now()
+	s.accessTokens[access] = now.Add(s.opts.TokenTTL)
+	s.refreshTokens[refresh] = true
+	return map[string]any{
+		"access_token":  access,
+		"refresh_token": refresh,
+		"token_type":    "Bearer",
+		"expires_in":    int(s.opts.TokenTTL.Seconds()),
+		"created_at":    now.Unix(),
+	}
+}
+
+// nextID returns a fresh id with the given prefix for records created through
+// the API.
+func (s *Server) nextID(prefix string) string {
+	s.seq++
+	return prefix + "_new_" + itoa(s.seq)
+}
+
+func randomToken() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, code, message string) {
+	writeJSON(w, status, map[string]any{"error": code, "message": message})
+}
+
+func notFound(w http.ResponseWriter) {
+	writeError(w, http.StatusNotFound, "not_found", "Record not found")
+}
@@ -0,0 +1,393 @@
+package fakesure
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func do(t *testing.T, h http.Handler, method, path, body string, hdr map[string]string) (*http.Response, map[string]any) {
+	t.Helper()
+	var rd io.Reader
+	if body != "" {
+		rd = strings.NewReader(body)
+	}
+	req := httptest.NewRequest(method, path, rd)
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if hdr == nil {
+		hdr = map[string]string{"X-Api-Key": DefaultAPIKey}
+	}
+	for k, v := range hdr {
+		req.Header.Set(k, v)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	res := rec.Result()
+	var out map[string]any
+	_ = json.NewDecoder(res.Body).Decode(&out)
+	return res, out
+}
+
+func TestServer_RequiresAuth(t *testing.T) {
+	s := New(Options{})
+	res, _ := do(t, s, "GET", "/api/v1/accounts", "", map[string]string{})
+	if res.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("status = %d, want 401", res.StatusCode)
+	}
+	res, _ = do(t, s, "GET", "/api/v1/accounts", "", map[string]string{"X-Api-Key": "wrong"})
+	if res.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("wrong key status = %d, want 401", res.StatusCode)
+	}
+	res, out := do(t, s, "GET", "/api/v1/accounts", "", nil)
+	if res.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d, want 200", res.StatusCode)
+	}
+	if accts, _ := out["accounts"].([]any); len(accts) != 4 {
+		t.Fatalf("accounts = %d, want 4", len(accts))
+	}
+}
+
+func TestServer_TransactionsPagination(t *testing.T) {
+	s := New(Options{})
+	total := len(s.Dataset().Transactions)
+	seen := map[string]bool{}
+	for page := 1; ; page++ {
+		_, out := do(t, s, "GET", "/api/v1/transactions?per_page=20&page="+itoa(page), "", nil)
+		txs, _ := out["transactions"].([]any)
+		for _, tx := range txs {
+			seen[tx.(map[string]any)["id"].(string)] = true
+		}
+		pg := out["pagination"].(map[string]any)
+		if int(pg["total_count"].(float64)) != total {
+			t.Fatalf("total_count = %v, want %d", pg["total_count"], total)
+		}
+		if page >= int(pg["total_pages"].(float64)) {
+			break
+		}
+	}
+	if len(seen) != total {
+		t.Fatalf("paged through %d unique transactions, want %d", len(seen), total)
+	}
+}
+
+func TestServer_TransactionFilters(t *testing.T) {
+	s := New(Options{})
+	cases := []struct {
+		query string
+		check func(tx map[string]any) bool
+	}{
+		{"start_date=2026-06-01&end_date=2026-06-30", func(tx map[string]any) bool {
+			d := tx["date"].(string)
+			return d >= "2026-06-01" && d <= "2026-06-30"
+		}},
+		{"account_ids[]=acc_credit", func(tx map[string]any) bool {
+			return tx["account"].(map[string]any)["id"] == "acc_credit"
+		}},
+		{"type=income", func(tx map[string]any) bool { return tx["classification"] == "income" }},
+		{"search=netflix", func(tx map[string]any) bool { return strings.Contains(strings.ToLower(tx["name"].(string)), "netflix") }},
+		{"category_id=cat_housing", func(tx map[string]any) bool {
+			c, _ := tx["category"].(map[string]any)
+			return c != nil && c["id"] == "cat_housing"
+		}},
+	}
+	for _, c := range cases {
+		_, out := do(t, s, "GET", "/api/v1/transactions?per_page=100&"+c.query, "", nil)
+		txs, _ := out["transactions"].([]any)
+		if len(txs) == 0 {
+			t.Fatalf("%s: no results", c.query)
+		}
+		for _, tx := range txs {
+			if !c.check(tx.(map[string]any)) {
+				t.Fatalf("%s: unexpected row %v", c.query, tx)
+			}
+		}
+	}
+}
+
+func TestServer_TransactionWrites(t *testing.T) {
+	s := New(Options{})
+	res, out := do(t, s, "POST", "/api/v1/transactions",
+		`{"transaction":{"account_id":"acc_checking","date":"2026-06-29","amount":"12.34","name":"Lunch","nature":"expense"}}`, nil)
+	if res.StatusCode != http.StatusCreated {
+		t.Fatalf("create status = %d (%v)", res.StatusCode, out)
+	}
+	id := out["id"].(string)
+	if out["amount"] != "€12.34" {
+		t.Fatalf("amount = %v, want €12.34", out["amount"])
+	}
+
+	res, out = do(t, s, "PATCH", "/api/v1/transactions/"+id, `{"transaction":{"category_id":"cat_dining"}}`, nil)
+	if res.StatusCode != http.StatusOK || out["category"].(map[string]any)["id"] != "cat_dining" {
+		t.Fatalf("update status = %d body = %v", res.StatusCode, out)
+	}
+
+	res, _ = do(t, s, "POST", "/api/v1/transactions", `{"transaction":{"account_id":"acc_nope","date":"2026-06-29","amount":"1","name":"x"}}`, nil)
+	if res.StatusCode != http.StatusUnprocessableEntity {
+		t.Fatalf("invalid create status = %d, want 422", res.StatusCode)
+	}
+
+	res, _ = do(t, s, "DELETE", "/api/v1/transactions/"+id, "", nil)
+	if res.StatusCode != http.StatusOK {
+		t.Fatalf("delete status = %d", res.StatusCode)
+	}
+	res, _ = do(t, s, "GET", "/api/v1/transactions/"+id, "", nil)
+	if res.StatusCode != http.StatusNotFound {
+		t.Fatalf("show after delete status = %d, want 404", res.StatusCode)
+	}
+}
+
+func TestServer_BudgetWrites(t *testing.T) {
+	s := New(Options{})
+	res, out := do(t, s, "POST", "/api/v1/budgets", `{"budget":{"start_date":"2030-01-01","budgeted_spending":"1500","expected_income":"3000"}}`, nil)
+	if res.StatusCode != http.StatusCreated || out["budgeted_spending"] != "€1,500.00" {
+		t.Fatalf("create status = %d body = %v", res.StatusCode, out)
+	}
+	res, _ = do(t, s, "POST", "/api/v1/budgets", `{"budget":{"start_date":"2030-01-01"}}`, nil)
+	if res.StatusCode != http.StatusUnprocessableEntity {
+		t.Fatalf("duplicate create status = %d, want 422", res.StatusCode)
+	}
+	res, out = do(t, s, "PATCH", "/api/v1/budgets/"+out["id"].(string), `{"budget":{"expected_income":"3200"}}`, nil)
+	if res.StatusCode != http.StatusOK || out["expected_income"] != "€3,200.00" {
+		t.Fatalf("update status = %d body = %v", res.StatusCode, out)
+	}
+
+	bc := s.ds.BudgetCategories[0].ID
+	res, out = do(t, s, "PATCH", "/api/v1/budget_categories/"+bc, `{"budget_category":{"budgeted_spending":"123.45"}}`, nil)
+	if res.StatusCode != http.StatusOK || out["budgeted_spending"] != "€123.45" {
+		t.Fatalf("budget category update status = %d body = %v", res.StatusCode, out)
+	}
+}
+
+func TestServer_RecurringTransactions(t *testing.T) {
+	s := New(Options{})
+	_, out := do(t, s, "GET", "/api/v1/recurring_transactions?status=active", "", nil)
+	items, _ := out["recurring_transactions"].([]any)
+	if len(items) != 2 {
+		t.Fatalf("active recurring = %d, want 2: %v", len(items), out)
+	}
+	rent := items[0].(map[string]any)
+	if rent["amount"] != "€950.00" || rent["expected_amount_avg"] != nil || rent["account"] == nil {
+		t.Fatalf("unexpected rent: %v", rent)
+	}
+	res, out := do(t, s, "GET", "/api/v1/recurring_transactions/rec_energy", "", nil)
+	if res.StatusCode != http.StatusOK || out["expected_amount_min"] != "€44.75" {
+		t.Fatalf("show status = %d body = %v", res.StatusCode, out)
+	}
+
+	res, out = do(t, s, "POST", "/api/v1/recurring_transactions",
+		`{"recurring_transaction":{"name":"Spotify","amount":"9.99","account_id":"acc_credit","expected_day_of_month":"3","last_occurrence_date":"2026-06-03","next_expected_date":"2026-07-03"}}`, nil)
+	if res.StatusCode != http.StatusCreated || out["amount"] != "€9.99" || out["status"] != "active" || out["expected_day_of_month"] != float64(3) {
+		t.Fatalf("create status = %d body = %v", res.StatusCode, out)
+	}
+	res, _ = do(t, s, "POST", "/api/v1/recurring_transactions", `{"recurring_transaction":{"name":"x"}}`, nil)
+	if res.StatusCode != http.StatusUnprocessableEntity {
+		t.Fatalf("invalid create status = %d, want 422", res.StatusCode)
+	}
+
+	res, out = do(t, s, "PATCH", "/api/v1/recurring_transactions/rec_gym", `{"recurring_transaction":{"status":"inactive","expected_day_of_month":"9"}}`, nil)
+	if res.StatusCode != http.StatusOK || out["status"] != "inactive" || out["expected_day_of_month"] != float64(9) {
+		t.Fatalf("update status = %d body = %v", res.StatusCode, out)
+	}
+	res, _ = do(t, s, "PATCH", "/api/v1/recurring_transactions/rec_gym", `{"recurring_transaction":{"status":"paused"}}`, nil)
+	if res.StatusCode != http.StatusUnprocessableEntity {
+		t.Fatalf("invalid update status = %d, want 422", res.StatusCode)
+	}
+	res, _ = do(t, s, "DELETE", "/api/v1/recurring_transactions/rec_gym", "", nil)
+	if res.StatusCode != http.StatusOK {
+		t.Fatalf("delete status = %d", res.StatusCode)
+	}
+	res, _ = do(t, s, "GET", "/api/v1/recurring_transactions/rec_gym", "", nil)
+	if res.StatusCode != http.StatusNotFound {
+		t.Fatalf("show after delete status = %d, want 404", res.StatusCode)
+	}
+}
+
+func TestServer_CategoryWrites(t *testing.T) {
+	s := New(Options{})
+	res, out := do(t, s, "PATCH", "/api/v1/categories/cat_dining", `{"category":{"name":"Eating out","color":"#f00"}}`, nil)
+	if res.StatusCode != http.StatusOK || out["name"] != "Eating out" || out["color"] != "#f00" {
+		t.Fatalf("update status = %d body = %v", res.StatusCode, out)
+	}
+	res, _ = do(t, s, "PATCH", "/api/v1/categories/cat_dining", `{"category":{"name":"Groceries"}}`, nil)
+	if res.StatusCode != http.StatusUnprocessableEntity {
+		t.Fatalf("duplicate name status = %d, want 422", res.StatusCode)
+	}
+
+	res, _ = do(t, s, "DELETE", "/api/v1/categories/cat_dining", "", nil)
+	if res.StatusCode != http.StatusOK {
+		t.Fatalf("delete status = %d", res.StatusCode)
+	}
+	for _, tx := range s.ds.Transactions {
+		if tx.CategoryID == "cat_dining" {
+			t.Fatalf("%s still in the deleted category", tx.ID)
+		}
+	}
+	res, _ = do(t, s, "DELETE", "/api/v1/categories/cat_dining", "", nil)
+	if res.StatusCode != http.StatusNotFound {
+		t.Fatalf("second delete status = %d, want 404", res.StatusCode)
+	}
+}
+
+func TestServer_UnmodeledWrites(t *testing.T) {
+	s := New(Options{})
+	for _, req := range [][2]string{{"POST", "/api/v1/trades"}, {"PATCH", "/api/v1/valuations/val_1"}, {"DELETE", "/api/v1/chats/chat_1"}} {
+		res, out := do(t, s, req[0], req[1], `{}`, nil)
+		if res.StatusCode != http.StatusNotImplemented || out["error"] != "not_implemented" {
+			t.Errorf("%s %s = %d %v, want 501", req[0], req[1], res.StatusCode, out)
+		}
+	}
+	if res, _ := do(t, s, "GET", "/api/v1/nope", "", nil); res.StatusCode != http.StatusNotFound {
+		t.Errorf("unknown read = %d, want 404", res.StatusCode)
+	}
+}
+
+func TestServer_BalanceSheet(t *testing.T) {
+	s := New(Options{})
+	res, out := do(t, s, "GET", "/api/v1/balance_sheet", "", nil)
+	if res.StatusCode != http.StatusOK || out["net_worth"] != "€39,810.00" || out["liabilities"] != "€840.00" {
+		t.Fatalf("status = %d body = %v", res.StatusCode, out)
+	}
+}
+
+func TestServer_Balances(t *testing.T) {
+	s := New(Options{})
+	_, out := do(t, s, "GET", "/api/v1/balances?account_id=acc_savings&start_date=2026-04-01", "", nil)
+	items, _ := out["balances"].([]any)
+	if len(items) != 3 {
+		t.Fatalf("records = %d, want 3 (anchor + two month ends): %v", len(items), out)
+	}
+	first := items[0].(map[string]any)
+	if first["date"] != "2026-06-30" || first["balance"] != "€12,000.00" {
+		t.Fatalf("latest record = %v", first)
+	}
+	_, out = do(t, s, "GET", "/api/v1/balances?account_id=acc_checking&start_date=2026-05-31&end_date=2026-05-31", "", nil)
+	items, _ = out["balances"].([]any)
+	if len(items) != 1 || items[0].(map[string]any)["balance"] == "€3,250.00" {
+		t.Fatalf("past checking balance should undo later transactions: %v", out)
+	}
+}
+
+func TestServer_RateLimit(t *testing.T) {
+	now := time.Date(2026, 6, 30, 12, 0, 0, 0, time.UTC)
+	s := New(Options{RateLimit: 2, RateWindow: time.Minute, Now: func() time.Time { return now }})
+	for i := 0; i < 2; i++ {
+		if res, _ := do(t, s, "GET", "/api/v1/tags", "", nil); res.StatusCode != http.StatusOK {
+			t.Fatalf("request %d status = %d", i, res.StatusCode)
+		}
+	}
+	res, _ := do(t, s, "GET", "/api/v1/tags", "", nil)
+	if res.StatusCode != http.StatusTooManyRequests {
+		t.Fatalf("status = %d, want 429", res.StatusCode)
+	}
+	if res.Header.Get("Retry-After") == "" || res.Header.Get("X-RateLimit-Remaining") != "0" {
+		t.Fatalf("missing rate-limit headers: %v", res.Header)
+	}
+	now = now.Add(time.Minute)
+	if res, _ := do(t, s, "GET", "/api/v1/tags", "", nil); res.StatusCode != http.StatusOK {
+		t.Fatalf("after window status = %d, want 200", res.StatusCode)
+	}
+}
+
+func TestServer_LoginAndRefresh(t *testing.T) {
+	s := New(Options{})
+	noAuth := map[string]string{}
+	res, _ := do(t, s, "POST", "/api/v1/auth/login", `{"email":"demo@example.com","password":"nope","device":{}}`, noAuth)
+	if res.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("bad password status = %d, want 401", res.StatusCode)
+	}
+	res, out := do(t, s, "POST", "/api/v1/auth/login", `{"email":"demo@example.com","password":"password","device":{"device_id":"x"}}`, noAuth)
+	if res.StatusCode != http.StatusOK {
+		t.Fatalf("login status = %d", res.StatusCode)
+	}
+	access, refresh := out["access_token"].(string), out["refresh_token"].(string)
+
+	res, _ = do(t, s, "GET", "/api/v1/accounts", "", map[string]string{"Authorization": "Bearer " + access})
+	if res.StatusCode != http.StatusOK {
+		t.Fatalf("bearer status = %d", res.StatusCode)
+	}
+
+	res, out = do(t, s, "POST", "/api/v1/auth/refresh", `{"refresh_token":"`+refresh+`"}`, noAuth)
+	if res.StatusCode != http.StatusOK || out["access_token"] == "" {
+		t.Fatalf("refresh status = %d body = %v", res.StatusCode, out)
+	}
+	// Refresh tokens rotate; replaying the old one must fail.
+	res, _ = do(t, s, "POST", "/api/v1/auth/refresh", `{"refresh_token":"`+refresh+`"}`, noAuth)
+	if res.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("replayed refresh status = %d, want 401", res.StatusCode)
+	}
+}
+
+func TestDataset_Rebase(t *testing.T) {
+	ds := DefaultDataset()
+	first := ds.Transactions[0].Date
+	if err := ds.Rebase(time.Date(2026, 7, 30, 0, 0, 0, 0, time.UTC)); err != nil {
+		t.Fatal(err)
+	}
+	if ds.AnchorDate != "2026-07-30" {
+		t.Fatalf("anchor = %s", ds.AnchorDate)
+	}
+	orig, _ := time.Parse(dateLayout, first)
+	got, _ := time.Parse(dateLayout, ds.Transactions[0].Date)
+	if got.Sub(orig) != 30*24*time.Hour {
+		t.Fatalf("first transaction moved %v, want 30 days", got.Sub(orig))
+	}
+	if ds.Budgets[0].StartDate != "2026-02-01" {
+		t.Fatalf("budget start = %s, want 2026-02-01", ds.Budgets[0].StartDate)
+	}
+}
+
+func TestServer_Transfers(t *testing.T) {
+	s := New(Options{})
+	_, out := do(t, s, "GET", "/api/v1/transfers?account_id=acc_savings&start_date=2026-03-01", "", nil)
+	trs, _ := out["transfers"].([]any)
+	if len(trs) != 4 {
+		t.Fatalf("transfers = %d, want 4", len(trs))
+	}
+	tr := trs[0].(map[string]any)
+	outflow := tr["outflow_transaction"].(map[string]any)
+	if tr["amount"] != "€300.00" || outflow["account"].(map[string]any)["id"] != "acc_checking" {
+		t.Fatalf("transfer = %v", tr)
+	}
+
+	_, tx := do(t, s, "GET", "/api/v1/transactions/"+outflow["id"].(string), "", nil)
+	marker, _ := tx["transfer"].(map[string]any)
+	if marker == nil || marker["id"] != tr["id"] || marker["other_account"].(map[string]any)["id"] != "acc_savings" {
+		t.Fatalf("transfer marker = %v", tx["transfer"])
+	}
+
+	do(t, s, "DELETE", "/api/v1/transactions/"+outflow["id"].(string), "", nil)
+	if res, _ := do(t, s, "GET", "/api/v1/transfers/"+tr["id"].(string), "", nil); res.StatusCode != http.StatusNotFound {
+		t.Fatalf("transfer after deleting a leg: status %d", res.StatusCode)
+	}
+}
+
+func TestServer_TransferCreate(t *testing.T) {
+	s := New(Options{})
+	_, out := do(t, s, "GET", "/api/v1/rejected_transfers", "", nil)
+	if rts, _ := out["rejected_transfers"].([]any); len(rts) != 1 {
+		t.Fatalf("rejected transfers = %v", out)
+	}
+
+	// Card payment: checking outflow, credit card inflow.
+	res, out := do(t, s, "POST", "/api/v1/transfers", `{"transfer":{"outflow_transaction_id":"txn_0078","inflow_transaction_id":"txn_0079"}}`, nil)
+	if res.StatusCode != http.StatusCreated || out["status"] != "confirmed" || out["amount"] != "€22.98" {
+		t.Fatalf("create: %d %v", res.StatusCode, out)
+	}
+	res, out = do(t, s, "POST", "/api/v1/transfers", `{"transfer":{"outflow_transaction_id":"txn_0078","inflow_transaction_id":"txn_0081"}}`, nil)
+	if res.StatusCode != http.StatusUnprocessableEntity || len(out["errors"].([]any)) != 1 {
+		t.Fatalf("leg already linked: %d %v", res.StatusCode, out)
+	}
+
+	// Confirming a rejected pair lifts the rejection.
+	res, _ = do(t, s, "POST", "/api/v1/transfers", `{"transfer":{"outflow_transaction_id":"txn_0088","inflow_transaction_id":"txn_0089"}}`, nil)
+	_, out = do(t, s, "GET", "/api/v1/rejected_transfers", "", nil)
+	if rts, _ := out["rejected_transfers"].([]any); res.StatusCode != http.StatusCreated || len(rts) != 0 {
+		t.Fatalf("rejection after linking: %d %v", res.StatusCode, out)
+	}
+}
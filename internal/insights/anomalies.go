@@ -0,0 +1,277 @@
+package insights
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+type AnomalyCandidate struct {
+	TxID            string   `json:"tx_id"`
+	Name            string   `json:"name"`
+	Merchant        string   `json:"merchant"` // normalized grouping key
+	Category        string   `json:"category,omitempty"`
+	Date            string   `json:"date"`
+	Amount          float64  `json:"amount"`             // positive
+	Baseline        float64  `json:"baseline,omitempty"` // median of the comparison group
+	Score           float64  `json:"score,omitempty"`    // robust z-score (outliers only)
+	RelatedTxIDs    []string `json:"related_tx_ids,omitempty"`
+	Confidence      float64  `json:"confidence"`
+	Reason          string   `json:"reason"`
+	SuggestedAction string   `json:"suggested_action"`
+}
+
+// AnomalyOptions tunes DetectAnomaliesWithOptions; zero fields use DefaultAnomalyOptions.
+type AnomalyOptions struct {
+	// ZThreshold is the robust z-score (0.6745*(x-median)/MAD) above which an
+	// amount is an outlier.
+	ZThreshold float64 `json:"z_threshold"`
+	// MinRatio additionally requires amount >= MinRatio*median, so a modest
+	// price rise on a fixed-price charge is not reported every month.
+	MinRatio float64 `json:"min_ratio"`
+	// MinHistory is how many other transactions a merchant or category needs
+	// before its distribution is trusted.
+	MinHistory int `json:"min_history"`
+	// NewMerchantMinAmount flags first-time merchants charging at least this much.
+	NewMerchantMinAmount float64 `json:"new_merchant_min_amount"`
+	// Since limits candidates to transactions on or after it; earlier ones
+	// only serve as history. Zero evaluates everything.
+	Since      time.Time   `json:"-"`
+	Normalizer *Normalizer `json:"-"`
+}
+
+// DefaultAnomalyOptions: z >= 3.5 and 1.5x the median, 5 transactions of
+// history, new merchants from 100.
+func DefaultAnomalyOptions() AnomalyOptions {
+	return AnomalyOptions{ZThreshold: 3.5, MinRatio: 1.5, MinHistory: 5, NewMerchantMinAmount: 100}
+}
+
+func (o AnomalyOptions) withDefaults() AnomalyOptions {
+	def := DefaultAnomalyOptions()
+	if o.ZThreshold <= 0 {
+		o.ZThreshold = def.ZThreshold
+	}
+	if o.MinRatio <= 0 {
+		o.MinRatio = def.MinRatio
+	}
+	if o.MinHistory <= 0 {
+		o.MinHistory = def.MinHistory
+	}
+	if o.NewMerchantMinAmount <= 0 {
+		o.NewMerchantMinAmount = def.NewMerchantMinAmount
+	}
+	return o
+}
+
+// DetectAnomalies flags unusual expenses using DefaultAnomalyOptions.
+func DetectAnomalies(txs []Transaction) []AnomalyCandidate {
+	return DetectAnomaliesWithOptions(txs, DefaultAnomalyOptions())
+}
+
+// DetectAnomaliesWithOptions flags expenses that stand out from history:
+// - amount far above the merchant's (or, lacking history, the category's) median
+// - first charge from a merchant that is already large
+// - the same merchant and amount charged twice on one day
+// - a weekend charge from a merchant only ever seen on weekdays, or vice versa
+//
+// Sure exposes dates only, so time-of-day is not considered.
+func DetectAnomaliesWithOptions(txs []Transaction, opts AnomalyOptions) []AnomalyCandidate {
+	opts = opts.withDefaults()
+
+	type entry struct {
+		tx  Transaction
+		amt float64
+	}
+	var all []entry
+	for _, tx := range txs {
+		if tx.Classification != "expense" {
+			continue
+		}
+		v, err := SignedAmount(tx)
+		if err != nil {
+			continue
+		}
+		all = append(all, entry{tx: tx, amt: math.Abs(v)})
+	}
+	sort.SliceStable(all, func(i, j int) bool { return all[i].tx.Date.Before(all[j].tx.Date) })
+
+	keys := make([]string, len(all))
+	byMerchant := map[string][]int{}
+	byCategory := map[string][]int{}
+	for i, e := range all {
+		k := opts.Normalizer.Key(e.tx)
+		keys[i] = k
+		byMerchant[k] = append(byMerchant[k], i)
+		if e.tx.CategoryName != "" {
+			byCategory[e.tx.CategoryName] = append(byCategory[e.tx.CategoryName], i)
+		}
+	}
+
+	// others returns the amounts of group members except i.
+	others := func(group []int, i int) []float64 {
+		out := make([]float64, 0, len(group))
+		for _, j := range group {
+			if j != i {
+				out = append(out, all[j].amt)
+			}
+		}
+		return out
+	}
+
+	var out []AnomalyCandidate
+	for i, e := range all {
+		if !opts.Since.IsZero() && e.tx.Date.Before(opts.Since) {
+			continue
+		}
+		k := keys[i]
+		base := AnomalyCandidate{
+			TxID:     e.tx.ID,
+			Name:     e.tx.Name,
+			Merchant: k,
+			Category: e.tx.CategoryName,
+			Date:     e.tx.Date.Format("2006-01-02"),
+			Amount:   round2(e.amt),
+		}
+		group := byMerchant[k]
+
+		// Amount outliers: merchant history first, category as a fallback.
+		if hist := others(group, i); len(hist) >= opts.MinHistory {
+			if z, med, ok := robustZ(e.amt, hist, opts.ZThreshold); ok && e.amt >= opts.MinRatio*med {
+				c := base
+				c.Baseline, c.Score = round2(med), round2(z)
+				c.Confidence = outlierConfidence(z, opts.ZThreshold)
+				c.Reason = "merchant_amount_outlier"
+				c.SuggestedAction = fmt.Sprintf("Charge is %.1fx the usual %.2f at this merchant; verify it", e.amt/med, med)
+				out = append(out, c)
+			}
+		} else if cat := byCategory[e.tx.CategoryName]; e.tx.CategoryName != "" {
+			if hist := others(cat, i); len(hist) >= opts.MinHistory {
+				if z, med, ok := robustZ(e.amt, hist, opts.ZThreshold); ok && e.amt >= opts.MinRatio*med {
+					c := base
+					c.Baseline, c.Score = round2(med), round2(z)
+					c.Confidence = outlierConfidence(z, opts.ZThreshold) - 0.1
+					c.Reason = "category_amount_outlier"
+					c.SuggestedAction = fmt.Sprintf("Unusually large for %s (typical %.2f); check it was intended", e.tx.CategoryName, med)
+					out = append(out, c)
+				}
+			}
+		}
+
+		// First-time merchant with a large charge. Only meaningful when there
+		// is history before it, so the first day of data never qualifies.
+		if group[0] == i && e.amt >= opts.NewMerchantMinAmount && e.tx.Date.After(all[0].tx.Date) {
+			c := base
+			c.Confidence = 0.6
+			if e.amt >= 3*opts.NewMerchantMinAmount {
+				c.Confidence = 0.75
+			}
+			c.Reason = "new_merchant_large_amount"
+			c.SuggestedAction = "First charge from this merchant; confirm you recognise it"
+			out = append(out, c)
+		}
+
+		// Same merchant, same day, same amount: keep the later id as the suspect.
+		for _, j := range group {
+			if j >= i {
+				break
+			}
+			p := all[j]
+			if p.tx.Date.Equal(e.tx.Date) && math.Abs(p.amt-e.amt) < 0.005 {
+				c := base
+				c.RelatedTxIDs = []string{p.tx.ID}
+				c.Confidence = 0.8
+				c.Reason = "duplicate_same_day"
+				c.SuggestedAction = "Possible double charge; compare with the related transaction and dispute if duplicated"
+				out = append(out, c)
+				break
+			}
+		}
+
+		// Weekend vs weekday habit.
+		if len(group) > opts.MinHistory {
+			weekend := isWeekend(e.tx.Date)
+			same := 0
+			for _, j := range group {
+				if j != i && isWeekend(all[j].tx.Date) == weekend {
+					same++
+				}
+			}
+			if same == 0 {
+				c := base
+				c.Confidence = 0.5
+				if weekend {
+					c.Reason = "unusual_weekend"
+					c.SuggestedAction = "Merchant is normally charged on weekdays; check this weekend charge"
+				} else {
+					c.Reason = "unusual_weekday"
+					c.SuggestedAction = "Merchant is normally charged on weekends; check this weekday charge"
+				}
+				out = append(out, c)
+			}
+		}
+	}
+
+	sort.SliceStable(out, func(i, j int) bool {
+		if out[i].Confidence == out[j].Confidence {
+			return out[i].Amount > out[j].Amount
+		}
+		return out[i].Confidence > out[j].Confidence
+	})
+	return out
+}
+
+// robustZ scores x against hist with the modified z-score. Only charges
+// above the median count: cheaper-than-usual is not an anomaly worth review.
+// When MAD is zero (e.g. a fixed-price subscription) the mean absolute
+// deviation is used, and when that is zero too any 50%+ jump qualifies.
+func robustZ(x float64, hist []float64, threshold float64) (z, med float64, ok bool) {
+	med = median(hist)
+	if x <= med {
+		return 0, med, false
+	}
+	dev := make([]float64, len(hist))
+	var meanAD float64
+	for i, h := range hist {
+		dev[i] = math.Abs(h - med)
+		meanAD += dev[i]
+	}
+	meanAD /= float64(len(hist))
+	switch mad := median(dev); {
+	case mad > 0:
+		z = 0.6745 * (x - med) / mad
+	case meanAD > 0:
+		z = (x - med) / (1.253314 * meanAD)
+	case med > 0 && (x-med)/med >= 0.5:
+		z = threshold * (x / med)
+	default:
+		return 0, med, false
+	}
+	return z, med, z >= threshold
+}
+
+func outlierConfidence(z, threshold float64) float64 {
+	c := 0.6 + 0.05*(z-threshold)
+	if c > 0.95 {
+		c = 0.95
+	}
+	return math.Round(c*100) / 100
+}
+
+func median(xs []float64) float64 {
+	if len(xs) == 0 {
+		return 0
+	}
+	s := append([]float64(nil), xs...)
+	sort.Float64s(s)
+	n := len(s)
+	if n%2 == 1 {
+		return s[n/2]
+	}
+	return (s[n/2-1] + s[n/2]) / 2
+}
+
+func isWeekend(t time.Time) bool {
+	wd := t.Weekday()
+	return wd == time.Saturday || wd == time.Sunday
+}
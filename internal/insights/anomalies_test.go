@@ -0,0 +1,84 @@
+package insights
+
+import (
+	"fmt"
+	"testing"
+)
+
+func reasons(cands []AnomalyCandidate) map[string]AnomalyCandidate {
+	out := map[string]AnomalyCandidate{}
+	for _, c := range cands {
+		out[c.Reason+":"+c.TxID] = c
+	}
+	return out
+}
+
+func TestDetectAnomalies_Reasons(t *testing.T) {
+	var txs []Transaction
+	// Weekday groceries around €60, then one €240 basket.
+	start := mustDate("2026-01-05") // Monday
+	for i := 0; i < 8; i++ {
+		amt := 55 + float64(i%3)*5
+		txs = append(txs, Transaction{ID: fmt.Sprintf("g%d", i), Name: "MERCADONA", CategoryName: "Groceries", Classification: "expense", AmountText: fmt.Sprintf("€%.2f", amt), Date: start.AddDate(0, 0, 7*i)})
+	}
+	txs = append(txs,
+		Transaction{ID: "g-big", Name: "MERCADONA", CategoryName: "Groceries", Classification: "expense", AmountText: "€240.00", Date: mustDate("2026-03-03")},
+		// Same merchant, same day, same amount.
+		Transaction{ID: "dup-1", Name: "Spotify", Classification: "expense", AmountText: "€10.99", Date: mustDate("2026-03-04")},
+		Transaction{ID: "dup-2", Name: "Spotify", Classification: "expense", AmountText: "€10.99", Date: mustDate("2026-03-04")},
+		// First charge from an unknown merchant.
+		Transaction{ID: "new-1", Name: "Electro Store", Classification: "expense", AmountText: "€450.00", Date: mustDate("2026-03-05")},
+		// Weekday merchant seen on a Saturday.
+		Transaction{ID: "sat", Name: "MERCADONA", CategoryName: "Groceries", Classification: "expense", AmountText: "€60.00", Date: mustDate("2026-03-07")},
+	)
+
+	got := reasons(DetectAnomalies(txs))
+	for _, key := range []string{
+		"merchant_amount_outlier:g-big",
+		"duplicate_same_day:dup-2",
+		"new_merchant_large_amount:new-1",
+		"unusual_weekend:sat",
+	} {
+		if _, ok := got[key]; !ok {
+			t.Errorf("expected %s; got %v", key, got)
+		}
+	}
+	if c := got["merchant_amount_outlier:g-big"]; c.Baseline != 60 || c.Confidence < 0.6 {
+		t.Errorf("outlier baseline/confidence: %+v", c)
+	}
+	if c := got["duplicate_same_day:dup-2"]; len(c.RelatedTxIDs) != 1 || c.RelatedTxIDs[0] != "dup-1" {
+		t.Errorf("duplicate related ids: %+v", c.RelatedTxIDs)
+	}
+	for key := range got {
+		if key == "merchant_amount_outlier:g0" || key == "new_merchant_large_amount:g0" {
+			t.Errorf("first transaction should not be flagged: %s", key)
+		}
+	}
+}
+
+func TestDetectAnomalies_PriceRiseAndSince(t *testing.T) {
+	var txs []Transaction
+	for i := 0; i < 8; i++ {
+		amt := "€12.99"
+		if i >= 6 {
+			amt = "€15.99"
+		}
+		txs = append(txs, Transaction{ID: fmt.Sprintf("n%d", i), Name: "NETFLIX.COM", Classification: "expense", AmountText: amt, Date: mustDate("2026-01-05").AddDate(0, i, 0)})
+	}
+	for _, c := range DetectAnomalies(txs) {
+		if c.Reason == "merchant_amount_outlier" {
+			t.Errorf("modest price rise should not be an outlier: %+v", c)
+		}
+	}
+
+	txs = append(txs, Transaction{ID: "n-spike", Name: "NETFLIX.COM", Classification: "expense", AmountText: "€39.99", Date: mustDate("2026-09-05")})
+	opts := DefaultAnomalyOptions()
+	opts.Since = mustDate("2026-09-01")
+	out := DetectAnomaliesWithOptions(txs, opts)
+	if len(out) != 1 || out[0].TxID != "n-spike" || out[0].Reason != "merchant_amount_outlier" {
+		t.Fatalf("expected only the recent spike, got %+v", out)
+	}
+	if out[0].Date != "2026-09-05" {
+		t.Errorf("unexpected date %s", out[0].Date)
+	}
+}
@@ -0,0 +1,302 @@
+package insights
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+type CashflowMonth struct {
+	Month       string   `json:"month"` // YYYY-MM
+	Income      float64  `json:"income"`
+	Expense     float64  `json:"expense"` // positive
+	Net         float64  `json:"net"`
+	SavingsRate *float64 `json:"savings_rate"` // net / income; null without income
+	Negative    bool     `json:"negative"`
+}
+
+type CashflowTotals struct {
+	Income      float64  `json:"income"`
+	Expense     float64  `json:"expense"`
+	Net         float64  `json:"net"`
+	SavingsRate *float64 `json:"savings_rate"`
+	AvgIncome   float64  `json:"avg_income"`
+	AvgExpense  float64  `json:"avg_expense"`
+	AvgNet      float64  `json:"avg_net"`
+}
+
+// CashflowVolatility describes month-to-month swings as standard deviation
+// and coefficient of variation (stddev / mean).
+type CashflowVolatility struct {
+	IncomeStdDev    float64 `json:"income_stddev"`
+	IncomeCV        float64 `json:"income_cv"`
+	ExpenseStdDev   float64 `json:"expense_stddev"`
+	ExpenseCV       float64 `json:"expense_cv"`
+	NetStdDev       float64 `json:"net_stddev"`
+	IncomeStability string  `json:"income_stability"` // stable|variable|volatile
+}
+
+type IncomeSource struct {
+	Source        string  `json:"source"` // normalized merchant key
+	Name          string  `json:"name"`
+	Type          string  `json:"type"` // salary|recurring|irregular
+	Count         int     `json:"count"`
+	Total         float64 `json:"total"`
+	AvgAmount     float64 `json:"avg_amount"`
+	ShareOfIncome float64 `json:"share_of_income"` // 0..1
+	MonthsPresent int     `json:"months_present"`
+	// Regularity is the share of months since the source first appeared in
+	// which it paid at least once.
+	Regularity    float64 `json:"regularity"`
+	AvgPeriodDays float64 `json:"avg_period_days,omitempty"`
+	AmountCV      float64 `json:"amount_cv"`
+	FirstSeen     string  `json:"first_seen"`
+	LastSeen      string  `json:"last_seen"`
+}
+
+type CashflowReport struct {
+	Months         []CashflowMonth    `json:"months"`
+	Totals         CashflowTotals     `json:"totals"`
+	Volatility     CashflowVolatility `json:"volatility"`
+	IncomeSources  []IncomeSource     `json:"income_sources"`
+	NegativeMonths []string           `json:"negative_months"`
+}
+
+// CashflowOptions tunes Cashflow; zero fields use DefaultCashflowOptions.
+type CashflowOptions struct {
+	// SalaryMaxIntervalStdDays and SalaryMaxAmountCV bound how steady a
+	// monthly or biweekly income must be to count as salary.
+	SalaryMaxIntervalStdDays float64 `json:"salary_max_interval_std_days"`
+	SalaryMaxAmountCV        float64 `json:"salary_max_amount_cv"`
+	// RecurringMinRegularity is the share of months a non-salary source must
+	// pay in to be "recurring" rather than "irregular".
+	RecurringMinRegularity float64 `json:"recurring_min_regularity"`
+	// Start and End bound the monthly buckets; zero uses the data's range.
+	Start      time.Time   `json:"-"`
+	End        time.Time   `json:"-"`
+	Normalizer *Normalizer `json:"-"`
+}
+
+// DefaultCashflowOptions: salary within 5 days and 20% amount variation,
+// recurring when paying in 75% of months.
+func DefaultCashflowOptions() CashflowOptions {
+	return CashflowOptions{SalaryMaxIntervalStdDays: 5, SalaryMaxAmountCV: 0.2, RecurringMinRegularity: 0.75}
+}
+
+func (o CashflowOptions) withDefaults() CashflowOptions {
+	def := DefaultCashflowOptions()
+	if o.SalaryMaxIntervalStdDays <= 0 {
+		o.SalaryMaxIntervalStdDays = def.SalaryMaxIntervalStdDays
+	}
+	if o.SalaryMaxAmountCV <= 0 {
+		o.SalaryMaxAmountCV = def.SalaryMaxAmountCV
+	}
+	if o.RecurringMinRegularity <= 0 {
+		o.RecurringMinRegularity = def.RecurringMinRegularity
+	}
+	return o
+}
+
+// Cashflow builds monthly income, expense, net and savings-rate series,
+// measures their volatility and classifies income sources.
+func Cashflow(txs []Transaction, opts CashflowOptions) CashflowReport {
+	opts = opts.withDefaults()
+
+	start, end := opts.Start, opts.End
+	var inWindow []Transaction
+	for _, tx := range txs {
+		if tx.Classification != "income" && tx.Classification != "expense" {
+			continue
+		}
+		if (!opts.Start.IsZero() && tx.Date.Before(opts.Start)) || (!opts.End.IsZero() && tx.Date.After(opts.End)) {
+			continue
+		}
+		inWindow = append(inWindow, tx)
+		if opts.Start.IsZero() && (start.IsZero() || tx.Date.Before(start)) {
+			start = tx.Date
+		}
+		if opts.End.IsZero() && tx.Date.After(end) {
+			end = tx.Date
+		}
+	}
+
+	report := CashflowReport{Months: []CashflowMonth{}, IncomeSources: []IncomeSource{}, NegativeMonths: []string{}}
+	if start.IsZero() {
+		return report
+	}
+
+	byMonth := map[string]*CashflowMonth{}
+	var order []string
+	for m := monthStart(start); !m.After(monthStart(end)); m = m.AddDate(0, 1, 0) {
+		ym := m.Format("2006-01")
+		byMonth[ym] = &CashflowMonth{Month: ym}
+		order = append(order, ym)
+	}
+
+	var incomes []Transaction
+	for _, tx := range inWindow {
+		v, err := SignedAmount(tx)
+		if err != nil {
+			continue
+		}
+		cm := byMonth[tx.Date.Format("2006-01")]
+		if tx.Classification == "income" {
+			cm.Income += math.Abs(v)
+			incomes = append(incomes, tx)
+		} else {
+			cm.Expense += math.Abs(v)
+		}
+	}
+
+	var inc, exp, net []float64
+	for _, ym := range order {
+		cm := byMonth[ym]
+		cm.Net = round2(cm.Income - cm.Expense)
+		cm.Income, cm.Expense = round2(cm.Income), round2(cm.Expense)
+		cm.SavingsRate = savingsRate(cm.Income, cm.Net)
+		cm.Negative = cm.Net < 0
+		if cm.Negative {
+			report.NegativeMonths = append(report.NegativeMonths, ym)
+		}
+		report.Totals.Income += cm.Income
+		report.Totals.Expense += cm.Expense
+		inc, exp, net = append(inc, cm.Income), append(exp, cm.Expense), append(net, cm.Net)
+		report.Months = append(report.Months, *cm)
+	}
+
+	t := &report.Totals
+	t.Income, t.Expense = round2(t.Income), round2(t.Expense)
+	t.Net = round2(t.Income - t.Expense)
+	t.SavingsRate = savingsRate(t.Income, t.Net)
+	n := float64(len(order))
+	t.AvgIncome, t.AvgExpense, t.AvgNet = round2(t.Income/n), round2(t.Expense/n), round2(t.Net/n)
+
+	v := &report.Volatility
+	incMean, incStd := meanStd(inc)
+	expMean, expStd := meanStd(exp)
+	_, netStd := meanStd(net)
+	v.IncomeStdDev, v.ExpenseStdDev, v.NetStdDev = round2(incStd), round2(expStd), round2(netStd)
+	if incMean > 0 {
+		v.IncomeCV = math.Round(incStd/incMean*1000) / 1000
+	}
+	if expMean > 0 {
+		v.ExpenseCV = math.Round(expStd/expMean*1000) / 1000
+	}
+	switch {
+	case v.IncomeCV < 0.1:
+		v.IncomeStability = "stable"
+	case v.IncomeCV < 0.3:
+		v.IncomeStability = "variable"
+	default:
+		v.IncomeStability = "volatile"
+	}
+
+	report.IncomeSources = incomeSources(incomes, monthStart(end), t.Income, opts)
+	return report
+}
+
+// RecurringIncome returns the salary and recurring income sources in txs,
+// i.e. the inflows a forecast can count on. Irregular income is dropped.
+func RecurringIncome(txs []Transaction, opts CashflowOptions) []IncomeSource {
+	opts = opts.withDefaults()
+	var incomes []Transaction
+	var last time.Time
+	var total float64
+	for _, tx := range txs {
+		if tx.Classification != "income" {
+			continue
+		}
+		v, err := SignedAmount(tx)
+		if err != nil {
+			continue
+		}
+		incomes = append(incomes, tx)
+		total += math.Abs(v)
+		if tx.Date.After(last) {
+			last = tx.Date
+		}
+	}
+	out := []IncomeSource{}
+	for _, src := range incomeSources(incomes, monthStart(last), total, opts) {
+		if src.Type != "irregular" {
+			out = append(out, src)
+		}
+	}
+	return out
+}
+
+// incomeSources groups income by payer and classifies each as salary (steady
+// monthly or biweekly amount and date), recurring (pays most months) or
+// irregular (freelance, one-offs).
+func incomeSources(incomes []Transaction, lastMonth time.Time, totalIncome float64, opts CashflowOptions) []IncomeSource {
+	out := []IncomeSource{}
+	for _, g := range GroupByMerchant(incomes, opts.Normalizer, nil) {
+		sort.Slice(g.Txs, func(i, j int) bool { return g.Txs[i].Date.Before(g.Txs[j].Date) })
+		src := IncomeSource{Source: g.Key, Name: g.Name, Count: len(g.Txs)}
+
+		var amounts []float64
+		months := map[string]bool{}
+		for _, tx := range g.Txs {
+			v, err := SignedAmount(tx)
+			if err != nil {
+				continue
+			}
+			amounts = append(amounts, math.Abs(v))
+			src.Total += math.Abs(v)
+			months[tx.Date.Format("2006-01")] = true
+		}
+		if len(amounts) == 0 {
+			continue
+		}
+		first, last := g.Txs[0].Date, g.Txs[len(g.Txs)-1].Date
+		src.FirstSeen, src.LastSeen = first.Format("2006-01-02"), last.Format("2006-01-02")
+		src.MonthsPresent = len(months)
+		span := (lastMonth.Year()-first.Year())*12 + int(lastMonth.Month()-first.Month()) + 1
+		src.Regularity = math.Round(float64(src.MonthsPresent)/float64(span)*100) / 100
+
+		amtMean, amtStd := meanStd(amounts)
+		src.AvgAmount = round2(amtMean)
+		if amtMean > 0 {
+			src.AmountCV = math.Round(amtStd/amtMean*1000) / 1000
+		}
+		if totalIncome > 0 {
+			src.ShareOfIncome = math.Round(src.Total/totalIncome*1000) / 1000
+		}
+		src.Total = round2(src.Total)
+
+		var intervals []float64
+		for i := 1; i < len(g.Txs); i++ {
+			intervals = append(intervals, g.Txs[i].Date.Sub(g.Txs[i-1].Date).Hours()/24)
+		}
+		periodMean, periodStd := meanStd(intervals)
+		if len(intervals) > 0 {
+			src.AvgPeriodDays = round2(periodMean)
+		}
+		cadence := (periodMean >= 12 && periodMean <= 16) || (periodMean >= 25 && periodMean <= 35)
+
+		switch {
+		case len(g.Txs) >= 3 && cadence && periodStd <= opts.SalaryMaxIntervalStdDays && src.AmountCV <= opts.SalaryMaxAmountCV:
+			src.Type = "salary"
+		case len(g.Txs) >= 3 && src.Regularity >= opts.RecurringMinRegularity:
+			src.Type = "recurring"
+		default:
+			src.Type = "irregular"
+		}
+		out = append(out, src)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Total != out[j].Total {
+			return out[i].Total > out[j].Total
+		}
+		return out[i].Source < out[j].Source
+	})
+	return out
+}
+
+func savingsRate(income, net float64) *float64 {
+	if income <= 0 {
+		return nil
+	}
+	r := math.Round(net/income*1000) / 1000
+	return &r
+}
@@ -0,0 +1,70 @@
+package insights
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestCashflow_SeriesAndNegativeMonths(t *testing.T) {
+	var txs []Transaction
+	for m := 0; m < 4; m++ {
+		d := mustDate("2026-01-25").AddDate(0, m, 0)
+		txs = append(txs,
+			Transaction{ID: fmt.Sprintf("sal%d", m), Name: "ACME PAYROLL", Classification: "income", AmountText: "-€3000.00", Date: d},
+			Transaction{ID: fmt.Sprintf("rent%d", m), Name: "Rent", Classification: "expense", AmountText: "€1500.00", Date: d.AddDate(0, 0, -20)},
+		)
+	}
+	// March: a big one-off purchase pushes the month negative.
+	txs = append(txs,
+		Transaction{ID: "tv", Name: "Electro Store", Classification: "expense", AmountText: "€2000.00", Date: mustDate("2026-03-15")},
+		Transaction{ID: "gig1", Name: "Upwork", Classification: "income", AmountText: "-€400.00", Date: mustDate("2026-02-09")},
+		Transaction{ID: "gig2", Name: "Upwork", Classification: "income", AmountText: "-€900.00", Date: mustDate("2026-04-20")},
+	)
+
+	r := Cashflow(txs, CashflowOptions{})
+	if len(r.Months) != 4 {
+		t.Fatalf("months: %+v", r.Months)
+	}
+	feb := r.Months[1]
+	if feb.Income != 3400 || feb.Expense != 1500 || feb.Net != 1900 || feb.SavingsRate == nil || *feb.SavingsRate != 0.559 {
+		t.Errorf("february: %+v", feb)
+	}
+	if len(r.NegativeMonths) != 1 || r.NegativeMonths[0] != "2026-03" || !r.Months[2].Negative {
+		t.Errorf("negative months: %v", r.NegativeMonths)
+	}
+	if r.Totals.Income != 13300 || r.Totals.Net != 5300 {
+		t.Errorf("totals: %+v", r.Totals)
+	}
+	if r.Volatility.IncomeStability != "variable" || r.Volatility.ExpenseCV == 0 {
+		t.Errorf("volatility: %+v", r.Volatility)
+	}
+
+	types := map[string]string{}
+	for _, s := range r.IncomeSources {
+		types[s.Name] = s.Type
+	}
+	if types["ACME PAYROLL"] != "salary" || types["Upwork"] != "irregular" {
+		t.Errorf("income source types: %v", types)
+	}
+}
+
+func TestCashflow_RecurringVariableIncome(t *testing.T) {
+	var txs []Transaction
+	// Rental income most months with varying amounts.
+	for m, amt := range []float64{800, 450, 0, 900, 600, 300} {
+		if amt == 0 {
+			continue
+		}
+		txs = append(txs, Transaction{ID: fmt.Sprintf("r%d", m), Name: "Airbnb", Classification: "income", AmountText: fmt.Sprintf("-€%.2f", amt), Date: mustDate("2026-01-03").AddDate(0, m, m*3)})
+	}
+	r := Cashflow(txs, CashflowOptions{})
+	if len(r.IncomeSources) != 1 || r.IncomeSources[0].Type != "recurring" {
+		t.Fatalf("expected a recurring source: %+v", r.IncomeSources)
+	}
+	if r.IncomeSources[0].Regularity != 0.83 {
+		t.Errorf("regularity = %v, want 0.83", r.IncomeSources[0].Regularity)
+	}
+	if r.Months[2].SavingsRate != nil {
+		t.Errorf("month without income should have null savings rate: %+v", r.Months[2])
+	}
+}
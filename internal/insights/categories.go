@@ -0,0 +1,275 @@
+package insights
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+const uncategorized = "Uncategorized"
+
+type CategoryTrend struct {
+	Category   string          `json:"category"`
+	CategoryID string          `json:"category_id,omitempty"`
+	Parent     string          `json:"parent,omitempty"`   // parent name for subcategories
+	Children   []string        `json:"children,omitempty"` // subcategories included in a rollup
+	Monthly    []MonthlyAmount `json:"monthly"`            // every month in the window, zero-filled
+	Total      float64         `json:"total"`
+	Current    float64         `json:"current"`  // report month
+	Previous   float64         `json:"previous"` // month before the report month
+	Delta      float64         `json:"delta"`
+	ChangePct  *float64        `json:"change_pct"` // null when previous is 0
+	// RollingAvg is the mean of the RollingMonths before the report month.
+	RollingAvg   float64  `json:"rolling_avg"`
+	VsRollingPct *float64 `json:"vs_rolling_pct"`
+	// LastYear is the same month a year earlier; null when outside the window.
+	LastYear *float64 `json:"last_year"`
+	YoYDelta *float64 `json:"yoy_delta"`
+	YoYPct   *float64 `json:"yoy_pct"`
+}
+
+type CategoryMover struct {
+	Category  string   `json:"category"`
+	Parent    string   `json:"parent,omitempty"`
+	Previous  float64  `json:"previous"`
+	Current   float64  `json:"current"`
+	Delta     float64  `json:"delta"`
+	ChangePct *float64 `json:"change_pct"`
+	Direction string   `json:"direction"` // up|down
+}
+
+type CategoryReport struct {
+	Month      string          `json:"month"`  // YYYY-MM report month
+	Months     []string        `json:"months"` // buckets, oldest first
+	Total      CategoryTrend   `json:"total"`
+	Categories []CategoryTrend `json:"categories"`
+	Parents    []CategoryTrend `json:"parents"` // rollups: parent's own spend plus its subcategories
+	Movers     []CategoryMover `json:"movers"`
+}
+
+// CategoryOptions tunes CategoryTrends; zero fields use DefaultCategoryOptions.
+type CategoryOptions struct {
+	RollingMonths int `json:"rolling_months"`
+	TopMovers     int `json:"top_movers"`
+	// Month is the report month; zero means the month of the latest transaction.
+	Month time.Time `json:"-"`
+	// Start is the first bucket; zero means the month of the earliest transaction.
+	Start time.Time `json:"-"`
+	// Categories is the category tree from `categories list`, used to roll
+	// subcategories up into their parents.
+	Categories []Category `json:"-"`
+}
+
+// DefaultCategoryOptions: 3-month rolling average, top 5 movers.
+func DefaultCategoryOptions() CategoryOptions {
+	return CategoryOptions{RollingMonths: 3, TopMovers: 5}
+}
+
+func (o CategoryOptions) withDefaults() CategoryOptions {
+	def := DefaultCategoryOptions()
+	if o.RollingMonths <= 0 {
+		o.RollingMonths = def.RollingMonths
+	}
+	if o.TopMovers <= 0 {
+		o.TopMovers = def.TopMovers
+	}
+	return o
+}
+
+// CategoryTrends buckets expenses per category and month and compares the
+// report month with the previous month, the rolling average and the same
+// month last year. Months after the report month are ignored.
+func CategoryTrends(txs []Transaction, opts CategoryOptions) CategoryReport {
+	opts = opts.withDefaults()
+
+	month, start := opts.Month, opts.Start
+	var expenses []Transaction
+	for _, tx := range txs {
+		if tx.Classification != "expense" {
+			continue
+		}
+		expenses = append(expenses, tx)
+		if opts.Month.IsZero() && tx.Date.After(month) {
+			month = tx.Date
+		}
+		if opts.Start.IsZero() && (start.IsZero() || tx.Date.Before(start)) {
+			start = tx.Date
+		}
+	}
+	month = monthStart(month)
+	start = monthStart(start)
+	if start.IsZero() || start.After(month) {
+		start = month
+	}
+
+	var months []string
+	for m := start; !m.After(month); m = m.AddDate(0, 1, 0) {
+		months = append(months, m.Format("2006-01"))
+	}
+
+	byID := map[string]Category{}
+	for _, c := range opts.Categories {
+		byID[c.ID] = c
+	}
+
+	type bucket struct {
+		name, id, parent string
+		children         map[string]bool
+		amounts          map[string]float64
+		counts           map[string]int
+	}
+	leaves := map[string]*bucket{}
+	parents := map[string]*bucket{}
+	total := &bucket{name: "Total", amounts: map[string]float64{}, counts: map[string]int{}}
+	get := func(m map[string]*bucket, key, name, id string) *bucket {
+		if m[key] == nil {
+			m[key] = &bucket{name: name, id: id, children: map[string]bool{}, amounts: map[string]float64{}, counts: map[string]int{}}
+		}
+		return m[key]
+	}
+
+	for _, tx := range expenses {
+		ym := tx.Date.Format("2006-01")
+		if ym < months[0] || ym > months[len(months)-1] {
+			continue
+		}
+		v, err := SignedAmount(tx)
+		if err != nil {
+			continue
+		}
+		amt := math.Abs(v)
+
+		name, id := tx.CategoryName, tx.CategoryID
+		if name == "" {
+			name, id = uncategorized, ""
+		}
+		key := id
+		if key == "" {
+			key = name
+		}
+		leaf := get(leaves, key, name, id)
+		leaf.amounts[ym] += amt
+		leaf.counts[ym]++
+		total.amounts[ym] += amt
+		total.counts[ym]++
+
+		c, known := byID[id]
+		switch {
+		case known && c.ParentID != "":
+			leaf.parent = c.ParentName
+			p := get(parents, c.ParentID, c.ParentName, c.ParentID)
+			p.children[name] = true
+			p.amounts[ym] += amt
+			p.counts[ym]++
+		case known:
+			// A root category's own spend counts towards its rollup.
+			p := get(parents, id, name, id)
+			p.amounts[ym] += amt
+			p.counts[ym]++
+		}
+	}
+
+	build := func(b *bucket) CategoryTrend {
+		t := CategoryTrend{Category: b.name, CategoryID: b.id, Parent: b.parent, Monthly: make([]MonthlyAmount, 0, len(months))}
+		for _, m := range months {
+			t.Monthly = append(t.Monthly, MonthlyAmount{Month: m, Amount: round2(b.amounts[m]), Count: b.counts[m]})
+			t.Total += b.amounts[m]
+		}
+		for c := range b.children {
+			t.Children = append(t.Children, c)
+		}
+		sort.Strings(t.Children)
+		t.Total = round2(t.Total)
+
+		at := func(m time.Time) (float64, bool) {
+			ym := m.Format("2006-01")
+			if ym < months[0] {
+				return 0, false
+			}
+			return b.amounts[ym], true
+		}
+		t.Current = round2(b.amounts[month.Format("2006-01")])
+		prev, _ := at(month.AddDate(0, -1, 0))
+		t.Previous = round2(prev)
+		t.Delta = round2(t.Current - t.Previous)
+		t.ChangePct = pctChange(t.Previous, t.Current)
+
+		var sum float64
+		var n int
+		for i := 1; i <= opts.RollingMonths; i++ {
+			if v, ok := at(month.AddDate(0, -i, 0)); ok {
+				sum += v
+				n++
+			}
+		}
+		if n > 0 {
+			t.RollingAvg = round2(sum / float64(n))
+			t.VsRollingPct = pctChange(t.RollingAvg, t.Current)
+		}
+
+		if ly, ok := at(month.AddDate(-1, 0, 0)); ok {
+			ly = round2(ly)
+			d := round2(t.Current - ly)
+			t.LastYear, t.YoYDelta, t.YoYPct = &ly, &d, pctChange(ly, t.Current)
+		}
+		return t
+	}
+
+	report := CategoryReport{
+		Month:      month.Format("2006-01"),
+		Months:     months,
+		Total:      build(total),
+		Categories: []CategoryTrend{},
+		Parents:    []CategoryTrend{},
+		Movers:     []CategoryMover{},
+	}
+	for _, b := range leaves {
+		report.Categories = append(report.Categories, build(b))
+	}
+	for _, b := range parents {
+		if len(b.children) == 0 {
+			continue
+		}
+		report.Parents = append(report.Parents, build(b))
+	}
+	byCurrent := func(list []CategoryTrend) {
+		sort.Slice(list, func(i, j int) bool {
+			if list[i].Current != list[j].Current {
+				return list[i].Current > list[j].Current
+			}
+			if list[i].Total != list[j].Total {
+				return list[i].Total > list[j].Total
+			}
+			return list[i].Category < list[j].Category
+		})
+	}
+	byCurrent(report.Categories)
+	byCurrent(report.Parents)
+
+	for _, t := range report.Categories {
+		if t.Delta == 0 {
+			continue
+		}
+		mv := CategoryMover{Category: t.Category, Parent: t.Parent, Previous: t.Previous, Current: t.Current, Delta: t.Delta, ChangePct: t.ChangePct, Direction: "up"}
+		if t.Delta < 0 {
+			mv.Direction = "down"
+		}
+		report.Movers = append(report.Movers, mv)
+	}
+	sort.SliceStable(report.Movers, func(i, j int) bool {
+		return math.Abs(report.Movers[i].Delta) > math.Abs(report.Movers[j].Delta)
+	})
+	if len(report.Movers) > opts.TopMovers {
+		report.Movers = report.Movers[:opts.TopMovers]
+	}
+	return report
+}
+
+// pctChange is the percent change from prev to cur, or nil when prev is 0.
+func pctChange(prev, cur float64) *float64 {
+	if prev == 0 {
+		return nil
+	}
+	p := math.Round((cur-prev)/prev*1000) / 10
+	return &p
+}
@@ -0,0 +1,83 @@
+package insights
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestCategoryTrends_DeltasRollingAndYoY(t *testing.T) {
+	cats := []Category{
+		{ID: "food", Name: "Food & Drink"},
+		{ID: "groc", Name: "Groceries", ParentID: "food", ParentName: "Food & Drink"},
+		{ID: "dine", Name: "Restaurants", ParentID: "food", ParentName: "Food & Drink"},
+		{ID: "util", Name: "Utilities"},
+	}
+	exp := func(id, cat, catID, date string, amt float64) Transaction {
+		return Transaction{ID: id, Name: cat, CategoryName: cat, CategoryID: catID, Classification: "expense", AmountText: fmt.Sprintf("€%.2f", amt), Date: mustDate(date)}
+	}
+	var txs []Transaction
+	// Dining 100/month from 2025-03 to 2026-02, then 180 in March 2026.
+	for m := 0; m < 12; m++ {
+		d := mustDate("2025-03-10").AddDate(0, m, 0)
+		txs = append(txs, exp(fmt.Sprintf("d%d", m), "Restaurants", "dine", d.Format("2006-01-02"), 100))
+		txs = append(txs, exp(fmt.Sprintf("g%d", m), "Groceries", "groc", d.Format("2006-01-02"), 200))
+	}
+	txs = append(txs,
+		exp("d-mar", "Restaurants", "dine", "2026-03-12", 180),
+		exp("g-mar", "Groceries", "groc", "2026-03-12", 200),
+		exp("u-mar", "Utilities", "util", "2026-03-02", 60),
+		// After the report month: ignored.
+		exp("d-apr", "Restaurants", "dine", "2026-04-02", 999),
+		Transaction{ID: "inc", Name: "Salary", Classification: "income", AmountText: "-€2000.00", Date: mustDate("2026-03-01")},
+	)
+
+	r := CategoryTrends(txs, CategoryOptions{Month: mustDate("2026-03-01"), Start: mustDate("2025-03-01"), Categories: cats})
+	if r.Month != "2026-03" || len(r.Months) != 13 {
+		t.Fatalf("month/months: %s %v", r.Month, r.Months)
+	}
+
+	var dining CategoryTrend
+	for _, c := range r.Categories {
+		if c.Category == "Restaurants" {
+			dining = c
+		}
+	}
+	if dining.Current != 180 || dining.Previous != 100 || dining.Delta != 80 || dining.ChangePct == nil || *dining.ChangePct != 80 {
+		t.Errorf("dining month-over-month: %+v", dining)
+	}
+	if dining.Parent != "Food & Drink" || dining.RollingAvg != 100 {
+		t.Errorf("dining parent/rolling: %+v", dining)
+	}
+	if dining.LastYear == nil || *dining.LastYear != 100 || *dining.YoYPct != 80 {
+		t.Errorf("dining year-over-year: %+v", dining)
+	}
+
+	if len(r.Parents) != 1 || r.Parents[0].Category != "Food & Drink" || r.Parents[0].Current != 380 || len(r.Parents[0].Children) != 2 {
+		t.Errorf("parent rollup: %+v", r.Parents)
+	}
+	if r.Total.Current != 440 {
+		t.Errorf("total current = %v, want 440", r.Total.Current)
+	}
+
+	if len(r.Movers) != 2 || r.Movers[0].Category != "Restaurants" || r.Movers[0].Direction != "up" {
+		t.Errorf("movers: %+v", r.Movers)
+	}
+	if r.Movers[1].Category != "Utilities" || r.Movers[1].ChangePct != nil {
+		t.Errorf("new category should have a null change_pct: %+v", r.Movers[1])
+	}
+}
+
+func TestCategoryTrends_UncategorizedAndShortWindow(t *testing.T) {
+	txs := []Transaction{
+		{ID: "a", Name: "Cash", Classification: "expense", AmountText: "€20.00", Date: mustDate("2026-02-05")},
+		{ID: "b", Name: "Cash", Classification: "expense", AmountText: "€30.00", Date: mustDate("2026-03-05")},
+	}
+	r := CategoryTrends(txs, CategoryOptions{})
+	if r.Month != "2026-03" || len(r.Categories) != 1 || r.Categories[0].Category != "Uncategorized" {
+		t.Fatalf("report: %+v", r)
+	}
+	c := r.Categories[0]
+	if c.LastYear != nil || c.RollingAvg != 20 || c.Monthly[0].Count != 1 {
+		t.Errorf("short window: %+v", c)
+	}
+}
@@ -0,0 +1,252 @@
+package insights
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// DuplicateCandidate is a set of transactions that look like the same
+// real-world charge recorded more than once (typically bank sync + CSV import).
+type DuplicateCandidate struct {
+	KeepTxID        string        `json:"keep_tx_id"`
+	DuplicateTxIDs  []string      `json:"duplicate_tx_ids"`
+	Transactions    []DuplicateTx `json:"transactions"`
+	Account         string        `json:"account"`
+	Amount          float64       `json:"amount"` // signed: expense negative, income positive
+	Merchant        string        `json:"merchant"`
+	DaysApart       int           `json:"days_apart"`
+	Similarity      float64       `json:"similarity"` // 0..1 name similarity (lowest pair)
+	Confidence      float64       `json:"confidence"`
+	Reason          string        `json:"reason"`
+	SuggestedAction string        `json:"suggested_action"`
+}
+
+type DuplicateTx struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	Date     string `json:"date"`
+	Category string `json:"category,omitempty"`
+}
+
+type dupEntry struct {
+	tx     Transaction
+	amount float64
+	key    string
+}
+
+// DuplicateOptions tunes DetectDuplicatesWithOptions; zero fields use DefaultDuplicateOptions.
+type DuplicateOptions struct {
+	WindowDays    int     `json:"window_days"`    // max days between copies
+	MinSimilarity float64 `json:"min_similarity"` // min name similarity to link two transactions
+	// HabitualCount is how many same-amount charges at one merchant and
+	// account make repeats plausible (daily coffee); such groups lose confidence.
+	HabitualCount int         `json:"habitual_count"`
+	Normalizer    *Normalizer `json:"-"`
+}
+
+// DefaultDuplicateOptions: within 3 days, 60% name similarity, habitual from 4 repeats.
+func DefaultDuplicateOptions() DuplicateOptions {
+	return DuplicateOptions{WindowDays: 3, MinSimilarity: 0.6, HabitualCount: 4}
+}
+
+func (o DuplicateOptions) withDefaults() DuplicateOptions {
+	def := DefaultDuplicateOptions()
+	if o.WindowDays <= 0 {
+		o.WindowDays = def.WindowDays
+	}
+	if o.MinSimilarity <= 0 {
+		o.MinSimilarity = def.MinSimilarity
+	}
+	if o.HabitualCount <= 0 {
+		o.HabitualCount = def.HabitualCount
+	}
+	return o
+}
+
+// DetectDuplicates finds likely duplicates using DefaultDuplicateOptions.
+func DetectDuplicates(txs []Transaction) []DuplicateCandidate {
+	return DetectDuplicatesWithOptions(txs, DefaultDuplicateOptions())
+}
+
+// DetectDuplicatesWithOptions links transactions on the same account with the
+// same signed amount, at most WindowDays apart and with similar names, then
+// reports each connected group once. The copy with the most metadata
+// (category, merchant) is kept; ties go to the earliest date, then lowest id.
+func DetectDuplicatesWithOptions(txs []Transaction, opts DuplicateOptions) []DuplicateCandidate {
+	opts = opts.withDefaults()
+
+	buckets := map[string][]dupEntry{}
+	for _, tx := range txs {
+		v, err := SignedAmount(tx)
+		if err != nil {
+			continue
+		}
+		account := tx.AccountID
+		if account == "" {
+			account = tx.AccountName
+		}
+		b := fmt.Sprintf("%s|%s|%d", account, tx.Classification, int64(math.Round(v*100)))
+		buckets[b] = append(buckets[b], dupEntry{tx: tx, amount: v, key: opts.Normalizer.Key(tx)})
+	}
+
+	var out []DuplicateCandidate
+	for _, list := range buckets {
+		if len(list) < 2 {
+			continue
+		}
+		sort.Slice(list, func(i, j int) bool {
+			if !list[i].tx.Date.Equal(list[j].tx.Date) {
+				return list[i].tx.Date.Before(list[j].tx.Date)
+			}
+			return list[i].tx.ID < list[j].tx.ID
+		})
+
+		// Union-find over linked pairs.
+		parent := make([]int, len(list))
+		for i := range parent {
+			parent[i] = i
+		}
+		var find func(int) int
+		find = func(i int) int {
+			if parent[i] != i {
+				parent[i] = find(parent[i])
+			}
+			return parent[i]
+		}
+		simOf := map[[2]int]float64{}
+		for i := range list {
+			for j := i + 1; j < len(list); j++ {
+				days := int(list[j].tx.Date.Sub(list[i].tx.Date).Hours() / 24)
+				if days > opts.WindowDays {
+					break
+				}
+				sim := nameSimilarity(list[i], list[j])
+				if sim < opts.MinSimilarity {
+					continue
+				}
+				simOf[[2]int{i, j}] = sim
+				parent[find(j)] = find(i)
+			}
+		}
+
+		groups := map[int][]int{}
+		for i := range list {
+			groups[find(i)] = append(groups[find(i)], i)
+		}
+		for _, idx := range groups {
+			if len(idx) < 2 {
+				continue
+			}
+			sim := 1.0
+			for pair, s := range simOf {
+				if find(pair[0]) == find(idx[0]) && s < sim {
+					sim = s
+				}
+			}
+			first, last := list[idx[0]].tx.Date, list[idx[len(idx)-1]].tx.Date
+			daysApart := int(last.Sub(first).Hours() / 24)
+
+			// Habitual purchases: many same-amount charges at this merchant.
+			habitual := 0
+			for _, e := range list {
+				if e.key == list[idx[0]].key {
+					habitual++
+				}
+			}
+
+			conf := 0.45 + 0.35*sim + 0.2*(1-float64(daysApart)/float64(opts.WindowDays+1))
+			reason := "same_account_amount_near_date"
+			action := "Review and delete the redundant copy (sure-cli transactions dedupe)"
+			if habitual >= opts.HabitualCount {
+				conf -= 0.25
+				reason = "same_amount_habitual_merchant"
+				action = "Frequent same-amount purchases here; confirm it is not a genuine repeat before deleting"
+			}
+			conf = math.Round(math.Min(conf, 0.99)*100) / 100
+
+			keep := idx[0]
+			for _, i := range idx[1:] {
+				if richness(list[i].tx) > richness(list[keep].tx) {
+					keep = i
+				}
+			}
+			c := DuplicateCandidate{
+				KeepTxID:        list[keep].tx.ID,
+				DuplicateTxIDs:  []string{},
+				Account:         list[keep].tx.AccountName,
+				Amount:          round2(list[keep].amount),
+				Merchant:        list[keep].key,
+				DaysApart:       daysApart,
+				Similarity:      round2(sim),
+				Confidence:      conf,
+				Reason:          reason,
+				SuggestedAction: action,
+			}
+			if c.Account == "" {
+				c.Account = list[keep].tx.AccountID
+			}
+			for _, i := range idx {
+				tx := list[i].tx
+				c.Transactions = append(c.Transactions, DuplicateTx{ID: tx.ID, Name: tx.Name, Date: tx.Date.Format("2006-01-02"), Category: tx.CategoryName})
+				if i != keep {
+					c.DuplicateTxIDs = append(c.DuplicateTxIDs, tx.ID)
+				}
+			}
+			out = append(out, c)
+		}
+	}
+
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Confidence != out[j].Confidence {
+			return out[i].Confidence > out[j].Confidence
+		}
+		return out[i].KeepTxID < out[j].KeepTxID
+	})
+	return out
+}
+
+// richness ranks which copy to keep: categorised beats merchant-tagged beats bare.
+func richness(tx Transaction) int {
+	n := 0
+	if tx.CategoryID != "" || tx.CategoryName != "" {
+		n += 2
+	}
+	if tx.MerchantID != "" || tx.MerchantName != "" {
+		n++
+	}
+	return n
+}
+
+// nameSimilarity is 1 for equal merchant keys, else the Dice coefficient of
+// the normalized names' character bigrams.
+func nameSimilarity(a, b dupEntry) float64 {
+	if a.key == b.key {
+		return 1
+	}
+	return dice(a.key, b.key)
+}
+
+func dice(a, b string) float64 {
+	a, b = strings.ReplaceAll(a, " ", ""), strings.ReplaceAll(b, " ", "")
+	if len(a) < 2 || len(b) < 2 {
+		if a == b {
+			return 1
+		}
+		return 0
+	}
+	grams := map[string]int{}
+	for i := 0; i+1 < len(a); i++ {
+		grams[a[i:i+2]]++
+	}
+	shared := 0
+	for i := 0; i+1 < len(b); i++ {
+		g := b[i : i+2]
+		if grams[g] > 0 {
+			grams[g]--
+			shared++
+		}
+	}
+	return 2 * float64(shared) / float64(len(a)-1+len(b)-1)
+}
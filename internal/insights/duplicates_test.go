@@ -0,0 +1,68 @@
+package insights
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestDetectDuplicates_SyncAndImport(t *testing.T) {
+	txs := []Transaction{
+		// Bank sync copy carries a category; the CSV import copy is bare and a day later.
+		{ID: "sync", Name: "MERCADONA VALENCIA", AccountID: "acc1", CategoryName: "Groceries", Classification: "expense", AmountText: "€54.20", Date: mustDate("2026-03-10")},
+		{ID: "csv", Name: "Mercadona", AccountID: "acc1", Classification: "expense", AmountText: "€54.20", Date: mustDate("2026-03-11")},
+		// Same amount on another account is not a duplicate.
+		{ID: "other-acc", Name: "Mercadona", AccountID: "acc2", Classification: "expense", AmountText: "€54.20", Date: mustDate("2026-03-10")},
+		// Same account and amount but a different merchant.
+		{ID: "other-name", Name: "Iberdrola", AccountID: "acc1", Classification: "expense", AmountText: "€54.20", Date: mustDate("2026-03-10")},
+		// Too far apart.
+		{ID: "late", Name: "Mercadona", AccountID: "acc1", Classification: "expense", AmountText: "€54.20", Date: mustDate("2026-03-20")},
+	}
+	got := DetectDuplicates(txs)
+	if len(got) != 1 {
+		t.Fatalf("expected 1 group, got %+v", got)
+	}
+	c := got[0]
+	if c.KeepTxID != "sync" || len(c.DuplicateTxIDs) != 1 || c.DuplicateTxIDs[0] != "csv" {
+		t.Errorf("keep/duplicates: %+v", c)
+	}
+	if c.DaysApart != 1 || c.Similarity != 1 || c.Confidence < 0.9 || c.Reason != "same_account_amount_near_date" {
+		t.Errorf("scoring: %+v", c)
+	}
+}
+
+func TestDetectDuplicates_FuzzyNamesAndWindow(t *testing.T) {
+	txs := []Transaction{
+		{ID: "a", Name: "Carrefour Express", AccountName: "Checking", Classification: "expense", AmountText: "€23.40", Date: mustDate("2026-03-01")},
+		{ID: "b", Name: "CARREFOUR EXP MADRID", AccountName: "Checking", Classification: "expense", AmountText: "€23.40", Date: mustDate("2026-03-04")},
+	}
+	got := DetectDuplicates(txs)
+	if len(got) != 1 || got[0].Similarity >= 1 || got[0].Similarity < 0.6 {
+		t.Fatalf("expected one fuzzy match, got %+v", got)
+	}
+	if got[0].KeepTxID != "a" {
+		t.Errorf("tie should keep the earliest: %+v", got[0])
+	}
+	if none := DetectDuplicatesWithOptions(txs, DuplicateOptions{WindowDays: 2}); len(none) != 0 {
+		t.Errorf("window 2 should not link 3 days apart: %+v", none)
+	}
+	if none := DetectDuplicatesWithOptions(txs, DuplicateOptions{MinSimilarity: 0.95}); len(none) != 0 {
+		t.Errorf("high similarity should not link: %+v", none)
+	}
+}
+
+func TestDetectDuplicates_HabitualMerchantLowersConfidence(t *testing.T) {
+	var txs []Transaction
+	// Same coffee every day: two on one day is plausible, not a sync glitch.
+	for i := 0; i < 6; i++ {
+		txs = append(txs, Transaction{ID: fmt.Sprintf("c%d", i), Name: "Starbucks", AccountID: "acc1", Classification: "expense", AmountText: "€3.50", Date: mustDate("2026-03-02").AddDate(0, 0, 7*i)})
+	}
+	txs = append(txs, Transaction{ID: "c-extra", Name: "Starbucks", AccountID: "acc1", Classification: "expense", AmountText: "€3.50", Date: mustDate("2026-03-02")})
+
+	got := DetectDuplicates(txs)
+	if len(got) != 1 {
+		t.Fatalf("expected 1 group, got %+v", got)
+	}
+	if got[0].Reason != "same_amount_habitual_merchant" || got[0].Confidence >= 0.8 {
+		t.Errorf("habitual group should fall below the dedupe default: %+v", got[0])
+	}
+}
@@ -8,6 +8,7 @@ import (
 
 type FeeCandidate struct {
 	Name            string   `json:"name"`
+	Merchant        string   `json:"merchant"` // normalized grouping key
 	Count           int      `json:"count"`
 	TotalAmount     float64  `json:"total_amount"` // positive number (absolute)
 	AvgAmount       float64  `json:"avg_amount"`
@@ -71,25 +72,30 @@ func GetFeeKeywords(custom []string) []string {
 	return DefaultFeeKeywords
 }
 
+// FeeOptions tunes DetectFeesWithOptions. Empty Keywords means DefaultFeeKeywords.
+type FeeOptions struct {
+	Keywords []string `json:"keywords,omitempty"`
+	// Normalizer groups descriptors by merchant; nil uses the built-in rules.
+	Normalizer *Normalizer `json:"-"`
+}
+
 // DetectFees flags expense transactions that look like bank/service fees.
 // Heuristic: expense + name contains a fee keyword OR absolute amount is small and name contains bank-y words.
 func DetectFees(txs []Transaction, keywords []string) []FeeCandidate {
-	keywords = GetFeeKeywords(keywords)
+	return DetectFeesWithOptions(txs, FeeOptions{Keywords: keywords})
+}
 
-	byName := map[string][]Transaction{}
-	for _, tx := range txs {
-		if tx.Classification != "expense" {
-			continue
-		}
-		nameLower := strings.ToLower(tx.Name)
-		if !containsAny(nameLower, keywords) {
-			continue
-		}
-		byName[tx.Name] = append(byName[tx.Name], tx)
+// DetectFeesWithOptions is DetectFees with an options struct.
+func DetectFeesWithOptions(txs []Transaction, opts FeeOptions) []FeeCandidate {
+	keywords := GetFeeKeywords(opts.Keywords)
+
+	isFee := func(tx Transaction) bool {
+		return isExpense(tx) && containsAny(strings.ToLower(tx.Name), keywords)
 	}
 
 	var out []FeeCandidate
-	for name, list := range byName {
+	for _, g := range GroupByMerchant(txs, opts.Normalizer, isFee) {
+		list := g.Txs
 		var total float64
 		ids := make([]string, 0, min(3, len(list)))
 		for i, tx := range list {
@@ -123,7 +129,8 @@ func DetectFees(txs []Transaction, keywords []string) []FeeCandidate {
 		}
 
 		out = append(out, FeeCandidate{
-			Name:            name,
+			Name:            g.Name,
+			Merchant:        g.Key,
 			Count:           len(list),
 			TotalAmount:     round2(total),
 			AvgAmount:       round2(avg),
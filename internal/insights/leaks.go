@@ -7,6 +7,7 @@ import (
 
 type LeakCandidate struct {
 	Name            string   `json:"name"`
+	Merchant        string   `json:"merchant"` // normalized grouping key
 	Count           int      `json:"count"`
 	TotalAmount     float64  `json:"total_amount"` // positive
 	AvgAmount       float64  `json:"avg_amount"`
@@ -28,26 +29,45 @@ type LeakCandidate struct {
 //
 // NOTE: Defaults are intentionally conservative to avoid noise.
 func DetectLeaks(txs []Transaction, minCount int, minTotal float64, maxAvg float64) []LeakCandidate {
-	if minCount <= 0 {
-		minCount = 3
+	return DetectLeaksWithOptions(txs, LeakOptions{MinCount: minCount, MinTotal: minTotal, MaxAvg: maxAvg})
+}
+
+// LeakOptions tunes DetectLeaksWithOptions; zero fields use DefaultLeakOptions.
+type LeakOptions struct {
+	MinCount int     `json:"min_count"`
+	MinTotal float64 `json:"min_total"`
+	MaxAvg   float64 `json:"max_avg"`
+	// Normalizer groups descriptors by merchant; nil uses the built-in rules.
+	Normalizer *Normalizer `json:"-"`
+}
+
+// DefaultLeakOptions: at least 3 occurrences totalling 15+ with an average of 10 or less.
+func DefaultLeakOptions() LeakOptions {
+	return LeakOptions{MinCount: 3, MinTotal: 15, MaxAvg: 10}
+}
+
+func (o LeakOptions) withDefaults() LeakOptions {
+	def := DefaultLeakOptions()
+	if o.MinCount <= 0 {
+		o.MinCount = def.MinCount
 	}
-	if minTotal <= 0 {
-		minTotal = 15
+	if o.MinTotal <= 0 {
+		o.MinTotal = def.MinTotal
 	}
-	if maxAvg <= 0 {
-		maxAvg = 10
+	if o.MaxAvg <= 0 {
+		o.MaxAvg = def.MaxAvg
 	}
+	return o
+}
 
-	byName := map[string][]Transaction{}
-	for _, tx := range txs {
-		if tx.Classification != "expense" {
-			continue
-		}
-		byName[tx.Name] = append(byName[tx.Name], tx)
-	}
+// DetectLeaksWithOptions is DetectLeaks with an options struct.
+func DetectLeaksWithOptions(txs []Transaction, opts LeakOptions) []LeakCandidate {
+	opts = opts.withDefaults()
+	minCount, minTotal, maxAvg := opts.MinCount, opts.MinTotal, opts.MaxAvg
 
 	var out []LeakCandidate
-	for name, list := range byName {
+	for _, g := range GroupByMerchant(txs, opts.Normalizer, isExpense) {
+		list := g.Txs
 		if len(list) < minCount {
 			continue
 		}
@@ -95,7 +115,8 @@ func DetectLeaks(txs []Transaction, minCount int, minTotal float64, maxAvg float
 		}
 
 		out = append(out, LeakCandidate{
-			Name:            name,
+			Name:            g.Name,
+			Merchant:        g.Key,
 			Count:           len(list),
 			TotalAmount:     round2(total),
 			AvgAmount:       round2(avg),
@@ -0,0 +1,241 @@
+package insights
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+type MerchantStat struct {
+	Merchant     string          `json:"merchant"` // grouping key (normalized name or Sure merchant id)
+	Name         string          `json:"name"`
+	MerchantID   string          `json:"merchant_id,omitempty"`
+	Count        int             `json:"count"`
+	TotalAmount  float64         `json:"total_amount"` // positive
+	AvgAmount    float64         `json:"avg_amount"`
+	ShareOfSpend float64         `json:"share_of_spend"` // 0..1 of all expense spend in the window
+	FirstSeen    string          `json:"first_seen"`
+	LastSeen     string          `json:"last_seen"`
+	Monthly      []MonthlyAmount `json:"monthly"`
+	Trend        string          `json:"trend"`          // up|down|flat|new|stopped
+	MoMChangePct float64         `json:"mom_change_pct"` // last complete month vs the one before, percent
+}
+
+type MonthlyAmount struct {
+	Month  string  `json:"month"` // YYYY-MM
+	Amount float64 `json:"amount"`
+	Count  int     `json:"count"`
+}
+
+type MerchantReport struct {
+	TotalSpend    float64        `json:"total_spend"`
+	MerchantCount int            `json:"merchant_count"`
+	Merchants     []MerchantStat `json:"merchants"`
+}
+
+// MerchantOptions tunes RankMerchants.
+type MerchantOptions struct {
+	Top     int    `json:"top"`      // 0 = all
+	SortBy  string `json:"sort_by"`  // spend|count|avg
+	GroupBy string `json:"group_by"` // name|id
+	// TrendThresholdPct is the month-over-month change below which a merchant
+	// is "flat".
+	TrendThresholdPct float64 `json:"trend_threshold_pct"`
+	// End marks the window end; its month only counts as complete when End
+	// is the month's last day. Zero means the latest transaction date.
+	End        time.Time   `json:"-"`
+	Normalizer *Normalizer `json:"-"`
+}
+
+// DefaultMerchantOptions: top 20 by spend, grouped by normalized name, 10% trend band.
+func DefaultMerchantOptions() MerchantOptions {
+	return MerchantOptions{Top: 20, SortBy: "spend", GroupBy: "name", TrendThresholdPct: 10}
+}
+
+func (o MerchantOptions) withDefaults() MerchantOptions {
+	def := DefaultMerchantOptions()
+	if o.Top < 0 {
+		o.Top = 0
+	}
+	if o.SortBy == "" {
+		o.SortBy = def.SortBy
+	}
+	if o.GroupBy == "" {
+		o.GroupBy = def.GroupBy
+	}
+	if o.TrendThresholdPct <= 0 {
+		o.TrendThresholdPct = def.TrendThresholdPct
+	}
+	return o
+}
+
+// RankMerchants aggregates expense transactions per merchant and ranks them.
+// With GroupBy "id", transactions carrying a Sure merchant id group by it and
+// the rest fall back to the normalized name.
+func RankMerchants(txs []Transaction, opts MerchantOptions) MerchantReport {
+	opts = opts.withDefaults()
+
+	var expenses []Transaction
+	end := opts.End
+	for _, tx := range txs {
+		if tx.Classification != "expense" {
+			continue
+		}
+		expenses = append(expenses, tx)
+		if opts.End.IsZero() && tx.Date.After(end) {
+			end = tx.Date
+		}
+	}
+	lastComplete := monthStart(end).AddDate(0, -1, 0)
+	if end.AddDate(0, 0, 1).Day() == 1 {
+		lastComplete = monthStart(end)
+	}
+
+	var groups []MerchantGroup
+	if opts.GroupBy == "id" {
+		groups = groupByMerchantID(expenses, opts.Normalizer)
+	} else {
+		groups = GroupByMerchant(expenses, opts.Normalizer, nil)
+	}
+
+	report := MerchantReport{Merchants: []MerchantStat{}}
+	for _, g := range groups {
+		st := MerchantStat{Merchant: g.Key, Name: g.Name, Count: len(g.Txs)}
+		byMonth := map[string]*MonthlyAmount{}
+		var first, last time.Time
+		for _, tx := range g.Txs {
+			if st.MerchantID == "" {
+				st.MerchantID = tx.MerchantID
+			}
+			v, err := SignedAmount(tx)
+			if err != nil {
+				continue
+			}
+			amt := math.Abs(v)
+			st.TotalAmount += amt
+			m := tx.Date.Format("2006-01")
+			if byMonth[m] == nil {
+				byMonth[m] = &MonthlyAmount{Month: m}
+			}
+			byMonth[m].Amount += amt
+			byMonth[m].Count++
+			if first.IsZero() || tx.Date.Before(first) {
+				first = tx.Date
+			}
+			if tx.Date.After(last) {
+				last = tx.Date
+			}
+		}
+		report.TotalSpend += st.TotalAmount
+		if st.Count > 0 {
+			st.AvgAmount = round2(st.TotalAmount / float64(st.Count))
+		}
+		st.FirstSeen = first.Format("2006-01-02")
+		st.LastSeen = last.Format("2006-01-02")
+
+		for _, m := range byMonth {
+			m.Amount = round2(m.Amount)
+			st.Monthly = append(st.Monthly, *m)
+		}
+		sort.Slice(st.Monthly, func(i, j int) bool { return st.Monthly[i].Month < st.Monthly[j].Month })
+
+		cur := monthAmount(byMonth, lastComplete)
+		prev := monthAmount(byMonth, lastComplete.AddDate(0, -1, 0))
+		st.Trend, st.MoMChangePct = trend(prev, cur, opts.TrendThresholdPct)
+		report.Merchants = append(report.Merchants, st)
+	}
+
+	for i := range report.Merchants {
+		if report.TotalSpend > 0 {
+			report.Merchants[i].ShareOfSpend = math.Round(report.Merchants[i].TotalAmount/report.TotalSpend*1000) / 1000
+		}
+		report.Merchants[i].TotalAmount = round2(report.Merchants[i].TotalAmount)
+	}
+	report.TotalSpend = round2(report.TotalSpend)
+	report.MerchantCount = len(report.Merchants)
+
+	less := func(a, b MerchantStat) bool {
+		switch opts.SortBy {
+		case "count":
+			if a.Count != b.Count {
+				return a.Count > b.Count
+			}
+		case "avg":
+			if a.AvgAmount != b.AvgAmount {
+				return a.AvgAmount > b.AvgAmount
+			}
+		}
+		if a.TotalAmount != b.TotalAmount {
+			return a.TotalAmount > b.TotalAmount
+		}
+		return a.Merchant < b.Merchant
+	}
+	sort.SliceStable(report.Merchants, func(i, j int) bool { return less(report.Merchants[i], report.Merchants[j]) })
+	if opts.Top > 0 && len(report.Merchants) > opts.Top {
+		report.Merchants = report.Merchants[:opts.Top]
+	}
+	return report
+}
+
+// groupByMerchantID keys by Sure merchant id where present, keeping the
+// normalized name for transactions without one.
+func groupByMerchantID(txs []Transaction, n *Normalizer) []MerchantGroup {
+	var withID, withoutID []Transaction
+	for _, tx := range txs {
+		if tx.MerchantID != "" {
+			withID = append(withID, tx)
+		} else {
+			withoutID = append(withoutID, tx)
+		}
+	}
+	byID := map[string][]Transaction{}
+	for _, tx := range withID {
+		byID[tx.MerchantID] = append(byID[tx.MerchantID], tx)
+	}
+	var out []MerchantGroup
+	for id, list := range byID {
+		name := list[0].MerchantName
+		if name == "" {
+			names := map[string]int{}
+			for _, tx := range list {
+				names[tx.Name]++
+			}
+			name = mostCommon(names)
+		}
+		out = append(out, MerchantGroup{Key: id, Name: name, Txs: list})
+	}
+	out = append(out, GroupByMerchant(withoutID, n, nil)...)
+	sort.Slice(out, func(i, j int) bool { return out[i].Key < out[j].Key })
+	return out
+}
+
+func monthStart(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
+
+func monthAmount(byMonth map[string]*MonthlyAmount, month time.Time) float64 {
+	if m, ok := byMonth[month.Format("2006-01")]; ok {
+		return m.Amount
+	}
+	return 0
+}
+
+// trend classifies the change from prev to cur.
+func trend(prev, cur, thresholdPct float64) (string, float64) {
+	switch {
+	case prev == 0 && cur == 0:
+		return "flat", 0
+	case prev == 0:
+		return "new", 0
+	case cur == 0:
+		return "stopped", -100
+	}
+	pct := math.Round((cur-prev)/prev*1000) / 10
+	switch {
+	case pct >= thresholdPct:
+		return "up", pct
+	case pct <= -thresholdPct:
+		return "down", pct
+	}
+	return "flat", pct
+}
@@ -0,0 +1,65 @@
+package insights
+
+import "testing"
+
+func TestRankMerchants_SpendShareAndTrend(t *testing.T) {
+	txs := []Transaction{
+		{ID: "1", Name: "Mercadona 0012", Classification: "expense", AmountText: "€100.00", Date: mustDate("2026-01-10")},
+		{ID: "2", Name: "MERCADONA", Classification: "expense", AmountText: "€150.00", Date: mustDate("2026-02-10")},
+		{ID: "3", Name: "Starbucks", Classification: "expense", AmountText: "€5.00", Date: mustDate("2026-01-03")},
+		{ID: "4", Name: "Starbucks", Classification: "expense", AmountText: "€5.00", Date: mustDate("2026-01-04")},
+		{ID: "5", Name: "Starbucks", Classification: "expense", AmountText: "€5.00", Date: mustDate("2026-02-04")},
+		{ID: "6", Name: "Zara", Classification: "expense", AmountText: "€35.00", Date: mustDate("2026-02-20")},
+		{ID: "7", Name: "Salary", Classification: "income", AmountText: "-€2000.00", Date: mustDate("2026-02-01")},
+	}
+	opts := DefaultMerchantOptions()
+	opts.End = mustDate("2026-02-28")
+
+	r := RankMerchants(txs, opts)
+	if r.MerchantCount != 3 || r.TotalSpend != 300 {
+		t.Fatalf("expected 3 merchants / 300 spend, got %d / %v", r.MerchantCount, r.TotalSpend)
+	}
+	top := r.Merchants[0]
+	if top.Merchant != "mercadona" || top.Count != 2 || top.TotalAmount != 250 {
+		t.Fatalf("unexpected top merchant: %+v", top)
+	}
+	if top.ShareOfSpend != 0.833 || top.Trend != "up" || top.MoMChangePct != 50 {
+		t.Errorf("share/trend mismatch: %+v", top)
+	}
+	if top.FirstSeen != "2026-01-10" || top.LastSeen != "2026-02-10" || len(top.Monthly) != 2 {
+		t.Errorf("seen/monthly mismatch: %+v", top)
+	}
+
+	byName := map[string]MerchantStat{}
+	for _, m := range r.Merchants {
+		byName[m.Merchant] = m
+	}
+	if byName["starbucks"].Trend != "down" || byName["zara"].Trend != "new" {
+		t.Errorf("trends: starbucks=%s zara=%s", byName["starbucks"].Trend, byName["zara"].Trend)
+	}
+
+	opts.SortBy = "count"
+	opts.Top = 1
+	r = RankMerchants(txs, opts)
+	if len(r.Merchants) != 1 || r.Merchants[0].Merchant != "starbucks" || r.MerchantCount != 3 {
+		t.Fatalf("expected starbucks first by count with full merchant_count, got %+v", r)
+	}
+}
+
+func TestRankMerchants_GroupByID(t *testing.T) {
+	txs := []Transaction{
+		{ID: "1", Name: "NFLX DIGITAL", MerchantID: "mer_1", MerchantName: "Netflix", Classification: "expense", AmountText: "€12.99", Date: mustDate("2026-01-05")},
+		{ID: "2", Name: "NETFLIX.COM", MerchantID: "mer_1", MerchantName: "Netflix", Classification: "expense", AmountText: "€12.99", Date: mustDate("2026-02-05")},
+		{ID: "3", Name: "Corner Shop 22", Classification: "expense", AmountText: "€8.00", Date: mustDate("2026-02-06")},
+	}
+	r := RankMerchants(txs, MerchantOptions{GroupBy: "id"})
+	if r.MerchantCount != 2 {
+		t.Fatalf("expected 2 merchants, got %+v", r.Merchants)
+	}
+	if r.Merchants[0].Merchant != "mer_1" || r.Merchants[0].Name != "Netflix" || r.Merchants[0].Count != 2 {
+		t.Errorf("unexpected id group: %+v", r.Merchants[0])
+	}
+	if r.Merchants[1].Merchant != "corner shop" {
+		t.Errorf("expected name fallback for merchant-less tx, got %q", r.Merchants[1].Merchant)
+	}
+}
@@ -0,0 +1,212 @@
+package insights
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// MerchantAlias maps raw descriptors to a canonical merchant. Pattern is a
+// case-insensitive regular expression matched against both the transaction
+// name and Sure's merchant name.
+type MerchantAlias struct {
+	Pattern string `json:"pattern"`
+	Name    string `json:"name"`
+}
+
+// Normalizer turns bank descriptors into merchant keys so that
+// "NETFLIX.COM 1234", "Netflix.com AMSTERDAM" and "NETFLIX" group together.
+// A nil *Normalizer is valid and applies the built-in pipeline only.
+type Normalizer struct {
+	aliases []compiledAlias
+}
+
+type compiledAlias struct {
+	re   *regexp.Regexp
+	name string
+}
+
+// NewNormalizer compiles alias rules; rules are tried in order.
+func NewNormalizer(aliases []MerchantAlias) (*Normalizer, error) {
+	n := &Normalizer{}
+	for i, a := range aliases {
+		if strings.TrimSpace(a.Pattern) == "" || strings.TrimSpace(a.Name) == "" {
+			return nil, fmt.Errorf("alias %d: pattern and name are required", i)
+		}
+		re, err := regexp.Compile("(?i)" + a.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("alias %d (%s): %w", i, a.Name, err)
+		}
+		n.aliases = append(n.aliases, compiledAlias{re: re, name: a.Name})
+	}
+	return n, nil
+}
+
+var (
+	// PAYPAL *NETFLIX, SQ *BLUE BOTTLE, SumUp *Cafe, GOOGLE *YouTube ...
+	processorPrefix = regexp.MustCompile(`(?i)^(?:paypal|pp|sq|sp|tst|sumup|zettle|izettle|stripe|google|amzn mktp|dlo|ccd|pos)\s*\*\s*`)
+	cardPrefix      = regexp.MustCompile(`(?i)^(?:card purchase|purchase|pos purchase|compra tarjeta|compra|pago en|kartenzahlung|paiement cb|cb)\s+`)
+	datePattern     = regexp.MustCompile(`\b\d{4}-\d{2}-\d{2}\b|\b\d{1,2}[/.-]\d{1,2}(?:[/.-]\d{2,4})?\b`)
+	domainPattern   = regexp.MustCompile(`(?i)^www\.|\.(?:com/bill|com|net|org|co\.uk|com\.au|co|io|eu|es|de|fr|nl|it|ie|uk|lu|pt|be)\b`)
+	separators      = regexp.MustCompile(`[^\p{L}\p{N}&'+]+`)
+	// #1234, *1234, XXXX1234, 0012345, REF123456
+	referenceToken = regexp.MustCompile(`(?i)^(?:[#*x]*\d{2,}[\d\-/.:]*|(?:ref|id|nr|no|num|txn)[:#.]?\d+)$`)
+)
+
+// countryCodes are ISO country codes (2 and 3 letters) that show up at the
+// end of card descriptors. Many are also words ("it", "at", "no", "de"), so
+// they are only dropped when upper-case in the raw text or after a city.
+var countryCodes = map[string]bool{
+	"us": true, "usa": true, "gb": true, "gbr": true, "uk": true, "ie": true, "irl": true,
+	"nl": true, "nld": true, "de": true, "deu": true, "fr": true, "fra": true,
+	"es": true, "esp": true, "it": true, "ita": true, "pt": true, "prt": true,
+	"be": true, "bel": true, "lu": true, "lux": true, "ch": true, "che": true,
+	"at": true, "aut": true, "se": true, "dk": true, "no": true, "fi": true, "pl": true,
+	"ca": true, "au": true,
+}
+
+// billingCities are cities common as billing locations on card
+// descriptors. Multi-word entries are matched as a whole.
+var billingCities = map[string]bool{
+	"amsterdam": true, "london": true, "dublin": true, "cork": true, "luxembourg": true,
+	"madrid": true, "barcelona": true, "valencia": true, "sevilla": true, "bilbao": true,
+	"paris": true, "berlin": true, "munich": true, "muenchen": true, "hamburg": true,
+	"lisboa": true, "lisbon": true, "milan": true, "milano": true, "zurich": true,
+	"stockholm": true, "seattle": true, "sunnyvale": true, "cupertino": true,
+	"new york": true, "san francisco": true, "los gatos": true, "mountain view": true,
+	"palo alto": true, "san jose": true,
+}
+
+// Normalize cleans a raw descriptor into a lower-case merchant key: payment
+// processor prefixes, dates, card/reference numbers, domain suffixes and
+// trailing city/country tokens are dropped. Aliases are not applied.
+func (n *Normalizer) Normalize(raw string) string {
+	// Case is kept until the end: it tells country codes from words.
+	s := strings.TrimSpace(raw)
+	if s == "" {
+		return ""
+	}
+	s = processorPrefix.ReplaceAllString(s, "")
+	s = cardPrefix.ReplaceAllString(s, "")
+	s = datePattern.ReplaceAllString(s, " ")
+
+	var tokens []string
+	for _, tok := range strings.Fields(s) {
+		if referenceToken.MatchString(tok) {
+			continue
+		}
+		tok = domainPattern.ReplaceAllString(tok, "")
+		for _, part := range separators.Split(tok, -1) {
+			if part != "" {
+				tokens = append(tokens, part)
+			}
+		}
+	}
+
+	// Drop trailing locations, keeping at least one token.
+	for len(tokens) > 1 {
+		last := tokens[len(tokens)-1]
+		if n := cityAtEnd(tokens); n > 0 && n < len(tokens) {
+			tokens = tokens[:len(tokens)-n]
+			continue
+		}
+		if countryCodes[strings.ToLower(last)] && (isUpper(last) || cityAtEnd(tokens[:len(tokens)-1]) > 0) {
+			tokens = tokens[:len(tokens)-1]
+			continue
+		}
+		break
+	}
+
+	if len(tokens) == 0 {
+		return strings.ToLower(strings.TrimSpace(raw))
+	}
+	return strings.ToLower(strings.Join(tokens, " "))
+}
+
+// cityAtEnd returns how many trailing tokens name a billing city (0 if
+// none).
+func cityAtEnd(tokens []string) int {
+	n := len(tokens)
+	if n >= 2 && billingCities[strings.ToLower(tokens[n-2]+" "+tokens[n-1])] {
+		return 2
+	}
+	if n >= 1 && billingCities[strings.ToLower(tokens[n-1])] {
+		return 1
+	}
+	return 0
+}
+
+func isUpper(s string) bool {
+	return s == strings.ToUpper(s) && s != strings.ToLower(s)
+}
+
+// Key returns the grouping key for a transaction: the first matching alias,
+// else the normalized Sure merchant name when present, else the normalized
+// transaction name.
+func (n *Normalizer) Key(tx Transaction) string {
+	if n != nil {
+		for _, a := range n.aliases {
+			if a.re.MatchString(tx.Name) || (tx.MerchantName != "" && a.re.MatchString(tx.MerchantName)) {
+				return strings.ToLower(strings.TrimSpace(a.name))
+			}
+		}
+	}
+	if strings.TrimSpace(tx.MerchantName) != "" {
+		return n.Normalize(tx.MerchantName)
+	}
+	return n.Normalize(tx.Name)
+}
+
+func isExpense(tx Transaction) bool { return tx.Classification == "expense" }
+
+// MerchantGroup is a set of transactions sharing a merchant key. Name is the
+// most frequent raw transaction name in the group (ties broken
+// alphabetically), which is what users recognise on their statements.
+type MerchantGroup struct {
+	Key  string
+	Name string
+	Txs  []Transaction
+}
+
+// GroupByMerchant buckets txs accepted by keep (nil keeps all) by n.Key, in
+// key order so callers iterate deterministically.
+func GroupByMerchant(txs []Transaction, n *Normalizer, keep func(Transaction) bool) []MerchantGroup {
+	byKey := map[string]int{}
+	var out []MerchantGroup
+	var names []map[string]int
+	for _, tx := range txs {
+		if keep != nil && !keep(tx) {
+			continue
+		}
+		key := n.Key(tx)
+		if key == "" {
+			continue
+		}
+		i, ok := byKey[key]
+		if !ok {
+			i = len(out)
+			byKey[key] = i
+			out = append(out, MerchantGroup{Key: key})
+			names = append(names, map[string]int{})
+		}
+		out[i].Txs = append(out[i].Txs, tx)
+		names[i][strings.TrimSpace(tx.Name)]++
+	}
+	for i := range out {
+		out[i].Name = mostCommon(names[i])
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Key < out[j].Key })
+	return out
+}
+
+// mostCommon returns the highest-count key, breaking ties alphabetically.
+func mostCommon(counts map[string]int) string {
+	best, bestN := "", 0
+	for name, c := range counts {
+		if c > bestN || (c == bestN && name < best) {
+			best, bestN = name, c
+		}
+	}
+	return best
+}
@@ -0,0 +1,70 @@
+package insights
+
+import "testing"
+
+func TestNormalize(t *testing.T) {
+	var n *Normalizer
+	cases := map[string]string{
+		"NETFLIX.COM 1234":                             "netflix",
+		"Netflix.com AMSTERDAM":                        "netflix",
+		"NETFLIX":                                      "netflix",
+		"PAYPAL *SPOTIFY":                              "spotify",
+		"SQ *BLUE BOTTLE COFFEE":                       "blue bottle coffee",
+		"Google *YouTube Premium":                      "youtube premium",
+		"APPLE.COM/BILL CORK IE":                       "apple",
+		"Uber *Trip 12/03 SAN FRANCISCO":               "uber trip",
+		"CARD PURCHASE MERCADONA 2026-03-01 REF123456": "mercadona",
+		"Basic-Fit":                                    "basic fit",
+		"Amazon Prime":                                 "amazon prime",
+		"7-Eleven":                                     "7 eleven",
+		"Comisión mantenimiento":                       "comisión mantenimiento",
+		// Country codes go when upper-case or after a city; words stay.
+		"Spotify AB Stockholm se": "spotify ab",
+		"BOL.COM NL":              "bol",
+		"Just Do It":              "just do it",
+		"Where It's At":           "where it's at",
+		"Say Yes Or No":           "say yes or no",
+		"Casa de":                 "casa de",
+		"Lo que es":               "lo que es",
+	}
+	for raw, want := range cases {
+		if got := n.Normalize(raw); got != want {
+			t.Errorf("Normalize(%q) = %q, want %q", raw, got, want)
+		}
+	}
+}
+
+func TestNormalizerKey_AliasThenMerchantName(t *testing.T) {
+	n, err := NewNormalizer([]MerchantAlias{{Pattern: `^amzn|amazon\.(es|de)`, Name: "Amazon"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := n.Key(Transaction{Name: "AMZN Mktp ES*2K4"}); got != "amazon" {
+		t.Errorf("alias key = %q", got)
+	}
+	if got := n.Key(Transaction{Name: "NFLX DIGITAL 0098", MerchantName: "Netflix"}); got != "netflix" {
+		t.Errorf("merchant name key = %q", got)
+	}
+	if _, err := NewNormalizer([]MerchantAlias{{Pattern: "(", Name: "Broken"}}); err == nil {
+		t.Error("expected invalid pattern to fail")
+	}
+}
+
+func TestDetectSubscriptions_GroupsDescriptorVariants(t *testing.T) {
+	txs := []Transaction{
+		{ID: "1", Name: "NETFLIX.COM 1234", Classification: "expense", AmountText: "€12.99", Date: mustDate("2026-01-05")},
+		{ID: "2", Name: "Netflix.com AMSTERDAM", Classification: "expense", AmountText: "€12.99", Date: mustDate("2026-02-05")},
+		{ID: "3", Name: "NETFLIX", Classification: "expense", AmountText: "€12.99", Date: mustDate("2026-03-05")},
+		{ID: "4", Name: "NETFLIX.COM 1234", Classification: "expense", AmountText: "€12.99", Date: mustDate("2026-04-05")},
+	}
+	out := DetectSubscriptions(txs)
+	if len(out) != 1 {
+		t.Fatalf("expected 1 candidate, got %d", len(out))
+	}
+	if out[0].Merchant != "netflix" || out[0].Count != 4 {
+		t.Fatalf("unexpected candidate: %+v", out[0])
+	}
+	if out[0].Name != "NETFLIX.COM 1234" {
+		t.Errorf("expected most frequent raw name, got %q", out[0].Name)
+	}
+}
@@ -8,33 +8,159 @@ import (
 )
 
 type SubscriptionCandidate struct {
-	Name            string    `json:"name"`
-	Count           int       `json:"count"`
-	AvgAmount       float64   `json:"avg_amount"`
-	AvgPeriodDays   float64   `json:"avg_period_days"`
-	StdDevDays      float64   `json:"stddev_days"`
-	LastDate        time.Time `json:"last_date"`
-	SampleTxIDs     []string  `json:"sample_tx_ids"`
-	Classification  string    `json:"classification"` // usually expense
-	Confidence      float64   `json:"confidence"`
-	Reason          string    `json:"reason"`
-	SuggestedAction string    `json:"suggested_action"`
-}
-
-// DetectSubscriptions finds recurring transactions by same name with roughly regular spacing and stable amounts.
-// Heuristic: at least 3 occurrences, avg period between 20-40 days OR 6-9 days (weekly), stddev <= 3 days.
+	Name          string    `json:"name"`
+	Merchant      string    `json:"merchant"` // normalized grouping key
+	Count         int       `json:"count"`
+	AvgAmount     float64   `json:"avg_amount"`
+	AvgPeriodDays float64   `json:"avg_period_days"`
+	StdDevDays    float64   `json:"stddev_days"`
+	Period        string    `json:"period"` // weekly|biweekly|monthly|quarterly|yearly
+	LastDate      time.Time `json:"last_date"`
+	LastAmount    float64   `json:"last_amount"`
+	// NextExpectedDate is LastDate plus the observed cadence.
+	NextExpectedDate string `json:"next_expected_date"`
+	// Status is "missed" when no charge arrived within the grace period after
+	// NextExpectedDate (likely cancelled or a failed payment), else "active".
+	Status          string        `json:"status"`
+	MissedCharges   int           `json:"missed_charges"`
+	AmountHistory   []AmountPoint `json:"amount_history"`
+	PriceChanges    []PriceChange `json:"price_changes"`
+	SampleTxIDs     []string      `json:"sample_tx_ids"`
+	Classification  string        `json:"classification"` // usually expense
+	Confidence      float64       `json:"confidence"`
+	Reason          string        `json:"reason"`
+	SuggestedAction string        `json:"suggested_action"`
+}
+
+type AmountPoint struct {
+	Date   string  `json:"date"`
+	Amount float64 `json:"amount"`
+	TxID   string  `json:"tx_id"`
+}
+
+// PriceChange is a step in a subscription's amount that held for the
+// following charge (or is the latest charge).
+type PriceChange struct {
+	Date    string  `json:"date"` // first charge at the new amount
+	From    float64 `json:"from"`
+	To      float64 `json:"to"`
+	Percent float64 `json:"percent"`
+}
+
+// PeriodBand is a cadence the subscription detector accepts: a group of
+// charges qualifies when its average spacing falls in [MinDays, MaxDays] and
+// it has at least MinCount occurrences.
+type PeriodBand struct {
+	Name     string  `json:"name"`
+	MinDays  float64 `json:"min_days"`
+	MaxDays  float64 `json:"max_days"`
+	MinCount int     `json:"min_count"`
+}
+
+// SubscriptionOptions tunes DetectSubscriptions. Bands are tried in order; the
+// first band containing the average spacing wins.
+type SubscriptionOptions struct {
+	Bands             []PeriodBand `json:"bands"`
+	StddevMaxDays     float64      `json:"stddev_max_days"`
+	AmountStddevRatio float64      `json:"amount_stddev_ratio"`
+	// AmountStddevAbs accepts small absolute variation regardless of ratio
+	// (e.g. a few cents of FX noise on cheap subscriptions).
+	AmountStddevAbs float64 `json:"amount_stddev_abs"`
+	// PriceChangeMinPct is the smallest step (percent) reported as a price
+	// change. Groups that are only unstable because of such steps still count
+	// as subscriptions when each segment is stable on its own.
+	PriceChangeMinPct float64 `json:"price_change_min_pct"`
+	// AsOf is the reference date for missed-charge detection; zero means the
+	// latest expense date in the input.
+	AsOf time.Time `json:"-"`
+	// Normalizer groups descriptors by merchant; nil uses the built-in rules.
+	Normalizer *Normalizer `json:"-"`
+}
+
+// DefaultSubscriptionOptions matches the historical hard-coded behavior for
+// weekly and monthly charges and adds biweekly, quarterly and yearly bands.
+func DefaultSubscriptionOptions() SubscriptionOptions {
+	return SubscriptionOptions{
+		Bands: []PeriodBand{
+			{Name: "weekly", MinDays: 6, MaxDays: 9, MinCount: 3},
+			{Name: "biweekly", MinDays: 12, MaxDays: 16, MinCount: 3},
+			{Name: "monthly", MinDays: 20, MaxDays: 40, MinCount: 3},
+			{Name: "quarterly", MinDays: 80, MaxDays: 100, MinCount: 3},
+			{Name: "yearly", MinDays: 350, MaxDays: 380, MinCount: 2},
+		},
+		StddevMaxDays:     3.0,
+		AmountStddevRatio: 0.1,
+		AmountStddevAbs:   1.0,
+		PriceChangeMinPct: 5,
+	}
+}
+
+// withDefaults fills zero fields from DefaultSubscriptionOptions so callers
+// can override a single knob.
+func (o SubscriptionOptions) withDefaults() SubscriptionOptions {
+	def := DefaultSubscriptionOptions()
+	if len(o.Bands) == 0 {
+		o.Bands = def.Bands
+	}
+	if o.StddevMaxDays <= 0 {
+		o.StddevMaxDays = def.StddevMaxDays
+	}
+	if o.AmountStddevRatio <= 0 {
+		o.AmountStddevRatio = def.AmountStddevRatio
+	}
+	if o.AmountStddevAbs <= 0 {
+		o.AmountStddevAbs = def.AmountStddevAbs
+	}
+	if o.PriceChangeMinPct <= 0 {
+		o.PriceChangeMinPct = def.PriceChangeMinPct
+	}
+	return o
+}
+
+// perYear estimates how often a band charges in a year, for savings hints.
+func (b PeriodBand) perYear() float64 {
+	mid := (b.MinDays + b.MaxDays) / 2
+	if mid <= 0 {
+		return 0
+	}
+	return math.Round(365 / mid)
+}
+
+// DetectSubscriptions finds recurring transactions by same name with roughly regular spacing and stable amounts,
+// using DefaultSubscriptionOptions.
 func DetectSubscriptions(txs []Transaction) []SubscriptionCandidate {
-	byName := map[string][]Transaction{}
-	for _, tx := range txs {
-		if tx.Classification != "expense" {
-			continue
+	return DetectSubscriptionsWithOptions(txs, DefaultSubscriptionOptions())
+}
+
+// DetectSubscriptionsWithOptions is DetectSubscriptions with explicit thresholds.
+// Heuristic: average spacing inside one of opts.Bands with at least that band's
+// occurrences, spacing stddev <= StddevMaxDays, amount stddev within
+// AmountStddevRatio of the mean (or below AmountStddevAbs).
+func DetectSubscriptionsWithOptions(txs []Transaction, opts SubscriptionOptions) []SubscriptionCandidate {
+	opts = opts.withDefaults()
+	minCount := opts.Bands[0].MinCount
+	for _, b := range opts.Bands {
+		if b.MinCount < minCount {
+			minCount = b.MinCount
+		}
+	}
+	if minCount < 2 {
+		minCount = 2
+	}
+
+	asOf := opts.AsOf
+	if asOf.IsZero() {
+		for _, tx := range txs {
+			if isExpense(tx) && tx.Date.After(asOf) {
+				asOf = tx.Date
+			}
 		}
-		byName[tx.Name] = append(byName[tx.Name], tx)
 	}
 
 	var out []SubscriptionCandidate
-	for name, list := range byName {
-		if len(list) < 3 {
+	for _, g := range GroupByMerchant(txs, opts.Normalizer, isExpense) {
+		list := g.Txs
+		if len(list) < minCount {
 			continue
 		}
 		sort.Slice(list, func(i, j int) bool { return list[i].Date.Before(list[j].Date) })
@@ -47,34 +173,66 @@ func DetectSubscriptions(txs []Transaction) []SubscriptionCandidate {
 		}
 		avg, std := meanStd(days)
 
-		monthly := avg >= 20 && avg <= 40
-		weekly := avg >= 6 && avg <= 9
-		if !(monthly || weekly) {
+		var band *PeriodBand
+		for i := range opts.Bands {
+			b := opts.Bands[i]
+			if avg >= b.MinDays && avg <= b.MaxDays && len(list) >= b.MinCount {
+				band = &opts.Bands[i]
+				break
+			}
+		}
+		if band == nil {
 			continue
 		}
-		if std > 3.0 {
+		if std > opts.StddevMaxDays {
 			continue
 		}
 
 		// amounts
 		amounts := make([]float64, 0, len(list))
+		history := make([]AmountPoint, 0, len(list))
 		ids := make([]string, 0, min(3, len(list)))
 		for i, tx := range list {
 			v, err := SignedAmount(tx)
 			if err == nil {
 				amounts = append(amounts, math.Abs(v))
+				history = append(history, AmountPoint{Date: tx.Date.Format("2006-01-02"), Amount: round2(math.Abs(v)), TxID: tx.ID})
 			}
 			if i >= len(list)-3 {
 				ids = append(ids, tx.ID)
 			}
 		}
-		avgAmt, stdAmt := meanStd(amounts)
-		// stable amount: std dev < 10% of mean (or < 1€)
-		stable := (avgAmt > 0 && stdAmt/avgAmt < 0.1) || stdAmt < 1.0
+		if len(amounts) == 0 {
+			continue
+		}
+		avgAmt, _ := meanStd(amounts)
+		changes, cuts := priceChanges(history, opts.PriceChangeMinPct)
+		stable := opts.amountsStable(amounts)
+		if !stable && len(cuts) > 0 && len(cuts) <= 2 {
+			stable = true
+			prev := 0
+			for _, c := range append(cuts, len(amounts)) {
+				if !opts.amountsStable(amounts[prev:c]) {
+					stable = false
+				}
+				prev = c
+			}
+		}
 		if !stable {
 			continue
 		}
+		lastAmt := amounts[len(amounts)-1]
+
+		last := list[len(list)-1].Date
+		next := last.AddDate(0, 0, int(math.Round(avg)))
+		grace := math.Max(3, math.Max(2*std, 0.1*avg))
+		status, missed := "active", 0
+		if asOf.After(next.AddDate(0, 0, int(math.Ceil(grace)))) {
+			status = "missed"
+			missed = int(asOf.Sub(last).Hours() / 24 / avg)
+		}
 
+		monthly := band.Name == "monthly"
 		conf := 0.7
 		if monthly {
 			conf += 0.1
@@ -89,30 +247,40 @@ func DetectSubscriptions(txs []Transaction) []SubscriptionCandidate {
 			conf = 1.0
 		}
 
-		reason := "recurring_expense"
-		if monthly {
-			reason = "monthly_recurring"
-		} else if weekly {
-			reason = "weekly_recurring"
-		}
+		reason := band.Name + "_recurring"
 
 		action := "Review if still needed"
-		if avgAmt > 20 {
-			action = "Review if still needed; consider canceling to save ~" + formatAmount(avgAmt*12) + "/year"
+		if lastAmt > 20 {
+			action = "Review if still needed; consider canceling to save ~" + formatAmount(lastAmt*band.perYear()) + "/year"
+		}
+		if n := len(changes); n > 0 {
+			pc := changes[n-1]
+			action = fmt.Sprintf("Price changed %.2f -> %.2f (%+.1f%%) on %s. %s", pc.From, pc.To, pc.Percent, pc.Date, action)
+		}
+		if status == "missed" {
+			action = fmt.Sprintf("No charge since %s (expected around %s); likely cancelled or payment failed, confirm", last.Format("2006-01-02"), next.Format("2006-01-02"))
 		}
 
 		out = append(out, SubscriptionCandidate{
-			Name:            name,
-			Count:           len(list),
-			AvgAmount:       round2(avgAmt),
-			AvgPeriodDays:   round2(avg),
-			StdDevDays:      round2(std),
-			LastDate:        list[len(list)-1].Date,
-			SampleTxIDs:     ids,
-			Classification:  "expense",
-			Confidence:      conf,
-			Reason:          reason,
-			SuggestedAction: action,
+			Name:             g.Name,
+			Merchant:         g.Key,
+			Count:            len(list),
+			AvgAmount:        round2(avgAmt),
+			AvgPeriodDays:    round2(avg),
+			StdDevDays:       round2(std),
+			Period:           band.Name,
+			LastDate:         last,
+			LastAmount:       round2(lastAmt),
+			NextExpectedDate: next.Format("2006-01-02"),
+			Status:           status,
+			MissedCharges:    missed,
+			AmountHistory:    history,
+			PriceChanges:     changes,
+			SampleTxIDs:      ids,
+			Classification:   "expense",
+			Confidence:       conf,
+			Reason:           reason,
+			SuggestedAction:  action,
 		})
 	}
 
@@ -125,6 +293,41 @@ func DetectSubscriptions(txs []Transaction) []SubscriptionCandidate {
 	return out
 }
 
+func (o SubscriptionOptions) amountsStable(amounts []float64) bool {
+	avg, std := meanStd(amounts)
+	return (avg > 0 && std/avg < o.AmountStddevRatio) || std < o.AmountStddevAbs
+}
+
+// priceChanges finds steps of at least minPct between consecutive charges.
+// Both sides must be steady (the charge before the step repeats the old
+// price and the one after repeats the new, within 1%) so metered bills and
+// one-off blips are not reported; a step on the latest charge only needs the
+// old side. cuts holds the index of the first charge at each new price.
+func priceChanges(history []AmountPoint, minPct float64) (changes []PriceChange, cuts []int) {
+	changes = []PriceChange{}
+	same := func(a, b float64) bool { return math.Abs(a-b) <= math.Max(0.01, 0.01*b) }
+	for i := 1; i < len(history); i++ {
+		from, to := history[i-1].Amount, history[i].Amount
+		if from <= 0 || math.Abs(to-from)/from*100 < minPct {
+			continue
+		}
+		if i >= 2 && !same(history[i-2].Amount, from) {
+			continue
+		}
+		if i+1 < len(history) && !same(history[i+1].Amount, to) {
+			continue
+		}
+		changes = append(changes, PriceChange{
+			Date:    history[i].Date,
+			From:    from,
+			To:      to,
+			Percent: math.Round((to-from)/from*1000) / 10,
+		})
+		cuts = append(cuts, i)
+	}
+	return changes, cuts
+}
+
 func meanStd(xs []float64) (mean float64, std float64) {
 	if len(xs) == 0 {
 		return 0, 0
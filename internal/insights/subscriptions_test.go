@@ -34,3 +34,91 @@ func mustDate(s string) (t time.Time) {
 	}
 	return t
 }
+
+func TestDetectSubscriptionsWithOptions_Bands(t *testing.T) {
+	var txs []Transaction
+	add := func(name, amount string, start time.Time, stepDays, n int) {
+		for i := 0; i < n; i++ {
+			txs = append(txs, Transaction{ID: name + string(rune('a'+i)), Name: name, Classification: "expense", AmountText: amount, Date: start.AddDate(0, 0, i*stepDays)})
+		}
+	}
+	start := mustDate("2024-01-05")
+	add("Cleaning", "€40.00", start, 14, 6)
+	add("Insurance", "€120.00", start, 91, 4)
+	add("Prime", "€49.90", start, 365, 2)
+
+	got := map[string]string{}
+	for _, c := range DetectSubscriptionsWithOptions(txs, DefaultSubscriptionOptions()) {
+		got[c.Name] = c.Period
+	}
+	want := map[string]string{"Cleaning": "biweekly", "Insurance": "quarterly", "Prime": "yearly"}
+	for name, period := range want {
+		if got[name] != period {
+			t.Errorf("%s: expected period %q, got %q", name, period, got[name])
+		}
+	}
+}
+
+func TestDetectSubscriptionsWithOptions_Stddev(t *testing.T) {
+	txs := []Transaction{
+		{ID: "1", Name: "Gym", Classification: "expense", AmountText: "€30.00", Date: mustDate("2026-01-01")},
+		{ID: "2", Name: "Gym", Classification: "expense", AmountText: "€30.00", Date: mustDate("2026-01-26")},
+		{ID: "3", Name: "Gym", Classification: "expense", AmountText: "€30.00", Date: mustDate("2026-03-02")},
+	}
+	if out := DetectSubscriptions(txs); len(out) != 0 {
+		t.Fatalf("expected irregular gaps to be rejected by default, got %d", len(out))
+	}
+	opts := DefaultSubscriptionOptions()
+	opts.StddevMaxDays = 10
+	out := DetectSubscriptionsWithOptions(txs, opts)
+	if len(out) != 1 || out[0].Period != "monthly" {
+		t.Fatalf("expected 1 monthly candidate with looser stddev, got %+v", out)
+	}
+}
+
+func TestDetectSubscriptions_PriceChange(t *testing.T) {
+	var txs []Transaction
+	for i := 0; i < 6; i++ {
+		amt := "€12.99"
+		if i >= 3 {
+			amt = "€15.99"
+		}
+		txs = append(txs, Transaction{ID: string(rune('a' + i)), Name: "NETFLIX.COM", Classification: "expense", AmountText: amt, Date: mustDate("2026-01-05").AddDate(0, i, 0)})
+	}
+	out := DetectSubscriptions(txs)
+	if len(out) != 1 {
+		t.Fatalf("expected price-changed subscription to be detected, got %d", len(out))
+	}
+	c := out[0]
+	if len(c.PriceChanges) != 1 {
+		t.Fatalf("expected 1 price change, got %+v", c.PriceChanges)
+	}
+	pc := c.PriceChanges[0]
+	if pc.Date != "2026-04-05" || pc.From != 12.99 || pc.To != 15.99 || pc.Percent != 23.1 {
+		t.Errorf("unexpected price change: %+v", pc)
+	}
+	if c.LastAmount != 15.99 || len(c.AmountHistory) != 6 {
+		t.Errorf("last amount / history mismatch: %v / %d", c.LastAmount, len(c.AmountHistory))
+	}
+	if c.Status != "active" || c.NextExpectedDate != "2026-07-05" {
+		t.Errorf("status/next: %s %s", c.Status, c.NextExpectedDate)
+	}
+}
+
+func TestDetectSubscriptions_MissedCharge(t *testing.T) {
+	txs := []Transaction{
+		{ID: "1", Name: "Gym", Classification: "expense", AmountText: "€30.00", Date: mustDate("2026-01-03")},
+		{ID: "2", Name: "Gym", Classification: "expense", AmountText: "€30.00", Date: mustDate("2026-02-03")},
+		{ID: "3", Name: "Gym", Classification: "expense", AmountText: "€30.00", Date: mustDate("2026-03-03")},
+	}
+	opts := DefaultSubscriptionOptions()
+	opts.AsOf = mustDate("2026-04-05")
+	if c := DetectSubscriptionsWithOptions(txs, opts)[0]; c.Status != "active" {
+		t.Fatalf("charge still within grace, got %s", c.Status)
+	}
+	opts.AsOf = mustDate("2026-05-20")
+	c := DetectSubscriptionsWithOptions(txs, opts)[0]
+	if c.Status != "missed" || c.MissedCharges != 2 {
+		t.Fatalf("expected 2 missed charges, got %s/%d", c.Status, c.MissedCharges)
+	}
+}
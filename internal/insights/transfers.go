@@ -0,0 +1,269 @@
+package insights
+
+import (
+	"math"
+	"sort"
+	"strings"
+)
+
+// transferPair is an outflow from one account matched with an inflow of
+// the same amount into another: money moving between the user's own
+// accounts (savings transfers, card and loan payments).
+type transferPair struct {
+	Outflow   Transaction
+	Inflow    Transaction
+	Amount    float64 // absolute
+	DaysApart int
+}
+
+// TransferOptions tunes SuggestTransfers; zero fields use DefaultTransferOptions.
+type TransferOptions struct {
+	WindowDays int `json:"window_days"` // max days between the two legs
+}
+
+// DefaultTransferOptions: legs at most 3 days apart.
+func DefaultTransferOptions() TransferOptions {
+	return TransferOptions{WindowDays: 3}
+}
+
+func (o TransferOptions) withDefaults() TransferOptions {
+	if o.WindowDays <= 0 {
+		o.WindowDays = DefaultTransferOptions().WindowDays
+	}
+	return o
+}
+
+type transferLeg struct {
+	tx    Transaction
+	cents int64
+}
+
+// matchTransfers pairs expenses with incomes of the same amount in a
+// different account at most WindowDays apart, except the pairs skip vetoes
+// (nil = none). Closest dates are paired first and each transaction is used
+// once. Transactions Sure already links (TransferID) are left out.
+func matchTransfers(txs []Transaction, opts TransferOptions, skip func(out, in Transaction) bool) []transferPair {
+	opts = opts.withDefaults()
+	var outs, ins []transferLeg
+	for _, tx := range txs {
+		if tx.TransferID != "" || tx.AccountID == "" {
+			continue
+		}
+		v, err := SignedAmount(tx)
+		if err != nil || v == 0 {
+			continue
+		}
+		leg := transferLeg{tx: tx, cents: int64(math.Round(abs(v) * 100))}
+		if v < 0 {
+			outs = append(outs, leg)
+		} else {
+			ins = append(ins, leg)
+		}
+	}
+
+	type candidate struct{ out, in, days int }
+	var cands []candidate
+	for i, o := range outs {
+		for j, in := range ins {
+			if o.cents != in.cents || o.tx.AccountID == in.tx.AccountID {
+				continue
+			}
+			if skip != nil && skip(o.tx, in.tx) {
+				continue
+			}
+			days := int(math.Abs(math.Round(in.tx.Date.Sub(o.tx.Date).Hours() / 24)))
+			if days <= opts.WindowDays {
+				cands = append(cands, candidate{i, j, days})
+			}
+		}
+	}
+	sort.SliceStable(cands, func(a, b int) bool {
+		ca, cb := cands[a], cands[b]
+		if ca.days != cb.days {
+			return ca.days < cb.days
+		}
+		if !outs[ca.out].tx.Date.Equal(outs[cb.out].tx.Date) {
+			return outs[ca.out].tx.Date.Before(outs[cb.out].tx.Date)
+		}
+		if outs[ca.out].tx.ID != outs[cb.out].tx.ID {
+			return outs[ca.out].tx.ID < outs[cb.out].tx.ID
+		}
+		return ins[ca.in].tx.ID < ins[cb.in].tx.ID
+	})
+
+	usedOut, usedIn := map[int]bool{}, map[int]bool{}
+	var pairs []transferPair
+	for _, c := range cands {
+		if usedOut[c.out] || usedIn[c.in] {
+			continue
+		}
+		usedOut[c.out], usedIn[c.in] = true, true
+		pairs = append(pairs, transferPair{
+			Outflow:   outs[c.out].tx,
+			Inflow:    ins[c.in].tx,
+			Amount:    float64(outs[c.out].cents) / 100,
+			DaysApart: c.days,
+		})
+	}
+	sort.SliceStable(pairs, func(i, j int) bool { return pairs[i].Outflow.Date.Before(pairs[j].Outflow.Date) })
+	return pairs
+}
+
+// TransferExclusion reports the transactions left out of spend and income
+// aggregates because they move money between the user's own accounts.
+type TransferExclusion struct {
+	Count   int     `json:"count"`
+	Expense float64 `json:"expense"` // outflow legs: transfers out, card and loan payments
+	Income  float64 `json:"income"`  // inflow legs
+}
+
+// transferKinds are the Sure transaction kinds of transfer legs.
+var transferKinds = map[string]bool{"funds_movement": true, "cc_payment": true, "loan_payment": true}
+
+// TransferIDs returns the ids of transactions Sure knows move money
+// between the user's own accounts: legs of Sure transfers (linked, e.g.
+// from the transfers list) and transactions Sure marks as transfers or
+// payments. Unlinked look-alike pairs are left in: they may be refunds or
+// reimbursements, and are only suggested for linking (SuggestTransfers).
+func TransferIDs(txs []Transaction, linked map[string]bool) map[string]bool {
+	ids := map[string]bool{}
+	for _, tx := range txs {
+		if linked[tx.ID] || tx.TransferID != "" || transferKinds[tx.Kind] {
+			ids[tx.ID] = true
+		}
+	}
+	return ids
+}
+
+// ExcludeTransfers drops the transactions TransferIDs finds and reports
+// what was left out.
+func ExcludeTransfers(txs []Transaction, linked map[string]bool) ([]Transaction, TransferExclusion) {
+	ids := TransferIDs(txs, linked)
+	kept := make([]Transaction, 0, len(txs))
+	var ex TransferExclusion
+	for _, tx := range txs {
+		if !ids[tx.ID] {
+			kept = append(kept, tx)
+			continue
+		}
+		ex.Count++
+		if v, err := SignedAmount(tx); err == nil {
+			if v < 0 {
+				ex.Expense -= v
+			} else {
+				ex.Income += v
+			}
+		}
+	}
+	ex.Expense, ex.Income = round2(ex.Expense), round2(ex.Income)
+	return kept, ex
+}
+
+// TransferSuggestion is an unlinked outflow/inflow pair scored for linking
+// as a transfer in Sure.
+type TransferSuggestion struct {
+	Outflow      TransferLegTx `json:"outflow"`
+	Inflow       TransferLegTx `json:"inflow"`
+	Amount       float64       `json:"amount"` // absolute
+	DaysApart    int           `json:"days_apart"`
+	Alternatives int           `json:"alternatives"` // other same-amount legs either side could pair with
+	Confidence   float64       `json:"confidence"`
+	Reason       string        `json:"reason"`
+}
+
+type TransferLegTx struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	Date      string `json:"date"`
+	AccountID string `json:"account_id"`
+	Account   string `json:"account"`
+}
+
+// transferKeywords hint that a descriptor is a transfer or a payment (EN/ES/DE/FR).
+var transferKeywords = []string{"transfer", "xfer", "trf", "payment", "savings", "deposit", "withdrawal", "transferencia", "traspaso", "pago", "überweisung", "umbuchung", "virement"}
+
+// TransferKey identifies a pair in the rejected set passed to SuggestTransfers.
+func TransferKey(outflowID, inflowID string) string {
+	return outflowID + "|" + inflowID
+}
+
+// SuggestTransfers matches the transactions not yet in a transfer (no
+// TransferID, not in linked) with matchTransfers, never pairing the legs of
+// a rejected transfer (keyed by TransferKey). Confidence grows with date
+// proximity and a transfer keyword in either name, and drops when a leg had
+// other same-amount partners in the window. Highest confidence first.
+func SuggestTransfers(txs []Transaction, linked, rejected map[string]bool, opts TransferOptions) []TransferSuggestion {
+	opts = opts.withDefaults()
+	var pool []Transaction
+	for _, tx := range txs {
+		if !linked[tx.ID] && tx.TransferID == "" {
+			pool = append(pool, tx)
+		}
+	}
+	skip := func(out, in Transaction) bool { return rejected[TransferKey(out.ID, in.ID)] }
+
+	// partners counts the legs of the opposite direction tx could pair with.
+	partners := func(tx Transaction, cents int64, outflow bool) int {
+		n := 0
+		for _, o := range pool {
+			v, err := SignedAmount(o)
+			if err != nil || (v < 0) == outflow || int64(math.Round(abs(v)*100)) != cents || o.AccountID == "" || o.AccountID == tx.AccountID {
+				continue
+			}
+			if math.Abs(math.Round(o.Date.Sub(tx.Date).Hours()/24)) > float64(opts.WindowDays) {
+				continue
+			}
+			if (outflow && skip(tx, o)) || (!outflow && skip(o, tx)) {
+				continue
+			}
+			n++
+		}
+		return n
+	}
+
+	var out []TransferSuggestion
+	for _, p := range matchTransfers(pool, opts, skip) {
+		cents := int64(math.Round(p.Amount * 100))
+		alts := partners(p.Outflow, cents, true) - 1 + partners(p.Inflow, cents, false) - 1
+
+		conf := 0.55 + 0.25*(1-float64(p.DaysApart)/float64(opts.WindowDays+1))
+		reason := "amount_date_match"
+		if hasTransferKeyword(p.Outflow.Name) || hasTransferKeyword(p.Inflow.Name) {
+			conf += 0.2
+			reason = "transfer_keyword"
+		}
+		if alts > 0 {
+			conf -= 0.3
+			reason = "ambiguous_amount"
+		}
+		out = append(out, TransferSuggestion{
+			Outflow:      transferLegTx(p.Outflow),
+			Inflow:       transferLegTx(p.Inflow),
+			Amount:       p.Amount,
+			DaysApart:    p.DaysApart,
+			Alternatives: alts,
+			Confidence:   math.Round(math.Max(0, math.Min(conf, 0.99))*100) / 100,
+			Reason:       reason,
+		})
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Confidence > out[j].Confidence })
+	return out
+}
+
+func hasTransferKeyword(name string) bool {
+	name = strings.ToLower(name)
+	for _, k := range transferKeywords {
+		if strings.Contains(name, k) {
+			return true
+		}
+	}
+	return false
+}
+
+func transferLegTx(tx Transaction) TransferLegTx {
+	account := tx.AccountName
+	if account == "" {
+		account = tx.AccountID
+	}
+	return TransferLegTx{ID: tx.ID, Name: tx.Name, Date: tx.Date.Format("2006-01-02"), AccountID: tx.AccountID, Account: account}
+}
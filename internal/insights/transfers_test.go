@@ -0,0 +1,75 @@
+package insights
+
+import "testing"
+
+func TestMatchTransfers(t *testing.T) {
+	txs := []Transaction{
+		{ID: "to-sav", Name: "Transfer to Savings", AccountID: "chk", Classification: "expense", AmountText: "€300.00", Date: mustDate("2026-03-26")},
+		{ID: "from-chk", Name: "Transfer from Checking", AccountID: "sav", Classification: "income", AmountText: "€300.00", Date: mustDate("2026-03-27")},
+		// A closer decoy on the same account never pairs.
+		{ID: "refund", Name: "Refund", AccountID: "chk", Classification: "income", AmountText: "€300.00", Date: mustDate("2026-03-26")},
+		// Card payment: the inflow lands two days later.
+		{ID: "pay", Name: "Visa payment", AccountID: "chk", Classification: "expense", AmountText: "€812.40", Date: mustDate("2026-03-08")},
+		{ID: "paid", Name: "Payment received", AccountID: "card", Classification: "income", AmountText: "€812.40", Date: mustDate("2026-03-10")},
+		// Too far apart.
+		{ID: "late-out", AccountID: "chk", Classification: "expense", AmountText: "€50.00", Date: mustDate("2026-03-01")},
+		{ID: "late-in", AccountID: "sav", Classification: "income", AmountText: "€50.00", Date: mustDate("2026-03-09")},
+		// Already linked by Sure.
+		{ID: "linked", AccountID: "chk", Classification: "expense", AmountText: "€20.00", Date: mustDate("2026-03-02"), TransferID: "tr_1"},
+		{ID: "linked-in", AccountID: "sav", Classification: "income", AmountText: "€20.00", Date: mustDate("2026-03-02")},
+	}
+	pairs := matchTransfers(txs, TransferOptions{}, nil)
+	if len(pairs) != 2 {
+		t.Fatalf("pairs = %+v", pairs)
+	}
+	if p := pairs[0]; p.Outflow.ID != "pay" || p.Inflow.ID != "paid" || p.Amount != 812.4 || p.DaysApart != 2 {
+		t.Errorf("card payment = %+v", p)
+	}
+	if p := pairs[1]; p.Outflow.ID != "to-sav" || p.Inflow.ID != "from-chk" {
+		t.Errorf("savings transfer = %+v", p)
+	}
+
+	// Only Sure's links and markers count; matched look-alikes stay in.
+	ids := TransferIDs(txs, map[string]bool{"late-out": true})
+	for _, id := range []string{"linked", "late-out"} {
+		if !ids[id] {
+			t.Errorf("%s should be a transfer", id)
+		}
+	}
+	if len(ids) != 2 {
+		t.Errorf("ids = %v", ids)
+	}
+
+	txs = append(txs, Transaction{ID: "loan", AccountID: "chk", Classification: "expense", AmountText: "€250.00", Date: mustDate("2026-03-15"), Kind: "loan_payment"})
+	kept, ex := ExcludeTransfers(txs, map[string]bool{"pay": true, "paid": true})
+	if len(kept) != 6 || ex.Count != 4 || ex.Expense != 1082.4 || ex.Income != 812.4 {
+		t.Fatalf("kept = %d, excluded = %+v", len(kept), ex)
+	}
+}
+
+func TestSuggestTransfers(t *testing.T) {
+	txs := []Transaction{
+		{ID: "to-sav", Name: "Transfer to Savings", AccountID: "chk", Classification: "expense", AmountText: "€300.00", Date: mustDate("2026-03-26")},
+		{ID: "from-chk", Name: "Transfer from Checking", AccountID: "sav", Classification: "income", AmountText: "€300.00", Date: mustDate("2026-03-27")},
+		// Same amount, no hint, and a second inflow to choose from.
+		{ID: "cash-out", Name: "ATM", AccountID: "chk", Classification: "expense", AmountText: "€40.00", Date: mustDate("2026-03-10")},
+		{ID: "refund", Name: "Refund", AccountID: "card", Classification: "income", AmountText: "€40.00", Date: mustDate("2026-03-10")},
+		{ID: "gift", Name: "Gift", AccountID: "sav", Classification: "income", AmountText: "€40.00", Date: mustDate("2026-03-12")},
+		// Rejected once in Sure.
+		{ID: "rent", Name: "Rent", AccountID: "chk", Classification: "expense", AmountText: "€900.00", Date: mustDate("2026-03-01")},
+		{ID: "sublet", Name: "Sublet", AccountID: "sav", Classification: "income", AmountText: "€900.00", Date: mustDate("2026-03-01")},
+		// Already a transfer in Sure.
+		{ID: "linked-out", AccountID: "chk", Classification: "expense", AmountText: "€20.00", Date: mustDate("2026-03-02")},
+		{ID: "linked-in", AccountID: "sav", Classification: "income", AmountText: "€20.00", Date: mustDate("2026-03-02")},
+	}
+	got := SuggestTransfers(txs, map[string]bool{"linked-out": true, "linked-in": true}, map[string]bool{TransferKey("rent", "sublet"): true}, TransferOptions{})
+	if len(got) != 2 {
+		t.Fatalf("suggestions = %+v", got)
+	}
+	if s := got[0]; s.Outflow.ID != "to-sav" || s.Inflow.Account != "sav" || s.Confidence != 0.94 || s.Reason != "transfer_keyword" {
+		t.Errorf("savings transfer = %+v", s)
+	}
+	if s := got[1]; s.Outflow.ID != "cash-out" || s.Inflow.ID != "refund" || s.Alternatives != 1 || s.Confidence != 0.5 || s.Reason != "ambiguous_amount" {
+		t.Errorf("ambiguous pair = %+v", s)
+	}
+}
@@ -0,0 +1,359 @@
+// Package journal records the writes sure-cli applies, together with the
+// state each one replaced, so they can be listed and reverted later.
+//
+// The journal is an append-only JSON Lines file: one Operation per applied
+// command. Undoing an operation appends a new operation whose Undoes field
+// points at the original, so an undo can itself be undone.
+package journal
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/we-promise/sure-cli/internal/insights"
+)
+
+// Operation is one applied command and the writes it sent.
+type Operation struct {
+	ID        string    `json:"id"`
+	Command   string    `json:"command"`
+	CreatedAt time.Time `json:"created_at"`
+	APIURL    string    `json:"api_url,omitempty"`
+	Undoes    string    `json:"undoes,omitempty"`
+	Writes    []Write   `json:"writes"`
+}
+
+// Write is one successful request. Before is the resource as fetched just
+// before an update or delete; After is the response body (for creates, the
+// new resource with its id).
+type Write struct {
+	Method string         `json:"method"`
+	Path   string         `json:"path"`
+	Body   any            `json:"body,omitempty"`
+	Status int            `json:"status"`
+	Before map[string]any `json:"before,omitempty"`
+	After  map[string]any `json:"after,omitempty"`
+}
+
+// Request is an inverse request planned by Inverse.
+type Request struct {
+	Method string `json:"method"`
+	Path   string `json:"path"`
+	Body   any    `json:"body,omitempty"`
+}
+
+// NewOperation starts an operation for command with a fresh id.
+func NewOperation(command, apiURL string, now time.Time) *Operation {
+	var b [3]byte
+	_, _ = rand.Read(b[:])
+	return &Operation{
+		ID:        fmt.Sprintf("op_%s_%s", now.UTC().Format("20060102T150405"), hex.EncodeToString(b[:])),
+		Command:   command,
+		CreatedAt: now.UTC().Truncate(time.Second),
+		APIURL:    apiURL,
+		Writes:    []Write{},
+	}
+}
+
+// Append adds op as one line at the end of the journal at path, creating
+// the file (owner-only, like the config) if needed.
+func Append(path string, op Operation) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	line, err := json.Marshal(op)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Load reads every operation in the journal, oldest first. A missing
+// journal is empty.
+func Load(path string) ([]Operation, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var ops []Operation
+	dec := json.NewDecoder(f)
+	for {
+		var op Operation
+		if err := dec.Decode(&op); err == io.EOF {
+			return ops, nil
+		} else if err != nil {
+			return nil, fmt.Errorf("journal %s: entry %d: %w", path, len(ops)+1, err)
+		}
+		ops = append(ops, op)
+	}
+}
+
+// Find returns the operation with the given id.
+func Find(ops []Operation, id string) (Operation, bool) {
+	for _, op := range ops {
+		if op.ID == id {
+			return op, true
+		}
+	}
+	return Operation{}, false
+}
+
+// UndoneBy returns the id of the latest operation that undid id, or "".
+func UndoneBy(ops []Operation, id string) string {
+	by := ""
+	for _, op := range ops {
+		if op.Undoes == id {
+			by = op.ID
+		}
+	}
+	return by
+}
+
+// resource describes how to rebuild the request params of one API
+// resource from its JSON representation.
+type resource struct {
+	collection string
+	key        string
+	params     func(m map[string]any) (map[string]any, error)
+	// methods limits the writes that are journaled (nil = POST, PATCH, PUT
+	// and DELETE).
+	methods []string
+	// irreversible, when set, says why journaled writes cannot be reverted.
+	irreversible string
+}
+
+var resources = []resource{
+	{collection: "/api/v1/transactions", key: "transaction", params: transactionParams},
+	{collection: "/api/v1/tags", key: "tag", params: tagParams},
+	{collection: "/api/v1/categories", key: "category", params: categoryParams},
+	// Budget categories come with their budget; only the amount changes.
+	{collection: "/api/v1/budget_categories", key: "budget_category", params: budgetCategoryParams, methods: []string{"PATCH", "PUT"}},
+	{collection: "/api/v1/recurring_transactions", key: "recurring_transaction", params: recurringParams},
+	// Transfer links are recorded for history only.
+	{collection: "/api/v1/transfers", key: "transfer", methods: []string{"POST"}, irreversible: "Sure has no endpoint to unlink a transfer"},
+}
+
+// resourceFor matches path to a journaled resource; id is "" for the
+// collection itself.
+func resourceFor(path string) (res resource, id string, ok bool) {
+	for _, r := range resources {
+		if path == r.collection {
+			return r, "", true
+		}
+		if rest, found := strings.CutPrefix(path, r.collection+"/"); found && rest != "" && !strings.Contains(rest, "/") {
+			return r, rest, true
+		}
+	}
+	return resource{}, "", false
+}
+
+// Undoable reports whether writes to path can be journaled and reverted.
+func Undoable(method, path string) bool {
+	res, _, _ := resourceFor(path)
+	return Journaled(method, path) && res.irreversible == ""
+}
+
+// Journaled reports whether writes to path are recorded in the journal.
+// Inverse rejects the ones that are not also Undoable.
+func Journaled(method, path string) bool {
+	res, id, ok := resourceFor(path)
+	if !ok || (res.methods != nil && !slices.Contains(res.methods, method)) {
+		return false
+	}
+	switch method {
+	case "POST":
+		return id == ""
+	case "PATCH", "PUT", "DELETE":
+		return id != ""
+	}
+	return false
+}
+
+// Inverse plans the requests that revert op, newest write first. Deleted
+// resources are recreated, so they come back with new ids.
+func Inverse(op Operation) ([]Request, error) {
+	reqs := make([]Request, 0, len(op.Writes))
+	for i := len(op.Writes) - 1; i >= 0; i-- {
+		req, err := inverseWrite(op.Writes[i])
+		if err != nil {
+			return nil, fmt.Errorf("%s %s: %w", op.Writes[i].Method, op.Writes[i].Path, err)
+		}
+		reqs = append(reqs, req)
+	}
+	return reqs, nil
+}
+
+func inverseWrite(w Write) (Request, error) {
+	res, id, ok := resourceFor(w.Path)
+	if !ok || !Journaled(w.Method, w.Path) {
+		return Request{}, errors.New("not an undoable write")
+	}
+	if res.irreversible != "" {
+		return Request{}, errors.New(res.irreversible)
+	}
+	switch w.Method {
+	case "POST":
+		created := optString(w.After["id"])
+		if created == "" {
+			return Request{}, errors.New("create response has no id")
+		}
+		return Request{Method: "DELETE", Path: res.collection + "/" + url.PathEscape(created)}, nil
+	case "DELETE":
+		if w.Before == nil {
+			return Request{}, errors.New("prior state was not recorded")
+		}
+		params, err := res.params(w.Before)
+		if err != nil {
+			return Request{}, err
+		}
+		return Request{Method: "POST", Path: res.collection, Body: map[string]any{res.key: params}}, nil
+	default:
+		if w.Before == nil {
+			return Request{}, errors.New("prior state was not recorded")
+		}
+		params, err := res.params(w.Before)
+		if err != nil {
+			return Request{}, err
+		}
+		body, _ := w.Body.(map[string]any)
+		sent, _ := body[res.key].(map[string]any)
+		if len(sent) == 0 {
+			return Request{}, fmt.Errorf("request body has no %q fields", res.key)
+		}
+		restore := map[string]any{}
+		for field := range sent {
+			if field == "amount" || field == "nature" {
+				restore["amount"], restore["nature"] = params["amount"], params["nature"]
+				continue
+			}
+			v, ok := params[field]
+			if !ok {
+				return Request{}, fmt.Errorf("cannot restore field %q", field)
+			}
+			restore[field] = v
+		}
+		return Request{Method: "PATCH", Path: res.collection + "/" + id, Body: map[string]any{res.key: restore}}, nil
+	}
+}
+
+// transactionParams maps a transaction as Sure renders it back to the
+// params its create/update endpoints take. Absent references map to "", so
+// restoring them clears whatever a later write set.
+func transactionParams(m map[string]any) (map[string]any, error) {
+	amount, err := insights.ParseAmount(optString(m["amount"]))
+	if err != nil {
+		return nil, fmt.Errorf("amount %q: %w", optString(m["amount"]), err)
+	}
+	if amount < 0 {
+		amount = -amount
+	}
+	nature := "expense"
+	if optString(m["classification"]) == "income" {
+		nature = "income"
+	}
+	p := map[string]any{
+		"account_id":  refID(m["account"]),
+		"date":        optString(m["date"]),
+		"amount":      fmt.Sprintf("%.2f", amount),
+		"nature":      nature,
+		"name":        optString(m["name"]),
+		"notes":       optString(m["notes"]),
+		"currency":    optString(m["currency"]),
+		"category_id": refID(m["category"]),
+		"merchant_id": refID(m["merchant"]),
+		"tag_ids":     []string{},
+	}
+	tags, _ := m["tags"].([]any)
+	for _, t := range tags {
+		if id := refID(t); id != "" {
+			p["tag_ids"] = append(p["tag_ids"].([]string), id)
+		}
+	}
+	return p, nil
+}
+
+func budgetCategoryParams(m map[string]any) (map[string]any, error) {
+	amount, err := insights.ParseAmount(optString(m["budgeted_spending"]))
+	if err != nil {
+		return nil, fmt.Errorf("budgeted_spending %q: %w", optString(m["budgeted_spending"]), err)
+	}
+	return map[string]any{"budgeted_spending": fmt.Sprintf("%.2f", amount)}, nil
+}
+
+// recurringParams maps a recurring transaction back to its create params.
+// Amounts keep Sure's sign, as the create endpoint takes them.
+func recurringParams(m map[string]any) (map[string]any, error) {
+	p := map[string]any{
+		"name":                  optString(m["name"]),
+		"currency":              optString(m["currency"]),
+		"account_id":            refID(m["account"]),
+		"merchant_id":           refID(m["merchant"]),
+		"expected_day_of_month": m["expected_day_of_month"],
+		"last_occurrence_date":  optString(m["last_occurrence_date"]),
+		"next_expected_date":    optString(m["next_expected_date"]),
+		"status":                optString(m["status"]),
+		"occurrence_count":      m["occurrence_count"],
+		"manual":                m["manual"],
+	}
+	for _, k := range []string{"amount", "expected_amount_min", "expected_amount_max", "expected_amount_avg"} {
+		if m[k] == nil {
+			continue
+		}
+		v, err := insights.ParseAmount(optString(m[k]))
+		if err != nil {
+			return nil, fmt.Errorf("%s %q: %w", k, optString(m[k]), err)
+		}
+		p[k] = fmt.Sprintf("%.2f", v)
+	}
+	return p, nil
+}
+
+func tagParams(m map[string]any) (map[string]any, error) {
+	return map[string]any{"name": optString(m["name"]), "color": optString(m["color"])}, nil
+}
+
+func categoryParams(m map[string]any) (map[string]any, error) {
+	p := map[string]any{"name": optString(m["name"]), "color": optString(m["color"])}
+	if icon := optString(m["icon"]); icon != "" {
+		p["icon"] = icon
+	}
+	if parent := refID(m["parent"]); parent != "" {
+		p["parent_id"] = parent
+	}
+	return p, nil
+}
+
+func refID(v any) string {
+	m, _ := v.(map[string]any)
+	return optString(m["id"])
+}
+
+func optString(v any) string {
+	if v == nil {
+		return ""
+	}
+	return fmt.Sprint(v)
+}
@@ -0,0 +1,169 @@
+package journal
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAppendLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "journal.jsonl")
+	if ops, err := Load(path); err != nil || ops != nil {
+		t.Fatalf("missing journal = %v, %v", ops, err)
+	}
+
+	now := time.Date(2026, 5, 1, 9, 30, 0, 0, time.UTC)
+	first := NewOperation("tags create", "http://sure.test", now)
+	first.Writes = append(first.Writes, Write{Method: "POST", Path: "/api/v1/tags", Status: 201, After: map[string]any{"id": "tag_1"}})
+	undo := NewOperation("undo", "http://sure.test", now.Add(time.Minute))
+	undo.Undoes = first.ID
+	for _, op := range []*Operation{first, undo} {
+		if err := Append(path, *op); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if fi, err := os.Stat(path); err != nil || fi.Mode().Perm() != 0o600 {
+		t.Fatalf("journal mode = %v, %v", fi, err)
+	}
+
+	ops, err := Load(path)
+	if err != nil || len(ops) != 2 {
+		t.Fatalf("Load = %v, %v", ops, err)
+	}
+	if got, ok := Find(ops, first.ID); !ok || got.Writes[0].After["id"] != "tag_1" || !got.CreatedAt.Equal(now) {
+		t.Fatalf("Find = %+v, %v", got, ok)
+	}
+	if by := UndoneBy(ops, first.ID); by != undo.ID {
+		t.Fatalf("UndoneBy = %q", by)
+	}
+	if by := UndoneBy(ops, undo.ID); by != "" {
+		t.Fatalf("undo undone by %q", by)
+	}
+
+	if err := os.WriteFile(path, []byte("{not json\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(path); err == nil {
+		t.Fatal("corrupt journal: want an error")
+	}
+}
+
+func TestInverse(t *testing.T) {
+	txn := map[string]any{
+		"id":             "txn_1",
+		"date":           "2026-04-02",
+		"amount":         "-€1,250.00",
+		"currency":       "EUR",
+		"name":           "Salary",
+		"notes":          nil,
+		"classification": "income",
+		"account":        map[string]any{"id": "acc_1", "name": "Checking"},
+		"category":       map[string]any{"id": "cat_1", "name": "Income"},
+		"merchant":       nil,
+		"tags":           []any{map[string]any{"id": "tag_1", "name": "Work"}},
+	}
+	op := Operation{ID: "op_1", Writes: []Write{
+		{Method: "POST", Path: "/api/v1/tags", Status: 201, After: map[string]any{"id": "tag 2"}},
+		{Method: "PUT", Path: "/api/v1/transactions/txn_1", Status: 200, Before: txn, Body: map[string]any{
+			"transaction": map[string]any{"amount": "10.00", "nature": "expense", "notes": "x", "category_id": "cat_2", "merchant_id": "mer_1"},
+		}},
+		{Method: "DELETE", Path: "/api/v1/transactions/txn_1", Status: 200, Before: txn},
+	}}
+	got, err := Inverse(op)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Request{
+		{Method: "POST", Path: "/api/v1/transactions", Body: map[string]any{"transaction": map[string]any{
+			"account_id": "acc_1", "date": "2026-04-02", "amount": "1250.00", "nature": "income", "name": "Salary",
+			"notes": "", "currency": "EUR", "category_id": "cat_1", "merchant_id": "", "tag_ids": []string{"tag_1"},
+		}}},
+		{Method: "PATCH", Path: "/api/v1/transactions/txn_1", Body: map[string]any{"transaction": map[string]any{
+			"amount": "1250.00", "nature": "income", "notes": "", "category_id": "cat_1", "merchant_id": "",
+		}}},
+		{Method: "DELETE", Path: "/api/v1/tags/tag%202"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("Inverse =\n%+v\nwant\n%+v", got, want)
+	}
+
+	bc := Write{Method: "PATCH", Path: "/api/v1/budget_categories/bc_1", Status: 200,
+		Before: map[string]any{"id": "bc_1", "budgeted_spending": "€1,200.50", "currency": "EUR"},
+		Body:   map[string]any{"budget_category": map[string]any{"budgeted_spending": "0.00"}}}
+	got, err = Inverse(Operation{Writes: []Write{bc}})
+	if err != nil || !reflect.DeepEqual(got, []Request{{Method: "PATCH", Path: "/api/v1/budget_categories/bc_1", Body: map[string]any{
+		"budget_category": map[string]any{"budgeted_spending": "1200.50"}}}}) {
+		t.Fatalf("budget category inverse = %+v, %v", got, err)
+	}
+
+	recurring := map[string]any{
+		"id": "rec_1", "name": "Gym", "amount": "€29.90", "currency": "EUR", "expected_day_of_month": float64(3),
+		"last_occurrence_date": "2026-09-03", "next_expected_date": "2026-10-03", "status": "active",
+		"occurrence_count": float64(6), "manual": false, "expected_amount_min": nil, "expected_amount_max": nil,
+		"expected_amount_avg": "€29.90", "account": map[string]any{"id": "acc_1"}, "merchant": nil,
+	}
+	got, err = Inverse(Operation{Writes: []Write{
+		{Method: "DELETE", Path: "/api/v1/recurring_transactions/rec_1", Status: 200, Before: recurring},
+		{Method: "PATCH", Path: "/api/v1/recurring_transactions/rec_2", Status: 200, Before: recurring,
+			Body: map[string]any{"recurring_transaction": map[string]any{"status": "inactive"}}},
+	}})
+	if err != nil || !reflect.DeepEqual(got, []Request{
+		{Method: "PATCH", Path: "/api/v1/recurring_transactions/rec_2", Body: map[string]any{"recurring_transaction": map[string]any{"status": "active"}}},
+		{Method: "POST", Path: "/api/v1/recurring_transactions", Body: map[string]any{"recurring_transaction": map[string]any{
+			"name": "Gym", "amount": "29.90", "currency": "EUR", "account_id": "acc_1", "merchant_id": "",
+			"expected_day_of_month": float64(3), "last_occurrence_date": "2026-09-03", "next_expected_date": "2026-10-03",
+			"status": "active", "occurrence_count": float64(6), "manual": false, "expected_amount_avg": "29.90",
+		}}},
+	}) {
+		t.Fatalf("recurring inverse = %+v, %v", got, err)
+	}
+
+	bad := map[string]Write{
+		"no prior state":  {Method: "PATCH", Path: "/api/v1/tags/tag_1", Body: map[string]any{"tag": map[string]any{"name": "x"}}},
+		"no created id":   {Method: "POST", Path: "/api/v1/categories", After: map[string]any{}},
+		"unknown field":   {Method: "PATCH", Path: "/api/v1/tags/tag_1", Before: map[string]any{"name": "a"}, Body: map[string]any{"tag": map[string]any{"icon": "x"}}},
+		"not journalable": {Method: "POST", Path: "/api/v1/budgets", After: map[string]any{"id": "bud_1"}},
+		"irreversible":    {Method: "POST", Path: "/api/v1/transfers", After: map[string]any{"id": "tr_1"}},
+	}
+	for name, w := range bad {
+		if _, err := Inverse(Operation{Writes: []Write{w}}); err == nil {
+			t.Errorf("%s: want an error", name)
+		}
+	}
+}
+
+func TestUndoable(t *testing.T) {
+	cases := map[string]bool{
+		"POST /api/v1/transactions":                   true,
+		"POST /api/v1/transactions/txn_1":             false,
+		"PATCH /api/v1/transactions/txn_1":            true,
+		"DELETE /api/v1/tags/tag_1":                   true,
+		"DELETE /api/v1/tags":                         false,
+		"PATCH /api/v1/budgets/bud_1":                 false,
+		"PATCH /api/v1/budget_categories/b1":          true,
+		"DELETE /api/v1/budget_categories/b1":         false,
+		"POST /api/v1/budget_categories":              false,
+		"POST /api/v1/transfers":                      false,
+		"POST /api/v1/recurring_transactions":         true,
+		"DELETE /api/v1/recurring_transactions/rec_1": true,
+		"PATCH /api/v1/transactions/a/b":              false,
+		"PUT /api/v1/transactions/txn_1":              true,
+		"GET /api/v1/transactions/txn_1":              false,
+		"DELETE /api/v1/categories/cat_1":             true,
+		"POST /api/v1/categories":                     true,
+		"PATCH /api/v1/transactionsx/txn_1":           false,
+		"DELETE /api/v1/transactions/txn_1/":          false,
+	}
+	for in, want := range cases {
+		method, path, _ := strings.Cut(in, " ")
+		if got := Undoable(method, path); got != want {
+			t.Errorf("Undoable(%s) = %v, want %v", in, got, want)
+		}
+	}
+	if !Journaled("POST", "/api/v1/transfers") || Journaled("DELETE", "/api/v1/transfers/tr_1") {
+		t.Errorf("transfer links should be journaled, and only their creation")
+	}
+}
@@ -0,0 +1,15 @@
+package models
+
+// Account is a minimal view of a Sure account. Balances are kept as
+// returned by Sure (see Transaction.AmountText); CashBalanceText is empty
+// when Sure does not report a cash balance (non-investment accounts).
+type Account struct {
+	ID              string
+	Name            string
+	AccountType     string // depository|credit_card|investment|loan|...
+	Subtype         string
+	Classification  string // asset|liability
+	Currency        string
+	BalanceText     string
+	CashBalanceText string
+}
@@ -0,0 +1,18 @@
+package models
+
+// BalanceSheet is Sure's family-level balance sheet in the family currency.
+type BalanceSheet struct {
+	Currency        string
+	AssetsText      string
+	LiabilitiesText string
+	NetWorthText    string
+}
+
+// Balance is one record of an account's balance history.
+type Balance struct {
+	ID          string
+	AccountID   string
+	Date        string // YYYY-MM-DD
+	BalanceText string
+	Currency    string
+}
@@ -0,0 +1,16 @@
+package models
+
+// BudgetCategory is a minimal view of a Sure budget category: the amount
+// budgeted for one category in one budget period. Amounts are kept as
+// returned by Sure (see Transaction.AmountText).
+type BudgetCategory struct {
+	ID           string
+	BudgetID     string
+	CategoryID   string
+	CategoryName string
+	BudgetedText string
+	ActualText   string
+	Currency     string
+	StartDate    string // YYYY-MM-DD
+	EndDate      string // YYYY-MM-DD
+}
@@ -0,0 +1,11 @@
+package models
+
+// Category is a minimal view of a Sure category. ParentID/ParentName are
+// empty for root categories.
+type Category struct {
+	ID             string
+	Name           string
+	Classification string // income|expense
+	ParentID       string
+	ParentName     string
+}
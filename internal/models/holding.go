@@ -0,0 +1,15 @@
+package models
+
+// Holding is a minimal view of a Sure investment holding on a given date.
+// ValueText is quantity × price as formatted by Sure.
+type Holding struct {
+	ID         string
+	AccountID  string
+	SecurityID string
+	Ticker     string
+	Name       string
+	Date       string // YYYY-MM-DD
+	Qty        string
+	ValueText  string
+	Currency   string
+}
@@ -0,0 +1,25 @@
+package models
+
+// RecurringTransaction is a minimal view of a Sure recurring transaction.
+// AmountText follows Sure's entry convention: positive is an outflow
+// (expense), negative an inflow (income). The ExpectedAmount* texts are
+// empty when Sure has no amount range for the entry.
+type RecurringTransaction struct {
+	ID                 string
+	Name               string
+	AmountText         string
+	Currency           string
+	AccountID          string
+	AccountName        string
+	MerchantID         string
+	MerchantName       string
+	ExpectedDayOfMonth int
+	LastOccurrenceDate string // YYYY-MM-DD
+	NextExpectedDate   string // YYYY-MM-DD
+	Status             string // active|inactive
+	OccurrenceCount    int
+	Manual             bool
+	ExpectedMinText    string
+	ExpectedMaxText    string
+	ExpectedAvgText    string
+}
@@ -0,0 +1,8 @@
+package models
+
+// Tag is a minimal view of a Sure tag.
+type Tag struct {
+	ID    string
+	Name  string
+	Color string
+}
@@ -14,8 +14,19 @@ type Transaction struct {
 	AmountText     string // e.g. "€1.00" or "-€2.00"
 	Currency       string
 	Date           time.Time
+	AccountID      string
 	AccountName    string
+	MerchantID     string
 	MerchantName   string
 	CategoryName   string
 	CategoryID     string
+	Notes          string
+	TagIDs         []string
+	TagNames       []string // same order as TagIDs
+	// TransferID is set when Sure links the transaction to another leg
+	// in one of the user's accounts (a transfer or payment).
+	TransferID string
+	// Kind is Sure's transaction kind when reported: standard,
+	// funds_movement, cc_payment, loan_payment, one_time.
+	Kind string
 }
@@ -0,0 +1,13 @@
+package models
+
+// Transfer links the outflow and inflow transactions of money moved
+// between two of the user's accounts (Sure transfers and rejected
+// transfer suggestions share this shape).
+type Transfer struct {
+	ID                   string
+	Status               string // pending|confirmed (transfers only)
+	Date                 string // YYYY-MM-DD
+	AmountText           string
+	OutflowTransactionID string
+	InflowTransactionID  string
+}
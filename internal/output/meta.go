@@ -4,7 +4,9 @@ package output
 //
 // Schema: optional $id of the JSON schema that `data` conforms to.
 // Status: HTTP status code (when known).
+// OperationID: journal id of the writes just applied (see `sure-cli undo`).
 type Meta struct {
-	Schema string `json:"schema,omitempty"`
-	Status int    `json:"status,omitempty"`
+	Schema      string `json:"schema,omitempty"`
+	Status      int    `json:"status,omitempty"`
+	OperationID string `json:"operation_id,omitempty"`
 }
@@ -0,0 +1,337 @@
+package plan
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/we-promise/sure-cli/internal/insights"
+	"github.com/we-promise/sure-cli/internal/models"
+)
+
+// ForecastEvent is a dated recurring inflow or outflow in a balance forecast.
+type ForecastEvent struct {
+	Date   string  `json:"date"`
+	Name   string  `json:"name"`
+	Amount float64 `json:"amount"` // signed: inflow positive, outflow negative
+	Source string  `json:"source"` // sure_recurring|detected_income|detected_subscription|scenario
+}
+
+type BalanceDay struct {
+	Date    string  `json:"date"`
+	Inflow  float64 `json:"inflow"`
+	Outflow float64 `json:"outflow"` // recurring outflows plus the average daily spend
+	Balance float64 `json:"balance"`
+}
+
+type AccountForecast struct {
+	AccountID       string  `json:"account_id"`
+	Account         string  `json:"account"`
+	Currency        string  `json:"currency"`
+	StartingBalance float64 `json:"starting_balance"`
+	EndingBalance   float64 `json:"ending_balance"`
+	LowestBalance   float64 `json:"lowest_balance"`
+	LowestDate      string  `json:"lowest_date"`
+	// OverdraftDate is the first day the balance is projected below zero.
+	OverdraftDate    *string         `json:"overdraft_date"`
+	AvgDailySpend    float64         `json:"avg_daily_spend"`
+	RecurringInflow  float64         `json:"recurring_inflow"`
+	RecurringOutflow float64         `json:"recurring_outflow"`
+	Events           []ForecastEvent `json:"events"`
+	Daily            []BalanceDay    `json:"daily"`
+}
+
+type BalanceTotals struct {
+	StartingBalance float64 `json:"starting_balance"`
+	EndingBalance   float64 `json:"ending_balance"`
+	LowestBalance   float64 `json:"lowest_balance"`
+	LowestDate      string  `json:"lowest_date"`
+}
+
+type ForecastWarning struct {
+	AccountID string  `json:"account_id"`
+	Account   string  `json:"account"`
+	Type      string  `json:"type"` // overdraft|below_minimum
+	Date      string  `json:"date"`
+	Balance   float64 `json:"balance"`
+	Message   string  `json:"message"`
+}
+
+type BalanceForecast struct {
+	AsOf        string            `json:"as_of"`
+	Days        int               `json:"days"`
+	MinBalance  float64           `json:"min_balance"`
+	Currency    string            `json:"currency"`
+	Accounts    []AccountForecast `json:"accounts"`
+	Totals      BalanceTotals     `json:"totals"`
+	Warnings    []ForecastWarning `json:"warnings"`
+	Assumptions []string          `json:"assumptions"`
+	// Transfers between own accounts left out of the history.
+	ExcludedTransfers *insights.TransferExclusion `json:"excluded_transfers,omitempty"`
+}
+
+// BalanceForecastOptions tunes ComputeBalanceForecast.
+type BalanceForecastOptions struct {
+	Days int
+	// AsOf is "today"; the projection covers the Days days after it.
+	AsOf time.Time
+	// HistoryStart is where txs begin, used to average discretionary spend
+	// per calendar day; zero uses the earliest transaction.
+	HistoryStart time.Time
+	// MinBalance adds a below_minimum warning when a balance drops under it.
+	MinBalance    float64
+	Subscriptions insights.SubscriptionOptions
+	Income        insights.CashflowOptions
+	// Scenario, when set, adds its changes to the accounts they name;
+	// changes without a forecast account go to the first account.
+	Scenario *ScenarioEffects
+}
+
+// recurringFlow is one projected recurring inflow or outflow on an account.
+type recurringFlow struct {
+	key    string
+	name   string
+	amount float64 // signed: inflow positive
+	source string
+	dates  []time.Time
+}
+
+// ComputeBalanceForecast projects each account's daily balance from its
+// current balance, Sure recurring transactions, detected recurring income and
+// subscriptions, and the account's average non-recurring daily spend. It
+// reports the lowest point per account and warns about overdrafts.
+func ComputeBalanceForecast(accounts []models.Account, txs []models.Transaction, recurring []models.RecurringTransaction, opts BalanceForecastOptions) BalanceForecast {
+	if opts.Days <= 0 {
+		opts.Days = 30
+	}
+	asOf := opts.AsOf
+	if asOf.IsZero() {
+		asOf = time.Now().UTC()
+	}
+	asOf = time.Date(asOf.Year(), asOf.Month(), asOf.Day(), 0, 0, 0, 0, time.UTC)
+	end := asOf.AddDate(0, 0, opts.Days)
+
+	historyStart := opts.HistoryStart
+	for _, tx := range txs {
+		if opts.HistoryStart.IsZero() && (historyStart.IsZero() || tx.Date.Before(historyStart)) {
+			historyStart = tx.Date
+		}
+	}
+	historyDays := asOf.Sub(historyStart).Hours()/24 + 1
+	if historyStart.IsZero() || historyDays < 1 {
+		historyDays = 1
+	}
+
+	out := BalanceForecast{
+		AsOf:       asOf.Format("2006-01-02"),
+		Days:       opts.Days,
+		MinBalance: opts.MinBalance,
+		Accounts:   []AccountForecast{},
+		Warnings:   []ForecastWarning{},
+		Assumptions: []string{
+			"starts from current Sure balances; the projection begins the day after as_of",
+			"Sure recurring transactions take precedence over detected ones for the same merchant; inactive ones are not projected",
+			"recurring income = salary or regular payers detected from history; irregular income is not projected",
+			"discretionary spend = non-recurring expenses averaged per calendar day of history",
+			"occurrences already past due are assumed settled",
+		},
+	}
+
+	byAccount := map[string][]models.Transaction{}
+	for _, tx := range txs {
+		byAccount[tx.AccountID] = append(byAccount[tx.AccountID], tx)
+	}
+	sureByAccount := map[string][]models.RecurringTransaction{}
+	for _, rt := range recurring {
+		sureByAccount[rt.AccountID] = append(sureByAccount[rt.AccountID], rt)
+	}
+
+	forecasted := map[string]bool{}
+	for _, acc := range accounts {
+		forecasted[acc.ID] = true
+	}
+	scenarioByAccount := map[string][]scenarioFlow{}
+	for _, f := range opts.Scenario.flows(asOf, end) {
+		if len(accounts) == 0 {
+			break
+		}
+		id := f.accountID
+		if !forecasted[id] {
+			id = accounts[0].ID
+		}
+		scenarioByAccount[id] = append(scenarioByAccount[id], f)
+	}
+	if opts.Scenario != nil {
+		out.Assumptions = append(out.Assumptions, "scenario changes applied on their dates to their account (else the first account); category changes are spread per day")
+	}
+
+	totals := map[string]float64{}
+	for _, acc := range accounts {
+		bal, _ := insights.ParseAmount(acc.BalanceText)
+		af := AccountForecast{
+			AccountID:       acc.ID,
+			Account:         acc.Name,
+			Currency:        acc.Currency,
+			StartingBalance: round2(bal),
+			Events:          []ForecastEvent{},
+			Daily:           []BalanceDay{},
+		}
+		if out.Currency == "" {
+			out.Currency = acc.Currency
+		}
+
+		flows := accountFlows(byAccount[acc.ID], sureByAccount[acc.ID], asOf, end, opts)
+		recurringKeys := map[string]bool{}
+		byDay := map[string][]ForecastEvent{}
+		for _, f := range flows {
+			recurringKeys[f.key] = true
+			for _, d := range f.dates {
+				ev := ForecastEvent{Date: d.Format("2006-01-02"), Name: f.name, Amount: round2(f.amount), Source: f.source}
+				af.Events = append(af.Events, ev)
+				byDay[ev.Date] = append(byDay[ev.Date], ev)
+				if f.amount > 0 {
+					af.RecurringInflow += f.amount
+				} else {
+					af.RecurringOutflow -= f.amount
+				}
+			}
+		}
+		scenarioDaily := map[string]float64{}
+		for _, f := range scenarioByAccount[acc.ID] {
+			ds := f.date.Format("2006-01-02")
+			if f.daily {
+				scenarioDaily[ds] += f.amount
+				continue
+			}
+			ev := ForecastEvent{Date: ds, Name: f.name, Amount: round2(f.amount), Source: "scenario"}
+			af.Events = append(af.Events, ev)
+			byDay[ds] = append(byDay[ds], ev)
+			switch {
+			case !f.recurring:
+			case f.amount > 0:
+				af.RecurringInflow += f.amount
+			default:
+				af.RecurringOutflow -= f.amount
+			}
+		}
+		sort.SliceStable(af.Events, func(i, j int) bool { return af.Events[i].Date < af.Events[j].Date })
+		af.RecurringInflow, af.RecurringOutflow = round2(af.RecurringInflow), round2(af.RecurringOutflow)
+
+		var discretionary float64
+		for _, tx := range byAccount[acc.ID] {
+			if tx.Classification != "expense" || tx.Date.After(asOf) || recurringKeys[opts.Subscriptions.Normalizer.Key(tx)] {
+				continue
+			}
+			if v, err := insights.SignedAmount(tx); err == nil {
+				discretionary += math.Abs(v)
+			}
+		}
+		daily := discretionary / historyDays
+		af.AvgDailySpend = round2(daily)
+
+		balance := bal
+		af.LowestBalance, af.LowestDate = af.StartingBalance, out.AsOf
+		var belowMin bool
+		for d := asOf.AddDate(0, 0, 1); !d.After(end); d = d.AddDate(0, 0, 1) {
+			ds := d.Format("2006-01-02")
+			day := BalanceDay{Date: ds, Outflow: daily - scenarioDaily[ds]}
+			for _, ev := range byDay[ds] {
+				if ev.Amount > 0 {
+					day.Inflow += ev.Amount
+				} else {
+					day.Outflow -= ev.Amount
+				}
+			}
+			balance += day.Inflow - day.Outflow
+			day.Inflow, day.Outflow, day.Balance = round2(day.Inflow), round2(day.Outflow), round2(balance)
+			af.Daily = append(af.Daily, day)
+			totals[ds] += balance
+
+			if day.Balance < af.LowestBalance {
+				af.LowestBalance, af.LowestDate = day.Balance, ds
+			}
+			if opts.MinBalance > 0 && !belowMin && day.Balance < opts.MinBalance && day.Balance >= 0 {
+				belowMin = true
+				out.Warnings = append(out.Warnings, ForecastWarning{
+					AccountID: acc.ID, Account: acc.Name, Type: "below_minimum", Date: ds, Balance: day.Balance,
+					Message: fmt.Sprintf("%s projected below the %.2f minimum on %s", acc.Name, opts.MinBalance, ds),
+				})
+			}
+			if af.OverdraftDate == nil && day.Balance < 0 {
+				date := ds
+				af.OverdraftDate = &date
+				msg := fmt.Sprintf("%s projected to go negative on %s", acc.Name, ds)
+				if in := nextInflow(af.Events, ds); in != nil {
+					msg += fmt.Sprintf(", before the next inflow (%s on %s)", in.Name, in.Date)
+				}
+				out.Warnings = append(out.Warnings, ForecastWarning{
+					AccountID: acc.ID, Account: acc.Name, Type: "overdraft", Date: ds, Balance: day.Balance, Message: msg,
+				})
+			}
+		}
+		af.EndingBalance = round2(balance)
+		out.Accounts = append(out.Accounts, af)
+		out.Totals.StartingBalance += bal
+	}
+
+	out.Totals.StartingBalance = round2(out.Totals.StartingBalance)
+	out.Totals.LowestBalance, out.Totals.LowestDate = out.Totals.StartingBalance, out.AsOf
+	out.Totals.EndingBalance = out.Totals.StartingBalance
+	for d := asOf.AddDate(0, 0, 1); !d.After(end) && len(out.Accounts) > 0; d = d.AddDate(0, 0, 1) {
+		v := round2(totals[d.Format("2006-01-02")])
+		out.Totals.EndingBalance = v
+		if v < out.Totals.LowestBalance {
+			out.Totals.LowestBalance, out.Totals.LowestDate = v, d.Format("2006-01-02")
+		}
+	}
+	return out
+}
+
+// accountFlows merges an account's Sure recurring transactions with detected
+// subscriptions (see MergeRecurring) and detected recurring income that Sure
+// does not track, and schedules each in (asOf, end].
+func accountFlows(txs []models.Transaction, sure []models.RecurringTransaction, asOf, end time.Time, opts BalanceForecastOptions) []recurringFlow {
+	n := opts.Subscriptions.Normalizer
+	subOpts := opts.Subscriptions
+	subOpts.AsOf = asOf
+
+	var flows []recurringFlow
+	for _, it := range MergeRecurring(sure, insights.DetectSubscriptionsWithOptions(txs, subOpts), n) {
+		f := recurringFlow{key: it.Merchant, name: it.Name, amount: -it.Amount, source: "detected_subscription", dates: it.Occurrences(asOf, end)}
+		if it.Classification == "income" {
+			f.amount = it.Amount
+		}
+		if it.RecurringID != "" {
+			f.source = "sure_recurring"
+		}
+		flows = append(flows, f)
+	}
+
+	tracked := map[string]bool{}
+	for _, rt := range sure {
+		tracked[n.Key(models.Transaction{Name: rt.Name, MerchantName: rt.MerchantName})] = true
+	}
+	incOpts := opts.Income
+	incOpts.Normalizer = n
+	for _, src := range insights.RecurringIncome(txs, incOpts) {
+		last, err := time.Parse("2006-01-02", src.LastSeen)
+		if err != nil || tracked[src.Source] {
+			continue
+		}
+		flows = append(flows, recurringFlow{
+			key: src.Source, name: src.Name, amount: src.AvgAmount, source: "detected_income",
+			dates: occurrences(last, src.AvgPeriodDays, last.Day(), asOf, end),
+		})
+	}
+	return flows
+}
+
+func nextInflow(events []ForecastEvent, after string) *ForecastEvent {
+	for i := range events {
+		if events[i].Amount > 0 && events[i].Date > after {
+			return &events[i]
+		}
+	}
+	return nil
+}
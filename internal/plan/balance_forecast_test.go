@@ -0,0 +1,95 @@
+package plan
+
+import (
+	"testing"
+	"time"
+
+	"github.com/we-promise/sure-cli/internal/models"
+)
+
+func TestComputeBalanceForecast(t *testing.T) {
+	asOf := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+	day := func(m time.Month, d int) time.Time { return time.Date(2026, m, d, 0, 0, 0, 0, time.UTC) }
+
+	var txs []models.Transaction
+	for m := time.October; m <= time.December; m++ {
+		txs = append(txs, models.Transaction{Name: "Acme Payroll", Classification: "income", AmountText: "-€2,000.00", AccountID: "chk", Date: time.Date(2025, m, 25, 0, 0, 0, 0, time.UTC)})
+	}
+	for m := time.January; m <= time.February; m++ {
+		txs = append(txs, models.Transaction{Name: "Acme Payroll", Classification: "income", AmountText: "-€2,000.00", AccountID: "chk", Date: day(m, 25)})
+		// Rent is tracked in Sure; the detected copy must not double count.
+		txs = append(txs, models.Transaction{Name: "Monthly Rent", Classification: "expense", AmountText: "€1,000.00", AccountID: "chk", Date: day(m, 15)})
+	}
+	// 100 of discretionary spend over 100 days of history -> 1/day.
+	txs = append(txs, models.Transaction{Name: "Groceries", Classification: "expense", AmountText: "€100.00", AccountID: "chk", Date: day(2, 1)})
+
+	accounts := []models.Account{
+		{ID: "chk", Name: "Checking", Classification: "asset", AccountType: "depository", Currency: "EUR", BalanceText: "€500.00"},
+		{ID: "sav", Name: "Savings", Classification: "asset", AccountType: "depository", Currency: "EUR", BalanceText: "€1,000.00"},
+	}
+	recurring := []models.RecurringTransaction{
+		{ID: "r1", Name: "Monthly Rent", AmountText: "€1,000.00", AccountID: "chk", ExpectedDayOfMonth: 15, NextExpectedDate: "2026-03-15", Status: "active"},
+		{ID: "r2", Name: "Old Gym", AmountText: "€40.00", AccountID: "chk", ExpectedDayOfMonth: 12, NextExpectedDate: "2026-03-12", Status: "inactive"},
+	}
+
+	r := ComputeBalanceForecast(accounts, txs, recurring, BalanceForecastOptions{
+		Days:         30,
+		AsOf:         asOf,
+		HistoryStart: asOf.AddDate(0, 0, -99),
+		MinBalance:   1000,
+	})
+	if len(r.Accounts) != 2 || r.AsOf != "2026-03-10" || r.Currency != "EUR" {
+		t.Fatalf("unexpected header: %+v", r)
+	}
+	chk := r.Accounts[0]
+	if len(chk.Daily) != 30 || chk.Daily[0].Date != "2026-03-11" {
+		t.Fatalf("daily: %d entries starting %s", len(chk.Daily), chk.Daily[0].Date)
+	}
+	if chk.AvgDailySpend != 1 {
+		t.Fatalf("avg daily spend = %v, want 1", chk.AvgDailySpend)
+	}
+	// Rent once (Sure, not detected), payroll once on the 25th.
+	if len(chk.Events) != 2 || chk.Events[0].Source != "sure_recurring" || chk.Events[1].Source != "detected_income" || chk.Events[1].Date != "2026-03-25" {
+		t.Fatalf("events: %+v", chk.Events)
+	}
+	// 500 - 5 days x 1/day - 1000 rent on the 15th; lowest the day before payday.
+	if chk.OverdraftDate == nil || *chk.OverdraftDate != "2026-03-15" {
+		t.Fatalf("overdraft date: %v", chk.OverdraftDate)
+	}
+	if chk.LowestBalance != -514 || chk.LowestDate != "2026-03-24" {
+		t.Fatalf("lowest: %v on %s", chk.LowestBalance, chk.LowestDate)
+	}
+	if chk.EndingBalance != 1470 {
+		t.Fatalf("ending balance = %v", chk.EndingBalance)
+	}
+
+	var overdraft, belowMin int
+	for _, w := range r.Warnings {
+		switch w.Type {
+		case "overdraft":
+			overdraft++
+			if w.AccountID != "chk" || w.Message != "Checking projected to go negative on 2026-03-15, before the next inflow (Acme Payroll on 2026-03-25)" {
+				t.Fatalf("overdraft warning: %+v", w)
+			}
+		case "below_minimum":
+			belowMin++
+		}
+	}
+	// Checking dips under 1000 immediately; savings sits exactly at it.
+	if overdraft != 1 || belowMin != 1 {
+		t.Fatalf("warnings: %+v", r.Warnings)
+	}
+	if r.Totals.StartingBalance != 1500 || r.Totals.LowestBalance != 486 || r.Totals.LowestDate != "2026-03-24" {
+		t.Fatalf("totals: %+v", r.Totals)
+	}
+}
+
+func TestAddMonthsClamped(t *testing.T) {
+	jan31 := time.Date(2026, 1, 31, 0, 0, 0, 0, time.UTC)
+	if got := addMonthsClamped(jan31, 1, 31).Format("2006-01-02"); got != "2026-02-28" {
+		t.Fatalf("got %s", got)
+	}
+	if got := addMonthsClamped(jan31, 2, 31).Format("2006-01-02"); got != "2026-03-31" {
+		t.Fatalf("got %s", got)
+	}
+}
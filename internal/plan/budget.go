@@ -16,6 +16,8 @@ type BudgetSummary struct {
 	Projected   float64  `json:"projected"`
 	Currency    string   `json:"currency"`
 	Assumptions []string `json:"assumptions"`
+	// Transfers and card payments left out of Spent.
+	ExcludedTransfers *insights.TransferExclusion `json:"excluded_transfers,omitempty"`
 }
 
 // ComputeMonthlyBudget is a lightweight client-side budget pacing view.
@@ -0,0 +1,198 @@
+package plan
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/we-promise/sure-cli/internal/insights"
+	"github.com/we-promise/sure-cli/internal/models"
+)
+
+type CategoryBudgetStatus struct {
+	BudgetCategoryID string  `json:"budget_category_id"`
+	CategoryID       string  `json:"category_id"`
+	Category         string  `json:"category"`
+	Budgeted         float64 `json:"budgeted"`
+	Spent            float64 `json:"spent"`
+	Remaining        float64 `json:"remaining"` // negative once over budget
+	PctUsed          float64 `json:"pct_used"`
+	Transactions     int     `json:"transactions"`
+	Projected        float64 `json:"projected"`
+	ProjectedOver    float64 `json:"projected_over"` // projected - budgeted when positive
+	// SafeDailySpend is what can still be spent per remaining day.
+	SafeDailySpend float64 `json:"safe_daily_spend"`
+	Status         string  `json:"status"` // on_track|at_risk|over_budget
+}
+
+type UnbudgetedSpend struct {
+	CategoryID string  `json:"category_id,omitempty"`
+	Category   string  `json:"category"`
+	Spent      float64 `json:"spent"`
+}
+
+type CategoryBudgetReport struct {
+	Month         string                 `json:"month"`
+	BudgetID      string                 `json:"budget_id"`
+	DaysElapsed   int                    `json:"days_elapsed"`
+	DaysRemaining int                    `json:"days_remaining"`
+	DaysInMonth   int                    `json:"days_in_month"`
+	Budgeted      float64                `json:"budgeted"`
+	Spent         float64                `json:"spent"` // budgeted categories only
+	Projected     float64                `json:"projected"`
+	Currency      string                 `json:"currency"`
+	Categories    []CategoryBudgetStatus `json:"categories"`
+	AtRisk        []string               `json:"at_risk"` // categories at_risk or over_budget
+	Unbudgeted    []UnbudgetedSpend      `json:"unbudgeted"`
+	Assumptions   []string               `json:"assumptions"`
+	// Transfers left out of the actuals.
+	ExcludedTransfers *insights.TransferExclusion `json:"excluded_transfers,omitempty"`
+}
+
+// ComputeCategoryBudgets joins Sure budget categories with the month's
+// expenses and projects each category to month end from its daily pace.
+// Spend in a subcategory counts towards its parent when only the parent is
+// budgeted. asOf sets "today"; months in the past are fully elapsed.
+func ComputeCategoryBudgets(month, asOf time.Time, budgets []models.BudgetCategory, categories []models.Category, txs []models.Transaction) CategoryBudgetReport {
+	start := time.Date(month.Year(), month.Month(), 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 1, 0)
+	daysInMonth := int(end.Sub(start).Hours() / 24)
+	daysElapsed := int(asOf.Sub(start).Hours()/24) + 1
+	switch {
+	case asOf.Before(start):
+		daysElapsed = 0
+	case !asOf.Before(end):
+		daysElapsed = daysInMonth
+	}
+
+	report := CategoryBudgetReport{
+		Month:         start.Format("2006-01"),
+		DaysElapsed:   daysElapsed,
+		DaysRemaining: daysInMonth - daysElapsed,
+		DaysInMonth:   daysInMonth,
+		Categories:    []CategoryBudgetStatus{},
+		AtRisk:        []string{},
+		Unbudgeted:    []UnbudgetedSpend{},
+		Assumptions: []string{
+			"projection = spent + (spent / days elapsed) x days remaining",
+			"a single transaction of at least 80% of the budget (e.g. rent) is treated as the month's fixed charge",
+			"subcategory spend counts towards a budgeted parent when the subcategory has no budget",
+		},
+	}
+
+	byCategory := map[string]int{}
+	for _, b := range budgets {
+		if report.BudgetID == "" {
+			report.BudgetID = b.BudgetID
+		}
+		if report.Currency == "" && b.Currency != "" && b.Currency != "<nil>" {
+			report.Currency = b.Currency
+		}
+		amt, _ := insights.ParseAmount(b.BudgetedText)
+		byCategory[b.CategoryID] = len(report.Categories)
+		report.Categories = append(report.Categories, CategoryBudgetStatus{
+			BudgetCategoryID: b.ID,
+			CategoryID:       b.CategoryID,
+			Category:         b.CategoryName,
+			Budgeted:         math.Abs(amt),
+		})
+	}
+	parentOf := map[string]string{}
+	for _, c := range categories {
+		parentOf[c.ID] = c.ParentID
+	}
+
+	unbudgeted := map[string]*UnbudgetedSpend{}
+	for _, tx := range txs {
+		if tx.Classification != "expense" || tx.Date.Before(start) || !tx.Date.Before(end) {
+			continue
+		}
+		v, err := insights.SignedAmount(tx)
+		if err != nil {
+			continue
+		}
+		amt := math.Abs(v)
+		i, ok := byCategory[tx.CategoryID]
+		if !ok && tx.CategoryID != "" {
+			i, ok = byCategory[parentOf[tx.CategoryID]]
+		}
+		if ok && tx.CategoryID != "" {
+			report.Categories[i].Spent += amt
+			report.Categories[i].Transactions++
+			continue
+		}
+		name, key := tx.CategoryName, tx.CategoryID
+		if name == "" {
+			name = "Uncategorized"
+		}
+		if key == "" {
+			key = name
+		}
+		if unbudgeted[key] == nil {
+			unbudgeted[key] = &UnbudgetedSpend{CategoryID: tx.CategoryID, Category: name}
+		}
+		unbudgeted[key].Spent += amt
+	}
+
+	for i := range report.Categories {
+		c := &report.Categories[i]
+		c.Spent, c.Budgeted = round2(c.Spent), round2(c.Budgeted)
+		c.Projected = c.Spent
+		fixed := c.Transactions == 1 && c.Spent >= 0.8*c.Budgeted
+		if daysElapsed > 0 && !fixed {
+			c.Projected = round2(c.Spent + c.Spent/float64(daysElapsed)*float64(report.DaysRemaining))
+		}
+		c.Remaining = round2(c.Budgeted - c.Spent)
+		if c.Budgeted > 0 {
+			c.PctUsed = math.Round(c.Spent/c.Budgeted*1000) / 10
+		}
+		if report.DaysRemaining > 0 && c.Remaining > 0 {
+			c.SafeDailySpend = round2(c.Remaining / float64(report.DaysRemaining))
+		}
+		switch {
+		case c.Spent > c.Budgeted:
+			c.Status = "over_budget"
+		case c.Projected > c.Budgeted:
+			c.Status = "at_risk"
+		default:
+			c.Status = "on_track"
+		}
+		if c.Projected > c.Budgeted {
+			c.ProjectedOver = round2(c.Projected - c.Budgeted)
+		}
+		report.Budgeted += c.Budgeted
+		report.Spent += c.Spent
+		report.Projected += c.Projected
+	}
+	report.Budgeted, report.Spent, report.Projected = round2(report.Budgeted), round2(report.Spent), round2(report.Projected)
+
+	// Worst first: biggest projected overspend, then highest usage.
+	sort.SliceStable(report.Categories, func(i, j int) bool {
+		a, b := report.Categories[i], report.Categories[j]
+		if a.ProjectedOver != b.ProjectedOver {
+			return a.ProjectedOver > b.ProjectedOver
+		}
+		return a.PctUsed > b.PctUsed
+	})
+	for _, c := range report.Categories {
+		if c.Status != "on_track" {
+			report.AtRisk = append(report.AtRisk, c.Category)
+		}
+	}
+	for _, u := range unbudgeted {
+		u.Spent = round2(u.Spent)
+		report.Unbudgeted = append(report.Unbudgeted, *u)
+	}
+	// Biggest first; ties by name so the output does not follow map order.
+	sort.Slice(report.Unbudgeted, func(i, j int) bool {
+		a, b := report.Unbudgeted[i], report.Unbudgeted[j]
+		if a.Spent != b.Spent {
+			return a.Spent > b.Spent
+		}
+		if a.Category != b.Category {
+			return a.Category < b.Category
+		}
+		return a.CategoryID < b.CategoryID
+	})
+	return report
+}
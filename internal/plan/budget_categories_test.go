@@ -0,0 +1,71 @@
+package plan
+
+import (
+	"testing"
+	"time"
+
+	"github.com/we-promise/sure-cli/internal/models"
+)
+
+func TestComputeCategoryBudgets(t *testing.T) {
+	month := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	asOf := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC) // 10 of 31 days elapsed
+	budgets := []models.BudgetCategory{
+		{ID: "bc_food", BudgetID: "bud", CategoryID: "food", CategoryName: "Food", BudgetedText: "€300.00", Currency: "EUR"},
+		{ID: "bc_rent", BudgetID: "bud", CategoryID: "rent", CategoryName: "Rent", BudgetedText: "€900.00", Currency: "EUR"},
+		{ID: "bc_fun", BudgetID: "bud", CategoryID: "fun", CategoryName: "Fun", BudgetedText: "€50.00", Currency: "EUR"},
+	}
+	cats := []models.Category{
+		{ID: "food", Name: "Food"},
+		{ID: "groc", Name: "Groceries", ParentID: "food", ParentName: "Food"},
+	}
+	exp := func(catID, catName, amount string, day int) models.Transaction {
+		return models.Transaction{Classification: "expense", AmountText: amount, CategoryID: catID, CategoryName: catName, Date: time.Date(2026, 3, day, 0, 0, 0, 0, time.UTC)}
+	}
+	txs := []models.Transaction{
+		// Food: 150 in 10 days via a subcategory -> pace 465 for the month.
+		exp("groc", "Groceries", "€100.00", 2),
+		exp("food", "Food", "€50.00", 8),
+		// Rent paid once, in full.
+		exp("rent", "Rent", "€900.00", 1),
+		// Fun already over budget.
+		exp("fun", "Fun", "€40.00", 3),
+		exp("fun", "Fun", "€30.00", 9),
+		// Not budgeted; and outside the month.
+		exp("travel", "Travel", "€200.00", 5),
+		exp("food", "Food", "€999.00", 0),
+	}
+
+	r := ComputeCategoryBudgets(month, asOf, budgets, cats, txs)
+	if r.DaysElapsed != 10 || r.DaysRemaining != 21 || r.BudgetID != "bud" {
+		t.Fatalf("days/budget: %+v", r)
+	}
+	got := map[string]CategoryBudgetStatus{}
+	for _, c := range r.Categories {
+		got[c.Category] = c
+	}
+	if f := got["Food"]; f.Spent != 150 || f.Projected != 465 || f.Status != "at_risk" || f.ProjectedOver != 165 || f.SafeDailySpend != 7.14 {
+		t.Errorf("food: %+v", f)
+	}
+	if rent := got["Rent"]; rent.Projected != 900 || rent.Status != "on_track" {
+		t.Errorf("rent should be treated as a fixed charge: %+v", rent)
+	}
+	if fun := got["Fun"]; fun.Status != "over_budget" || fun.Remaining != -20 {
+		t.Errorf("fun: %+v", fun)
+	}
+	if r.Categories[0].Category != "Fun" || len(r.AtRisk) != 2 || r.AtRisk[1] != "Food" {
+		t.Errorf("ordering/at_risk: %v %v", r.Categories, r.AtRisk)
+	}
+	if len(r.Unbudgeted) != 1 || r.Unbudgeted[0].Category != "Travel" || r.Unbudgeted[0].Spent != 200 {
+		t.Errorf("unbudgeted: %+v", r.Unbudgeted)
+	}
+
+	// Equal unbudgeted spend is ordered by name, not map order.
+	txs = append(txs, exp("gifts", "Gifts", "€200.00", 6), exp("books", "Books", "€200.00", 7))
+	for range 20 {
+		r = ComputeCategoryBudgets(month, asOf, budgets, cats, txs)
+		if len(r.Unbudgeted) != 3 || r.Unbudgeted[0].Category != "Books" || r.Unbudgeted[1].Category != "Gifts" || r.Unbudgeted[2].Category != "Travel" {
+			t.Fatalf("unbudgeted ties: %+v", r.Unbudgeted)
+		}
+	}
+}
@@ -0,0 +1,140 @@
+package plan
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/we-promise/sure-cli/internal/insights"
+	"github.com/we-promise/sure-cli/internal/models"
+)
+
+type BudgetSuggestion struct {
+	CategoryID string    `json:"category_id"`
+	Category   string    `json:"category"`
+	History    []float64 `json:"history"` // monthly spend, oldest first (see history_months)
+	Median     float64   `json:"median"`
+	Mean       float64   `json:"mean"`
+	Suggested  float64   `json:"suggested"`
+	// BudgetCategoryID and Current describe the existing budget category for
+	// the target month, when there is one.
+	BudgetCategoryID string   `json:"budget_category_id,omitempty"`
+	Current          *float64 `json:"current"`
+	Delta            *float64 `json:"delta"` // suggested - current
+}
+
+type BudgetSuggestReport struct {
+	Month          string             `json:"month"`
+	HistoryMonths  []string           `json:"history_months"`
+	TotalSuggested float64            `json:"total_suggested"`
+	Suggestions    []BudgetSuggestion `json:"suggestions"`
+	Assumptions    []string           `json:"assumptions"`
+}
+
+// SuggestBudgets proposes a budget per category for month from the median of
+// the previous `months` complete months of expenses (months without spend
+// count as zero), rounded up to a multiple of roundTo. existing is the
+// month's current budget categories, if any, to report deltas against.
+// Uncategorized spend cannot be budgeted and is ignored.
+func SuggestBudgets(month time.Time, months int, roundTo float64, txs []models.Transaction, existing []models.BudgetCategory) BudgetSuggestReport {
+	if months <= 0 {
+		months = 6
+	}
+	target := time.Date(month.Year(), month.Month(), 1, 0, 0, 0, 0, time.UTC)
+	first := target.AddDate(0, -months, 0)
+
+	report := BudgetSuggestReport{
+		Month:       target.Format("2006-01"),
+		Suggestions: []BudgetSuggestion{},
+		Assumptions: []string{
+			"suggested = median monthly spend over the history months, rounded up",
+			"months without spend in a category count as zero",
+		},
+	}
+	index := map[string]int{}
+	for m := first; m.Before(target); m = m.AddDate(0, 1, 0) {
+		index[m.Format("2006-01")] = len(report.HistoryMonths)
+		report.HistoryMonths = append(report.HistoryMonths, m.Format("2006-01"))
+	}
+
+	type cat struct {
+		name    string
+		monthly []float64
+	}
+	cats := map[string]*cat{}
+	get := func(id, name string) *cat {
+		if cats[id] == nil {
+			cats[id] = &cat{name: name, monthly: make([]float64, months)}
+		}
+		return cats[id]
+	}
+	for _, tx := range txs {
+		if tx.Classification != "expense" || tx.CategoryID == "" {
+			continue
+		}
+		i, ok := index[tx.Date.Format("2006-01")]
+		if !ok {
+			continue
+		}
+		v, err := insights.SignedAmount(tx)
+		if err != nil {
+			continue
+		}
+		get(tx.CategoryID, tx.CategoryName).monthly[i] += math.Abs(v)
+	}
+	current := map[string]models.BudgetCategory{}
+	for _, b := range existing {
+		current[b.CategoryID] = b
+		get(b.CategoryID, b.CategoryName)
+	}
+
+	for id, c := range cats {
+		s := BudgetSuggestion{CategoryID: id, Category: c.name, History: make([]float64, len(c.monthly))}
+		var sum float64
+		for i, v := range c.monthly {
+			s.History[i] = round2(v)
+			sum += v
+		}
+		s.Median = round2(medianOf(c.monthly))
+		s.Mean = round2(sum / float64(len(c.monthly)))
+		s.Suggested = roundUp(s.Median, roundTo)
+		if b, ok := current[id]; ok {
+			amt, _ := insights.ParseAmount(b.BudgetedText)
+			cur := round2(math.Abs(amt))
+			delta := round2(s.Suggested - cur)
+			s.BudgetCategoryID, s.Current, s.Delta = b.ID, &cur, &delta
+		}
+		report.TotalSuggested += s.Suggested
+		report.Suggestions = append(report.Suggestions, s)
+	}
+	report.TotalSuggested = round2(report.TotalSuggested)
+	sort.Slice(report.Suggestions, func(i, j int) bool {
+		a, b := report.Suggestions[i], report.Suggestions[j]
+		if a.Suggested != b.Suggested {
+			return a.Suggested > b.Suggested
+		}
+		return a.Category < b.Category
+	})
+	return report
+}
+
+func medianOf(xs []float64) float64 {
+	if len(xs) == 0 {
+		return 0
+	}
+	s := append([]float64(nil), xs...)
+	sort.Float64s(s)
+	n := len(s)
+	if n%2 == 1 {
+		return s[n/2]
+	}
+	return (s[n/2-1] + s[n/2]) / 2
+}
+
+// roundUp rounds v up to a multiple of step; step <= 0 keeps cents.
+func roundUp(v, step float64) float64 {
+	if step <= 0 {
+		return round2(v)
+	}
+	return math.Ceil(round2(v)/step) * step
+}
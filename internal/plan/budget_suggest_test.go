@@ -0,0 +1,69 @@
+package plan
+
+import (
+	"testing"
+	"time"
+
+	"github.com/we-promise/sure-cli/internal/models"
+)
+
+func TestSuggestBudgets(t *testing.T) {
+	month := time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)
+	exp := func(catID, catName, amount string, m time.Month, day int) models.Transaction {
+		return models.Transaction{Classification: "expense", AmountText: amount, CategoryID: catID, CategoryName: catName, Date: time.Date(2026, m, day, 0, 0, 0, 0, time.UTC)}
+	}
+	txs := []models.Transaction{
+		// Food: 210, 190, 400 -> median 210, rounded up to 210.
+		exp("food", "Food", "€210.00", 1, 5),
+		exp("food", "Food", "€150.00", 2, 5),
+		exp("food", "Food", "€40.00", 2, 20),
+		exp("food", "Food", "€400.00", 3, 5),
+		// Fun only in one month: median 0.
+		exp("fun", "Fun", "€80.00", 3, 9),
+		// Gym: 31.20 each month -> rounded up to 35.
+		exp("gym", "Gym", "€31.20", 1, 2),
+		exp("gym", "Gym", "€31.20", 2, 2),
+		exp("gym", "Gym", "€31.20", 3, 2),
+		// Ignored: outside history, target month, uncategorized, income.
+		exp("food", "Food", "€999.00", 4, 2),
+		exp("food", "Food", "€999.00", 12, 2),
+		exp("", "", "€50.00", 2, 2),
+		{Classification: "income", AmountText: "€3000.00", CategoryID: "salary", Date: time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	existing := []models.BudgetCategory{
+		{ID: "bc_food", CategoryID: "food", CategoryName: "Food", BudgetedText: "€250.00"},
+		{ID: "bc_travel", CategoryID: "travel", CategoryName: "Travel", BudgetedText: "€100.00"},
+	}
+
+	r := SuggestBudgets(month, 3, 5, txs, existing)
+	if r.Month != "2026-04" || len(r.HistoryMonths) != 3 || r.HistoryMonths[0] != "2026-01" {
+		t.Fatalf("unexpected header: %+v", r)
+	}
+	by := map[string]BudgetSuggestion{}
+	for _, s := range r.Suggestions {
+		by[s.CategoryID] = s
+	}
+	if len(by) != 4 {
+		t.Fatalf("expected food, fun, gym, travel; got %+v", r.Suggestions)
+	}
+	food := by["food"]
+	if food.Median != 210 || food.Suggested != 210 || food.Mean != 266.67 {
+		t.Fatalf("food: %+v", food)
+	}
+	if food.BudgetCategoryID != "bc_food" || *food.Current != 250 || *food.Delta != -40 {
+		t.Fatalf("food vs current: %+v", food)
+	}
+	if by["fun"].Suggested != 0 || by["fun"].Current != nil {
+		t.Fatalf("fun: %+v", by["fun"])
+	}
+	if by["gym"].Suggested != 35 {
+		t.Fatalf("gym: %+v", by["gym"])
+	}
+	// Budgeted but no history: suggest dropping to zero.
+	if tr := by["travel"]; tr.Suggested != 0 || *tr.Delta != -100 {
+		t.Fatalf("travel: %+v", tr)
+	}
+	if r.TotalSuggested != 245 || r.Suggestions[0].CategoryID != "food" {
+		t.Fatalf("total/order: %v %+v", r.TotalSuggested, r.Suggestions)
+	}
+}
@@ -0,0 +1,270 @@
+package plan
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+// Debt is one liability to pay off. APR is a yearly fraction (0.199).
+type Debt struct {
+	AccountID  string  `json:"account_id"`
+	Account    string  `json:"account"`
+	Balance    float64 `json:"balance"`
+	APR        float64 `json:"apr"`
+	MinPayment float64 `json:"min_payment"`
+	// Source says where the terms came from: config|flag|assumed.
+	Source string `json:"source"`
+}
+
+type DebtPayment struct {
+	AccountID string  `json:"account_id"`
+	Payment   float64 `json:"payment"`
+	Interest  float64 `json:"interest"`
+	Balance   float64 `json:"balance"` // after the payment
+}
+
+type DebtMonth struct {
+	Month     string        `json:"month"` // YYYY-MM
+	Payments  []DebtPayment `json:"payments"`
+	Paid      float64       `json:"paid"`
+	Interest  float64       `json:"interest"`
+	Remaining float64       `json:"remaining"`
+}
+
+type DebtPayoff struct {
+	AccountID  string  `json:"account_id"`
+	Account    string  `json:"account"`
+	Order      int     `json:"order"`
+	Months     int     `json:"months"`
+	PayoffDate *string `json:"payoff_date"` // YYYY-MM; nil when not paid off within the horizon
+	Interest   float64 `json:"interest"`
+}
+
+type DebtStrategy struct {
+	Strategy   string       `json:"strategy"` // avalanche|snowball|custom
+	Order      []string     `json:"order"`
+	PaidOff    bool         `json:"paid_off"`
+	Months     int          `json:"months"`
+	PayoffDate *string      `json:"payoff_date"`
+	TotalPaid  float64      `json:"total_paid"`
+	Interest   float64      `json:"interest"`
+	Debts      []DebtPayoff `json:"debts"`
+	Schedule   []DebtMonth  `json:"schedule"`
+}
+
+type DebtPlan struct {
+	AsOf          string         `json:"as_of"`
+	Currency      string         `json:"currency"`
+	Extra         float64        `json:"extra"`
+	MonthlyBudget float64        `json:"monthly_budget"` // minimums + extra
+	TotalBalance  float64        `json:"total_balance"`
+	Debts         []Debt         `json:"debts"`
+	Strategies    []DebtStrategy `json:"strategies"`
+	// Recommended is the strategy with the least interest (ties keep the
+	// first listed).
+	Recommended string   `json:"recommended"`
+	Warnings    []string `json:"warnings"`
+	Assumptions []string `json:"assumptions"`
+}
+
+// DebtOptions tunes PlanDebtPayoff.
+type DebtOptions struct {
+	// Extra is paid on top of the minimums every month.
+	Extra float64
+	// Strategies to simulate (avalanche, snowball, custom); empty means
+	// avalanche and snowball, plus custom when Order is set.
+	Strategies []string
+	// Order is the custom priority by account id; debts not listed follow
+	// in avalanche order.
+	Order     []string
+	MaxMonths int // 600
+	AsOf      time.Time
+}
+
+// PlanDebtPayoff simulates paying off debts month by month under each
+// strategy. Every month interest accrues at APR/12, each debt gets its
+// minimum, and the rest of the budget (minimums + extra, including the
+// minimums of debts already paid off) goes to the highest-priority debt:
+// highest APR first (avalanche), smallest balance first (snowball) or the
+// given order (custom).
+func PlanDebtPayoff(debts []Debt, opts DebtOptions) DebtPlan {
+	if opts.MaxMonths <= 0 {
+		opts.MaxMonths = 600
+	}
+	if opts.AsOf.IsZero() {
+		opts.AsOf = time.Now().UTC()
+	}
+	strategies := opts.Strategies
+	if len(strategies) == 0 {
+		strategies = []string{"avalanche", "snowball"}
+		if len(opts.Order) > 0 {
+			strategies = append(strategies, "custom")
+		}
+	}
+
+	out := DebtPlan{
+		AsOf:       opts.AsOf.Format("2006-01-02"),
+		Extra:      round2(opts.Extra),
+		Debts:      debts,
+		Strategies: []DebtStrategy{},
+		Warnings:   []string{},
+		Assumptions: []string{
+			"interest accrues monthly at apr/12 on the balance before payment",
+			"minimum payments stay fixed; minimums freed by paid-off debts roll over to the next debt",
+			"no new charges are added to the debts",
+		},
+	}
+	if out.Debts == nil {
+		out.Debts = []Debt{}
+	}
+	var budget float64
+	for _, d := range debts {
+		budget += d.MinPayment
+		out.TotalBalance += d.Balance
+		if d.Balance*d.APR/12 >= d.MinPayment && d.Balance > 0 {
+			out.Warnings = append(out.Warnings, fmt.Sprintf("%s: minimum payment %.2f does not cover the monthly interest %.2f", d.Account, d.MinPayment, d.Balance*d.APR/12))
+		}
+	}
+	out.MonthlyBudget = round2(budget + opts.Extra)
+	out.TotalBalance = round2(out.TotalBalance)
+
+	for _, s := range strategies {
+		st := simulateDebt(debts, debtOrder(debts, s, opts.Order), out.MonthlyBudget, opts)
+		st.Strategy = s
+		out.Strategies = append(out.Strategies, st)
+		if !st.PaidOff {
+			out.Warnings = append(out.Warnings, fmt.Sprintf("%s: debts are not paid off within %d months", s, opts.MaxMonths))
+		}
+	}
+	best := -1
+	for i, st := range out.Strategies {
+		if st.PaidOff && (best < 0 || st.Interest < out.Strategies[best].Interest) {
+			best = i
+		}
+	}
+	if best >= 0 {
+		out.Recommended = out.Strategies[best].Strategy
+	}
+	return out
+}
+
+// debtOrder returns the indices of debts in payoff priority.
+func debtOrder(debts []Debt, strategy string, custom []string) []int {
+	idx := make([]int, len(debts))
+	for i := range idx {
+		idx[i] = i
+	}
+	avalanche := func(a, b Debt) bool {
+		if a.APR != b.APR {
+			return a.APR > b.APR
+		}
+		return a.Balance < b.Balance
+	}
+	sort.SliceStable(idx, func(i, j int) bool {
+		a, b := debts[idx[i]], debts[idx[j]]
+		switch strategy {
+		case "snowball":
+			if a.Balance != b.Balance {
+				return a.Balance < b.Balance
+			}
+		case "custom":
+			ra, rb := rank(custom, a.AccountID), rank(custom, b.AccountID)
+			if ra != rb {
+				return ra < rb
+			}
+		}
+		return avalanche(a, b)
+	})
+	return idx
+}
+
+func rank(order []string, id string) int {
+	for i, v := range order {
+		if v == id {
+			return i
+		}
+	}
+	return len(order)
+}
+
+func simulateDebt(debts []Debt, order []int, budget float64, opts DebtOptions) DebtStrategy {
+	st := DebtStrategy{Order: []string{}, Debts: []DebtPayoff{}, Schedule: []DebtMonth{}}
+	bal := make([]float64, len(debts))
+	interest := make([]float64, len(debts))
+	done := make([]int, len(debts))
+	for i, d := range debts {
+		bal[i] = d.Balance
+	}
+	remaining := func() (total float64) {
+		for _, b := range bal {
+			total += b
+		}
+		return total
+	}
+	start := time.Date(opts.AsOf.Year(), opts.AsOf.Month(), 1, 0, 0, 0, 0, time.UTC)
+
+	for m := 1; m <= opts.MaxMonths && remaining() > 0.005; m++ {
+		month := DebtMonth{Month: start.AddDate(0, m, 0).Format("2006-01"), Payments: []DebtPayment{}}
+		left := budget
+		pay := make([]float64, len(debts))
+		accrued := make([]float64, len(debts))
+		for i, d := range debts {
+			if bal[i] <= 0 {
+				continue
+			}
+			accrued[i] = bal[i] * d.APR / 12
+			bal[i] += accrued[i]
+			pay[i] = math.Min(d.MinPayment, bal[i])
+			left -= pay[i]
+		}
+		for _, i := range order {
+			if left <= 0 {
+				break
+			}
+			extra := math.Min(left, bal[i]-pay[i])
+			pay[i] += extra
+			left -= extra
+		}
+		for i, d := range debts {
+			if bal[i] <= 0 && pay[i] == 0 {
+				continue
+			}
+			bal[i] -= pay[i]
+			if bal[i] < 0.005 {
+				bal[i] = 0
+				if done[i] == 0 {
+					done[i] = m
+				}
+			}
+			interest[i] += accrued[i]
+			month.Payments = append(month.Payments, DebtPayment{AccountID: d.AccountID, Payment: round2(pay[i]), Interest: round2(accrued[i]), Balance: round2(bal[i])})
+			month.Paid += pay[i]
+			month.Interest += accrued[i]
+		}
+		st.TotalPaid += month.Paid
+		st.Interest += month.Interest
+		month.Paid, month.Interest, month.Remaining = round2(month.Paid), round2(month.Interest), round2(remaining())
+		st.Schedule = append(st.Schedule, month)
+		st.Months = m
+	}
+
+	st.PaidOff = remaining() <= 0.005
+	if st.PaidOff && st.Months > 0 {
+		date := start.AddDate(0, st.Months, 0).Format("2006-01")
+		st.PayoffDate = &date
+	}
+	st.TotalPaid, st.Interest = round2(st.TotalPaid), round2(st.Interest)
+	for n, i := range order {
+		d := debts[i]
+		st.Order = append(st.Order, d.AccountID)
+		p := DebtPayoff{AccountID: d.AccountID, Account: d.Account, Order: n + 1, Months: done[i], Interest: round2(interest[i])}
+		if done[i] > 0 {
+			date := start.AddDate(0, done[i], 0).Format("2006-01")
+			p.PayoffDate = &date
+		}
+		st.Debts = append(st.Debts, p)
+	}
+	return st
+}
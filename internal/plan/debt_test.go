@@ -0,0 +1,59 @@
+package plan
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPlanDebtPayoff(t *testing.T) {
+	asOf := time.Date(2026, 6, 15, 0, 0, 0, 0, time.UTC)
+	debts := []Debt{
+		{AccountID: "card", Account: "Card", Balance: 3000, APR: 0.24, MinPayment: 90},
+		{AccountID: "store", Account: "Store card", Balance: 500, APR: 0.10, MinPayment: 25},
+		{AccountID: "loan", Account: "Car loan", Balance: 8000, APR: 0.06, MinPayment: 200},
+	}
+	res := PlanDebtPayoff(debts, DebtOptions{Extra: 300, Order: []string{"loan"}, AsOf: asOf})
+	if res.MonthlyBudget != 615 || res.TotalBalance != 11500 || len(res.Strategies) != 3 {
+		t.Fatalf("budget=%v total=%v strategies=%d", res.MonthlyBudget, res.TotalBalance, len(res.Strategies))
+	}
+	byName := map[string]DebtStrategy{}
+	for _, st := range res.Strategies {
+		byName[st.Strategy] = st
+		if !st.PaidOff || st.PayoffDate == nil {
+			t.Fatalf("%s not paid off", st.Strategy)
+		}
+	}
+	av, sb, cu := byName["avalanche"], byName["snowball"], byName["custom"]
+	if !reflect.DeepEqual(av.Order, []string{"card", "store", "loan"}) || !reflect.DeepEqual(sb.Order, []string{"store", "card", "loan"}) || !reflect.DeepEqual(cu.Order, []string{"loan", "card", "store"}) {
+		t.Fatalf("orders: avalanche=%v snowball=%v custom=%v", av.Order, sb.Order, cu.Order)
+	}
+	if av.Interest > sb.Interest || av.Interest > cu.Interest || res.Recommended != "avalanche" {
+		t.Fatalf("avalanche should pay least interest: %v vs %v vs %v (recommended %s)", av.Interest, sb.Interest, cu.Interest, res.Recommended)
+	}
+	// Snowball clears the small balance first.
+	if sb.Debts[0].AccountID != "store" || sb.Debts[0].Months >= av.Debts[1].Months {
+		t.Fatalf("snowball first payoff = %+v, avalanche store = %+v", sb.Debts[0], av.Debts[1])
+	}
+	// The whole budget is used each month until the last one.
+	if first := av.Schedule[0]; first.Month != "2026-07" || first.Paid != 615 || first.Interest != 104.17 {
+		t.Fatalf("first month = %+v", first)
+	}
+	last := av.Schedule[len(av.Schedule)-1]
+	if last.Remaining != 0 || last.Month != *av.PayoffDate || av.Months != len(av.Schedule) {
+		t.Fatalf("last month = %+v, payoff %v, months %d", last, *av.PayoffDate, av.Months)
+	}
+	if diff := av.TotalPaid - av.Interest - res.TotalBalance; diff > 0.05 || diff < -0.05 {
+		t.Fatalf("total paid %v - interest %v should equal the balance %v", av.TotalPaid, av.Interest, res.TotalBalance)
+	}
+
+	// A minimum below the interest never pays off.
+	res = PlanDebtPayoff([]Debt{{AccountID: "x", Account: "X", Balance: 10000, APR: 0.3, MinPayment: 100}}, DebtOptions{Strategies: []string{"avalanche"}, MaxMonths: 24, AsOf: asOf})
+	if res.Strategies[0].PaidOff || res.Recommended != "" || len(res.Warnings) != 2 || !strings.Contains(res.Warnings[0], "does not cover") {
+		t.Fatalf("unpayable debt: paid_off=%v recommended=%q warnings=%v", res.Strategies[0].PaidOff, res.Recommended, res.Warnings)
+	}
+	if len(res.Strategies[0].Schedule) != 24 || res.Strategies[0].Debts[0].PayoffDate != nil {
+		t.Fatalf("schedule should stop at the horizon: %d months", len(res.Strategies[0].Schedule))
+	}
+}
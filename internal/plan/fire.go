@@ -0,0 +1,321 @@
+package plan
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/we-promise/sure-cli/internal/insights"
+	"github.com/we-promise/sure-cli/internal/models"
+)
+
+// InvestableAccount is one account counted towards the FI portfolio.
+type InvestableAccount struct {
+	AccountID   string  `json:"account_id"`
+	Account     string  `json:"account"`
+	AccountType string  `json:"account_type"`
+	Balance     float64 `json:"balance"`
+	// HoldingsValue sums the account's latest holdings; nil when Sure
+	// reports none (cash accounts).
+	HoldingsValue *float64 `json:"holdings_value"`
+	Holdings      int      `json:"holdings"`
+}
+
+// FireYear is one row of the FI projection, in today's money.
+type FireYear struct {
+	Year         int     `json:"year"`
+	Age          *int    `json:"age"`
+	StartBalance float64 `json:"start_balance"`
+	Contribution float64 `json:"contribution"`
+	Growth       float64 `json:"growth"`
+	EndBalance   float64 `json:"end_balance"`
+	Progress     float64 `json:"progress"` // end_balance / fi_number
+	Reached      bool    `json:"reached"`
+}
+
+type FireResult struct {
+	AsOf               string              `json:"as_of"`
+	Currency           string              `json:"currency"`
+	AnnualSpend        float64             `json:"annual_spend"`
+	SpendSource        string              `json:"spend_source"` // history|override
+	AnnualIncome       float64             `json:"annual_income"`
+	WithdrawalRate     float64             `json:"withdrawal_rate"`
+	RealReturn         float64             `json:"real_return"`
+	AnnualContribution float64             `json:"annual_contribution"`
+	ContributionSource string              `json:"contribution_source"` // history|override
+	NetWorth           *float64            `json:"net_worth"`           // from Sure's balance sheet
+	InvestableAssets   float64             `json:"investable_assets"`
+	Accounts           []InvestableAccount `json:"accounts"`
+	FINumber           float64             `json:"fi_number"`
+	Progress           float64             `json:"progress"`
+	// YearsToFI is nil when FI is not reached within 100 years.
+	YearsToFI   *float64   `json:"years_to_fi"`
+	FIYear      *int       `json:"fi_year"`
+	FIAge       *int       `json:"fi_age"`
+	Projection  []FireYear `json:"projection"`
+	Assumptions []string   `json:"assumptions"`
+	// Transfers left out of the annual spend and income.
+	ExcludedTransfers *insights.TransferExclusion `json:"excluded_transfers,omitempty"`
+	// Accounts left out of the investable assets because they are not in
+	// Currency.
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// FireOptions tunes ComputeFire. Zero values use the defaults noted.
+type FireOptions struct {
+	// AnnualSpend overrides the spend derived from history.
+	AnnualSpend float64
+	// WithdrawalRate is the safe withdrawal rate as a fraction (0.04).
+	WithdrawalRate float64
+	// RealReturn is the expected inflation-adjusted yearly return as a
+	// fraction; zero projects contributions only.
+	RealReturn float64
+	// Contribution overrides the yearly amount invested; nil uses
+	// history income minus spend (never below zero).
+	Contribution *float64
+	// Years is the projection length (30); it is extended to the FI year.
+	Years int
+	// Age, when set, adds ages to the projection.
+	Age int
+	// IncludeCash counts depository balances as investable.
+	IncludeCash bool
+	AsOf        time.Time
+	// HistoryStart is where txs begin, used to annualize spend and income;
+	// zero uses the earliest transaction.
+	HistoryStart time.Time
+	// Scenario, when set, changes spend and income by its lasting
+	// adjustments and each year's contribution by its dated changes.
+	Scenario *ScenarioEffects
+}
+
+const fireMaxYears = 100
+
+func (o FireOptions) withDefaults() FireOptions {
+	if o.WithdrawalRate <= 0 {
+		o.WithdrawalRate = 0.04
+	}
+	if o.Years <= 0 {
+		o.Years = 30
+	}
+	if o.AsOf.IsZero() {
+		o.AsOf = time.Now().UTC()
+	}
+	return o
+}
+
+// AnnualizedFlows sums expenses and income in txs and scales them to a
+// 365-day year over the history window [start, asOf].
+func AnnualizedFlows(txs []models.Transaction, start, asOf time.Time) (spend, income float64) {
+	if start.IsZero() {
+		for _, tx := range txs {
+			if start.IsZero() || tx.Date.Before(start) {
+				start = tx.Date
+			}
+		}
+	}
+	for _, tx := range txs {
+		if tx.Date.After(asOf) {
+			continue
+		}
+		v, err := insights.SignedAmount(tx)
+		if err != nil {
+			continue
+		}
+		switch tx.Classification {
+		case "expense":
+			spend += math.Abs(v)
+		case "income":
+			income += math.Abs(v)
+		}
+	}
+	days := asOf.Sub(start).Hours()/24 + 1
+	if start.IsZero() || days < 1 {
+		days = 1
+	}
+	return spend * 365 / days, income * 365 / days
+}
+
+// ComputeFire estimates the financial-independence number (annual spend /
+// withdrawal rate), the years needed to reach it from the investable
+// portfolio plus yearly contributions at a real return, and a year-by-year
+// projection. Amounts are in today's money.
+func ComputeFire(accounts []models.Account, holdings []models.Holding, sheet *models.BalanceSheet, txs []models.Transaction, opts FireOptions) FireResult {
+	opts = opts.withDefaults()
+	asOf := opts.AsOf
+	spend, income := AnnualizedFlows(txs, opts.HistoryStart, asOf)
+
+	out := FireResult{
+		AsOf:               asOf.Format("2006-01-02"),
+		AnnualSpend:        round2(spend),
+		SpendSource:        "history",
+		AnnualIncome:       round2(income),
+		WithdrawalRate:     opts.WithdrawalRate,
+		RealReturn:         opts.RealReturn,
+		AnnualContribution: round2(math.Max(0, income-spend)),
+		ContributionSource: "history",
+		Accounts:           []InvestableAccount{},
+		Projection:         []FireYear{},
+		Assumptions: []string{
+			"investable assets = investment and crypto account balances",
+			"annual spend and income are annualized from transaction history unless overridden",
+			"returns are real (after inflation); all amounts are in today's money",
+			"contributions are invested at the end of each year",
+		},
+	}
+	if opts.IncludeCash {
+		out.Assumptions[0] += " plus depository cash"
+	}
+	if opts.AnnualSpend > 0 {
+		// The default contribution is what the overridden spend leaves
+		// of the income.
+		spend = opts.AnnualSpend
+		out.AnnualSpend, out.SpendSource = round2(spend), "override"
+		out.AnnualContribution = round2(math.Max(0, income-spend))
+	}
+	surplus := income - spend
+	if opts.Contribution != nil {
+		surplus = *opts.Contribution
+		out.AnnualContribution, out.ContributionSource = round2(*opts.Contribution), "override"
+	}
+	if opts.Scenario != nil {
+		spendDelta, incomeDelta := opts.Scenario.annual()
+		out.AnnualSpend = round2(math.Max(0, out.AnnualSpend+spendDelta))
+		out.AnnualIncome = round2(out.AnnualIncome + incomeDelta)
+		out.AnnualContribution = round2(math.Max(0, surplus+incomeDelta-spendDelta))
+		out.Assumptions = append(out.Assumptions, "scenario: lasting changes adjust annual spend and income; each year's contribution includes the changes dated in it")
+	}
+	if sheet != nil {
+		out.Currency = sheet.Currency
+		if v, err := insights.ParseAmount(sheet.NetWorthText); err == nil {
+			v = round2(v)
+			out.NetWorth = &v
+		}
+	}
+
+	out.Accounts, out.InvestableAssets, out.Currency, out.Warnings = InvestablePortfolio(accounts, holdings, opts.IncludeCash, out.Currency)
+
+	out.FINumber = round2(out.AnnualSpend / opts.WithdrawalRate)
+	if out.FINumber > 0 {
+		out.Progress = round2(out.InvestableAssets / out.FINumber)
+	}
+
+	balance := out.InvestableAssets
+	if out.FINumber <= 0 || balance >= out.FINumber {
+		zero := 0.0
+		out.YearsToFI = &zero
+	}
+	keep := opts.Years
+	for y := 1; y <= fireMaxYears && (y <= keep || out.YearsToFI == nil); y++ {
+		row := FireYear{Year: asOf.Year() + y, StartBalance: round2(balance), Contribution: out.AnnualContribution}
+		if opts.Scenario != nil {
+			var change float64
+			for _, f := range opts.Scenario.flows(asOf.AddDate(y-1, 0, 0), asOf.AddDate(y, 0, 0)) {
+				change += f.amount
+			}
+			row.Contribution = round2(math.Max(0, surplus+change))
+		}
+		growth := balance * opts.RealReturn
+		end := balance + growth + row.Contribution
+		row.Growth, row.EndBalance = round2(growth), round2(end)
+		if opts.Age > 0 {
+			age := opts.Age + y
+			row.Age = &age
+		}
+		if out.FINumber > 0 {
+			row.Progress = round2(end / out.FINumber)
+		}
+		row.Reached = end >= out.FINumber
+		if row.Reached && out.YearsToFI == nil {
+			// Interpolate within the year the target is crossed.
+			years := round2(float64(y-1) + (out.FINumber-balance)/(end-balance))
+			out.YearsToFI = &years
+			keep = max(keep, y)
+		}
+		out.Projection = append(out.Projection, row)
+		balance = end
+	}
+	// Never reached: only the requested years are shown.
+	if len(out.Projection) > opts.Years && out.YearsToFI == nil {
+		out.Projection = out.Projection[:opts.Years]
+	}
+	if out.YearsToFI != nil {
+		year := asOf.Year() + int(math.Ceil(*out.YearsToFI))
+		out.FIYear = &year
+		if opts.Age > 0 {
+			age := opts.Age + int(math.Ceil(*out.YearsToFI))
+			out.FIAge = &age
+		}
+	}
+	return out
+}
+
+// InvestablePortfolio picks the investment and crypto accounts (plus
+// depository ones with includeCash), sums each account's latest holdings,
+// and returns the accounts, their total balance and their currency.
+// Balances are not converted: only accounts in currency (default: the first
+// counted account's) are included, and the others are reported as warnings.
+func InvestablePortfolio(accounts []models.Account, holdings []models.Holding, includeCash bool, currency string) ([]InvestableAccount, float64, string, []string) {
+	out := []InvestableAccount{}
+	var total float64
+	var warnings []string
+	currency = strings.ToUpper(currency)
+	latest := map[string]string{}
+	for _, h := range holdings {
+		if h.Date > latest[h.AccountID] {
+			latest[h.AccountID] = h.Date
+		}
+	}
+	for _, acc := range accounts {
+		if acc.Classification == "liability" || !investable(acc.AccountType, includeCash) {
+			continue
+		}
+		if currency == "" {
+			currency = strings.ToUpper(acc.Currency)
+		}
+		if acc.Currency != "" && !strings.EqualFold(acc.Currency, currency) {
+			name := acc.Name
+			if name == "" {
+				name = acc.ID
+			}
+			warnings = append(warnings, fmt.Sprintf("%s: balance in %s left out (portfolio is in %s)", name, acc.Currency, currency))
+			continue
+		}
+		ia := InvestableAccount{AccountID: acc.ID, Account: acc.Name, AccountType: acc.AccountType}
+		for _, h := range holdings {
+			if h.AccountID != acc.ID || h.Date != latest[acc.ID] {
+				continue
+			}
+			if v, err := insights.ParseAmount(h.ValueText); err == nil {
+				if ia.HoldingsValue == nil {
+					ia.HoldingsValue = new(float64)
+				}
+				*ia.HoldingsValue += v
+				ia.Holdings++
+			}
+		}
+		bal, err := insights.ParseAmount(acc.BalanceText)
+		if err != nil && ia.HoldingsValue != nil {
+			// No usable balance: fall back to holdings plus reported cash.
+			cash, _ := insights.ParseAmount(acc.CashBalanceText)
+			bal = *ia.HoldingsValue + cash
+		}
+		ia.Balance = round2(bal)
+		if ia.HoldingsValue != nil {
+			*ia.HoldingsValue = round2(*ia.HoldingsValue)
+		}
+		total += bal
+		out = append(out, ia)
+	}
+	return out, round2(total), currency, warnings
+}
+
+func investable(accountType string, includeCash bool) bool {
+	switch accountType {
+	case "investment", "crypto":
+		return true
+	case "depository":
+		return includeCash
+	}
+	return false
+}
@@ -0,0 +1,97 @@
+package plan
+
+import (
+	"testing"
+	"time"
+
+	"github.com/we-promise/sure-cli/internal/models"
+)
+
+func TestComputeFire(t *testing.T) {
+	asOf := time.Date(2026, 6, 30, 0, 0, 0, 0, time.UTC)
+	start := asOf.AddDate(0, 0, -364) // 365-day window
+	txs := []models.Transaction{
+		{Classification: "expense", AmountText: "€30,000.00", Currency: "EUR", Date: asOf.AddDate(0, -1, 0)},
+		{Classification: "income", AmountText: "€50,000.00", Currency: "EUR", Date: asOf.AddDate(0, -2, 0)},
+	}
+	accounts := []models.Account{
+		{ID: "brk", Name: "Brokerage", AccountType: "investment", Classification: "asset", Currency: "EUR", BalanceText: "€100,000.00"},
+		{ID: "chk", Name: "Checking", AccountType: "depository", Classification: "asset", Currency: "EUR", BalanceText: "€50,000.00"},
+		{ID: "cc", Name: "Card", AccountType: "credit_card", Classification: "liability", Currency: "EUR", BalanceText: "€1,000.00"},
+	}
+	holdings := []models.Holding{
+		{AccountID: "brk", Date: "2026-05-31", ValueText: "€1.00"},
+		{AccountID: "brk", Date: "2026-06-30", ValueText: "€60,000.00"},
+		{AccountID: "brk", Date: "2026-06-30", ValueText: "€35,000.00"},
+	}
+	sheet := &models.BalanceSheet{Currency: "EUR", NetWorthText: "€149,000.00"}
+
+	res := ComputeFire(accounts, holdings, sheet, txs, FireOptions{WithdrawalRate: 0.04, AsOf: asOf, HistoryStart: start, Years: 2, Age: 40})
+	if res.AnnualSpend != 30000 || res.AnnualContribution != 20000 || res.SpendSource != "history" {
+		t.Fatalf("flows: spend=%v contribution=%v source=%s", res.AnnualSpend, res.AnnualContribution, res.SpendSource)
+	}
+	if res.FINumber != 750000 || res.InvestableAssets != 100000 || len(res.Accounts) != 1 {
+		t.Fatalf("fi=%v investable=%v accounts=%+v", res.FINumber, res.InvestableAssets, res.Accounts)
+	}
+	if h := res.Accounts[0].HoldingsValue; h == nil || *h != 95000 || res.Accounts[0].Holdings != 2 {
+		t.Fatalf("latest holdings not summed: %+v", res.Accounts[0])
+	}
+	if res.NetWorth == nil || *res.NetWorth != 149000 {
+		t.Fatalf("net worth = %v", res.NetWorth)
+	}
+	// Zero return: 100k + 20k/yr reaches 750k after 32.5 years.
+	if res.YearsToFI == nil || *res.YearsToFI != 32.5 || *res.FIYear != 2059 || *res.FIAge != 73 {
+		t.Fatalf("years=%v year=%v age=%v", res.YearsToFI, res.FIYear, res.FIAge)
+	}
+	if len(res.Projection) != 33 || !res.Projection[32].Reached || res.Projection[31].Reached {
+		t.Fatalf("projection should run through the FI year: %d rows", len(res.Projection))
+	}
+
+	// Overriding the spend moves the default contribution with it.
+	res = ComputeFire(accounts, holdings, sheet, txs, FireOptions{AnnualSpend: 40000, AsOf: asOf, HistoryStart: start})
+	if res.AnnualContribution != 10000 || res.ContributionSource != "history" {
+		t.Fatalf("spend override: contribution=%v source=%s", res.AnnualContribution, res.ContributionSource)
+	}
+
+	spend := 4000.0
+	zero := 0.0
+	res = ComputeFire(accounts, holdings, nil, txs, FireOptions{AnnualSpend: spend, Contribution: &zero, IncludeCash: true, RealReturn: 0.05, AsOf: asOf, HistoryStart: start})
+	if res.FINumber != 100000 || res.InvestableAssets != 150000 || *res.YearsToFI != 0 || res.SpendSource != "override" {
+		t.Fatalf("already FI: fi=%v investable=%v years=%v", res.FINumber, res.InvestableAssets, res.YearsToFI)
+	}
+	if len(res.Projection) != 30 || res.Projection[0].Growth != 7500 {
+		t.Fatalf("default projection: %d rows, first %+v", len(res.Projection), res.Projection[0])
+	}
+
+	res = ComputeFire(accounts, nil, nil, nil, FireOptions{AnnualSpend: 1e9, Contribution: &zero, AsOf: asOf, Years: 5})
+	if res.YearsToFI != nil || res.FIYear != nil || len(res.Projection) != 5 {
+		t.Fatalf("unreachable FI: years=%v rows=%d", res.YearsToFI, len(res.Projection))
+	}
+}
+
+func TestInvestablePortfolioKeepsOneCurrency(t *testing.T) {
+	accounts := []models.Account{
+		{ID: "eu", Name: "EU Brokerage", AccountType: "investment", Classification: "asset", Currency: "EUR", BalanceText: "€100,000.00"},
+		{ID: "us", Name: "US Brokerage", AccountType: "investment", Classification: "asset", Currency: "USD", BalanceText: "$40,000.00"},
+		{ID: "btc", Name: "Wallet", AccountType: "crypto", Classification: "asset", Currency: "eur", BalanceText: "€5,000.00"},
+	}
+
+	out, total, currency, warnings := InvestablePortfolio(accounts, nil, false, "")
+	if currency != "EUR" || total != 105000 || len(out) != 2 {
+		t.Fatalf("first currency: %s total=%v accounts=%+v", currency, total, out)
+	}
+	if len(warnings) != 1 || warnings[0] != "US Brokerage: balance in USD left out (portfolio is in EUR)" {
+		t.Fatalf("warnings = %q", warnings)
+	}
+
+	_, total, currency, warnings = InvestablePortfolio(accounts, nil, false, "usd")
+	if currency != "USD" || total != 40000 || len(warnings) != 2 {
+		t.Fatalf("usd: %s total=%v warnings=%q", currency, total, warnings)
+	}
+
+	// The balance sheet's currency decides which accounts ComputeFire counts.
+	res := ComputeFire(accounts, nil, &models.BalanceSheet{Currency: "USD"}, nil, FireOptions{AnnualSpend: 40000})
+	if res.Currency != "USD" || res.InvestableAssets != 40000 || len(res.Warnings) != 2 {
+		t.Fatalf("fire: %s investable=%v warnings=%q", res.Currency, res.InvestableAssets, res.Warnings)
+	}
+}
@@ -1,6 +1,7 @@
 package plan
 
 import (
+	"math"
 	"time"
 
 	"github.com/we-promise/sure-cli/internal/insights"
@@ -12,6 +13,8 @@ type ForecastSummary struct {
 	RecurringExpenses float64  `json:"recurring_expenses"`
 	AverageDailySpend float64  `json:"avg_daily_spend"`
 	ProjectedSpend    float64  `json:"projected_spend"`
+	SureRecurring     int      `json:"sure_recurring"`     // recurring items tracked in Sure
+	DetectedRecurring int      `json:"detected_recurring"` // detected only
 	Currency          string   `json:"currency"`
 	Assumptions       []string `json:"assumptions"`
 }
@@ -24,25 +27,51 @@ type DailyForecast struct {
 }
 
 type ForecastResult struct {
-	Summary ForecastSummary `json:"summary"`
-	Daily   []DailyForecast `json:"daily,omitempty"`
+	Summary           ForecastSummary             `json:"summary"`
+	Recurring         []RecurringItem             `json:"recurring"`
+	Daily             []DailyForecast             `json:"daily,omitempty"`
+	ExcludedTransfers *insights.TransferExclusion `json:"excluded_transfers,omitempty"`
+}
+
+// ForecastOptions tunes ComputeForecastWithOptions.
+type ForecastOptions struct {
+	// Subscriptions drives recurring-expense detection.
+	Subscriptions insights.SubscriptionOptions
+	// Recurring are Sure's recurring transactions, merged with detected
+	// subscriptions as ground truth (see MergeRecurring).
+	Recurring []models.RecurringTransaction
+	// Scenario, when set, adds its spend changes to the forecast.
+	Scenario *ScenarioEffects
 }
 
 // ComputeForecast projects spending for the next N days based on:
 // - detected recurring expenses (subscriptions)
 // - average daily non-recurring spend
 func ComputeForecast(txs []models.Transaction, days int, includeDaily bool) ForecastResult {
+	return ComputeForecastWithOptions(txs, days, includeDaily, ForecastOptions{Subscriptions: insights.DefaultSubscriptionOptions()})
+}
+
+// ComputeForecastWithOptions is ComputeForecast with explicit detector
+// options and Sure recurring transactions.
+func ComputeForecastWithOptions(txs []models.Transaction, days int, includeDaily bool, opts ForecastOptions) ForecastResult {
 	if days <= 0 {
 		days = 30
 	}
 
-	// Detect subscriptions for recurring
-	subs := insights.DetectSubscriptions(txs)
+	// Merge Sure recurring transactions with detected subscriptions; ones
+	// that stopped charging (or that Sure marks inactive) are not projected.
+	merged := MergeRecurring(opts.Recurring, insights.DetectSubscriptionsWithOptions(txs, opts.Subscriptions), opts.Subscriptions.Normalizer)
+	var items []RecurringItem
+	for _, it := range merged {
+		if it.Classification == "expense" {
+			items = append(items, it)
+		}
+	}
 
-	// Calculate average daily spend (non-subscription expenses)
-	subNames := make(map[string]bool)
-	for _, s := range subs {
-		subNames[s.Name] = true
+	// Calculate average daily spend (non-recurring expenses)
+	recurringKeys := make(map[string]bool)
+	for _, it := range merged {
+		recurringKeys[it.Merchant] = true
 	}
 
 	var nonRecurringTotal float64
@@ -53,7 +82,7 @@ func ComputeForecast(txs []models.Transaction, days int, includeDaily bool) Fore
 		if tx.Classification != "expense" {
 			continue
 		}
-		if subNames[tx.Name] {
+		if recurringKeys[opts.Subscriptions.Normalizer.Key(tx)] {
 			continue // skip recurring
 		}
 		amt, err := insights.ParseAmountEUR(tx.AmountText)
@@ -72,34 +101,51 @@ func ComputeForecast(txs []models.Transaction, days int, includeDaily bool) Fore
 
 	// Calculate recurring expenses for forecast period
 	var recurringTotal float64
-	recurringByDay := make(map[string][]string)
+	recurringByDay := make(map[string][]RecurringItem)
+	scenarioNames := make(map[string][]string)
 
 	now := time.Now().UTC()
-	for _, sub := range subs {
-		// Estimate how many times this subscription will hit in the forecast period
-		periodDays := sub.AvgPeriodDays
-		if periodDays <= 0 {
-			periodDays = 30
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	var sureCount, detectedCount int
+	for _, it := range items {
+		if it.RecurringID != "" {
+			sureCount++
+		} else {
+			detectedCount++
 		}
+		// Estimate how many times this charge will hit in the forecast period
+		recurringTotal += it.Amount * float64(days) / it.PeriodDays
 
-		occurrences := float64(days) / periodDays
-		recurringTotal += sub.AvgAmount * occurrences
-
-		// For daily forecast, estimate when it will hit
+		// For daily forecast, place each expected charge on its date
 		if includeDaily {
-			nextHit := sub.LastDate
-			for nextHit.Before(now) {
-				nextHit = nextHit.AddDate(0, 0, int(periodDays))
-			}
-			for nextHit.Before(now.AddDate(0, 0, days)) {
-				dateStr := nextHit.Format("2006-01-02")
-				recurringByDay[dateStr] = append(recurringByDay[dateStr], sub.Name)
-				nextHit = nextHit.AddDate(0, 0, int(periodDays))
+			for _, d := range it.Occurrences(today.AddDate(0, 0, -1), today.AddDate(0, 0, days-1)) {
+				dateStr := d.Format("2006-01-02")
+				recurringByDay[dateStr] = append(recurringByDay[dateStr], it)
 			}
 		}
 	}
 
-	projectedSpend := recurringTotal + (avgDailyNonRecurring * float64(days))
+	// Scenario spend changes land on their dates; income changes do not
+	// affect a spend forecast.
+	scenarioByDay := map[string]float64{}
+	var scenarioOther float64 // category changes and one-offs
+	for _, f := range opts.Scenario.flows(today.AddDate(0, 0, -1), today.AddDate(0, 0, days-1)) {
+		if f.kind != "spend" {
+			continue
+		}
+		dateStr := f.date.Format("2006-01-02")
+		scenarioByDay[dateStr] -= f.amount
+		if f.recurring {
+			recurringTotal -= f.amount
+		} else {
+			scenarioOther -= f.amount
+		}
+		if !f.daily {
+			scenarioNames[dateStr] = append(scenarioNames[dateStr], f.name)
+		}
+	}
+
+	projectedSpend := recurringTotal + (avgDailyNonRecurring * float64(days)) + scenarioOther
 
 	result := ForecastResult{
 		Summary: ForecastSummary{
@@ -107,41 +153,43 @@ func ComputeForecast(txs []models.Transaction, days int, includeDaily bool) Fore
 			RecurringExpenses: round2(recurringTotal),
 			AverageDailySpend: round2(avgDailyNonRecurring),
 			ProjectedSpend:    round2(projectedSpend),
+			SureRecurring:     sureCount,
+			DetectedRecurring: detectedCount,
 			Currency:          "EUR",
 			Assumptions: []string{
-				"recurring detected via subscription heuristics",
+				"recurring = Sure recurring transactions (expected average amount) plus subscriptions detected at the latest price that Sure does not track",
 				"non-recurring extrapolated from historical average",
 			},
 		},
+		Recurring: items,
+	}
+	if result.Recurring == nil {
+		result.Recurring = []RecurringItem{}
+	}
+	if opts.Scenario != nil {
+		result.Summary.Assumptions = append(result.Summary.Assumptions, "scenario spend changes applied on their dates")
 	}
 
 	if includeDaily {
 		var daily []DailyForecast
 		var cumulative float64
 		for i := 0; i < days; i++ {
-			date := now.AddDate(0, 0, i)
-			dateStr := date.Format("2006-01-02")
-
-			daySpend := avgDailyNonRecurring
-			var items []string
-			if recItems, ok := recurringByDay[dateStr]; ok {
-				items = recItems
-				for _, name := range recItems {
-					for _, sub := range subs {
-						if sub.Name == name {
-							daySpend += sub.AvgAmount
-							break
-						}
-					}
-				}
+			dateStr := today.AddDate(0, 0, i).Format("2006-01-02")
+
+			daySpend := avgDailyNonRecurring + scenarioByDay[dateStr]
+			var names []string
+			for _, it := range recurringByDay[dateStr] {
+				names = append(names, it.Name)
+				daySpend += it.Amount
 			}
+			names = append(names, scenarioNames[dateStr]...)
 
 			cumulative += daySpend
 			daily = append(daily, DailyForecast{
 				Date:            dateStr,
 				ExpectedSpend:   round2(daySpend),
 				CumulativeSpend: round2(cumulative),
-				RecurringItems:  items,
+				RecurringItems:  names,
 			})
 		}
 		result.Daily = daily
@@ -151,7 +199,7 @@ func ComputeForecast(txs []models.Transaction, days int, includeDaily bool) Fore
 }
 
 func round2(v float64) float64 {
-	return float64(int(v*100+0.5)) / 100
+	return math.Round(v*100) / 100
 }
 
 func abs(v float64) float64 {
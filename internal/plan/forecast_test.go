@@ -59,3 +59,25 @@ func TestComputeForecastWithDaily(t *testing.T) {
 		}
 	}
 }
+
+func TestComputeForecast_LatestPriceAndSkipsMissed(t *testing.T) {
+	now := time.Now().UTC()
+	var txs []models.Transaction
+	for i := 5; i >= 0; i-- {
+		amt := "€20.00"
+		if i < 2 {
+			amt = "€25.00"
+		}
+		txs = append(txs, models.Transaction{ID: "s" + string(rune('0'+i)), Name: "Streaming", Classification: "expense", AmountText: amt, Date: now.AddDate(0, -i, -1)})
+	}
+	// Stopped five months ago.
+	for i := 8; i >= 5; i-- {
+		txs = append(txs, models.Transaction{ID: "g" + string(rune('0'+i)), Name: "Gym", Classification: "expense", AmountText: "€40.00", Date: now.AddDate(0, -i, -2)})
+	}
+
+	result := ComputeForecast(txs, 30, false)
+	// Only Streaming is projected, at €25 for roughly one cycle.
+	if result.Summary.RecurringExpenses < 24 || result.Summary.RecurringExpenses > 27 {
+		t.Fatalf("expected ~25 recurring, got %v", result.Summary.RecurringExpenses)
+	}
+}
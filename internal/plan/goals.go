@@ -0,0 +1,199 @@
+package plan
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/we-promise/sure-cli/internal/insights"
+	"github.com/we-promise/sure-cli/internal/models"
+)
+
+// Goal is a savings goal to evaluate. Either Target or TargetMonths (months
+// of average spend) is set; TargetDate (YYYY-MM-DD) is optional.
+type Goal struct {
+	ID           string
+	Name         string
+	Target       float64
+	TargetMonths float64
+	TargetDate   string
+	AccountIDs   []string
+}
+
+type GoalStatus struct {
+	ID           string   `json:"id"`
+	Name         string   `json:"name"`
+	AccountIDs   []string `json:"account_ids"`
+	Target       float64  `json:"target"`
+	TargetSource string   `json:"target_source"` // amount|months_of_spend
+	TargetDate   *string  `json:"target_date"`
+	Current      float64  `json:"current"`
+	Remaining    float64  `json:"remaining"`
+	Progress     float64  `json:"progress"` // current / target, capped at 1
+	// MonthlyVelocity is the average monthly change of the linked balances
+	// over the velocity window; nil without balance history.
+	MonthlyVelocity *float64 `json:"monthly_velocity"`
+	// RequiredMonthly is what must be saved per month to hit TargetDate.
+	RequiredMonthly *float64 `json:"required_monthly"`
+	ProjectedDate   *string  `json:"projected_date"`
+	Status          string   `json:"status"` // complete|on_track|at_risk
+	AtRisk          bool     `json:"at_risk"`
+	Reasons         []string `json:"reasons"`
+}
+
+type GoalsReport struct {
+	AsOf           string       `json:"as_of"`
+	Currency       string       `json:"currency"`
+	VelocityMonths int          `json:"velocity_months"`
+	MonthlySpend   float64      `json:"monthly_spend"` // used by months-of-spend targets
+	Goals          []GoalStatus `json:"goals"`
+	Assumptions    []string     `json:"assumptions"`
+	// Transfers left out of MonthlySpend.
+	ExcludedTransfers *insights.TransferExclusion `json:"excluded_transfers,omitempty"`
+}
+
+// GoalsOptions tunes EvaluateGoals.
+type GoalsOptions struct {
+	AsOf time.Time
+	// VelocityMonths is the window the contribution velocity is measured
+	// over (3).
+	VelocityMonths int
+	// MonthlySpend is the average monthly spend for months-of-spend goals.
+	MonthlySpend float64
+}
+
+const daysPerMonth = 365.25 / 12
+
+// EvaluateGoals measures each goal against the current balances of its
+// linked accounts: progress, the monthly saving needed to meet the target
+// date, the completion date projected from the recent balance velocity
+// (from history), and at-risk flags.
+func EvaluateGoals(goals []Goal, accounts []models.Account, history []models.Balance, opts GoalsOptions) GoalsReport {
+	if opts.VelocityMonths <= 0 {
+		opts.VelocityMonths = 3
+	}
+	if opts.AsOf.IsZero() {
+		opts.AsOf = time.Now().UTC()
+	}
+	asOf := time.Date(opts.AsOf.Year(), opts.AsOf.Month(), opts.AsOf.Day(), 0, 0, 0, 0, time.UTC)
+	since := asOf.AddDate(0, -opts.VelocityMonths, 0).Format("2006-01-02")
+
+	out := GoalsReport{
+		AsOf:           asOf.Format("2006-01-02"),
+		VelocityMonths: opts.VelocityMonths,
+		MonthlySpend:   round2(opts.MonthlySpend),
+		Goals:          []GoalStatus{},
+		Assumptions: []string{
+			"current = sum of the linked accounts' current balances",
+			"velocity = change of each linked balance since about velocity_months ago (nearest history record), per month",
+			"projected_date extends the velocity linearly; months are 30.44 days",
+		},
+	}
+
+	balances := map[string]float64{}
+	for _, a := range accounts {
+		if v, err := insights.ParseAmount(a.BalanceText); err == nil {
+			balances[a.ID] = v
+		}
+		if out.Currency == "" {
+			out.Currency = a.Currency
+		}
+	}
+	// Each account's velocity is measured from the latest record on or
+	// before the window start (else the earliest one after it) to now.
+	pastDate := map[string]string{}
+	past := map[string]float64{}
+	for _, b := range history {
+		prev, seen := pastDate[b.AccountID]
+		better := !seen ||
+			(b.Date <= since && (prev > since || b.Date > prev)) ||
+			(b.Date > since && prev > since && b.Date < prev)
+		if !better || b.Date >= out.AsOf {
+			continue
+		}
+		if v, err := insights.ParseAmount(b.BalanceText); err == nil {
+			pastDate[b.AccountID], past[b.AccountID] = b.Date, v
+		}
+	}
+	velocity := func(id string) (float64, bool) {
+		d, err := time.Parse("2006-01-02", pastDate[id])
+		if err != nil {
+			return 0, false
+		}
+		months := asOf.Sub(d).Hours() / 24 / daysPerMonth
+		return (balances[id] - past[id]) / months, true
+	}
+
+	for _, g := range goals {
+		st := GoalStatus{ID: g.ID, Name: g.Name, AccountIDs: g.AccountIDs, Target: round2(g.Target), TargetSource: "amount", Reasons: []string{}}
+		if st.AccountIDs == nil {
+			st.AccountIDs = []string{}
+		}
+		if g.TargetMonths > 0 {
+			st.Target, st.TargetSource = round2(g.TargetMonths*opts.MonthlySpend), "months_of_spend"
+		}
+		var current, monthly float64
+		hasHistory := len(g.AccountIDs) > 0
+		for _, id := range g.AccountIDs {
+			current += balances[id]
+			v, ok := velocity(id)
+			hasHistory = hasHistory && ok
+			monthly += v
+		}
+		st.Current = round2(current)
+		st.Remaining = round2(math.Max(0, st.Target-current))
+		if st.Target > 0 {
+			st.Progress = round4(math.Min(1, current/st.Target))
+		}
+		if hasHistory {
+			v := round2(monthly)
+			st.MonthlyVelocity = &v
+		}
+
+		var deadline time.Time
+		if g.TargetDate != "" {
+			date := g.TargetDate
+			st.TargetDate = &date
+			deadline, _ = time.Parse("2006-01-02", g.TargetDate)
+		}
+		if st.Remaining == 0 {
+			st.Status = "complete"
+			zero := 0.0
+			st.RequiredMonthly = &zero
+			out.Goals = append(out.Goals, st)
+			continue
+		}
+		if !deadline.IsZero() {
+			months := deadline.Sub(asOf).Hours() / 24 / daysPerMonth
+			if months <= 0 {
+				st.Reasons = append(st.Reasons, "target date has passed")
+			} else {
+				req := round2(st.Remaining / math.Max(1, months))
+				st.RequiredMonthly = &req
+			}
+		}
+		switch {
+		case st.MonthlyVelocity == nil:
+			st.Reasons = append(st.Reasons, "no balance history to measure progress")
+		case *st.MonthlyVelocity <= 0:
+			st.Reasons = append(st.Reasons, fmt.Sprintf("linked balances have not grown in the last %d months", opts.VelocityMonths))
+		default:
+			days := int(math.Ceil(st.Remaining / *st.MonthlyVelocity * daysPerMonth))
+			projected := asOf.AddDate(0, 0, days)
+			date := projected.Format("2006-01-02")
+			st.ProjectedDate = &date
+			if !deadline.IsZero() && projected.After(deadline) {
+				st.Reasons = append(st.Reasons, fmt.Sprintf("projected %s, after the %s target date", date, g.TargetDate))
+			}
+		}
+		st.AtRisk = len(st.Reasons) > 0
+		st.Status = "on_track"
+		if st.AtRisk {
+			st.Status = "at_risk"
+		}
+		out.Goals = append(out.Goals, st)
+	}
+	sort.SliceStable(out.Goals, func(i, j int) bool { return out.Goals[i].AtRisk && !out.Goals[j].AtRisk })
+	return out
+}
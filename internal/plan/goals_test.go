@@ -0,0 +1,68 @@
+package plan
+
+import (
+	"testing"
+	"time"
+
+	"github.com/we-promise/sure-cli/internal/models"
+)
+
+func TestEvaluateGoals(t *testing.T) {
+	asOf := time.Date(2026, 6, 30, 0, 0, 0, 0, time.UTC)
+	accounts := []models.Account{
+		{ID: "sav", Currency: "EUR", BalanceText: "€6,000.00"},
+		{ID: "chk", Currency: "EUR", BalanceText: "€2,000.00"},
+		{ID: "new", Currency: "EUR", BalanceText: "€100.00"},
+	}
+	history := []models.Balance{
+		{AccountID: "sav", Date: "2026-02-28", BalanceText: "€1.00"},
+		{AccountID: "sav", Date: "2026-03-30", BalanceText: "€3,100.00"}, // latest on or before the window start
+		{AccountID: "sav", Date: "2026-04-30", BalanceText: "€4,000.00"},
+		{AccountID: "sav", Date: "2026-05-31", BalanceText: "€5,000.00"},
+		{AccountID: "chk", Date: "2026-03-30", BalanceText: "€2,500.00"},
+		{AccountID: "chk", Date: "2026-06-30", BalanceText: "€2,000.00"}, // as_of itself is ignored
+	}
+	goals := []Goal{
+		{ID: "car", Name: "Car", Target: 12000, TargetDate: "2027-01-31", AccountIDs: []string{"sav"}},
+		{ID: "trip", Name: "Trip", Target: 9000, TargetDate: "2026-09-30", AccountIDs: []string{"sav"}},
+		{ID: "ef", Name: "Emergency fund", TargetMonths: 3, AccountIDs: []string{"sav", "chk"}},
+		{ID: "house", Name: "House", Target: 50000, AccountIDs: []string{"chk"}},
+		{ID: "bike", Name: "Bike", Target: 500, AccountIDs: []string{"new"}},
+	}
+	res := EvaluateGoals(goals, accounts, history, GoalsOptions{AsOf: asOf, MonthlySpend: 2000})
+	byID := map[string]GoalStatus{}
+	for _, g := range res.Goals {
+		byID[g.ID] = g
+	}
+
+	car := byID["car"]
+	// 3100 -> 6000 over 92 days ≈ 959.44 a month; the 6000 left takes 191 days.
+	if car.MonthlyVelocity == nil || *car.MonthlyVelocity != 959.44 || car.Progress != 0.5 {
+		t.Fatalf("car velocity/progress = %v/%v", ptrVal(car.MonthlyVelocity), car.Progress)
+	}
+	if car.RequiredMonthly == nil || *car.RequiredMonthly != 849.42 || car.ProjectedDate == nil || *car.ProjectedDate != "2027-01-07" || car.Status != "on_track" {
+		t.Fatalf("car status=%s required=%v projected=%v", car.Status, ptrVal(car.RequiredMonthly), ptrVal(car.ProjectedDate))
+	}
+	if trip := byID["trip"]; !trip.AtRisk || trip.Status != "at_risk" || len(trip.Reasons) != 1 {
+		t.Fatalf("trip should be at risk (projected after its date): %+v", trip)
+	}
+	if ef := byID["ef"]; ef.Target != 6000 || ef.TargetSource != "months_of_spend" || ef.Status != "complete" || ef.Remaining != 0 {
+		t.Fatalf("emergency fund = %+v", ef)
+	}
+	if house := byID["house"]; !house.AtRisk || house.MonthlyVelocity == nil || *house.MonthlyVelocity >= 0 || house.ProjectedDate != nil {
+		t.Fatalf("shrinking balance should be at risk: %+v", house)
+	}
+	if bike := byID["bike"]; bike.MonthlyVelocity != nil || !bike.AtRisk {
+		t.Fatalf("no history: %+v", bike)
+	}
+	if !res.Goals[0].AtRisk || res.Goals[len(res.Goals)-1].AtRisk {
+		t.Fatal("at-risk goals should sort first")
+	}
+}
+
+func ptrVal[T any](p *T) any {
+	if p == nil {
+		return nil
+	}
+	return *p
+}
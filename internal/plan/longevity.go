@@ -0,0 +1,276 @@
+package plan
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"time"
+
+	"github.com/we-promise/sure-cli/internal/insights"
+)
+
+// WealthBand is the spread of simulated wealth at the end of one year of
+// the simulation, in today's money.
+type WealthBand struct {
+	Year int     `json:"year"`
+	Age  int     `json:"age"`
+	P10  float64 `json:"p10"`
+	P25  float64 `json:"p25"`
+	P50  float64 `json:"p50"`
+	P75  float64 `json:"p75"`
+	P90  float64 `json:"p90"`
+	// Depleted is the share of paths that have run out of money by this age.
+	Depleted float64 `json:"depleted"`
+}
+
+// SustainableSpend is the highest annual spend that keeps the success rate
+// at or above the target.
+type SustainableSpend struct {
+	TargetSuccess  float64 `json:"target_success"`
+	AnnualSpend    float64 `json:"annual_spend"`
+	WithdrawalRate float64 `json:"withdrawal_rate"` // annual_spend / starting_portfolio
+}
+
+type LongevityResult struct {
+	AsOf                string  `json:"as_of"`
+	Currency            string  `json:"currency"`
+	Age                 int     `json:"age"`
+	RetireAge           int     `json:"retire_age"`
+	TargetAge           int     `json:"target_age"`
+	Paths               int     `json:"paths"`
+	Seed                int64   `json:"seed"`
+	StartingPortfolio   float64 `json:"starting_portfolio"`
+	AnnualSpend         float64 `json:"annual_spend"`
+	SpendSource         string  `json:"spend_source"` // history|override
+	AnnualContribution  float64 `json:"annual_contribution"`
+	ExpectedReturn      float64 `json:"expected_return"`
+	Volatility          float64 `json:"volatility"`
+	Inflation           float64 `json:"inflation"`
+	InflationVolatility float64 `json:"inflation_volatility"`
+	// SuccessRate is the share of paths that never run out of money before
+	// TargetAge.
+	SuccessRate float64 `json:"success_rate"`
+	// MedianDepletionAge is the median age money runs out on failed paths;
+	// nil when every path succeeds.
+	MedianDepletionAge *int             `json:"median_depletion_age"`
+	Bands              []WealthBand     `json:"bands"`
+	Sustainable        SustainableSpend `json:"sustainable"`
+	Assumptions        []string         `json:"assumptions"`
+	// Transfers left out of the spend and contribution history.
+	ExcludedTransfers *insights.TransferExclusion `json:"excluded_transfers,omitempty"`
+	// Accounts left out of the starting portfolio because they are not in
+	// Currency.
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// LongevityOptions tunes SimulateLongevity. Rates are yearly fractions and
+// nominal; spend and contributions are in today's money and grow with the
+// simulated inflation.
+type LongevityOptions struct {
+	Age       int
+	TargetAge int
+	// RetireAge is when contributions stop and withdrawals start; zero (or
+	// anything at or below Age) means already retired.
+	RetireAge           int
+	Portfolio           float64
+	AnnualSpend         float64
+	AnnualContribution  float64
+	ExpectedReturn      float64
+	Volatility          float64
+	Inflation           float64
+	InflationVolatility float64
+	// TargetSuccess is the success rate the sustainable spend is solved for
+	// (0.9).
+	TargetSuccess float64
+	Paths         int   // 5000
+	Seed          int64 // same seed + options = identical output
+	AsOf          time.Time
+}
+
+func (o LongevityOptions) withDefaults() LongevityOptions {
+	if o.Paths <= 0 {
+		o.Paths = 5000
+	}
+	if o.TargetSuccess <= 0 || o.TargetSuccess > 1 {
+		o.TargetSuccess = 0.9
+	}
+	if o.RetireAge < o.Age {
+		o.RetireAge = o.Age
+	}
+	if o.AsOf.IsZero() {
+		o.AsOf = time.Now().UTC()
+	}
+	return o
+}
+
+// marketDraws holds one path's yearly return and inflation samples so every
+// spend level is evaluated against the same markets.
+type marketDraws struct {
+	returns   []float64
+	inflation []float64
+}
+
+// SimulateLongevity runs a Monte Carlo simulation of the portfolio from Age
+// to TargetAge: contributions until RetireAge, then inflation-adjusted
+// withdrawals at the start of each year, with normally distributed returns
+// and inflation. It reports the probability of not running out of money,
+// percentile wealth bands per year and the spend that meets TargetSuccess.
+func SimulateLongevity(opts LongevityOptions) LongevityResult {
+	opts = opts.withDefaults()
+	years := opts.TargetAge - opts.Age
+	if years < 0 {
+		years = 0
+	}
+
+	rng := rand.New(rand.NewSource(opts.Seed))
+	draws := make([]marketDraws, opts.Paths)
+	for p := range draws {
+		d := marketDraws{returns: make([]float64, years), inflation: make([]float64, years)}
+		for y := 0; y < years; y++ {
+			// A year can't lose more than everything.
+			d.returns[y] = math.Max(-1, opts.ExpectedReturn+opts.Volatility*rng.NormFloat64())
+			d.inflation[y] = opts.Inflation + opts.InflationVolatility*rng.NormFloat64()
+		}
+		draws[p] = d
+	}
+
+	out := LongevityResult{
+		AsOf:                opts.AsOf.Format("2006-01-02"),
+		Age:                 opts.Age,
+		RetireAge:           opts.RetireAge,
+		TargetAge:           opts.TargetAge,
+		Paths:               opts.Paths,
+		Seed:                opts.Seed,
+		StartingPortfolio:   round2(opts.Portfolio),
+		AnnualSpend:         round2(opts.AnnualSpend),
+		AnnualContribution:  round2(opts.AnnualContribution),
+		ExpectedReturn:      opts.ExpectedReturn,
+		Volatility:          opts.Volatility,
+		Inflation:           opts.Inflation,
+		InflationVolatility: opts.InflationVolatility,
+		Bands:               []WealthBand{},
+		Assumptions: []string{
+			"yearly returns and inflation are independent normal draws (nominal)",
+			"spend and contributions are in today's money and grow with simulated inflation",
+			"contributions are made until retire_age; withdrawals are taken at the start of each year after",
+			"wealth bands are deflated to today's money",
+		},
+	}
+
+	wealth := make([][]float64, years) // [year][path], today's money
+	for y := range wealth {
+		wealth[y] = make([]float64, opts.Paths)
+	}
+	var depletionAges []int
+	depletedBy := make([]int, years)
+	for p, d := range draws {
+		age, ok := runPath(d, opts, opts.AnnualSpend, wealth, p)
+		if !ok {
+			depletionAges = append(depletionAges, age)
+			for y := age - opts.Age; y < years; y++ {
+				depletedBy[y]++
+			}
+		}
+	}
+	out.SuccessRate = round4(1 - float64(len(depletionAges))/float64(opts.Paths))
+	if len(depletionAges) > 0 {
+		sort.Ints(depletionAges)
+		median := depletionAges[len(depletionAges)/2]
+		out.MedianDepletionAge = &median
+	}
+	for y := 0; y < years; y++ {
+		sorted := append([]float64(nil), wealth[y]...)
+		sort.Float64s(sorted)
+		out.Bands = append(out.Bands, WealthBand{
+			Year:     opts.AsOf.Year() + y + 1,
+			Age:      opts.Age + y + 1,
+			P10:      round2(percentile(sorted, 0.10)),
+			P25:      round2(percentile(sorted, 0.25)),
+			P50:      round2(percentile(sorted, 0.50)),
+			P75:      round2(percentile(sorted, 0.75)),
+			P90:      round2(percentile(sorted, 0.90)),
+			Depleted: round4(float64(depletedBy[y]) / float64(opts.Paths)),
+		})
+	}
+
+	out.Sustainable = SustainableSpend{TargetSuccess: opts.TargetSuccess}
+	out.Sustainable.AnnualSpend = round2(sustainableSpend(draws, opts))
+	if opts.Portfolio > 0 {
+		out.Sustainable.WithdrawalRate = round4(out.Sustainable.AnnualSpend / opts.Portfolio)
+	}
+	return out
+}
+
+// runPath simulates one path at the given spend. It records the year-end
+// wealth in today's money into wealth[year][p] when wealth is non-nil and
+// returns the age money ran out (ok=false) or ok=true.
+func runPath(d marketDraws, opts LongevityOptions, spend float64, wealth [][]float64, p int) (int, bool) {
+	w, index := opts.Portfolio, 1.0
+	depletedAt := -1
+	for y := range d.returns {
+		age := opts.Age + y
+		if depletedAt < 0 {
+			if age < opts.RetireAge {
+				w += opts.AnnualContribution * index
+			} else {
+				w -= spend * index
+			}
+			if w < 0 {
+				w, depletedAt = 0, age
+			}
+			w *= 1 + d.returns[y]
+		}
+		index *= 1 + d.inflation[y]
+		if wealth != nil {
+			wealth[y][p] = w / index
+		}
+	}
+	if depletedAt >= 0 {
+		return depletedAt, false
+	}
+	return 0, true
+}
+
+// successRate is the share of paths that never run out at the given spend.
+func successRate(draws []marketDraws, opts LongevityOptions, spend float64) float64 {
+	ok := 0
+	for _, d := range draws {
+		if _, survived := runPath(d, opts, spend, nil, 0); survived {
+			ok++
+		}
+	}
+	return float64(ok) / float64(len(draws))
+}
+
+// sustainableSpend bisects for the highest spend whose success rate meets
+// the target. All candidates share the same draws, so success falls
+// monotonically as spend rises.
+func sustainableSpend(draws []marketDraws, opts LongevityOptions) float64 {
+	lo, hi := 0.0, math.Max(opts.Portfolio, 1)
+	for successRate(draws, opts, hi) >= opts.TargetSuccess && hi < 1e12 {
+		lo, hi = hi, hi*2
+	}
+	for i := 0; i < 50 && hi-lo > 0.01; i++ {
+		mid := (lo + hi) / 2
+		if successRate(draws, opts, mid) >= opts.TargetSuccess {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+	return lo
+}
+
+// percentile reads the q-quantile from sorted values (nearest rank).
+func percentile(sorted []float64, q float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	i := int(math.Ceil(q*float64(len(sorted)))) - 1
+	if i < 0 {
+		i = 0
+	}
+	return sorted[i]
+}
+
+func round4(v float64) float64 { return math.Round(v*10000) / 10000 }
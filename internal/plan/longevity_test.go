@@ -0,0 +1,55 @@
+package plan
+
+import (
+	"math"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSimulateLongevity_Deterministic(t *testing.T) {
+	// No volatility and no growth: 300k lasts 30 years at 10k, not at 12k.
+	opts := LongevityOptions{Age: 65, TargetAge: 95, Portfolio: 300000, AnnualSpend: 12000, Paths: 10, AsOf: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)}
+	res := SimulateLongevity(opts)
+	if res.SuccessRate != 0 || res.MedianDepletionAge == nil || *res.MedianDepletionAge != 90 {
+		t.Fatalf("success=%v depletion=%v", res.SuccessRate, res.MedianDepletionAge)
+	}
+	if math.Abs(res.Sustainable.AnnualSpend-10000) > 0.05 || res.Sustainable.WithdrawalRate != 0.0333 {
+		t.Fatalf("sustainable = %+v", res.Sustainable)
+	}
+	if len(res.Bands) != 30 || res.Bands[0].Age != 66 || res.Bands[0].P50 != 288000 || res.Bands[24].Depleted != 0 || res.Bands[25].Depleted != 1 {
+		t.Fatalf("bands: %d, first %+v, 25 %+v, 26 %+v", len(res.Bands), res.Bands[0], res.Bands[24], res.Bands[25])
+	}
+
+	// Contributions until retirement, then withdrawals.
+	opts = LongevityOptions{Age: 60, TargetAge: 70, RetireAge: 65, AnnualContribution: 10000, AnnualSpend: 10000, Paths: 1}
+	if res := SimulateLongevity(opts); res.SuccessRate != 1 || res.MedianDepletionAge != nil || res.Bands[4].P50 != 50000 {
+		t.Fatalf("accumulation: success=%v bands=%+v", res.SuccessRate, res.Bands[4])
+	}
+}
+
+func TestSimulateLongevity_Seeded(t *testing.T) {
+	opts := LongevityOptions{Age: 50, TargetAge: 90, Portfolio: 1000000, AnnualSpend: 40000, ExpectedReturn: 0.07, Volatility: 0.15, Inflation: 0.025, InflationVolatility: 0.01, Paths: 500, Seed: 7}
+	a, b := SimulateLongevity(opts), SimulateLongevity(opts)
+	if !reflect.DeepEqual(a, b) {
+		t.Fatal("same seed and options should give identical results")
+	}
+	if a.SuccessRate <= 0 || a.SuccessRate >= 1 {
+		t.Fatalf("success rate = %v, want a probability strictly between 0 and 1", a.SuccessRate)
+	}
+	for _, band := range a.Bands {
+		if band.P10 > band.P25 || band.P25 > band.P50 || band.P50 > band.P75 || band.P75 > band.P90 {
+			t.Fatalf("percentiles out of order: %+v", band)
+		}
+	}
+	// The sustainable spend meets the target, and spending it is at least
+	// as safe as the target.
+	opts.AnnualSpend = a.Sustainable.AnnualSpend
+	if got := SimulateLongevity(opts).SuccessRate; got < a.Sustainable.TargetSuccess {
+		t.Fatalf("success at sustainable spend = %v, want >= %v", got, a.Sustainable.TargetSuccess)
+	}
+	opts.Seed = 8
+	if reflect.DeepEqual(SimulateLongevity(opts).Bands, a.Bands) {
+		t.Fatal("a different seed should change the bands")
+	}
+}
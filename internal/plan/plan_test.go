@@ -1,28 +1,89 @@
 package plan
 
 import (
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/we-promise/sure-cli/internal/insights"
 	"github.com/we-promise/sure-cli/internal/models"
 )
 
-func TestComputeRunway(t *testing.T) {
-	now := time.Now().UTC()
+func TestComputeLiquidRunway(t *testing.T) {
+	asOf := time.Date(2026, 6, 30, 0, 0, 0, 0, time.UTC)
+	accounts := []models.Account{
+		{ID: "chk", AccountType: "depository", Subtype: "checking", Classification: "asset", Currency: "EUR", BalanceText: "€3,000.00"},
+		{ID: "sav", AccountType: "depository", Subtype: "savings", Classification: "asset", BalanceText: "€9,000.00"},
+		{ID: "brk", AccountType: "investment", Classification: "asset", BalanceText: "€20,000.00", CashBalanceText: "€2,000.00"},
+		{ID: "card", AccountType: "credit_card", Classification: "liability", BalanceText: "€400.00"},
+		{ID: "home", AccountType: "property", Classification: "asset", BalanceText: "€300,000.00"},
+	}
+	day := func(n int) time.Time { return asOf.AddDate(0, 0, -n) }
 	txs := []models.Transaction{
-		{Classification: "expense", AmountText: "€10.00", Currency: "EUR", Date: now.AddDate(0, 0, -1)},
-		{Classification: "expense", AmountText: "€20.00", Currency: "EUR", Date: now.AddDate(0, 0, -2)},
-		{Classification: "income", AmountText: "€100.00", Currency: "EUR", Date: now.AddDate(0, 0, -2)},
+		{ID: "groceries", AccountID: "chk", Classification: "expense", AmountText: "€600.00", Date: day(10)},
+		{ID: "shopping", AccountID: "card", Classification: "expense", AmountText: "€400.00", Date: day(20)},
+		{ID: "card-pay", AccountID: "chk", Classification: "expense", AmountText: "€400.00", Date: day(5), Kind: "cc_payment"},
+		{ID: "card-paid", AccountID: "card", Classification: "income", AmountText: "€400.00", Date: day(4), TransferID: "tr_2"},
+		{ID: "to-sav", AccountID: "chk", Classification: "expense", AmountText: "€1,000.00", Date: day(3), TransferID: "tr_1"},
+		{ID: "old", AccountID: "chk", Classification: "expense", AmountText: "€999.00", Date: day(45)},
 	}
-
-	s, err := ComputeRunway("€300.00", txs, 30)
+	opts := RunwayOptions{AsOf: asOf, WindowDays: 30, Tiers: map[string]string{"Savings": "near_cash", "property": "exclude"}}
+	txs, excluded := insights.ExcludeTransfers(txs, nil)
+	s, err := ComputeLiquidRunway(accounts, txs, opts)
 	if err != nil {
-		t.Fatalf("unexpected err: %v", err)
+		t.Fatal(err)
+	}
+	// The card payment and the savings transfer are not spending.
+	if s.AvgMonthlyBurn != 1000 || excluded.Count != 3 || excluded.Expense != 1400 {
+		t.Fatalf("burn = %v excluded = %+v", s.AvgMonthlyBurn, excluded)
+	}
+	want := []RunwayTier{
+		{Tier: "cash", Balance: 3000, Cumulative: 3000, RunwayMonths: 3},
+		{Tier: "near_cash", Balance: 11000, Cumulative: 14000, RunwayMonths: 14},
+		{Tier: "illiquid", Balance: 18000, Cumulative: 32000, RunwayMonths: 32},
 	}
-	if s.AvgMonthlyBurn <= 0 {
-		t.Fatalf("expected burn > 0")
+	for i, w := range want {
+		if s.Tiers[i] != w {
+			t.Errorf("tier %d = %+v, want %+v", i, s.Tiers[i], w)
+		}
 	}
-	if s.RunwayMonths <= 0 {
-		t.Fatalf("expected runway > 0")
+	if s.Balance != 3000 || s.RunwayMonths != 3 || len(s.Accounts) != 3 || s.Currency != "EUR" {
+		t.Fatalf("summary = %+v", s)
+	}
+	if a := s.Accounts[2]; a.AccountID != "brk" || a.NearCash != 2000 || a.Illiquid != 18000 || a.Source != "default" {
+		t.Errorf("brokerage = %+v", a)
+	}
+	if a := s.Accounts[1]; a.Tier != "near_cash" || a.Source != "config" || a.NearCash != 9000 {
+		t.Errorf("savings = %+v", a)
+	}
+
+	opts.AccountIDs = []string{"chk"}
+	if s, _ := ComputeLiquidRunway(accounts, txs, opts); s.Balance != 3000 || s.Tiers[2].Cumulative != 3000 {
+		t.Errorf("filtered = %+v", s.Tiers)
+	}
+	opts.Tiers = map[string]string{"chk": "liquid"}
+	if _, err := ComputeLiquidRunway(accounts, txs, opts); err == nil {
+		t.Error("want an error for an unknown tier")
+	}
+
+	// Balances and expenses in another currency are not added up.
+	usd := []models.Account{accounts[0], {ID: "us", Name: "US Checking", AccountType: "depository", Currency: "USD", BalanceText: "$5,000.00"}}
+	usdTxs := append(txs, models.Transaction{ID: "usd", Classification: "expense", AmountText: "$900.00", Currency: "USD", Date: day(2)})
+	s, err = ComputeLiquidRunway(usd, usdTxs, RunwayOptions{AsOf: asOf, WindowDays: 30})
+	if err != nil || s.Balance != 3000 || s.AvgMonthlyBurn != 1000 || s.Currency != "EUR" || len(s.Warnings) != 2 {
+		t.Fatalf("mixed currencies = %+v, %v", s, err)
+	}
+	if s, _ := ComputeLiquidRunway(usd, usdTxs, RunwayOptions{AsOf: asOf, WindowDays: 30, Currency: "usd"}); s.Balance != 5000 || len(s.Warnings) != 1 || !strings.Contains(s.Warnings[0], "chk") {
+		t.Fatalf("USD runway = %+v", s)
+	}
+}
+
+func TestAccountTierLegacyTypes(t *testing.T) {
+	// Older Sure versions report checking and savings as account types;
+	// status counted them as cash before tiers existed.
+	for _, typ := range []string{"depository", "checking", "savings"} {
+		if tier, _ := AccountTier(models.Account{AccountType: typ, Classification: "asset"}, nil); tier != TierCash {
+			t.Errorf("%s: tier = %s, want cash", typ, tier)
+		}
 	}
 }
@@ -0,0 +1,176 @@
+package plan
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/we-promise/sure-cli/internal/insights"
+	"github.com/we-promise/sure-cli/internal/models"
+)
+
+// RecurringItem is one recurring charge (or payment) known to a forecast:
+// tracked in Sure, detected from history, or both.
+type RecurringItem struct {
+	Name           string `json:"name"`
+	Merchant       string `json:"merchant"` // normalized grouping key
+	Source         string `json:"source"`   // sure|detected|sure+detected
+	RecurringID    string `json:"recurring_id,omitempty"`
+	AccountID      string `json:"account_id,omitempty"`
+	Classification string `json:"classification"` // expense|income
+	// Amount is the expected charge (positive): Sure's expected average when
+	// it has one, else its amount, else the latest detected price.
+	Amount             float64  `json:"amount"`
+	AmountMin          *float64 `json:"amount_min"`
+	AmountMax          *float64 `json:"amount_max"`
+	AmountAvg          *float64 `json:"amount_avg"`
+	PeriodDays         float64  `json:"period_days"`
+	ExpectedDayOfMonth int      `json:"expected_day_of_month,omitempty"`
+	NextExpectedDate   string   `json:"next_expected_date"`
+}
+
+// MergeRecurring combines Sure recurring transactions with detected
+// subscriptions. Sure is the ground truth: a detected subscription whose
+// merchant Sure already tracks is folded into the Sure entry (source
+// "sure+detected"), and one Sure marks inactive is dropped. Inactive Sure
+// entries and missed detections are not returned.
+func MergeRecurring(sure []models.RecurringTransaction, detected []insights.SubscriptionCandidate, n *insights.Normalizer) []RecurringItem {
+	var out []RecurringItem
+	byKey := map[string]int{}
+	inactive := map[string]bool{}
+	for _, rt := range sure {
+		key := n.Key(models.Transaction{Name: rt.Name, MerchantName: rt.MerchantName})
+		if rt.Status != "active" {
+			inactive[key] = true
+			continue
+		}
+		amt, err := insights.ParseAmount(rt.AmountText)
+		if err != nil {
+			continue
+		}
+		it := RecurringItem{
+			Name:               rt.Name,
+			Merchant:           key,
+			Source:             "sure",
+			RecurringID:        rt.ID,
+			AccountID:          rt.AccountID,
+			Classification:     "expense",
+			Amount:             round2(math.Abs(amt)),
+			AmountMin:          optAmount(rt.ExpectedMinText),
+			AmountMax:          optAmount(rt.ExpectedMaxText),
+			AmountAvg:          optAmount(rt.ExpectedAvgText),
+			PeriodDays:         30,
+			ExpectedDayOfMonth: rt.ExpectedDayOfMonth,
+			NextExpectedDate:   rt.NextExpectedDate,
+		}
+		// Sure stores amounts as entries: negative is an inflow.
+		if amt < 0 {
+			it.Classification = "income"
+		}
+		if it.AmountAvg != nil {
+			it.Amount = *it.AmountAvg
+		}
+		if it.ExpectedDayOfMonth <= 0 {
+			if next, err := time.Parse("2006-01-02", rt.NextExpectedDate); err == nil {
+				it.ExpectedDayOfMonth = next.Day()
+			}
+		}
+		byKey[key] = len(out)
+		out = append(out, it)
+	}
+
+	for _, s := range detected {
+		if s.Status == "missed" || inactive[s.Merchant] {
+			continue
+		}
+		if i, ok := byKey[s.Merchant]; ok {
+			out[i].Source = "sure+detected"
+			continue
+		}
+		it := RecurringItem{
+			Name:             s.Name,
+			Merchant:         s.Merchant,
+			Source:           "detected",
+			Classification:   "expense",
+			Amount:           round2(math.Abs(s.LastAmount)),
+			PeriodDays:       s.AvgPeriodDays,
+			NextExpectedDate: s.NextExpectedDate,
+		}
+		if len(s.AmountHistory) > 0 {
+			lo, hi := s.AmountHistory[0].Amount, s.AmountHistory[0].Amount
+			for _, p := range s.AmountHistory {
+				lo, hi = math.Min(lo, p.Amount), math.Max(hi, p.Amount)
+			}
+			avg := s.AvgAmount
+			it.AmountMin, it.AmountMax, it.AmountAvg = &lo, &hi, &avg
+		}
+		if s.Period == "monthly" {
+			it.ExpectedDayOfMonth = s.LastDate.Day()
+			it.NextExpectedDate = addMonthsClamped(s.LastDate, 1, it.ExpectedDayOfMonth).Format("2006-01-02")
+		}
+		out = append(out, it)
+	}
+
+	sort.SliceStable(out, func(i, j int) bool { return out[i].NextExpectedDate < out[j].NextExpectedDate })
+	return out
+}
+
+// Occurrences returns the item's expected dates in (after, end]. Dates
+// already past are rolled forward by the cadence (assumed settled).
+func (it RecurringItem) Occurrences(after, end time.Time) []time.Time {
+	next, err := time.Parse("2006-01-02", it.NextExpectedDate)
+	if err != nil {
+		return nil
+	}
+	day := it.ExpectedDayOfMonth
+	if day <= 0 {
+		day = next.Day()
+	}
+	return occurrences(next, it.PeriodDays, day, after, end)
+}
+
+// occurrences steps from first by the cadence and returns the dates in
+// (after, end]. Monthly cadences (25-35 days) land on day of month `day`.
+func occurrences(first time.Time, periodDays float64, day int, after, end time.Time) []time.Time {
+	if periodDays <= 0 {
+		periodDays = 30
+	}
+	monthly := periodDays >= 25 && periodDays <= 35
+	step := int(math.Round(periodDays))
+	var out []time.Time
+	for k := 0; ; k++ {
+		d := first.AddDate(0, 0, k*step)
+		if monthly {
+			d = addMonthsClamped(first, k, day)
+		}
+		if d.After(end) {
+			return out
+		}
+		if d.After(after) {
+			out = append(out, d)
+		}
+	}
+}
+
+// addMonthsClamped moves t by k months and sets the day, clamped to the
+// target month's length (the 31st becomes the 30th in April).
+func addMonthsClamped(t time.Time, k, day int) time.Time {
+	first := time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC).AddDate(0, k, 0)
+	last := first.AddDate(0, 1, -1).Day()
+	if day > last {
+		day = last
+	}
+	return first.AddDate(0, 0, day-1)
+}
+
+func optAmount(s string) *float64 {
+	if s == "" {
+		return nil
+	}
+	v, err := insights.ParseAmount(s)
+	if err != nil {
+		return nil
+	}
+	v = round2(math.Abs(v))
+	return &v
+}
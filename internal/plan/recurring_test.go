@@ -0,0 +1,91 @@
+package plan
+
+import (
+	"testing"
+	"time"
+
+	"github.com/we-promise/sure-cli/internal/insights"
+	"github.com/we-promise/sure-cli/internal/models"
+)
+
+func TestMergeRecurring(t *testing.T) {
+	sure := []models.RecurringTransaction{
+		{ID: "r_rent", Name: "Rent", AmountText: "€950.00", ExpectedDayOfMonth: 1, NextExpectedDate: "2026-04-01", Status: "active"},
+		{ID: "r_power", Name: "Power Co", AmountText: "€55.00", ExpectedAvgText: "€50.00", ExpectedMinText: "€40.00", ExpectedMaxText: "€60.00", NextExpectedDate: "2026-03-20", Status: "active"},
+		{ID: "r_gym", Name: "Gym", AmountText: "€40.00", NextExpectedDate: "2026-01-10", Status: "inactive"},
+		{ID: "r_pay", Name: "Payroll", AmountText: "-€2,000.00", NextExpectedDate: "2026-03-25", Status: "active"},
+	}
+	last := time.Date(2026, 3, 5, 0, 0, 0, 0, time.UTC)
+	detected := []insights.SubscriptionCandidate{
+		{Name: "RENT", Merchant: "rent", AvgPeriodDays: 30.5, Period: "monthly", LastDate: last, LastAmount: 950, Status: "active"},
+		{Name: "GYM", Merchant: "gym", AvgPeriodDays: 30, Period: "monthly", LastDate: last, LastAmount: 40, Status: "active"},
+		{Name: "Stream", Merchant: "stream", AvgPeriodDays: 30.2, Period: "monthly", LastDate: last, LastAmount: 13, AvgAmount: 12.5, Status: "active",
+			AmountHistory: []insights.AmountPoint{{Amount: 12}, {Amount: 13}}},
+		{Name: "Old", Merchant: "old", AvgPeriodDays: 30, Period: "monthly", LastDate: last, LastAmount: 5, Status: "missed"},
+	}
+
+	items := MergeRecurring(sure, detected, nil)
+	by := map[string]RecurringItem{}
+	for _, it := range items {
+		by[it.Merchant] = it
+	}
+	if len(items) != 4 {
+		t.Fatalf("want rent, power co, payroll, stream; got %+v", items)
+	}
+	if by["rent"].Source != "sure+detected" || by["rent"].RecurringID != "r_rent" {
+		t.Fatalf("rent: %+v", by["rent"])
+	}
+	if p := by["power co"]; p.Amount != 50 || *p.AmountMin != 40 || *p.AmountMax != 60 || p.ExpectedDayOfMonth != 20 || p.Source != "sure" {
+		t.Fatalf("power: %+v", p)
+	}
+	if by["payroll"].Classification != "income" || by["payroll"].Amount != 2000 {
+		t.Fatalf("payroll: %+v", by["payroll"])
+	}
+	if s := by["stream"]; s.Source != "detected" || s.NextExpectedDate != "2026-04-05" || *s.AmountMin != 12 || *s.AmountAvg != 12.5 {
+		t.Fatalf("stream: %+v", s)
+	}
+	if _, ok := by["gym"]; ok {
+		t.Fatalf("gym is inactive in Sure and must not be projected")
+	}
+
+	after := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+	got := by["rent"].Occurrences(after, after.AddDate(0, 2, 0))
+	if len(got) != 2 || got[0].Format("2006-01-02") != "2026-04-01" || got[1].Format("2006-01-02") != "2026-05-01" {
+		t.Fatalf("rent occurrences: %v", got)
+	}
+}
+
+func TestComputeForecast_UsesSureRecurring(t *testing.T) {
+	now := time.Now().UTC()
+	var txs []models.Transaction
+	for i := 5; i >= 0; i-- {
+		txs = append(txs, models.Transaction{Name: "Streaming", Classification: "expense", AmountText: "€20.00", Date: now.AddDate(0, -i, -1)})
+	}
+	next := now.AddDate(0, 0, 5).Format("2006-01-02")
+	recurring := []models.RecurringTransaction{
+		// Tracked in Sure with a higher expected average: Sure wins, no double count.
+		{ID: "r1", Name: "Streaming", AmountText: "€20.00", ExpectedAvgText: "€22.00", NextExpectedDate: next, Status: "active"},
+		{ID: "r2", Name: "Insurance", AmountText: "€60.00", NextExpectedDate: next, Status: "active"},
+	}
+
+	result := ComputeForecastWithOptions(txs, 30, true, ForecastOptions{Subscriptions: insights.DefaultSubscriptionOptions(), Recurring: recurring})
+	if result.Summary.SureRecurring != 2 || result.Summary.DetectedRecurring != 0 || len(result.Recurring) != 2 {
+		t.Fatalf("recurring: %+v / %+v", result.Summary, result.Recurring)
+	}
+	if result.Summary.RecurringExpenses != 82 {
+		t.Fatalf("recurring expenses = %v, want 82", result.Summary.RecurringExpenses)
+	}
+	// Streaming history is all recurring, so nothing is left as daily spend.
+	if result.Summary.AverageDailySpend != 0 {
+		t.Fatalf("avg daily spend = %v", result.Summary.AverageDailySpend)
+	}
+	var onNext []string
+	for _, d := range result.Daily {
+		if d.Date == next {
+			onNext = d.RecurringItems
+		}
+	}
+	if len(onNext) != 2 {
+		t.Fatalf("expected both charges on %s, got %v", next, onNext)
+	}
+}
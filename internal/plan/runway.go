@@ -1,6 +1,9 @@
 package plan
 
 import (
+	"fmt"
+	"math"
+	"strings"
 	"time"
 
 	"github.com/we-promise/sure-cli/internal/insights"
@@ -14,50 +17,206 @@ type RunwaySummary struct {
 	Currency       string   `json:"currency"`
 	WindowDays     int      `json:"window_days"`
 	Assumptions    []string `json:"assumptions"`
+	// Accounts and expenses left out because they are not in Currency.
+	Warnings []string `json:"warnings,omitempty"`
+	// Set by ComputeLiquidRunway: runway per liquidity tier and each
+	// account's split across tiers.
+	Tiers    []RunwayTier    `json:"tiers,omitempty"`
+	Accounts []RunwayAccount `json:"accounts,omitempty"`
+	// Transfers left out of the burn.
+	ExcludedTransfers *insights.TransferExclusion `json:"excluded_transfers,omitempty"`
 }
 
-// ComputeRunway estimates runway months based on recent spending.
-func ComputeRunway(balanceText string, txs []models.Transaction, windowDays int) (RunwaySummary, error) {
-	bal, err := insights.ParseAmountEUR(balanceText)
-	cur := "EUR"
-	if err != nil {
-		return RunwaySummary{}, err
+// Liquidity tiers, most liquid first.
+const (
+	TierCash     = "cash"
+	TierNearCash = "near_cash"
+	TierIlliquid = "illiquid"
+	TierExclude  = "exclude"
+)
+
+// LiquidityTiers lists the runway tiers in order.
+var LiquidityTiers = []string{TierCash, TierNearCash, TierIlliquid}
+
+// RunwayTier is the runway of one tier together with the more liquid ones.
+type RunwayTier struct {
+	Tier         string  `json:"tier"`
+	Balance      float64 `json:"balance"`
+	Cumulative   float64 `json:"cumulative"` // this tier plus the more liquid ones
+	RunwayMonths float64 `json:"runway_months"`
+}
+
+// RunwayAccount is one account's balance split across tiers.
+type RunwayAccount struct {
+	AccountID   string  `json:"account_id"`
+	Account     string  `json:"account"`
+	AccountType string  `json:"account_type"`
+	Tier        string  `json:"tier"`   // configured or default tier of the balance
+	Source      string  `json:"source"` // default|config
+	Balance     float64 `json:"balance"`
+	Cash        float64 `json:"cash"`
+	NearCash    float64 `json:"near_cash"`
+	Illiquid    float64 `json:"illiquid"`
+}
+
+// RunwayOptions tunes ComputeLiquidRunway.
+type RunwayOptions struct {
+	AsOf       time.Time
+	WindowDays int // 90
+	// Tiers maps an account id, subtype or account type to a tier
+	// (cash|near_cash|illiquid|exclude); the id wins over the subtype,
+	// the subtype over the type.
+	Tiers map[string]string
+	// AccountIDs limits the balances counted (every account when empty).
+	AccountIDs []string
+	// Currency is the runway currency (default: the first counted
+	// account's). Balances and expenses in other currencies are left out.
+	Currency string
+}
+
+// AccountTier returns the tier an account's balance is counted in and
+// whether it comes from tiers. By default depository accounts (and the
+// checking/savings types older Sure versions report) are cash, liabilities
+// are excluded and the rest is illiquid; investment and crypto
+// accounts still count their reported cash balance as near-cash.
+func AccountTier(acc models.Account, tiers map[string]string) (string, bool) {
+	for _, key := range []string{acc.ID, acc.Subtype, acc.AccountType} {
+		if t, ok := tiers[strings.ToLower(key)]; ok && key != "" {
+			return t, true
+		}
+	}
+	switch {
+	case acc.Classification == "liability":
+		return TierExclude, false
+	case acc.AccountType == "depository", acc.AccountType == "checking", acc.AccountType == "savings":
+		return TierCash, false
 	}
+	return TierIlliquid, false
+}
 
-	end := time.Now().UTC()
-	start := end.AddDate(0, 0, -windowDays)
+// ValidateTiers checks configured tier names.
+func ValidateTiers(tiers map[string]string) error {
+	for key, t := range tiers {
+		switch t {
+		case TierCash, TierNearCash, TierIlliquid, TierExclude:
+		default:
+			return fmt.Errorf("tier for %q must be one of cash, near_cash, illiquid, exclude (got %q)", key, t)
+		}
+	}
+	return nil
+}
 
-	spent := 0.0
-	for _, tx := range txs {
-		if tx.Date.Before(start) || tx.Date.After(end) {
+// ComputeLiquidRunway estimates runway across accounts by liquidity tier.
+// Depository accounts count their cash balance (else their balance) as
+// cash; investment and crypto accounts count their cash balance as
+// near-cash and the rest as illiquid. The burn is the expenses of the last
+// WindowDays per 30 days (drop transfers between own accounts from txs
+// first; see insights.ExcludeTransfers). The summary's Balance and
+// RunwayMonths are those of the cash tier.
+func ComputeLiquidRunway(accounts []models.Account, txs []models.Transaction, opts RunwayOptions) (RunwaySummary, error) {
+	if err := ValidateTiers(opts.Tiers); err != nil {
+		return RunwaySummary{}, err
+	}
+	if opts.WindowDays <= 0 {
+		opts.WindowDays = 90
+	}
+	if opts.AsOf.IsZero() {
+		opts.AsOf = time.Now().UTC()
+	}
+	tiers := map[string]string{}
+	for k, v := range opts.Tiers {
+		tiers[strings.ToLower(k)] = v
+	}
+	only := map[string]bool{}
+	for _, id := range opts.AccountIDs {
+		only[id] = true
+	}
+
+	out := RunwaySummary{
+		WindowDays: opts.WindowDays,
+		Tiers:      []RunwayTier{},
+		Accounts:   []RunwayAccount{},
+		Assumptions: []string{
+			"burn = expenses over window_days, extrapolated to 30-day months",
+			"depository accounts count their cash balance (else balance) as cash; investment and crypto cash balances are near-cash, the rest illiquid; liabilities are excluded",
+			"each tier's runway spends it together with the more liquid tiers",
+		},
+	}
+	out.Currency = strings.ToUpper(opts.Currency)
+	byTier := map[string]float64{}
+	for _, acc := range accounts {
+		if len(only) > 0 && !only[acc.ID] {
 			continue
 		}
-		if tx.Classification != "expense" {
+		tier, configured := AccountTier(acc, tiers)
+		bal, err := insights.ParseAmount(acc.BalanceText)
+		if err != nil || tier == TierExclude {
 			continue
 		}
-		amt, err := insights.ParseAmountEUR(tx.AmountText)
-		if err != nil {
+		if out.Currency == "" {
+			out.Currency = strings.ToUpper(acc.Currency)
+		}
+		if acc.Currency != "" && !strings.EqualFold(acc.Currency, out.Currency) {
+			name := acc.Name
+			if name == "" {
+				name = acc.ID
+			}
+			out.Warnings = append(out.Warnings, fmt.Sprintf("%s: balance in %s left out (runway is in %s)", name, acc.Currency, out.Currency))
 			continue
 		}
-		spent += amt
+		ra := RunwayAccount{AccountID: acc.ID, Account: acc.Name, AccountType: acc.AccountType, Tier: tier, Source: "default", Balance: round2(bal)}
+		split := map[string]float64{tier: bal}
+		if configured {
+			ra.Source = "config"
+		} else if cash, err := insights.ParseAmount(acc.CashBalanceText); err == nil && acc.CashBalanceText != "" {
+			// Cash inside the account is at least near-cash; the rest
+			// (pending, invested) stays one tier down.
+			cash = math.Min(cash, bal)
+			if tier == TierCash {
+				split = map[string]float64{TierCash: cash, TierNearCash: bal - cash}
+			} else {
+				split = map[string]float64{TierNearCash: cash, tier: bal - cash}
+			}
+		}
+		ra.Cash, ra.NearCash, ra.Illiquid = round2(split[TierCash]), round2(split[TierNearCash]), round2(split[TierIlliquid])
+		for t, v := range split {
+			byTier[t] += v
+		}
+		out.Accounts = append(out.Accounts, ra)
+	}
+	if out.Currency == "" {
+		out.Currency = "EUR"
 	}
 
-	avgMonthly := 0.0
-	if windowDays > 0 {
-		avgDaily := spent / float64(windowDays)
-		avgMonthly = avgDaily * 30.0
+	end := time.Date(opts.AsOf.Year(), opts.AsOf.Month(), opts.AsOf.Day(), 0, 0, 0, 0, time.UTC)
+	start := end.AddDate(0, 0, -opts.WindowDays)
+	spent, foreign := 0.0, 0
+	for _, tx := range txs {
+		if tx.Date.Before(start) || tx.Date.After(end) || tx.Classification != "expense" {
+			continue
+		}
+		if tx.Currency != "" && !strings.EqualFold(tx.Currency, out.Currency) {
+			foreign++
+			continue
+		}
+		if amt, err := insights.ParseAmount(tx.AmountText); err == nil {
+			spent += math.Abs(amt)
+		}
 	}
-	runway := 0.0
-	if avgMonthly > 0 {
-		runway = bal / avgMonthly
+	if foreign > 0 {
+		out.Warnings = append(out.Warnings, fmt.Sprintf("%d expenses in other currencies left out of the burn (runway is in %s)", foreign, out.Currency))
 	}
+	out.AvgMonthlyBurn = round2(spent / float64(opts.WindowDays) * 30)
 
-	return RunwaySummary{
-		Balance:        bal,
-		AvgMonthlyBurn: avgMonthly,
-		RunwayMonths:   runway,
-		Currency:       cur,
-		WindowDays:     windowDays,
-		Assumptions:    []string{"expense sign normalized via classification; burn extrapolated to 30-day month"},
-	}, nil
+	var cumulative float64
+	for _, t := range LiquidityTiers {
+		cumulative += byTier[t]
+		rt := RunwayTier{Tier: t, Balance: round2(byTier[t]), Cumulative: round2(cumulative)}
+		if out.AvgMonthlyBurn > 0 && cumulative > 0 {
+			rt.RunwayMonths = round2(cumulative / out.AvgMonthlyBurn)
+		}
+		out.Tiers = append(out.Tiers, rt)
+	}
+	out.Balance, out.RunwayMonths = out.Tiers[0].Balance, out.Tiers[0].RunwayMonths
+	return out, nil
 }
@@ -0,0 +1,728 @@
+package plan
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.yaml.in/yaml/v3"
+
+	"github.com/we-promise/sure-cli/internal/insights"
+	"github.com/we-promise/sure-cli/internal/models"
+)
+
+// Scenario is a what-if file: adjustments to recurring items, category
+// spend and one-off events, applied on top of a baseline plan.
+//
+//	name: Lean year
+//	recurring:
+//	  - match: netflix         # existing item (name or merchant, case-insensitive)
+//	    cancel: true
+//	  - match: rent
+//	    change: 8%             # or an amount per charge, e.g. 95
+//	    from: 2026-06
+//	  - name: Freelance        # new item
+//	    amount: 400            # signed: inflow positive
+//	    every: monthly
+//	categories:
+//	  - category: Restaurants
+//	    change: -30%           # or an amount per month
+//	events:
+//	  - name: Car repair
+//	    amount: -1200
+//	    date: 2026-11-15
+type Scenario struct {
+	Name        string                `yaml:"name"`
+	Description string                `yaml:"description"`
+	Recurring   []RecurringAdjustment `yaml:"recurring"`
+	Categories  []CategoryAdjustment  `yaml:"categories"`
+	Events      []ScenarioEvent       `yaml:"events"`
+}
+
+// RecurringAdjustment cancels or changes existing recurring items (Match)
+// or adds a new one (Name and Amount).
+type RecurringAdjustment struct {
+	Match  string `yaml:"match"`
+	Cancel bool   `yaml:"cancel"`
+	// Change is a percentage ("8%") or an amount per charge ("95");
+	// positive makes the item larger.
+	Change    string  `yaml:"change"`
+	Name      string  `yaml:"name"`
+	Amount    float64 `yaml:"amount"`
+	Every     string  `yaml:"every"` // weekly|biweekly|monthly|quarterly|yearly (monthly)
+	From      string  `yaml:"from"`  // YYYY-MM-DD or YYYY-MM
+	Until     string  `yaml:"until"`
+	AccountID string  `yaml:"account_id"`
+}
+
+// CategoryAdjustment changes a category's spend by a percentage or an
+// amount per month.
+type CategoryAdjustment struct {
+	Category  string `yaml:"category"`
+	Change    string `yaml:"change"`
+	From      string `yaml:"from"`
+	Until     string `yaml:"until"`
+	AccountID string `yaml:"account_id"`
+}
+
+// ScenarioEvent is a one-off inflow (positive) or outflow (negative).
+type ScenarioEvent struct {
+	Name      string  `yaml:"name"`
+	Amount    float64 `yaml:"amount"`
+	Date      string  `yaml:"date"`
+	AccountID string  `yaml:"account_id"`
+}
+
+// LoadScenario reads and validates a scenario file (YAML or JSON). The
+// name defaults to the file name.
+func LoadScenario(path string) (Scenario, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return Scenario{}, err
+	}
+	s, err := ParseScenario(b)
+	if err != nil {
+		return Scenario{}, err
+	}
+	if s.Name == "" {
+		s.Name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+	return s, nil
+}
+
+// ParseScenario decodes a scenario document; unknown keys are errors so
+// typos do not silently drop an adjustment.
+func ParseScenario(b []byte) (Scenario, error) {
+	var s Scenario
+	dec := yaml.NewDecoder(bytes.NewReader(b))
+	dec.KnownFields(true)
+	if err := dec.Decode(&s); err != nil && !errors.Is(err, io.EOF) {
+		return Scenario{}, err
+	}
+	return s, s.validate()
+}
+
+func (s Scenario) validate() error {
+	if len(s.Recurring)+len(s.Categories)+len(s.Events) == 0 {
+		return fmt.Errorf("scenario has no recurring, categories or events adjustments")
+	}
+	for i, r := range s.Recurring {
+		at := fmt.Sprintf("recurring[%d]", i)
+		switch {
+		case r.Match != "" && (r.Name != "" || r.Amount != 0):
+			return fmt.Errorf("%s: use match to adjust an existing item, or name and amount to add one", at)
+		case r.Match != "" && r.Cancel == (r.Change != ""):
+			return fmt.Errorf("%s: set exactly one of cancel or change", at)
+		case r.Match == "" && (r.Name == "" || r.Amount == 0):
+			return fmt.Errorf("%s: a new item needs name and a non-zero amount", at)
+		case r.Match == "" && (r.Cancel || r.Change != ""):
+			return fmt.Errorf("%s: cancel and change need match", at)
+		}
+		if r.Change != "" {
+			if _, _, err := parseChange(r.Change); err != nil {
+				return fmt.Errorf("%s: %w", at, err)
+			}
+		}
+		if _, err := cadenceDays(r.Every); err != nil {
+			return fmt.Errorf("%s: %w", at, err)
+		}
+		if err := validateRange(r.From, r.Until); err != nil {
+			return fmt.Errorf("%s: %w", at, err)
+		}
+	}
+	for i, c := range s.Categories {
+		at := fmt.Sprintf("categories[%d]", i)
+		if c.Category == "" || c.Change == "" {
+			return fmt.Errorf("%s: category and change are required", at)
+		}
+		if _, _, err := parseChange(c.Change); err != nil {
+			return fmt.Errorf("%s: %w", at, err)
+		}
+		if err := validateRange(c.From, c.Until); err != nil {
+			return fmt.Errorf("%s: %w", at, err)
+		}
+	}
+	for i, e := range s.Events {
+		at := fmt.Sprintf("events[%d]", i)
+		if e.Amount == 0 {
+			return fmt.Errorf("%s: amount is required (negative for an outflow)", at)
+		}
+		if _, err := time.Parse("2006-01-02", e.Date); err != nil {
+			return fmt.Errorf("%s: date must be YYYY-MM-DD", at)
+		}
+	}
+	return nil
+}
+
+// parseChange reads "8%" as (0.08, true) and "95" as (95, false).
+func parseChange(s string) (float64, bool, error) {
+	t := strings.TrimSpace(s)
+	pct := strings.HasSuffix(t, "%")
+	v, err := strconv.ParseFloat(strings.TrimPrefix(strings.TrimSuffix(t, "%"), "+"), 64)
+	if err != nil {
+		return 0, false, fmt.Errorf("invalid change %q (use a percentage like 8%% or an amount)", s)
+	}
+	if pct {
+		v /= 100
+	}
+	return v, pct, nil
+}
+
+func cadenceDays(every string) (float64, error) {
+	switch every {
+	case "", "monthly":
+		return 30, nil
+	case "weekly":
+		return 7, nil
+	case "biweekly":
+		return 14, nil
+	case "quarterly":
+		return 91, nil
+	case "yearly":
+		return 365, nil
+	}
+	return 0, fmt.Errorf("every must be weekly, biweekly, monthly, quarterly or yearly")
+}
+
+// parseScenarioDate reads YYYY-MM-DD or YYYY-MM (the first of the month,
+// or its last day with monthEnd). Empty is the zero time.
+func parseScenarioDate(s string, monthEnd bool) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	if d, err := time.Parse("2006-01-02", s); err == nil {
+		return d, nil
+	}
+	m, err := time.Parse("2006-01", s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date %q (use YYYY-MM-DD or YYYY-MM)", s)
+	}
+	if monthEnd {
+		return m.AddDate(0, 1, -1), nil
+	}
+	return m, nil
+}
+
+func validateRange(from, until string) error {
+	f, err := parseScenarioDate(from, false)
+	if err != nil {
+		return err
+	}
+	u, err := parseScenarioDate(until, true)
+	if err != nil {
+		return err
+	}
+	if !f.IsZero() && !u.IsZero() && u.Before(f) {
+		return fmt.Errorf("until is before from")
+	}
+	return nil
+}
+
+// ScenarioBase is the baseline a scenario is resolved against.
+type ScenarioBase struct {
+	AsOf time.Time
+	// Items are the baseline recurring items (see BaselineRecurring).
+	Items []RecurringItem
+	// Transactions are the history used for category spend and to place
+	// detected items on an account; HistoryStart is where they begin
+	// (zero uses the earliest).
+	Transactions []models.Transaction
+	HistoryStart time.Time
+	Normalizer   *insights.Normalizer
+}
+
+// BaselineRecurring lists the recurring items a scenario can match: Sure
+// recurring transactions merged with detected subscriptions, plus detected
+// recurring income Sure does not track.
+func BaselineRecurring(txs []models.Transaction, sure []models.RecurringTransaction, sub insights.SubscriptionOptions, inc insights.CashflowOptions) []RecurringItem {
+	n := sub.Normalizer
+	items := MergeRecurring(sure, insights.DetectSubscriptionsWithOptions(txs, sub), n)
+	known := map[string]bool{}
+	for _, it := range items {
+		known[it.Merchant] = true
+	}
+	inc.Normalizer = n
+	for _, src := range insights.RecurringIncome(txs, inc) {
+		last, err := time.Parse("2006-01-02", src.LastSeen)
+		if err != nil || known[src.Source] {
+			continue
+		}
+		period := src.AvgPeriodDays
+		if period <= 0 {
+			period = 30
+		}
+		next := last.AddDate(0, 0, int(math.Round(period)))
+		if period >= 25 && period <= 35 {
+			next = addMonthsClamped(last, 1, last.Day())
+		}
+		items = append(items, RecurringItem{
+			Name:               src.Name,
+			Merchant:           src.Source,
+			Source:             "detected",
+			Classification:     "income",
+			Amount:             round2(src.AvgAmount),
+			PeriodDays:         period,
+			ExpectedDayOfMonth: last.Day(),
+			NextExpectedDate:   next.Format("2006-01-02"),
+		})
+	}
+	return items
+}
+
+// ScenarioAdjustment is one resolved adjustment as reported to the user.
+type ScenarioAdjustment struct {
+	Type    string   `json:"type"` // cancel_recurring|change_recurring|new_recurring|category|event
+	Name    string   `json:"name"`
+	Matched []string `json:"matched"` // baseline items or categories adjusted
+	From    *string  `json:"from"`
+	Until   *string  `json:"until"`
+	// Effect is the change in net cash flow (inflow positive) per month,
+	// or the event amount for one-offs (per "once").
+	Effect float64 `json:"effect"`
+	Per    string  `json:"per"` // month|once
+}
+
+// ScenarioEffects is a scenario resolved against a baseline: the dated
+// cash-flow changes it makes, ready to apply to a plan.
+type ScenarioEffects struct {
+	Name        string               `json:"name"`
+	Description string               `json:"description"`
+	Adjustments []ScenarioAdjustment `json:"adjustments"`
+	Warnings    []string             `json:"warnings"`
+	streams     []scenarioStream
+}
+
+// scenarioStream is one source of dated cash-flow changes.
+type scenarioStream struct {
+	name      string
+	kind      string // spend|income
+	accountID string
+	// amount is the cash effect per occurrence (per day when daily),
+	// inflow positive.
+	amount    float64
+	recurring bool // changes a recurring charge rather than discretionary spend
+	daily     bool
+	once      bool
+	item      *RecurringItem // occurrences of a baseline item
+	first     time.Time      // new items and one-offs
+	period    float64
+	from      time.Time // zero = open
+	until     time.Time
+}
+
+// scenarioFlow is one dated cash-flow change.
+type scenarioFlow struct {
+	date      time.Time
+	name      string
+	kind      string
+	accountID string
+	amount    float64
+	recurring bool
+	daily     bool
+}
+
+// Resolve matches the scenario's adjustments to the baseline and turns
+// them into dated cash-flow changes. Adjustments that match nothing are
+// reported as warnings.
+func (s Scenario) Resolve(base ScenarioBase) *ScenarioEffects {
+	asOf := base.AsOf
+	if asOf.IsZero() {
+		asOf = time.Now().UTC()
+	}
+	asOf = time.Date(asOf.Year(), asOf.Month(), asOf.Day(), 0, 0, 0, 0, time.UTC)
+	out := &ScenarioEffects{Name: s.Name, Description: s.Description, Adjustments: []ScenarioAdjustment{}, Warnings: []string{}}
+
+	// Detected items carry no account: place them where they were charged.
+	itemAccount := map[string]map[string]int{}
+	categorySpend := map[string]float64{}
+	categoryAccount := map[string]map[string]float64{}
+	historyStart := base.HistoryStart
+	for _, tx := range base.Transactions {
+		if base.HistoryStart.IsZero() && (historyStart.IsZero() || tx.Date.Before(historyStart)) {
+			historyStart = tx.Date
+		}
+		key := base.Normalizer.Key(tx)
+		if itemAccount[key] == nil {
+			itemAccount[key] = map[string]int{}
+		}
+		itemAccount[key][tx.AccountID]++
+		if tx.Classification != "expense" || tx.Date.After(asOf) {
+			continue
+		}
+		v, err := insights.SignedAmount(tx)
+		if err != nil {
+			continue
+		}
+		cat := strings.ToLower(tx.CategoryName)
+		categorySpend[cat] += math.Abs(v)
+		if categoryAccount[cat] == nil {
+			categoryAccount[cat] = map[string]float64{}
+		}
+		categoryAccount[cat][tx.AccountID] += math.Abs(v)
+	}
+	historyMonths := (asOf.Sub(historyStart).Hours()/24 + 1) / daysPerMonth
+	if historyStart.IsZero() || historyMonths < 1.0/daysPerMonth {
+		historyMonths = 1
+	}
+
+	for _, r := range s.Recurring {
+		from, _ := parseScenarioDate(r.From, false)
+		until, _ := parseScenarioDate(r.Until, true)
+		if r.Match == "" {
+			period, _ := cadenceDays(r.Every)
+			first := from
+			if first.IsZero() {
+				first = asOf.AddDate(0, 0, 1)
+			}
+			st := scenarioStream{name: r.Name, kind: flowKind(r.Amount), accountID: r.AccountID, amount: r.Amount, recurring: true, first: first, period: period, until: until}
+			out.add(ScenarioAdjustment{Type: "new_recurring", Name: r.Name, Matched: []string{}}, first, until, st)
+			continue
+		}
+
+		adj := ScenarioAdjustment{Type: "change_recurring", Name: r.Match, Matched: []string{}}
+		if r.Cancel {
+			adj.Type = "cancel_recurring"
+		}
+		change, pct, _ := parseChange(r.Change)
+		var streams []scenarioStream
+		for i := range base.Items {
+			it := base.Items[i]
+			m := strings.ToLower(r.Match)
+			if !strings.Contains(strings.ToLower(it.Name), m) && !strings.Contains(it.Merchant, m) && it.RecurringID != r.Match {
+				continue
+			}
+			delta := -it.Amount // cancel
+			if !r.Cancel {
+				delta = change
+				if pct {
+					delta = it.Amount * change
+				}
+			}
+			// Expenses cost more as they grow; income brings in more.
+			effect := -delta
+			if it.Classification == "income" {
+				effect = delta
+			}
+			account := r.AccountID
+			if account == "" {
+				account = it.AccountID
+			}
+			if account == "" {
+				account = mostFrequent(itemAccount[it.Merchant])
+			}
+			kind := "spend"
+			if it.Classification == "income" {
+				kind = "income"
+			}
+			adj.Matched = append(adj.Matched, it.Name)
+			streams = append(streams, scenarioStream{name: adj.Type + ": " + it.Name, kind: kind, accountID: account, amount: effect, recurring: true, item: &base.Items[i], period: it.PeriodDays, from: from, until: until})
+		}
+		if len(streams) == 0 {
+			out.Warnings = append(out.Warnings, fmt.Sprintf("recurring %q matched no recurring item", r.Match))
+		}
+		out.add(adj, from, until, streams...)
+	}
+
+	for _, c := range s.Categories {
+		from, _ := parseScenarioDate(c.From, false)
+		until, _ := parseScenarioDate(c.Until, true)
+		key := strings.ToLower(c.Category)
+		monthly := categorySpend[key] / historyMonths
+		change, pct, _ := parseChange(c.Change)
+		delta := change
+		if pct {
+			delta = monthly * change
+		}
+		adj := ScenarioAdjustment{Type: "category", Name: c.Category, Matched: []string{}}
+		if _, ok := categorySpend[key]; ok {
+			adj.Matched = append(adj.Matched, c.Category)
+		} else {
+			out.Warnings = append(out.Warnings, fmt.Sprintf("category %q has no spend in the history window", c.Category))
+		}
+		account := c.AccountID
+		if account == "" {
+			account = largest(categoryAccount[key])
+		}
+		st := scenarioStream{name: "category: " + c.Category, kind: "spend", accountID: account, amount: -delta / daysPerMonth, daily: true, from: from, until: until}
+		out.add(adj, from, until, st)
+	}
+
+	for _, e := range s.Events {
+		date, _ := time.Parse("2006-01-02", e.Date)
+		name := e.Name
+		if name == "" {
+			name = "event"
+		}
+		st := scenarioStream{name: name, kind: flowKind(e.Amount), accountID: e.AccountID, amount: e.Amount, once: true, first: date}
+		if !date.After(asOf) {
+			out.Warnings = append(out.Warnings, fmt.Sprintf("event %q on %s is not in the future", name, e.Date))
+		}
+		out.add(ScenarioAdjustment{Type: "event", Name: name, Matched: []string{}}, date, time.Time{}, st)
+	}
+	return out
+}
+
+// add records an adjustment and the streams it resolved to.
+func (e *ScenarioEffects) add(adj ScenarioAdjustment, from, until time.Time, streams ...scenarioStream) {
+	adj.Per = "month"
+	for _, s := range streams {
+		adj.Effect += s.monthly()
+		if s.once {
+			adj.Effect, adj.Per = s.amount, "once"
+		}
+	}
+	adj.Effect = round2(adj.Effect)
+	if !from.IsZero() {
+		d := from.Format("2006-01-02")
+		adj.From = &d
+	}
+	if !until.IsZero() {
+		d := until.Format("2006-01-02")
+		adj.Until = &d
+	}
+	e.Adjustments = append(e.Adjustments, adj)
+	e.streams = append(e.streams, streams...)
+}
+
+func flowKind(amount float64) string {
+	if amount > 0 {
+		return "income"
+	}
+	return "spend"
+}
+
+// monthly is the stream's cash effect per month once it applies.
+func (s scenarioStream) monthly() float64 {
+	switch {
+	case s.once:
+		return 0
+	case s.daily:
+		return s.amount * daysPerMonth
+	case s.period >= 25 && s.period <= 35:
+		return s.amount // charged on a day of month
+	case s.period > 0:
+		return s.amount * daysPerMonth / s.period
+	}
+	return s.amount
+}
+
+// flows returns the stream's dated changes in (after, end].
+func (s scenarioStream) flows(after, end time.Time) []scenarioFlow {
+	if !s.from.IsZero() && s.from.AddDate(0, 0, -1).After(after) {
+		after = s.from.AddDate(0, 0, -1)
+	}
+	if !s.until.IsZero() && s.until.Before(end) {
+		end = s.until
+	}
+	if !end.After(after) {
+		return nil
+	}
+	var dates []time.Time
+	switch {
+	case s.once:
+		if s.first.After(after) && !s.first.After(end) {
+			dates = []time.Time{s.first}
+		}
+	case s.daily:
+		for d := after.AddDate(0, 0, 1); !d.After(end); d = d.AddDate(0, 0, 1) {
+			dates = append(dates, d)
+		}
+	case s.item != nil:
+		dates = s.item.Occurrences(after, end)
+	default:
+		dates = occurrences(s.first, s.period, s.first.Day(), after, end)
+	}
+	out := make([]scenarioFlow, 0, len(dates))
+	for _, d := range dates {
+		out = append(out, scenarioFlow{date: d, name: s.name, kind: s.kind, accountID: s.accountID, amount: s.amount, recurring: s.recurring, daily: s.daily})
+	}
+	return out
+}
+
+// flows returns every dated change in (after, end], in date order.
+func (e *ScenarioEffects) flows(after, end time.Time) []scenarioFlow {
+	if e == nil {
+		return nil
+	}
+	var out []scenarioFlow
+	for _, s := range e.streams {
+		out = append(out, s.flows(after, end)...)
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].date.Before(out[j].date) })
+	return out
+}
+
+// annual returns the lasting yearly change in spend (positive = more
+// spend) and income: recurring and category changes without an end date.
+func (e *ScenarioEffects) annual() (spend, income float64) {
+	if e == nil {
+		return 0, 0
+	}
+	for _, s := range e.streams {
+		if s.once || !s.until.IsZero() {
+			continue
+		}
+		if s.kind == "income" {
+			income += s.monthly() * 12
+		} else {
+			spend -= s.monthly() * 12
+		}
+	}
+	return spend, income
+}
+
+func mostFrequent(counts map[string]int) string {
+	best, n := "", 0
+	for k, c := range counts {
+		if c > n || (c == n && k < best) {
+			best, n = k, c
+		}
+	}
+	return best
+}
+
+func largest(totals map[string]float64) string {
+	best, v := "", 0.0
+	for k, t := range totals {
+		if t > v || (t == v && k < best) {
+			best, v = k, t
+		}
+	}
+	return best
+}
+
+// ScenarioRunway reruns a runway estimate with the scenario's changes:
+// the baseline burn is spent day by day (30-day months) and the dated
+// changes are added until the balance runs out, up to 600 months. Each
+// liquidity tier is run down the same way.
+func ScenarioRunway(base RunwaySummary, e *ScenarioEffects, asOf time.Time) RunwaySummary {
+	const maxMonths = 600
+	asOf = time.Date(asOf.Year(), asOf.Month(), asOf.Day(), 0, 0, 0, 0, time.UTC)
+	out := base
+	out.Assumptions = append(append([]string{}, base.Assumptions...), "scenario changes applied on their dates; runway is capped at 600 months")
+
+	byDay := map[string]float64{}
+	var firstYear float64
+	for _, f := range e.flows(asOf, asOf.AddDate(0, 0, maxMonths*30)) {
+		byDay[f.date.Format("2006-01-02")] += f.amount
+		if f.date.Sub(asOf).Hours()/24 <= 360 {
+			firstYear += f.amount
+		}
+	}
+	out.AvgMonthlyBurn = math.Max(0, base.AvgMonthlyBurn-firstYear/12)
+
+	daily := base.AvgMonthlyBurn / 30
+	rundown := func(balance float64) float64 {
+		if balance <= 0 {
+			return 0
+		}
+		for day := 1; day <= maxMonths*30; day++ {
+			net := byDay[asOf.AddDate(0, 0, day).Format("2006-01-02")] - daily
+			if balance+net < 0 {
+				return (float64(day-1) + balance/-net) / 30
+			}
+			balance += net
+		}
+		return maxMonths
+	}
+	out.RunwayMonths = rundown(base.Balance)
+	if base.Tiers != nil {
+		out.Tiers = make([]RunwayTier, len(base.Tiers))
+		for i, t := range base.Tiers {
+			t.RunwayMonths = round2(rundown(t.Cumulative))
+			out.Tiers[i] = t
+		}
+	}
+	return out
+}
+
+// ScenarioDelta compares one metric between the baseline and the scenario.
+type ScenarioDelta struct {
+	Metric   string   `json:"metric"`
+	Baseline *float64 `json:"baseline"`
+	Scenario *float64 `json:"scenario"`
+	Delta    *float64 `json:"delta"`
+}
+
+// ScenarioComparison is a baseline plan and the same plan with a scenario
+// applied, side by side.
+type ScenarioComparison struct {
+	Scenario     *ScenarioEffects `json:"scenario"`
+	Baseline     any              `json:"baseline"`
+	WithScenario any              `json:"with_scenario"`
+	Deltas       []ScenarioDelta  `json:"deltas"`
+}
+
+func metricDelta(metric string, base, scen *float64) ScenarioDelta {
+	d := ScenarioDelta{Metric: metric, Baseline: base, Scenario: scen}
+	if base != nil && scen != nil {
+		v := round2(*scen - *base)
+		d.Delta = &v
+	}
+	return d
+}
+
+func num(v float64) *float64 {
+	v = round2(v)
+	return &v
+}
+
+// CompareForecast compares spend forecasts.
+func CompareForecast(e *ScenarioEffects, base, scen ForecastResult) ScenarioComparison {
+	return ScenarioComparison{Scenario: e, Baseline: base, WithScenario: scen, Deltas: []ScenarioDelta{
+		metricDelta("projected_spend", num(base.Summary.ProjectedSpend), num(scen.Summary.ProjectedSpend)),
+		metricDelta("recurring_expenses", num(base.Summary.RecurringExpenses), num(scen.Summary.RecurringExpenses)),
+	}}
+}
+
+// CompareBalanceForecast compares balance forecasts: totals, then each
+// account's ending and lowest balance.
+func CompareBalanceForecast(e *ScenarioEffects, base, scen BalanceForecast) ScenarioComparison {
+	out := ScenarioComparison{Scenario: e, Baseline: base, WithScenario: scen, Deltas: []ScenarioDelta{
+		metricDelta("totals.ending_balance", num(base.Totals.EndingBalance), num(scen.Totals.EndingBalance)),
+		metricDelta("totals.lowest_balance", num(base.Totals.LowestBalance), num(scen.Totals.LowestBalance)),
+		metricDelta("warnings", num(float64(len(base.Warnings))), num(float64(len(scen.Warnings)))),
+	}}
+	for i, a := range base.Accounts {
+		s := scen.Accounts[i]
+		out.Deltas = append(out.Deltas,
+			metricDelta("accounts."+a.AccountID+".ending_balance", num(a.EndingBalance), num(s.EndingBalance)),
+			metricDelta("accounts."+a.AccountID+".lowest_balance", num(a.LowestBalance), num(s.LowestBalance)),
+		)
+	}
+	return out
+}
+
+// CompareRunway compares runway estimates, per liquidity tier when set.
+func CompareRunway(e *ScenarioEffects, base, scen RunwaySummary) ScenarioComparison {
+	deltas := []ScenarioDelta{
+		metricDelta("avg_monthly_burn", num(base.AvgMonthlyBurn), num(scen.AvgMonthlyBurn)),
+		metricDelta("runway_months", num(base.RunwayMonths), num(scen.RunwayMonths)),
+	}
+	for i, t := range base.Tiers {
+		if i < len(scen.Tiers) {
+			deltas = append(deltas, metricDelta("runway_months."+t.Tier, num(t.RunwayMonths), num(scen.Tiers[i].RunwayMonths)))
+		}
+	}
+	return ScenarioComparison{Scenario: e, Baseline: base, WithScenario: scen, Deltas: deltas}
+}
+
+// CompareFire compares FI estimates; years_to_fi is null on a side that
+// never reaches FI.
+func CompareFire(e *ScenarioEffects, base, scen FireResult) ScenarioComparison {
+	return ScenarioComparison{Scenario: e, Baseline: base, WithScenario: scen, Deltas: []ScenarioDelta{
+		metricDelta("annual_spend", num(base.AnnualSpend), num(scen.AnnualSpend)),
+		metricDelta("annual_income", num(base.AnnualIncome), num(scen.AnnualIncome)),
+		metricDelta("annual_contribution", num(base.AnnualContribution), num(scen.AnnualContribution)),
+		metricDelta("fi_number", num(base.FINumber), num(scen.FINumber)),
+		metricDelta("years_to_fi", base.YearsToFI, scen.YearsToFI),
+	}}
+}
@@ -0,0 +1,152 @@
+package plan
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/we-promise/sure-cli/internal/models"
+)
+
+func TestParseScenario_Validation(t *testing.T) {
+	bad := map[string]string{
+		"empty":          "name: nothing\n",
+		"unknown key":    "recurring:\n  - match: netflix\n    cancle: true\n",
+		"cancel+change":  "recurring:\n  - match: rent\n    cancel: true\n    change: 5%\n",
+		"match+amount":   "recurring:\n  - match: rent\n    amount: 10\n",
+		"new no amount":  "recurring:\n  - name: Gym\n",
+		"bad change":     "categories:\n  - category: Dining\n    change: lots\n",
+		"bad cadence":    "recurring:\n  - name: Gym\n    amount: -30\n    every: daily\n",
+		"until < from":   "recurring:\n  - match: rent\n    change: 5%\n    from: 2027-01\n    until: 2026-12\n",
+		"event no date":  "events:\n  - name: Car\n    amount: -100\n",
+		"event no money": "events:\n  - name: Car\n    date: 2026-12-01\n",
+	}
+	for name, doc := range bad {
+		if _, err := ParseScenario([]byte(doc)); err == nil {
+			t.Errorf("%s: want an error", name)
+		}
+	}
+	s, err := ParseScenario([]byte("categories:\n  - category: Dining\n    change: 95\n"))
+	if err != nil || s.Categories[0].Change != "95" {
+		t.Fatalf("plain amount change: %+v, %v", s, err)
+	}
+}
+
+func TestScenarioResolve(t *testing.T) {
+	asOf := time.Date(2026, 6, 30, 0, 0, 0, 0, time.UTC)
+	items := []RecurringItem{
+		{Name: "NETFLIX.COM", Merchant: "netflix", Classification: "expense", Amount: 15, PeriodDays: 30, ExpectedDayOfMonth: 5, NextExpectedDate: "2026-07-05"},
+		{Name: "ACME Payroll", Merchant: "acme payroll", AccountID: "chk", Classification: "income", Amount: 3000, PeriodDays: 30, ExpectedDayOfMonth: 25, NextExpectedDate: "2026-07-25"},
+	}
+	txs := []models.Transaction{
+		{Classification: "expense", AmountText: "€300.00", CategoryName: "Dining", AccountID: "card", Date: asOf.AddDate(0, -1, 0)},
+		{Classification: "expense", AmountText: "€300.00", CategoryName: "Dining", AccountID: "card", Date: asOf.AddDate(0, -2, 0)},
+		{Classification: "expense", AmountText: "€15.00", Name: "NETFLIX.COM", AccountID: "chk", Date: asOf.AddDate(0, -1, 0)},
+	}
+	s, err := ParseScenario([]byte(`
+name: test
+recurring:
+  - match: netflix
+    cancel: true
+  - match: payroll
+    change: 10%
+    from: 2026-08
+  - name: Gym
+    amount: -30
+    from: 2026-07-10
+  - match: nothing-like-this
+    cancel: true
+categories:
+  - category: dining
+    change: -50%
+    until: 2026-07
+events:
+  - name: Car repair
+    amount: -500
+    date: 2026-07-20
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	e := s.Resolve(ScenarioBase{AsOf: asOf, Items: items, Transactions: txs, HistoryStart: asOf.AddDate(0, 0, -90)})
+	if len(e.Adjustments) != 6 || len(e.Warnings) != 1 || !strings.Contains(e.Warnings[0], "nothing-like-this") {
+		t.Fatalf("adjustments=%d warnings=%v", len(e.Adjustments), e.Warnings)
+	}
+	// 600 of dining over 91 days (2.99 months): -50% saves 100.34 a month.
+	if a := e.Adjustments[4]; a.Effect != 100.34 || a.Per != "month" || a.Until == nil || *a.Until != "2026-07-31" {
+		t.Fatalf("category adjustment = %+v", a)
+	}
+
+	sums := map[string]float64{}
+	accounts := map[string]string{}
+	for _, f := range e.flows(asOf, time.Date(2026, 8, 31, 0, 0, 0, 0, time.UTC)) {
+		sums[f.name] += f.amount
+		accounts[f.name] = f.accountID
+	}
+	want := map[string]float64{
+		"cancel_recurring: NETFLIX.COM":  30,  // July and August charges dropped
+		"change_recurring: ACME Payroll": 300, // August only
+		"Gym":                            -60,
+		"Car repair":                     -500,
+	}
+	for name, v := range want {
+		if round2(sums[name]) != v {
+			t.Errorf("%s = %v, want %v", name, sums[name], v)
+		}
+	}
+	if v := round2(sums["category: dining"]); v != 102.2 { // July only: 31 days at 100.34/30.44
+		t.Errorf("dining = %v", v)
+	}
+	if accounts["cancel_recurring: NETFLIX.COM"] != "chk" || accounts["category: dining"] != "card" || accounts["change_recurring: ACME Payroll"] != "chk" {
+		t.Errorf("accounts = %v", accounts)
+	}
+
+	// Lasting changes only: the dining cut ends and the event is one-off.
+	spend, income := e.annual()
+	if round2(spend) != 180 || round2(income) != 3600 {
+		t.Fatalf("annual spend=%v income=%v", spend, income)
+	}
+}
+
+func TestScenarioRunway(t *testing.T) {
+	asOf := time.Date(2026, 6, 30, 0, 0, 0, 0, time.UTC)
+	base := RunwaySummary{Balance: 3000, AvgMonthlyBurn: 1000, RunwayMonths: 3, Tiers: []RunwayTier{
+		{Tier: TierCash, Balance: 3000, Cumulative: 3000, RunwayMonths: 3},
+		{Tier: TierNearCash, Balance: 3000, Cumulative: 6000, RunwayMonths: 6},
+	}}
+	s, _ := ParseScenario([]byte("events:\n  - name: Bonus\n    amount: 1000\n    date: 2026-07-10\n"))
+	res := ScenarioRunway(base, s.Resolve(ScenarioBase{AsOf: asOf}), asOf)
+	if round2(res.RunwayMonths) != 4 || round2(res.AvgMonthlyBurn) != 916.67 {
+		t.Fatalf("runway = %v burn = %v", res.RunwayMonths, res.AvgMonthlyBurn)
+	}
+	if res.Tiers[1].RunwayMonths != 7 || base.Tiers[1].RunwayMonths != 6 {
+		t.Fatalf("tiers = %+v", res.Tiers)
+	}
+	if cmp := CompareRunway(nil, base, res); *cmp.Deltas[1].Delta != 1 || cmp.Deltas[3].Metric != "runway_months.near_cash" || *cmp.Deltas[3].Delta != 1 {
+		t.Fatalf("deltas = %+v", cmp.Deltas)
+	}
+}
+
+func TestComputeFire_Scenario(t *testing.T) {
+	asOf := time.Date(2026, 6, 30, 0, 0, 0, 0, time.UTC)
+	txs := []models.Transaction{
+		{Classification: "expense", AmountText: "€30,000.00", Date: asOf.AddDate(0, -1, 0)},
+		{Classification: "income", AmountText: "€50,000.00", Date: asOf.AddDate(0, -2, 0)},
+	}
+	accounts := []models.Account{{ID: "brk", AccountType: "investment", Classification: "asset", BalanceText: "€100,000.00"}}
+	items := []RecurringItem{{Name: "Rent", Merchant: "rent", Classification: "expense", Amount: 1000, PeriodDays: 30, NextExpectedDate: "2026-07-01"}}
+	s, _ := ParseScenario([]byte("recurring:\n  - match: rent\n    change: -100\nevents:\n  - name: Wedding\n    amount: -15000\n    date: 2026-09-01\n"))
+	opts := FireOptions{WithdrawalRate: 0.04, AsOf: asOf, HistoryStart: asOf.AddDate(0, 0, -364), Years: 2}
+	base := ComputeFire(accounts, nil, nil, txs, opts)
+	opts.Scenario = s.Resolve(ScenarioBase{AsOf: asOf, Items: items})
+	res := ComputeFire(accounts, nil, nil, txs, opts)
+
+	// Rent -100 a month: 1,200 less spend a year, 30,000 off the FI number.
+	if res.AnnualSpend != 28800 || res.FINumber != base.FINumber-30000 || res.AnnualContribution != 21200 {
+		t.Fatalf("spend=%v fi=%v contribution=%v", res.AnnualSpend, res.FINumber, res.AnnualContribution)
+	}
+	// The wedding lands in the first year's contribution only.
+	if res.Projection[0].Contribution != 6200 || res.Projection[1].Contribution != 21200 {
+		t.Fatalf("contributions = %v, %v", res.Projection[0].Contribution, res.Projection[1].Contribution)
+	}
+}
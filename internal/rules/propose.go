@@ -5,12 +5,14 @@ import (
 	"sort"
 	"strings"
 
+	"github.com/we-promise/sure-cli/internal/insights"
 	"github.com/we-promise/sure-cli/internal/models"
 )
 
 type RuleProposal struct {
 	Type            string   `json:"type"`     // "category" | "tag" | "merchant"
 	Pattern         string   `json:"pattern"`  // merchant name or pattern
+	Merchant        string   `json:"merchant"` // normalized grouping key
 	Action          string   `json:"action"`   // e.g. "set_category", "add_tag"
 	Value           string   `json:"value"`    // category name or tag
 	ValueID         string   `json:"value_id"` // category ID (for applying)
@@ -26,6 +28,35 @@ type ProposeResult struct {
 	Proposals     []RuleProposal `json:"proposals"`
 	TotalTx       int            `json:"total_transactions"`
 	Uncategorized int            `json:"uncategorized_count"`
+	Params        Options        `json:"params"`
+}
+
+// Options tunes ProposeRulesWithOptions; zero fields use DefaultOptions.
+type Options struct {
+	MinConsistency float64 `json:"min_consistency"` // share of a group's transactions in the dominant category
+	MinOccurrences int     `json:"min_occurrences"` // group size before a rule is considered
+	MaxProposals   int     `json:"max_proposals"`
+	// Normalizer groups descriptors by merchant; nil uses the built-in rules.
+	Normalizer *insights.Normalizer `json:"-"`
+}
+
+// DefaultOptions: 70% consistency, at least 2 occurrences, top 20 proposals.
+func DefaultOptions() Options {
+	return Options{MinConsistency: 0.7, MinOccurrences: 2, MaxProposals: 20}
+}
+
+func (o Options) withDefaults() Options {
+	def := DefaultOptions()
+	if o.MinConsistency <= 0 {
+		o.MinConsistency = def.MinConsistency
+	}
+	if o.MinOccurrences <= 0 {
+		o.MinOccurrences = def.MinOccurrences
+	}
+	if o.MaxProposals <= 0 {
+		o.MaxProposals = def.MaxProposals
+	}
+	return o
 }
 
 // ProposeRules analyzes transactions and suggests categorization rules.
@@ -34,23 +65,25 @@ type ProposeResult struct {
 // - If a merchant always has the same category, suggest a rule
 // - If a merchant is uncategorized but similar names have categories, suggest
 func ProposeRules(txs []models.Transaction) ProposeResult {
-	// Group by merchant/name
-	byName := make(map[string][]models.Transaction)
-	for _, tx := range txs {
-		name := strings.TrimSpace(tx.Name)
-		if name == "" {
-			continue
-		}
-		byName[name] = append(byName[name], tx)
-	}
+	return ProposeRulesWithOptions(txs, DefaultOptions())
+}
+
+// ProposeRulesWithOptions is ProposeRules with explicit thresholds.
+func ProposeRulesWithOptions(txs []models.Transaction, opts Options) ProposeResult {
+	opts = opts.withDefaults()
+
+	// Group by normalized merchant
+	hasName := func(tx models.Transaction) bool { return strings.TrimSpace(tx.Name) != "" }
+	groups := insights.GroupByMerchant(txs, opts.Normalizer, hasName)
 
 	var proposals []RuleProposal
 	var uncategorized int
 
 	// Analyze each merchant group
-	for name, txList := range byName {
-		if len(txList) < 2 {
-			continue // need at least 2 occurrences
+	for _, g := range groups {
+		txList := g.Txs
+		if len(txList) < opts.MinOccurrences {
+			continue
 		}
 
 		// Count categories and track category IDs
@@ -87,7 +120,7 @@ func ProposeRules(txs []models.Transaction) ProposeResult {
 
 		// Calculate confidence based on consistency
 		consistency := float64(dominantCount) / float64(len(txList))
-		if consistency < 0.7 {
+		if consistency < opts.MinConsistency {
 			continue // not consistent enough
 		}
 
@@ -117,7 +150,8 @@ func ProposeRules(txs []models.Transaction) ProposeResult {
 
 		proposals = append(proposals, RuleProposal{
 			Type:            "category",
-			Pattern:         name,
+			Pattern:         g.Name,
+			Merchant:        g.Key,
 			Action:          "set_category",
 			Value:           dominantCat,
 			ValueID:         catIDs[dominantCat],
@@ -138,14 +172,14 @@ func ProposeRules(txs []models.Transaction) ProposeResult {
 		return proposals[i].Confidence > proposals[j].Confidence
 	})
 
-	// Limit to top 20
-	if len(proposals) > 20 {
-		proposals = proposals[:20]
+	if len(proposals) > opts.MaxProposals {
+		proposals = proposals[:opts.MaxProposals]
 	}
 
 	return ProposeResult{
 		Proposals:     proposals,
 		TotalTx:       len(txs),
 		Uncategorized: uncategorized,
+		Params:        opts,
 	}
 }
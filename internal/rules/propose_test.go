@@ -80,3 +80,30 @@ func TestProposeRules_InconsistentCategory(t *testing.T) {
 		}
 	}
 }
+
+func TestProposeRulesWithOptions_Thresholds(t *testing.T) {
+	now := time.Now().UTC()
+
+	txs := []models.Transaction{
+		{ID: "1", Name: "Mercadona", Classification: "expense", CategoryName: "Groceries", Date: now.AddDate(0, 0, -1)},
+		{ID: "2", Name: "Mercadona", Classification: "expense", CategoryName: "Groceries", Date: now.AddDate(0, 0, -2)},
+		{ID: "3", Name: "Mercadona", Classification: "expense", CategoryName: "Household", Date: now.AddDate(0, 0, -3)},
+	}
+
+	if got := ProposeRules(txs); len(got.Proposals) != 0 {
+		t.Fatalf("expected 2/3 consistency to fall below default, got %d proposals", len(got.Proposals))
+	}
+
+	loose := ProposeRulesWithOptions(txs, Options{MinConsistency: 0.6})
+	if len(loose.Proposals) != 1 {
+		t.Fatalf("expected 1 proposal at 0.6 consistency, got %d", len(loose.Proposals))
+	}
+	if loose.Params.MinOccurrences != DefaultOptions().MinOccurrences {
+		t.Errorf("expected unset min_occurrences to default, got %d", loose.Params.MinOccurrences)
+	}
+
+	strict := ProposeRulesWithOptions(txs, Options{MinConsistency: 0.6, MinOccurrences: 4})
+	if len(strict.Proposals) != 0 {
+		t.Errorf("expected min_occurrences=4 to suppress proposal, got %d", len(strict.Proposals))
+	}
+}
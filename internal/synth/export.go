@@ -0,0 +1,173 @@
+package synth
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strconv"
+
+	"github.com/we-promise/sure-cli/internal/models"
+)
+
+// CSVHeader is the column layout WriteCSV emits; pass the same labels to
+// `imports create --type TransactionImport`.
+var CSVHeader = []string{"date", "amount", "name", "category", "account", "notes"}
+
+var currencySymbols = map[string]string{"EUR": "€", "USD": "$", "GBP": "£"}
+
+// decimal renders cents as a plain decimal string ("-12.05").
+func decimal(cents int64) string {
+	neg := cents < 0
+	if neg {
+		cents = -cents
+	}
+	s := strconv.FormatInt(cents/100, 10) + "." + strconv.FormatInt(cents%100/10, 10) + strconv.FormatInt(cents%10, 10)
+	if neg {
+		s = "-" + s
+	}
+	return s
+}
+
+// WriteNDJSON emits the household in Sure's SureImport format: one
+// {"type": ..., "data": {...}} record per line, accounts and reference data
+// first so transactions can refer to them by id.
+func (h *Household) WriteNDJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	emit := func(typ string, data map[string]any) error {
+		return enc.Encode(map[string]any{"type": typ, "data": data})
+	}
+	cur := h.Options.Currency
+
+	for _, a := range h.Accounts {
+		data := map[string]any{
+			"id":               a.ID,
+			"name":             a.Name,
+			"balance":          decimal(a.BalanceCents),
+			"currency":         a.Currency,
+			"accountable_type": a.AccountableType,
+			"classification":   a.Classification,
+		}
+		if a.Subtype != "" {
+			data["subtype"] = a.Subtype
+		}
+		if err := emit("Account", data); err != nil {
+			return err
+		}
+	}
+	for _, c := range h.Categories {
+		if err := emit("Category", map[string]any{"id": c.ID, "name": c.Name, "classification": c.Classification, "color": c.Color}); err != nil {
+			return err
+		}
+	}
+	for _, m := range h.Merchants {
+		if err := emit("Merchant", map[string]any{"id": m.ID, "name": m.Name}); err != nil {
+			return err
+		}
+	}
+	for _, tx := range h.Transactions {
+		data := map[string]any{
+			"id":         tx.ID,
+			"account_id": tx.AccountID,
+			"date":       tx.Date.Format(dateLayout),
+			"amount":     decimal(tx.AmountCents),
+			"currency":   cur,
+			"name":       tx.Name,
+			"kind":       tx.Kind,
+			"notes":      tx.Notes,
+		}
+		if tx.CategoryID != "" {
+			data["category_id"] = tx.CategoryID
+		}
+		if tx.MerchantID != "" {
+			data["merchant_id"] = tx.MerchantID
+		}
+		if err := emit("Transaction", data); err != nil {
+			return err
+		}
+	}
+	for _, t := range h.Trades {
+		ticker := ""
+		for _, s := range h.Securities {
+			if s.ID == t.SecurityID {
+				ticker = s.Ticker
+			}
+		}
+		amount := int64(t.Qty*float64(t.PriceCents) + 0.5)
+		if err := emit("Trade", map[string]any{
+			"id":         t.ID,
+			"account_id": t.AccountID,
+			"ticker":     ticker,
+			"date":       t.Date.Format(dateLayout),
+			"qty":        strconv.FormatFloat(t.Qty, 'f', 4, 64),
+			"price":      decimal(t.PriceCents),
+			"amount":     decimal(amount),
+			"currency":   cur,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteCSV emits transactions only (TransactionImport cannot carry trades),
+// with inflows positive so the default signage convention applies.
+func (h *Household) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(CSVHeader); err != nil {
+		return err
+	}
+	for _, tx := range h.Transactions {
+		account := ""
+		if a := h.accountByID(tx.AccountID); a != nil {
+			account = a.Name
+		}
+		if err := cw.Write([]string{
+			tx.Date.Format(dateLayout),
+			decimal(-tx.AmountCents),
+			tx.Name,
+			h.CategoryName(tx.CategoryID),
+			account,
+			tx.Notes,
+		}); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// ModelTransactions converts the household to the shape
+// api.FetchTransactionsWindow returns, so detectors can run on it directly.
+func (h *Household) ModelTransactions() []models.Transaction {
+	sym, ok := currencySymbols[h.Options.Currency]
+	if !ok {
+		sym = h.Options.Currency + " "
+	}
+	out := make([]models.Transaction, 0, len(h.Transactions))
+	for _, tx := range h.Transactions {
+		class := "expense"
+		amount := sym + decimal(tx.AmountCents)
+		if tx.AmountCents < 0 {
+			class = "income"
+			amount = "-" + sym + decimal(-tx.AmountCents)
+		}
+		m := models.Transaction{
+			ID:             tx.ID,
+			Name:           tx.Name,
+			Classification: class,
+			AmountText:     amount,
+			Currency:       h.Options.Currency,
+			Date:           tx.Date,
+			AccountID:      tx.AccountID,
+			MerchantID:     tx.MerchantID,
+			MerchantName:   h.merchantName(tx.MerchantID),
+			CategoryName:   h.CategoryName(tx.CategoryID),
+			CategoryID:     tx.CategoryID,
+		}
+		if a := h.accountByID(tx.AccountID); a != nil {
+			m.AccountName = a.Name
+		}
+		out = append(out, m)
+	}
+	return out
+}
@@ -0,0 +1,148 @@
+package synth
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/we-promise/sure-cli/internal/insights"
+)
+
+func testOptions() Options {
+	o := DefaultOptions()
+	o.End = time.Date(2026, 6, 30, 0, 0, 0, 0, time.UTC)
+	return o
+}
+
+func TestGenerate_Deterministic(t *testing.T) {
+	render := func(o Options) string {
+		h, err := Generate(o)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var b bytes.Buffer
+		if err := h.WriteNDJSON(&b); err != nil {
+			t.Fatal(err)
+		}
+		return b.String()
+	}
+	o := testOptions()
+	a, b := render(o), render(o)
+	if a != b {
+		t.Fatal("same seed produced different output")
+	}
+	o.Seed = 7
+	if render(o) == a {
+		t.Fatal("different seed produced identical output")
+	}
+}
+
+func TestWriteNDJSON_RecordsAreTypedAndReferenceAccounts(t *testing.T) {
+	h, err := Generate(testOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var b bytes.Buffer
+	if err := h.WriteNDJSON(&b); err != nil {
+		t.Fatal(err)
+	}
+	accounts := map[string]bool{}
+	types := map[string]int{}
+	for _, line := range strings.Split(strings.TrimSpace(b.String()), "\n") {
+		var rec struct {
+			Type string         `json:"type"`
+			Data map[string]any `json:"data"`
+		}
+		if err := json.Unmarshal([]byte(line), &rec); err != nil {
+			t.Fatalf("invalid line %q: %v", line, err)
+		}
+		types[rec.Type]++
+		switch rec.Type {
+		case "Account":
+			accounts[rec.Data["id"].(string)] = true
+		case "Transaction", "Trade":
+			if !accounts[rec.Data["account_id"].(string)] {
+				t.Fatalf("%s references unknown account: %v", rec.Type, rec.Data)
+			}
+		}
+	}
+	for _, typ := range []string{"Account", "Category", "Merchant", "Transaction", "Trade"} {
+		if types[typ] == 0 {
+			t.Fatalf("no %s records", typ)
+		}
+	}
+}
+
+func TestWriteCSV_Header(t *testing.T) {
+	h, err := Generate(testOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var b bytes.Buffer
+	if err := h.WriteCSV(&b); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(b.String()), "\n")
+	if lines[0] != "date,amount,name,category,account,notes" {
+		t.Fatalf("header = %q", lines[0])
+	}
+	if len(lines)-1 != len(h.Transactions) {
+		t.Fatalf("rows = %d, want %d", len(lines)-1, len(h.Transactions))
+	}
+}
+
+func TestLabels_MatchGeneratedData(t *testing.T) {
+	o := testOptions()
+	o.FeeLanguages = []string{"en", "es", "de", "fr"}
+	h, err := Generate(o)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ids := map[string]bool{}
+	for _, tx := range h.Transactions {
+		ids[tx.ID] = true
+	}
+	if len(h.Labels.Transfers) == 0 {
+		t.Fatal("expected transfer labels")
+	}
+	for _, tr := range h.Labels.Transfers {
+		if !ids[tr.OutflowID] || !ids[tr.InflowID] {
+			t.Fatalf("transfer label references missing transactions: %+v", tr)
+		}
+	}
+
+	// Every labeled fee is phrased in a language the default keyword list
+	// covers, so the fee detector must find all of them.
+	found := map[string]bool{}
+	for _, f := range insights.DetectFees(h.ModelTransactions(), nil) {
+		found[f.Name] = true
+	}
+	langs := map[string]bool{}
+	for _, f := range h.Labels.Fees {
+		langs[f.Language] = true
+		if !found[f.Name] {
+			t.Fatalf("fee %q (%s) not detected", f.Name, f.Language)
+		}
+	}
+	if len(langs) != 4 {
+		t.Fatalf("fee languages = %v, want 4", langs)
+	}
+
+	var priceChanges int
+	for _, s := range h.Labels.Subscriptions {
+		priceChanges += len(s.PriceChanges)
+	}
+	if priceChanges == 0 {
+		t.Fatal("expected at least one labeled price change")
+	}
+}
+
+func TestGenerate_RejectsUnknownFeeLanguage(t *testing.T) {
+	o := testOptions()
+	o.FeeLanguages = []string{"xx"}
+	if _, err := Generate(o); err == nil {
+		t.Fatal("expected error for unsupported language")
+	}
+}
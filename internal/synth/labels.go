@@ -0,0 +1,88 @@
+package synth
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+)
+
+// Labels is the ground truth for a generated household. Detectors group by
+// transaction name, so labels are keyed by name as well.
+type Labels struct {
+	Seed          int64               `json:"seed"`
+	Start         string              `json:"start"`
+	End           string              `json:"end"`
+	Months        int                 `json:"months"`
+	Subscriptions []SubscriptionLabel `json:"subscriptions"`
+	Fees          []FeeLabel          `json:"fees"`
+	Leaks         []LeakLabel         `json:"leaks"`
+	Rules         []RuleLabel         `json:"rules"`
+	Transfers     []TransferLabel     `json:"transfers"`
+}
+
+type SubscriptionLabel struct {
+	Name         string             `json:"name"`
+	Kind         string             `json:"kind"`   // subscription | bill
+	Period       string             `json:"period"` // weekly | biweekly | monthly | quarterly | yearly
+	PeriodDays   int                `json:"period_days"`
+	Amount       float64            `json:"amount"`
+	Count        int                `json:"count"`
+	PriceChanges []PriceChangeLabel `json:"price_changes,omitempty"`
+}
+
+type PriceChangeLabel struct {
+	Date string  `json:"date"`
+	From float64 `json:"from"`
+	To   float64 `json:"to"`
+}
+
+type FeeLabel struct {
+	Name     string `json:"name"`
+	Language string `json:"language"`
+	Count    int    `json:"count"`
+}
+
+type LeakLabel struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+type RuleLabel struct {
+	Pattern  string `json:"pattern"`
+	Category string `json:"category"`
+}
+
+type TransferLabel struct {
+	OutflowID string  `json:"outflow_id"`
+	InflowID  string  `json:"inflow_id"`
+	Amount    float64 `json:"amount"`
+	Date      string  `json:"date"`
+	Kind      string  `json:"kind"`
+}
+
+// ReadLabels decodes a labels.json document.
+func ReadLabels(r io.Reader) (*Labels, error) {
+	var l Labels
+	if err := json.NewDecoder(r).Decode(&l); err != nil {
+		return nil, fmt.Errorf("decode labels: %w", err)
+	}
+	return &l, nil
+}
+
+// LoadLabels reads labels from a file path.
+func LoadLabels(path string) (*Labels, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ReadLabels(f)
+}
+
+// WriteLabels encodes labels as indented JSON.
+func WriteLabels(w io.Writer, l Labels) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(l)
+}
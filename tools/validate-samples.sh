@@ -15,3 +15,26 @@ validate "$root/docs/schemas/v1/envelope.schema.json" "$root/docs/examples/trans
 validate "$root/docs/schemas/v1/envelope.schema.json" "$root/docs/examples/insights_subscriptions.json"
 validate "$root/docs/schemas/v1/envelope.schema.json" "$root/docs/examples/insights_fees.json"
 validate "$root/docs/schemas/v1/envelope.schema.json" "$root/docs/examples/insights_leaks.json"
+validate "$root/docs/schemas/v1/envelope.schema.json" "$root/docs/examples/insights_eval.json"
+validate "$root/docs/schemas/v1/envelope.schema.json" "$root/docs/examples/insights_merchants.json"
+validate "$root/docs/schemas/v1/envelope.schema.json" "$root/docs/examples/insights_anomalies.json"
+validate "$root/docs/schemas/v1/envelope.schema.json" "$root/docs/examples/insights_duplicates.json"
+validate "$root/docs/schemas/v1/envelope.schema.json" "$root/docs/examples/transactions_dedupe.json"
+validate "$root/docs/schemas/v1/envelope.schema.json" "$root/docs/examples/insights_categories.json"
+validate "$root/docs/schemas/v1/envelope.schema.json" "$root/docs/examples/insights_cashflow.json"
+validate "$root/docs/schemas/v1/envelope.schema.json" "$root/docs/examples/plan_budget_categories.json"
+validate "$root/docs/schemas/v1/envelope.schema.json" "$root/docs/examples/budgets_suggest.json"
+validate "$root/docs/schemas/v1/envelope.schema.json" "$root/docs/examples/plan_forecast.json"
+validate "$root/docs/schemas/v1/envelope.schema.json" "$root/docs/examples/plan_forecast_balances.json"
+validate "$root/docs/schemas/v1/envelope.schema.json" "$root/docs/examples/recurring_sync_detected.json"
+validate "$root/docs/schemas/v1/envelope.schema.json" "$root/docs/examples/plan_fire.json"
+validate "$root/docs/schemas/v1/envelope.schema.json" "$root/docs/examples/plan_longevity.json"
+validate "$root/docs/schemas/v1/envelope.schema.json" "$root/docs/examples/plan_debt.json"
+validate "$root/docs/schemas/v1/envelope.schema.json" "$root/docs/examples/goals_status.json"
+validate "$root/docs/schemas/v1/envelope.schema.json" "$root/docs/examples/plan_scenario.json"
+validate "$root/docs/schemas/v1/envelope.schema.json" "$root/docs/examples/plan_runway.json"
+validate "$root/docs/schemas/v1/envelope.schema.json" "$root/docs/examples/transfers_suggest.json"
+validate "$root/docs/schemas/v1/envelope.schema.json" "$root/docs/examples/transactions_bulk_update.json"
+validate "$root/docs/schemas/v1/envelope.schema.json" "$root/docs/examples/history_list.json"
+validate "$root/docs/schemas/v1/envelope.schema.json" "$root/docs/examples/history_show.json"
+validate "$root/docs/schemas/v1/envelope.schema.json" "$root/docs/examples/undo.json"